@@ -0,0 +1,139 @@
+package bloomfilter
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+
+	"github.com/multiversx/mx-chain-storage-go/common"
+)
+
+const bitsPerWord = 64
+const minNumBits = bitsPerWord
+const minNumHashes = 1
+
+// bloomFilter implements a classic, fixed size, bloom filter. It is not concurrent safe.
+type bloomFilter struct {
+	words     []uint64
+	numBits   uint64
+	numHashes uint32
+	capacity  uint64
+	fpRate    float64
+	numItems  uint64
+}
+
+// NewBloomFilter creates a fixed size bloom filter, dimensioned for the provided capacity and false positive rate.
+// The returned instance is not concurrent safe.
+func NewBloomFilter(capacity uint64, falsePositiveRate float64) (*bloomFilter, error) {
+	err := checkFilterParameters(capacity, falsePositiveRate)
+	if err != nil {
+		return nil, err
+	}
+
+	return newBloomFilter(capacity, falsePositiveRate), nil
+}
+
+func checkFilterParameters(capacity uint64, falsePositiveRate float64) error {
+	if capacity == 0 {
+		return common.ErrInvalidBloomFilterCapacity
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		return common.ErrInvalidFalsePositiveRate
+	}
+
+	return nil
+}
+
+func newBloomFilter(capacity uint64, falsePositiveRate float64) *bloomFilter {
+	numBits := computeNumBits(capacity, falsePositiveRate)
+	numWords := (numBits + bitsPerWord - 1) / bitsPerWord
+
+	return &bloomFilter{
+		words:     make([]uint64, numWords),
+		numBits:   numWords * bitsPerWord,
+		numHashes: computeNumHashes(numBits, capacity),
+		capacity:  capacity,
+		fpRate:    falsePositiveRate,
+	}
+}
+
+// computeNumBits returns the optimal number of bits: m = -n * ln(p) / (ln 2)^2
+func computeNumBits(capacity uint64, falsePositiveRate float64) uint64 {
+	numBits := -float64(capacity) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)
+
+	return uint64(math.Max(math.Ceil(numBits), minNumBits))
+}
+
+// computeNumHashes returns the optimal number of hash functions: k = m / n * ln 2
+func computeNumHashes(numBits uint64, capacity uint64) uint32 {
+	numHashes := float64(numBits) / float64(capacity) * math.Ln2
+
+	return uint32(math.Max(math.Round(numHashes), minNumHashes))
+}
+
+// computeHashes returns the two base hashes used for the double hashing scheme (Kirsch-Mitzenmacher)
+func computeHashes(key []byte) (uint64, uint64) {
+	hasher := fnv.New128a()
+	_, _ = hasher.Write(key)
+	sum := hasher.Sum(nil)
+
+	h1 := binary.BigEndian.Uint64(sum[:8])
+	h2 := binary.BigEndian.Uint64(sum[8:])
+
+	// h2 is forced to be odd so that all the derived positions are distinct
+	return h1, h2 | 1
+}
+
+// Add adds the provided key in the filter
+func (bf *bloomFilter) Add(key []byte) {
+	h1, h2 := computeHashes(key)
+	bf.addHashes(h1, h2)
+}
+
+func (bf *bloomFilter) addHashes(h1 uint64, h2 uint64) {
+	for i := uint64(0); i < uint64(bf.numHashes); i++ {
+		position := (h1 + i*h2) % bf.numBits
+		bf.words[position/bitsPerWord] |= 1 << (position % bitsPerWord)
+	}
+
+	bf.numItems++
+}
+
+// Contains returns false if the key was certainly not added, true if it might have been added
+func (bf *bloomFilter) Contains(key []byte) bool {
+	h1, h2 := computeHashes(key)
+	return bf.containsHashes(h1, h2)
+}
+
+func (bf *bloomFilter) containsHashes(h1 uint64, h2 uint64) bool {
+	for i := uint64(0); i < uint64(bf.numHashes); i++ {
+		position := (h1 + i*h2) % bf.numBits
+		if bf.words[position/bitsPerWord]&(1<<(position%bitsPerWord)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NumItems returns the number of additions done on the filter
+func (bf *bloomFilter) NumItems() uint64 {
+	return bf.numItems
+}
+
+// Reset clears the filter
+func (bf *bloomFilter) Reset() {
+	for i := range bf.words {
+		bf.words[i] = 0
+	}
+	bf.numItems = 0
+}
+
+func (bf *bloomFilter) isFull() bool {
+	return bf.numItems >= bf.capacity
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (bf *bloomFilter) IsInterfaceNil() bool {
+	return bf == nil
+}
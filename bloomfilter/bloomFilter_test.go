@@ -0,0 +1,120 @@
+package bloomfilter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBloomFilter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("zero capacity should error", func(t *testing.T) {
+		t.Parallel()
+
+		bf, err := NewBloomFilter(0, 0.01)
+		assert.True(t, check.IfNil(bf))
+		assert.Equal(t, common.ErrInvalidBloomFilterCapacity, err)
+	})
+	t.Run("invalid false positive rate should error", func(t *testing.T) {
+		t.Parallel()
+
+		bf, err := NewBloomFilter(10, 0)
+		assert.True(t, check.IfNil(bf))
+		assert.Equal(t, common.ErrInvalidFalsePositiveRate, err)
+
+		bf, err = NewBloomFilter(10, 1)
+		assert.True(t, check.IfNil(bf))
+		assert.Equal(t, common.ErrInvalidFalsePositiveRate, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		bf, err := NewBloomFilter(10, 0.01)
+		assert.False(t, check.IfNil(bf))
+		assert.Nil(t, err)
+	})
+}
+
+func TestBloomFilter_AddContainsReset(t *testing.T) {
+	t.Parallel()
+
+	numItems := 1000
+	bf, _ := NewBloomFilter(uint64(numItems), 0.01)
+	for i := 0; i < numItems; i++ {
+		bf.Add([]byte(fmt.Sprintf("key%d", i)))
+	}
+	assert.Equal(t, uint64(numItems), bf.NumItems())
+
+	for i := 0; i < numItems; i++ {
+		assert.True(t, bf.Contains([]byte(fmt.Sprintf("key%d", i))))
+	}
+
+	numFalsePositives := 0
+	for i := 0; i < numItems; i++ {
+		if bf.Contains([]byte(fmt.Sprintf("missing%d", i))) {
+			numFalsePositives++
+		}
+	}
+	assert.Less(t, numFalsePositives, numItems*5/100)
+
+	bf.Reset()
+	assert.Equal(t, uint64(0), bf.NumItems())
+	assert.False(t, bf.Contains([]byte("key0")))
+}
+
+func TestScalableBloomFilter_ShouldGrow(t *testing.T) {
+	t.Parallel()
+
+	sbf := newScalableBloomFilter(10, 0.01)
+	numItems := 1000
+	for i := 0; i < numItems; i++ {
+		sbf.add([]byte(fmt.Sprintf("key%d", i)))
+	}
+
+	assert.Greater(t, len(sbf.filters), 1)
+	assert.Equal(t, uint64(numItems), sbf.numItems())
+	for i := 0; i < numItems; i++ {
+		assert.True(t, sbf.contains([]byte(fmt.Sprintf("key%d", i))))
+	}
+}
+
+func TestScalableBloomFilter_SerializeDeserialize(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		sbf := newScalableBloomFilter(10, 0.01)
+		for i := 0; i < 100; i++ {
+			sbf.add([]byte(fmt.Sprintf("key%d", i)))
+		}
+
+		recovered, err := deserializeScalableBloomFilter(sbf.serialize())
+		require.Nil(t, err)
+		assert.Equal(t, sbf, recovered)
+	})
+	t.Run("corrupted data should error", func(t *testing.T) {
+		t.Parallel()
+
+		sbf := newScalableBloomFilter(10, 0.01)
+		sbf.add([]byte("key"))
+		data := sbf.serialize()
+		data[len(data)/2]++
+
+		recovered, err := deserializeScalableBloomFilter(data)
+		assert.Nil(t, recovered)
+		assert.Equal(t, common.ErrCorruptedBloomFilterData, err)
+	})
+	t.Run("too short data should error", func(t *testing.T) {
+		t.Parallel()
+
+		recovered, err := deserializeScalableBloomFilter([]byte{1, 2})
+		assert.Nil(t, recovered)
+		assert.Equal(t, common.ErrCorruptedBloomFilterData, err)
+	})
+}
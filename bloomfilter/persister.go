@@ -0,0 +1,223 @@
+package bloomfilter
+
+import (
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-storage-go/changefeed"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+var _ types.Persister = (*bloomFilterPersister)(nil)
+
+var log = logger.GetOrCreate("storage/bloomfilter")
+
+// read + write for owner only
+const rwOwner = 0600
+
+// ArgsBloomFilterPersister is the DTO used to create a new bloom filter persister instance
+type ArgsBloomFilterPersister struct {
+	Persister         types.Persister
+	FilePath          string
+	InitialCapacity   uint64
+	FalsePositiveRate float64
+}
+
+// bloomFilterPersister is a persister decorator that keeps a scalable bloom filter over all the written keys.
+// Lookups for keys the filter reports as absent are answered without touching the wrapped persister.
+type bloomFilterPersister struct {
+	persister         types.Persister
+	filePath          string
+	initialCapacity   uint64
+	falsePositiveRate float64
+	mutFilter         sync.RWMutex
+	filter            *scalableBloomFilter
+	mutWrite          sync.Mutex
+	changeFeed        types.ChangeFeed
+}
+
+// NewBloomFilterPersister creates a new bloom filter persister decorator. The filter is loaded from the sidecar file,
+// if a valid one is found, otherwise it is rebuilt by iterating over all the keys of the wrapped persister.
+// The sidecar file is removed once loaded so that an unclean shutdown will never lead to a stale filter.
+func NewBloomFilterPersister(args ArgsBloomFilterPersister) (*bloomFilterPersister, error) {
+	err := checkArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	bfp := &bloomFilterPersister{
+		persister:         args.Persister,
+		filePath:          args.FilePath,
+		initialCapacity:   args.InitialCapacity,
+		falsePositiveRate: args.FalsePositiveRate,
+		changeFeed:        changefeed.NewChangeFeed(),
+	}
+
+	bfp.filter, err = bfp.loadFilter()
+	if err != nil {
+		log.Debug("bloom filter persister: rebuilding filter", "path", args.FilePath, "reason", err)
+		bfp.filter = bfp.rebuildFilter()
+	}
+
+	return bfp, nil
+}
+
+func checkArgs(args ArgsBloomFilterPersister) error {
+	if check.IfNil(args.Persister) {
+		return common.ErrNilPersister
+	}
+	if len(args.FilePath) == 0 {
+		return common.ErrEmptyFilePath
+	}
+
+	return checkFilterParameters(args.InitialCapacity, args.FalsePositiveRate)
+}
+
+func (bfp *bloomFilterPersister) loadFilter() (*scalableBloomFilter, error) {
+	data, err := os.ReadFile(bfp.filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	bfp.removeSidecarFile()
+
+	return deserializeScalableBloomFilter(data)
+}
+
+func (bfp *bloomFilterPersister) rebuildFilter() *scalableBloomFilter {
+	filter := newScalableBloomFilter(bfp.initialCapacity, bfp.falsePositiveRate)
+	bfp.persister.RangeKeys(func(key []byte, _ []byte) bool {
+		filter.add(key)
+		return true
+	})
+
+	log.Debug("bloom filter persister: filter rebuilt", "path", bfp.filePath, "num keys", filter.numItems())
+
+	return filter
+}
+
+func (bfp *bloomFilterPersister) removeSidecarFile() {
+	err := os.Remove(bfp.filePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Warn("bloom filter persister: cannot remove sidecar file", "path", bfp.filePath, "error", err)
+	}
+}
+
+func (bfp *bloomFilterPersister) mightContain(key []byte) bool {
+	bfp.mutFilter.RLock()
+	defer bfp.mutFilter.RUnlock()
+
+	return bfp.filter.contains(key)
+}
+
+// Put adds the key in the bloom filter and then writes the value in the wrapped persister
+func (bfp *bloomFilterPersister) Put(key, val []byte) error {
+	// the key is added before the write so that no concurrent reader can be wrongly told that the key is absent
+	bfp.mutFilter.Lock()
+	bfp.filter.add(key)
+	bfp.mutFilter.Unlock()
+
+	bfp.mutWrite.Lock()
+	defer bfp.mutWrite.Unlock()
+
+	err := bfp.persister.Put(key, val)
+	if err != nil {
+		return err
+	}
+
+	bfp.changeFeed.Publish(types.PutOperation, key, val)
+
+	return nil
+}
+
+// Get returns common.ErrKeyNotFound if the bloom filter reports the key as absent, otherwise it calls the wrapped persister
+func (bfp *bloomFilterPersister) Get(key []byte) ([]byte, error) {
+	if !bfp.mightContain(key) {
+		return nil, common.ErrKeyNotFound
+	}
+
+	return bfp.persister.Get(key)
+}
+
+// Has returns common.ErrKeyNotFound if the bloom filter reports the key as absent, otherwise it calls the wrapped persister
+func (bfp *bloomFilterPersister) Has(key []byte) error {
+	if !bfp.mightContain(key) {
+		return common.ErrKeyNotFound
+	}
+
+	return bfp.persister.Has(key)
+}
+
+// Close writes the bloom filter in the sidecar file and closes the wrapped persister
+func (bfp *bloomFilterPersister) Close() error {
+	bfp.mutFilter.RLock()
+	data := bfp.filter.serialize()
+	bfp.mutFilter.RUnlock()
+
+	err := os.WriteFile(bfp.filePath, data, rwOwner)
+	if err != nil {
+		log.Warn("bloom filter persister: cannot save the filter", "path", bfp.filePath, "error", err)
+	}
+
+	bfp.changeFeed.Close()
+
+	return bfp.persister.Close()
+}
+
+// Remove removes the key from the wrapped persister. The key remains in the bloom filter
+// as bloom filters do not support removals, which only affects the false positive rate.
+func (bfp *bloomFilterPersister) Remove(key []byte) error {
+	bfp.mutWrite.Lock()
+	defer bfp.mutWrite.Unlock()
+
+	err := bfp.persister.Remove(key)
+	if err != nil {
+		return err
+	}
+
+	bfp.changeFeed.Publish(types.RemoveOperation, key, nil)
+
+	return nil
+}
+
+// Destroy removes the wrapped persister's data and the sidecar file
+func (bfp *bloomFilterPersister) Destroy() error {
+	bfp.removeSidecarFile()
+
+	bfp.mutFilter.Lock()
+	bfp.filter = newScalableBloomFilter(bfp.initialCapacity, bfp.falsePositiveRate)
+	bfp.mutFilter.Unlock()
+
+	return bfp.persister.Destroy()
+}
+
+// DestroyClosed removes the already closed wrapped persister's data and the sidecar file
+func (bfp *bloomFilterPersister) DestroyClosed() error {
+	bfp.removeSidecarFile()
+
+	return bfp.persister.DestroyClosed()
+}
+
+// RangeKeys calls the wrapped persister's RangeKeys
+func (bfp *bloomFilterPersister) RangeKeys(handler func(key []byte, val []byte) bool) {
+	bfp.persister.RangeKeys(handler)
+}
+
+// Subscribe registers a new consumer of the changes written through this persister
+func (bfp *bloomFilterPersister) Subscribe(config common.SubscriptionConfig) (types.ChangeSubscription, error) {
+	return bfp.changeFeed.Subscribe(config)
+}
+
+// Unsubscribe removes the consumer with the provided ID, closing its events channel
+func (bfp *bloomFilterPersister) Unsubscribe(id string) {
+	bfp.changeFeed.Unsubscribe(id)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (bfp *bloomFilterPersister) IsInterfaceNil() bool {
+	return bfp == nil
+}
@@ -0,0 +1,216 @@
+package bloomfilter_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/bloomfilter"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/memorydb"
+	"github.com/multiversx/mx-chain-storage-go/testscommon"
+	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createArgsBloomFilterPersister(tb testing.TB) bloomfilter.ArgsBloomFilterPersister {
+	return bloomfilter.ArgsBloomFilterPersister{
+		Persister:         memorydb.New(),
+		FilePath:          filepath.Join(tb.TempDir(), "bloom"),
+		InitialCapacity:   100,
+		FalsePositiveRate: 0.01,
+	}
+}
+
+func TestNewBloomFilterPersister(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil persister should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgsBloomFilterPersister(t)
+		args.Persister = nil
+		bfp, err := bloomfilter.NewBloomFilterPersister(args)
+		assert.True(t, check.IfNil(bfp))
+		assert.Equal(t, common.ErrNilPersister, err)
+	})
+	t.Run("empty file path should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgsBloomFilterPersister(t)
+		args.FilePath = ""
+		bfp, err := bloomfilter.NewBloomFilterPersister(args)
+		assert.True(t, check.IfNil(bfp))
+		assert.Equal(t, common.ErrEmptyFilePath, err)
+	})
+	t.Run("invalid capacity should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgsBloomFilterPersister(t)
+		args.InitialCapacity = 0
+		bfp, err := bloomfilter.NewBloomFilterPersister(args)
+		assert.True(t, check.IfNil(bfp))
+		assert.Equal(t, common.ErrInvalidBloomFilterCapacity, err)
+	})
+	t.Run("invalid false positive rate should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgsBloomFilterPersister(t)
+		args.FalsePositiveRate = 2
+		bfp, err := bloomfilter.NewBloomFilterPersister(args)
+		assert.True(t, check.IfNil(bfp))
+		assert.Equal(t, common.ErrInvalidFalsePositiveRate, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		bfp, err := bloomfilter.NewBloomFilterPersister(createArgsBloomFilterPersister(t))
+		assert.False(t, check.IfNil(bfp))
+		assert.Nil(t, err)
+	})
+}
+
+func TestBloomFilterPersister_AbsentKeysShouldNotReachThePersister(t *testing.T) {
+	t.Parallel()
+
+	numGetCalls := 0
+	numHasCalls := 0
+	args := createArgsBloomFilterPersister(t)
+	args.Persister = &testscommon.PersisterStub{
+		GetCalled: func(key []byte) ([]byte, error) {
+			numGetCalls++
+			return []byte("value"), nil
+		},
+		HasCalled: func(key []byte) error {
+			numHasCalls++
+			return nil
+		},
+	}
+	bfp, _ := bloomfilter.NewBloomFilterPersister(args)
+
+	val, err := bfp.Get([]byte("missing"))
+	assert.Nil(t, val)
+	assert.Equal(t, common.ErrKeyNotFound, err)
+	assert.Equal(t, common.ErrKeyNotFound, bfp.Has([]byte("missing")))
+	assert.Zero(t, numGetCalls)
+	assert.Zero(t, numHasCalls)
+
+	err = bfp.Put([]byte("key"), []byte("value"))
+	require.Nil(t, err)
+
+	val, err = bfp.Get([]byte("key"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("value"), val)
+	assert.Nil(t, bfp.Has([]byte("key")))
+	assert.Equal(t, 1, numGetCalls)
+	assert.Equal(t, 1, numHasCalls)
+}
+
+func TestBloomFilterPersister_ShouldRebuildFromExistingKeys(t *testing.T) {
+	t.Parallel()
+
+	args := createArgsBloomFilterPersister(t)
+	db := memorydb.New()
+	numKeys := 500
+	for i := 0; i < numKeys; i++ {
+		_ = db.Put([]byte(fmt.Sprintf("key%d", i)), []byte("value"))
+	}
+	args.Persister = db
+
+	bfp, _ := bloomfilter.NewBloomFilterPersister(args)
+	for i := 0; i < numKeys; i++ {
+		assert.Nil(t, bfp.Has([]byte(fmt.Sprintf("key%d", i))))
+	}
+}
+
+func TestBloomFilterPersister_CloseShouldSaveTheFilterAndOpenShouldConsumeIt(t *testing.T) {
+	t.Parallel()
+
+	args := createArgsBloomFilterPersister(t)
+	numRangeKeysCalls := 0
+	db := memorydb.New()
+	args.Persister = &testscommon.PersisterStub{
+		PutCalled: db.Put,
+		GetCalled: db.Get,
+		HasCalled: db.Has,
+		RangeKeysCalled: func(handler func(key []byte, val []byte) bool) {
+			numRangeKeysCalls++
+			db.RangeKeys(handler)
+		},
+	}
+
+	bfp, _ := bloomfilter.NewBloomFilterPersister(args)
+	assert.Equal(t, 1, numRangeKeysCalls)
+	_ = bfp.Put([]byte("key"), []byte("value"))
+
+	err := bfp.Close()
+	require.Nil(t, err)
+	_, err = os.Stat(args.FilePath)
+	require.Nil(t, err)
+
+	bfp, _ = bloomfilter.NewBloomFilterPersister(args)
+	assert.Equal(t, 1, numRangeKeysCalls)
+	assert.Nil(t, bfp.Has([]byte("key")))
+
+	_, err = os.Stat(args.FilePath)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestBloomFilterPersister_CorruptedSidecarShouldRebuild(t *testing.T) {
+	t.Parallel()
+
+	args := createArgsBloomFilterPersister(t)
+	_ = args.Persister.Put([]byte("key"), []byte("value"))
+	err := os.WriteFile(args.FilePath, []byte("corrupted data"), 0600)
+	require.Nil(t, err)
+
+	bfp, err := bloomfilter.NewBloomFilterPersister(args)
+	require.Nil(t, err)
+	assert.Nil(t, bfp.Has([]byte("key")))
+}
+
+func TestBloomFilterPersister_DestroyShouldRemoveSidecarFile(t *testing.T) {
+	t.Parallel()
+
+	args := createArgsBloomFilterPersister(t)
+	bfp, _ := bloomfilter.NewBloomFilterPersister(args)
+	_ = bfp.Put([]byte("key"), []byte("value"))
+	_ = bfp.Close()
+
+	bfp, _ = bloomfilter.NewBloomFilterPersister(args)
+	_ = bfp.Close()
+	err := bfp.DestroyClosed()
+	assert.Nil(t, err)
+
+	_, err = os.Stat(args.FilePath)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestBloomFilterPersister_SubscribeShouldReceiveChanges(t *testing.T) {
+	t.Parallel()
+
+	bfp, _ := bloomfilter.NewBloomFilterPersister(createArgsBloomFilterPersister(t))
+	sub, err := bfp.Subscribe(common.SubscriptionConfig{
+		ID:                 "indexer",
+		BufferSize:         10,
+		SlowConsumerPolicy: common.DropEventsPolicy,
+	})
+	require.Nil(t, err)
+
+	_ = bfp.Put([]byte("key"), []byte("value"))
+	_ = bfp.Remove([]byte("key"))
+
+	event := <-sub.Events()
+	assert.Equal(t, uint64(1), event.SequenceNumber)
+	assert.Equal(t, types.PutOperation, event.Operation)
+	event = <-sub.Events()
+	assert.Equal(t, uint64(2), event.SequenceNumber)
+	assert.Equal(t, types.RemoveOperation, event.Operation)
+
+	bfp.Unsubscribe("indexer")
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+}
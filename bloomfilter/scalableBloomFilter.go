@@ -0,0 +1,165 @@
+package bloomfilter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"math"
+
+	"github.com/multiversx/mx-chain-storage-go/common"
+)
+
+const growthFactor = 2
+const tighteningRatio = 0.8
+
+const serializationMagic = uint32(0x4d584246) // "MXBF"
+const serializationVersion = uint32(1)
+const checksumLength = 4
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// scalableBloomFilter is a bloom filter that grows by appending new, larger, filters each time the current one
+// reaches its capacity. Each appended filter uses a tighter false positive rate so that the compounded
+// false positive rate stays bounded. It is not concurrent safe.
+type scalableBloomFilter struct {
+	filters []*bloomFilter
+}
+
+func newScalableBloomFilter(initialCapacity uint64, falsePositiveRate float64) *scalableBloomFilter {
+	return &scalableBloomFilter{
+		filters: []*bloomFilter{newBloomFilter(initialCapacity, falsePositiveRate*(1-tighteningRatio))},
+	}
+}
+
+func (sbf *scalableBloomFilter) add(key []byte) {
+	current := sbf.filters[len(sbf.filters)-1]
+	if current.isFull() {
+		current = newBloomFilter(current.capacity*growthFactor, current.fpRate*tighteningRatio)
+		sbf.filters = append(sbf.filters, current)
+	}
+
+	h1, h2 := computeHashes(key)
+	current.addHashes(h1, h2)
+}
+
+func (sbf *scalableBloomFilter) contains(key []byte) bool {
+	h1, h2 := computeHashes(key)
+	for _, filter := range sbf.filters {
+		if filter.containsHashes(h1, h2) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (sbf *scalableBloomFilter) numItems() uint64 {
+	total := uint64(0)
+	for _, filter := range sbf.filters {
+		total += filter.numItems
+	}
+
+	return total
+}
+
+// serialize encodes the filter as: magic, version, number of filters, filters, crc32 checksum of everything before
+func (sbf *scalableBloomFilter) serialize() []byte {
+	buff := &bytes.Buffer{}
+	writeValues(buff, serializationMagic, serializationVersion, uint32(len(sbf.filters)))
+	for _, filter := range sbf.filters {
+		writeValues(buff,
+			filter.capacity,
+			math.Float64bits(filter.fpRate),
+			filter.numItems,
+			filter.numHashes,
+			uint64(len(filter.words)),
+		)
+		writeValues(buff, filter.words)
+	}
+
+	checksum := crc32.Checksum(buff.Bytes(), crcTable)
+	writeValues(buff, checksum)
+
+	return buff.Bytes()
+}
+
+func writeValues(buff *bytes.Buffer, values ...interface{}) {
+	for _, value := range values {
+		// writing into a bytes.Buffer can not fail for fixed size values
+		_ = binary.Write(buff, binary.LittleEndian, value)
+	}
+}
+
+func deserializeScalableBloomFilter(data []byte) (*scalableBloomFilter, error) {
+	if len(data) < checksumLength {
+		return nil, common.ErrCorruptedBloomFilterData
+	}
+
+	payload := data[:len(data)-checksumLength]
+	expectedChecksum := binary.LittleEndian.Uint32(data[len(data)-checksumLength:])
+	if crc32.Checksum(payload, crcTable) != expectedChecksum {
+		return nil, common.ErrCorruptedBloomFilterData
+	}
+
+	reader := bytes.NewReader(payload)
+	var magic, version, numFilters uint32
+	err := readValues(reader, &magic, &version, &numFilters)
+	if err != nil {
+		return nil, err
+	}
+	if magic != serializationMagic || version != serializationVersion || numFilters == 0 {
+		return nil, common.ErrCorruptedBloomFilterData
+	}
+
+	sbf := &scalableBloomFilter{
+		filters: make([]*bloomFilter, 0, numFilters),
+	}
+	for i := uint32(0); i < numFilters; i++ {
+		filter, errRead := readBloomFilter(reader)
+		if errRead != nil {
+			return nil, errRead
+		}
+
+		sbf.filters = append(sbf.filters, filter)
+	}
+	if reader.Len() != 0 {
+		return nil, common.ErrCorruptedBloomFilterData
+	}
+
+	return sbf, nil
+}
+
+func readBloomFilter(reader *bytes.Reader) (*bloomFilter, error) {
+	filter := &bloomFilter{}
+	var fpRateBits, numWords uint64
+	err := readValues(reader, &filter.capacity, &fpRateBits, &filter.numItems, &filter.numHashes, &numWords)
+	if err != nil {
+		return nil, err
+	}
+
+	maxNumWords := uint64(reader.Len()) / 8
+	if numWords == 0 || numWords > maxNumWords || filter.capacity == 0 || filter.numHashes == 0 {
+		return nil, common.ErrCorruptedBloomFilterData
+	}
+
+	filter.fpRate = math.Float64frombits(fpRateBits)
+	filter.numBits = numWords * bitsPerWord
+	filter.words = make([]uint64, numWords)
+	err = readValues(reader, filter.words)
+	if err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+func readValues(reader *bytes.Reader, values ...interface{}) error {
+	for _, value := range values {
+		err := binary.Read(reader, binary.LittleEndian, value)
+		if err != nil {
+			return common.ErrCorruptedBloomFilterData
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,276 @@
+package cachedump
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+var _ types.CacheDumper = (*cacheDumper)(nil)
+
+var log = logger.GetOrCreate("storage/cachedump")
+
+// dumpHeader prefixes every dump file and holds the version of the format
+var dumpHeader = []byte("CDMP\x01")
+
+const tempFileSuffix = ".tmp"
+
+// ArgsCacheDumper holds the arguments needed to create a cache dumper
+type ArgsCacheDumper struct {
+	FilePath    string
+	Marshalizer marshal.Marshalizer
+	DumpValues  bool
+}
+
+// cacheDumper saves the keys of a cache, from the oldest to the newest, in a file. The values are saved
+// optionally: byte slices are saved as they are, while the other values are saved in their marshaled form.
+type cacheDumper struct {
+	filePath    string
+	marshalizer marshal.Marshalizer
+	dumpValues  bool
+}
+
+// NewCacheDumper creates a new cache dumper writing to the provided file path
+func NewCacheDumper(args ArgsCacheDumper) (*cacheDumper, error) {
+	if len(args.FilePath) == 0 {
+		return nil, common.ErrEmptyDumpFilePath
+	}
+	if check.IfNil(args.Marshalizer) {
+		return nil, common.ErrNilMarshalizer
+	}
+
+	return &cacheDumper{
+		filePath:    args.FilePath,
+		marshalizer: args.Marshalizer,
+		dumpValues:  args.DumpValues,
+	}, nil
+}
+
+// Dump saves the contents of the provided cacher, replacing the previous dump. The file is written
+// aside and renamed at the end, so that a failed dump does not corrupt the previous one.
+func (cd *cacheDumper) Dump(cacher types.Cacher) error {
+	if check.IfNil(cacher) {
+		return common.ErrNilCacher
+	}
+
+	err := os.MkdirAll(filepath.Dir(cd.filePath), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	tempFilePath := cd.filePath + tempFileSuffix
+	err = cd.writeFile(tempFilePath, cacher)
+	if err != nil {
+		_ = os.Remove(tempFilePath)
+		return err
+	}
+
+	return os.Rename(tempFilePath, cd.filePath)
+}
+
+func (cd *cacheDumper) writeFile(path string, cacher types.Cacher) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+	err = cd.writeEntries(writer, cacher)
+	if err == nil {
+		err = writer.Flush()
+	}
+
+	errClose := file.Close()
+	if err != nil {
+		return err
+	}
+
+	return errClose
+}
+
+func (cd *cacheDumper) writeEntries(writer *bufio.Writer, cacher types.Cacher) error {
+	_, err := writer.Write(dumpHeader)
+	if err != nil {
+		return err
+	}
+
+	numDumped := 0
+	for _, key := range cacher.Keys() {
+		value, hasValue, ok := cd.valueOf(cacher, key)
+		if !ok {
+			// removed in the meantime
+			continue
+		}
+
+		err = writeEntry(writer, key, value, hasValue)
+		if err != nil {
+			return err
+		}
+		numDumped++
+	}
+
+	log.Debug("cacheDumper.Dump", "file", cd.filePath, "num keys", numDumped, "with values", cd.dumpValues)
+
+	return nil
+}
+
+func (cd *cacheDumper) valueOf(cacher types.Cacher, key []byte) (value []byte, hasValue bool, ok bool) {
+	if !cd.dumpValues {
+		return nil, false, true
+	}
+
+	// peek, in order to keep the recent-ness of the keys
+	rawValue, ok := cacher.Peek(key)
+	if !ok {
+		return nil, false, false
+	}
+
+	buff, isBuff := rawValue.([]byte)
+	if isBuff {
+		return buff, true, true
+	}
+
+	buff, err := cd.marshalizer.Marshal(rawValue)
+	if err != nil {
+		log.Trace("cacheDumper: value will not be dumped", "key", key, "error", err)
+		return nil, false, true
+	}
+
+	return buff, true, true
+}
+
+// an entry is made of the key length, the key, the value length incremented by one (zero if there
+// is no value) and the value
+func writeEntry(writer *bufio.Writer, key []byte, value []byte, hasValue bool) error {
+	err := writeUvarint(writer, uint64(len(key)))
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(key)
+	if err != nil {
+		return err
+	}
+
+	if !hasValue {
+		return writeUvarint(writer, 0)
+	}
+
+	err = writeUvarint(writer, uint64(len(value))+1)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(value)
+
+	return err
+}
+
+func writeUvarint(writer *bufio.Writer, value uint64) error {
+	buff := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buff, value)
+	_, err := writer.Write(buff[:n])
+
+	return err
+}
+
+// Load reads the dumped entries, from the oldest to the newest. A missing dump file is not an error,
+// as there is nothing to restore on the first start.
+func (cd *cacheDumper) Load() ([]types.CacheDumpEntry, error) {
+	file, err := os.Open(cd.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return make([]types.CacheDumpEntry, 0), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	entries, err := readEntries(bufio.NewReader(file))
+	if err != nil {
+		return nil, fmt.Errorf("%w in file %s: %s", common.ErrInvalidCacheDump, cd.filePath, err.Error())
+	}
+
+	log.Debug("cacheDumper.Load", "file", cd.filePath, "num keys", len(entries))
+
+	return entries, nil
+}
+
+func readEntries(reader *bufio.Reader) ([]types.CacheDumpEntry, error) {
+	header := make([]byte, len(dumpHeader))
+	_, err := io.ReadFull(reader, header)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(header, dumpHeader) {
+		return nil, errors.New("unknown header")
+	}
+
+	entries := make([]types.CacheDumpEntry, 0)
+	for {
+		keyLen, errRead := binary.ReadUvarint(reader)
+		if errRead == io.EOF {
+			return entries, nil
+		}
+		if errRead != nil {
+			return nil, errRead
+		}
+
+		entry := types.CacheDumpEntry{}
+		entry.Key, err = readBytes(reader, keyLen)
+		if err != nil {
+			return nil, err
+		}
+
+		valueLen, errRead := binary.ReadUvarint(reader)
+		if errRead != nil {
+			return nil, unexpectedEOF(errRead)
+		}
+		if valueLen > 0 {
+			entry.Value, err = readBytes(reader, valueLen-1)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+}
+
+func readBytes(reader *bufio.Reader, length uint64) ([]byte, error) {
+	// the buffer grows along the read bytes, so that a corrupted length does not allocate a huge buffer
+	buff := bytes.NewBuffer(make([]byte, 0))
+	n, err := io.CopyN(buff, reader, int64(length))
+	if err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	if uint64(n) != length {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	return buff.Bytes(), nil
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (cd *cacheDumper) IsInterfaceNil() bool {
+	return cd == nil
+}
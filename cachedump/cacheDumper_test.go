@@ -0,0 +1,189 @@
+package cachedump_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/cachedump"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/lrucache"
+	"github.com/multiversx/mx-chain-storage-go/testscommon"
+	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type dumpedStruct struct {
+	Field string
+}
+
+func createArgsCacheDumper(t *testing.T) cachedump.ArgsCacheDumper {
+	return cachedump.ArgsCacheDumper{
+		FilePath:    filepath.Join(t.TempDir(), "cache.dump"),
+		Marshalizer: &testscommon.MarshalizerMock{},
+		DumpValues:  true,
+	}
+}
+
+func TestNewCacheDumper(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty file path should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgsCacheDumper(t)
+		args.FilePath = ""
+		dumper, err := cachedump.NewCacheDumper(args)
+		assert.True(t, check.IfNil(dumper))
+		assert.Equal(t, common.ErrEmptyDumpFilePath, err)
+	})
+	t.Run("nil marshalizer should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgsCacheDumper(t)
+		args.Marshalizer = nil
+		dumper, err := cachedump.NewCacheDumper(args)
+		assert.True(t, check.IfNil(dumper))
+		assert.Equal(t, common.ErrNilMarshalizer, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		dumper, err := cachedump.NewCacheDumper(createArgsCacheDumper(t))
+		assert.False(t, check.IfNil(dumper))
+		assert.Nil(t, err)
+	})
+}
+
+func TestCacheDumper_Dump(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil cacher should error", func(t *testing.T) {
+		t.Parallel()
+
+		dumper, _ := cachedump.NewCacheDumper(createArgsCacheDumper(t))
+		err := dumper.Dump(nil)
+		assert.Equal(t, common.ErrNilCacher, err)
+	})
+	t.Run("keys and values should be dumped in LRU order", func(t *testing.T) {
+		t.Parallel()
+
+		cache, _ := lrucache.NewCache(10)
+		cache.Put([]byte("key1"), []byte("value1"), 6)
+		cache.Put([]byte("key2"), &dumpedStruct{Field: "value2"}, 0)
+		cache.Put([]byte("key3"), []byte{}, 0)
+		_, _ = cache.Get([]byte("key1"))
+
+		dumper, _ := cachedump.NewCacheDumper(createArgsCacheDumper(t))
+		err := dumper.Dump(cache)
+		require.Nil(t, err)
+
+		entries, err := dumper.Load()
+		require.Nil(t, err)
+		expectedEntries := []types.CacheDumpEntry{
+			{Key: []byte("key2"), Value: []byte(`{"Field":"value2"}`)},
+			{Key: []byte("key3"), Value: []byte{}},
+			{Key: []byte("key1"), Value: []byte("value1")},
+		}
+		assert.Equal(t, expectedEntries, entries)
+		assert.Equal(t, []byte("key1"), cache.Keys()[2], "dumping should not change the recent-ness of the keys")
+	})
+	t.Run("only keys should be dumped if the values are not requested", func(t *testing.T) {
+		t.Parallel()
+
+		cache, _ := lrucache.NewCache(10)
+		cache.Put([]byte("key1"), []byte("value1"), 6)
+		cache.Put([]byte("key2"), []byte("value2"), 6)
+
+		args := createArgsCacheDumper(t)
+		args.DumpValues = false
+		dumper, _ := cachedump.NewCacheDumper(args)
+		err := dumper.Dump(cache)
+		require.Nil(t, err)
+
+		entries, err := dumper.Load()
+		require.Nil(t, err)
+		expectedEntries := []types.CacheDumpEntry{
+			{Key: []byte("key1")},
+			{Key: []byte("key2")},
+		}
+		assert.Equal(t, expectedEntries, entries)
+	})
+	t.Run("values failing to marshal should be skipped", func(t *testing.T) {
+		t.Parallel()
+
+		cache, _ := lrucache.NewCache(10)
+		cache.Put([]byte("key1"), &dumpedStruct{}, 0)
+
+		args := createArgsCacheDumper(t)
+		args.Marshalizer = &testscommon.MarshalizerMock{Fail: true}
+		dumper, _ := cachedump.NewCacheDumper(args)
+		err := dumper.Dump(cache)
+		require.Nil(t, err)
+
+		entries, err := dumper.Load()
+		require.Nil(t, err)
+		assert.Equal(t, []types.CacheDumpEntry{{Key: []byte("key1")}}, entries)
+	})
+	t.Run("dump should replace the previous one", func(t *testing.T) {
+		t.Parallel()
+
+		dumper, _ := cachedump.NewCacheDumper(createArgsCacheDumper(t))
+
+		cache, _ := lrucache.NewCache(10)
+		cache.Put([]byte("key1"), []byte("value1"), 6)
+		_ = dumper.Dump(cache)
+
+		cache.Clear()
+		cache.Put([]byte("key2"), []byte("value2"), 6)
+		_ = dumper.Dump(cache)
+
+		entries, err := dumper.Load()
+		require.Nil(t, err)
+		assert.Equal(t, []types.CacheDumpEntry{{Key: []byte("key2"), Value: []byte("value2")}}, entries)
+	})
+}
+
+func TestCacheDumper_Load(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing file should return no entries", func(t *testing.T) {
+		t.Parallel()
+
+		dumper, _ := cachedump.NewCacheDumper(createArgsCacheDumper(t))
+		entries, err := dumper.Load()
+		assert.Nil(t, err)
+		assert.Empty(t, entries)
+	})
+	t.Run("unknown header should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgsCacheDumper(t)
+		_ = os.WriteFile(args.FilePath, []byte("not a dump"), os.ModePerm)
+
+		dumper, _ := cachedump.NewCacheDumper(args)
+		entries, err := dumper.Load()
+		assert.Nil(t, entries)
+		assert.True(t, errors.Is(err, common.ErrInvalidCacheDump))
+	})
+	t.Run("truncated file should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgsCacheDumper(t)
+		dumper, _ := cachedump.NewCacheDumper(args)
+
+		cache, _ := lrucache.NewCache(10)
+		cache.Put([]byte("key1"), []byte("value1"), 6)
+		_ = dumper.Dump(cache)
+
+		buff, _ := os.ReadFile(args.FilePath)
+		_ = os.WriteFile(args.FilePath, buff[:len(buff)-2], os.ModePerm)
+
+		entries, err := dumper.Load()
+		assert.Nil(t, entries)
+		assert.True(t, errors.Is(err, common.ErrInvalidCacheDump))
+	})
+}
@@ -0,0 +1,199 @@
+package changefeed
+
+import (
+	"sync"
+
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+var _ types.ChangeFeed = (*changeFeed)(nil)
+
+var log = logger.GetOrCreate("storage/changefeed")
+
+// changeFeed delivers ordered change events to the registered subscriptions
+type changeFeed struct {
+	mutPublish sync.Mutex
+	// publishedTurn is signaled, under the publish lock, each time a change has been delivered
+	publishedTurn          *sync.Cond
+	sequenceNumber         uint64
+	mutReserve             sync.Mutex
+	reservedSequenceNumber uint64
+	mutSubscriptions       sync.RWMutex
+	subscriptions          map[string]*subscription
+}
+
+// NewChangeFeed creates a new change feed instance
+func NewChangeFeed() *changeFeed {
+	cf := &changeFeed{
+		subscriptions: make(map[string]*subscription),
+	}
+	cf.publishedTurn = sync.NewCond(&cf.mutPublish)
+
+	return cf
+}
+
+// Subscribe registers a new subscription that will receive all the changes published from now on
+func (cf *changeFeed) Subscribe(config common.SubscriptionConfig) (types.ChangeSubscription, error) {
+	err := checkSubscriptionConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	cf.mutSubscriptions.Lock()
+	defer cf.mutSubscriptions.Unlock()
+
+	_, exists := cf.subscriptions[config.ID]
+	if exists {
+		return nil, common.ErrSubscriptionAlreadyExists
+	}
+
+	sub := newSubscription(config)
+	cf.subscriptions[config.ID] = sub
+
+	return sub, nil
+}
+
+func checkSubscriptionConfig(config common.SubscriptionConfig) error {
+	if len(config.ID) == 0 {
+		return common.ErrEmptySubscriptionID
+	}
+	if config.BufferSize == 0 {
+		return common.ErrInvalidBufferSize
+	}
+
+	switch config.SlowConsumerPolicy {
+	case common.DropEventsPolicy, common.BlockPolicy, common.DisconnectPolicy:
+		return nil
+	default:
+		return common.ErrNotSupportedSlowConsumerPolicy
+	}
+}
+
+// Unsubscribe removes the subscription and closes its events channel
+func (cf *changeFeed) Unsubscribe(id string) {
+	cf.mutSubscriptions.Lock()
+	sub, exists := cf.subscriptions[id]
+	delete(cf.subscriptions, id)
+	cf.mutSubscriptions.Unlock()
+	if !exists {
+		return
+	}
+
+	// a producer might be blocked on this subscription, so it has to be released before acquiring the publish lock
+	sub.markClosing()
+
+	cf.mutPublish.Lock()
+	close(sub.events)
+	cf.mutPublish.Unlock()
+}
+
+// removeSubscriptionOnPublish closes the subscription, unless it was concurrently removed by Unsubscribe.
+// Must be called under the publish lock.
+func (cf *changeFeed) removeSubscriptionOnPublish(sub *subscription) {
+	cf.mutSubscriptions.Lock()
+	existing, exists := cf.subscriptions[sub.id]
+	isOwner := exists && existing == sub
+	if isOwner {
+		delete(cf.subscriptions, sub.id)
+	}
+	cf.mutSubscriptions.Unlock()
+
+	if !isOwner {
+		return
+	}
+
+	sub.markClosing()
+	close(sub.events)
+}
+
+func (cf *changeFeed) getSubscriptions() []*subscription {
+	cf.mutSubscriptions.RLock()
+	defer cf.mutSubscriptions.RUnlock()
+
+	subscriptions := make([]*subscription, 0, len(cf.subscriptions))
+	for _, sub := range cf.subscriptions {
+		subscriptions = append(subscriptions, sub)
+	}
+
+	return subscriptions
+}
+
+// Publish delivers the change to all subscriptions, assigning it the next sequence number.
+// The caller should publish the changes in the same order it applies them.
+func (cf *changeFeed) Publish(operation types.ChangeOperation, key []byte, value []byte) {
+	cf.PublishReserved(cf.ReserveSequenceNumber(), operation, key, value)
+}
+
+// ReserveSequenceNumber takes the next position in the delivery order, so that a caller can reserve it while applying
+// the change and deliver the change later, outside its own locks. Every reserved sequence number should be published,
+// as the changes reserved after it wait for it.
+func (cf *changeFeed) ReserveSequenceNumber() uint64 {
+	cf.mutReserve.Lock()
+	defer cf.mutReserve.Unlock()
+
+	cf.reservedSequenceNumber++
+
+	return cf.reservedSequenceNumber
+}
+
+// PublishReserved delivers the change to all subscriptions under the reserved sequence number, after all the changes
+// reserved before it have been delivered
+func (cf *changeFeed) PublishReserved(sequenceNumber uint64, operation types.ChangeOperation, key []byte, value []byte) {
+	cf.mutPublish.Lock()
+	defer cf.mutPublish.Unlock()
+
+	for cf.sequenceNumber+1 != sequenceNumber {
+		cf.publishedTurn.Wait()
+	}
+
+	cf.deliverNoLock(sequenceNumber, operation, key, value)
+	cf.sequenceNumber = sequenceNumber
+	cf.publishedTurn.Broadcast()
+}
+
+func (cf *changeFeed) deliverNoLock(sequenceNumber uint64, operation types.ChangeOperation, key []byte, value []byte) {
+	subscriptions := cf.getSubscriptions()
+	if len(subscriptions) == 0 {
+		return
+	}
+
+	event := types.ChangeEvent{
+		SequenceNumber: sequenceNumber,
+		Operation:      operation,
+		Key:            cloneBytes(key),
+		Value:          cloneBytes(value),
+	}
+
+	for _, sub := range subscriptions {
+		isConnected := sub.deliver(event)
+		if !isConnected {
+			log.Debug("changeFeed.Publish: disconnecting slow consumer", "id", sub.id, "sequence number", event.SequenceNumber)
+			cf.removeSubscriptionOnPublish(sub)
+		}
+	}
+}
+
+func cloneBytes(buff []byte) []byte {
+	if buff == nil {
+		return nil
+	}
+
+	cloned := make([]byte, len(buff))
+	copy(cloned, buff)
+
+	return cloned
+}
+
+// Close removes all the subscriptions, closing their events channels
+func (cf *changeFeed) Close() {
+	for _, sub := range cf.getSubscriptions() {
+		cf.Unsubscribe(sub.id)
+	}
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (cf *changeFeed) IsInterfaceNil() bool {
+	return cf == nil
+}
@@ -0,0 +1,250 @@
+package changefeed_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/changefeed"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var timeoutWaitForEvents = time.Second * 2
+
+func createSubscriptionConfig(policy common.SlowConsumerPolicy) common.SubscriptionConfig {
+	return common.SubscriptionConfig{
+		ID:                 "subscription",
+		BufferSize:         2,
+		SlowConsumerPolicy: policy,
+	}
+}
+
+func TestNewChangeFeed(t *testing.T) {
+	t.Parallel()
+
+	cf := changefeed.NewChangeFeed()
+	assert.False(t, check.IfNil(cf))
+}
+
+func TestChangeFeed_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty ID should error", func(t *testing.T) {
+		t.Parallel()
+
+		config := createSubscriptionConfig(common.DropEventsPolicy)
+		config.ID = ""
+		sub, err := changefeed.NewChangeFeed().Subscribe(config)
+		assert.Nil(t, sub)
+		assert.Equal(t, common.ErrEmptySubscriptionID, err)
+	})
+	t.Run("zero buffer size should error", func(t *testing.T) {
+		t.Parallel()
+
+		config := createSubscriptionConfig(common.DropEventsPolicy)
+		config.BufferSize = 0
+		sub, err := changefeed.NewChangeFeed().Subscribe(config)
+		assert.Nil(t, sub)
+		assert.Equal(t, common.ErrInvalidBufferSize, err)
+	})
+	t.Run("unknown policy should error", func(t *testing.T) {
+		t.Parallel()
+
+		sub, err := changefeed.NewChangeFeed().Subscribe(createSubscriptionConfig("unknown"))
+		assert.Nil(t, sub)
+		assert.Equal(t, common.ErrNotSupportedSlowConsumerPolicy, err)
+	})
+	t.Run("duplicated ID should error", func(t *testing.T) {
+		t.Parallel()
+
+		cf := changefeed.NewChangeFeed()
+		_, _ = cf.Subscribe(createSubscriptionConfig(common.DropEventsPolicy))
+		sub, err := cf.Subscribe(createSubscriptionConfig(common.DropEventsPolicy))
+		assert.Nil(t, sub)
+		assert.Equal(t, common.ErrSubscriptionAlreadyExists, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		sub, err := changefeed.NewChangeFeed().Subscribe(createSubscriptionConfig(common.DropEventsPolicy))
+		assert.Nil(t, err)
+		assert.False(t, check.IfNil(sub))
+		assert.Equal(t, "subscription", sub.ID())
+	})
+}
+
+func TestChangeFeed_PublishShouldDeliverOrderedEvents(t *testing.T) {
+	t.Parallel()
+
+	cf := changefeed.NewChangeFeed()
+	config := createSubscriptionConfig(common.BlockPolicy)
+	sub, _ := cf.Subscribe(config)
+
+	numEvents := 100
+	go func() {
+		for i := 0; i < numEvents; i++ {
+			cf.Publish(types.PutOperation, []byte(fmt.Sprintf("key%d", i)), []byte("value"))
+		}
+		cf.Publish(types.RemoveOperation, []byte("key0"), nil)
+	}()
+
+	for i := 0; i < numEvents; i++ {
+		event := <-sub.Events()
+		assert.Equal(t, uint64(i+1), event.SequenceNumber)
+		assert.Equal(t, types.PutOperation, event.Operation)
+		assert.Equal(t, []byte(fmt.Sprintf("key%d", i)), event.Key)
+		assert.Equal(t, []byte("value"), event.Value)
+	}
+
+	event := <-sub.Events()
+	assert.Equal(t, uint64(numEvents+1), event.SequenceNumber)
+	assert.Equal(t, types.RemoveOperation, event.Operation)
+	assert.Nil(t, event.Value)
+}
+
+func TestChangeFeed_PublishReservedShouldDeliverInTheReservedOrder(t *testing.T) {
+	t.Parallel()
+
+	cf := changefeed.NewChangeFeed()
+	sub, _ := cf.Subscribe(createSubscriptionConfig(common.DropEventsPolicy))
+
+	first := cf.ReserveSequenceNumber()
+	second := cf.ReserveSequenceNumber()
+
+	published := make(chan struct{})
+	go func() {
+		cf.PublishReserved(second, types.RemoveOperation, []byte("key"), nil)
+		close(published)
+	}()
+
+	select {
+	case <-published:
+		assert.Fail(t, "the second change should wait for the first one")
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	cf.PublishReserved(first, types.PutOperation, []byte("key"), []byte("value"))
+	<-published
+
+	event := <-sub.Events()
+	assert.Equal(t, first, event.SequenceNumber)
+	assert.Equal(t, types.PutOperation, event.Operation)
+
+	event = <-sub.Events()
+	assert.Equal(t, second, event.SequenceNumber)
+	assert.Equal(t, types.RemoveOperation, event.Operation)
+}
+
+func TestChangeFeed_PublishShouldCloneTheData(t *testing.T) {
+	t.Parallel()
+
+	cf := changefeed.NewChangeFeed()
+	sub, _ := cf.Subscribe(createSubscriptionConfig(common.DropEventsPolicy))
+
+	key, value := []byte("key"), []byte("value")
+	cf.Publish(types.PutOperation, key, value)
+	key[0] = 'x'
+	value[0] = 'x'
+
+	event := <-sub.Events()
+	assert.Equal(t, []byte("key"), event.Key)
+	assert.Equal(t, []byte("value"), event.Value)
+}
+
+func TestChangeFeed_DropPolicyShouldDropEvents(t *testing.T) {
+	t.Parallel()
+
+	cf := changefeed.NewChangeFeed()
+	sub, _ := cf.Subscribe(createSubscriptionConfig(common.DropEventsPolicy))
+
+	for i := 0; i < 5; i++ {
+		cf.Publish(types.PutOperation, []byte("key"), []byte("value"))
+	}
+
+	assert.Equal(t, uint64(3), sub.NumDropped())
+	assert.Equal(t, uint64(1), (<-sub.Events()).SequenceNumber)
+	assert.Equal(t, uint64(2), (<-sub.Events()).SequenceNumber)
+
+	cf.Publish(types.PutOperation, []byte("key"), []byte("value"))
+	assert.Equal(t, uint64(6), (<-sub.Events()).SequenceNumber)
+}
+
+func TestChangeFeed_DisconnectPolicyShouldCloseTheSubscription(t *testing.T) {
+	t.Parallel()
+
+	cf := changefeed.NewChangeFeed()
+	sub, _ := cf.Subscribe(createSubscriptionConfig(common.DisconnectPolicy))
+
+	for i := 0; i < 3; i++ {
+		cf.Publish(types.PutOperation, []byte("key"), []byte("value"))
+	}
+
+	numReceived := 0
+	for range sub.Events() {
+		numReceived++
+	}
+	assert.Equal(t, 2, numReceived)
+
+	// the ID can be reused after the disconnection
+	_, err := cf.Subscribe(createSubscriptionConfig(common.DisconnectPolicy))
+	assert.Nil(t, err)
+}
+
+func TestChangeFeed_UnsubscribeShouldUnblockTheProducer(t *testing.T) {
+	t.Parallel()
+
+	cf := changefeed.NewChangeFeed()
+	sub, _ := cf.Subscribe(createSubscriptionConfig(common.BlockPolicy))
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		for i := 0; i < 3; i++ {
+			cf.Publish(types.PutOperation, []byte("key"), []byte("value"))
+		}
+		wg.Done()
+	}()
+
+	time.Sleep(time.Millisecond * 100)
+	cf.Unsubscribe(sub.ID())
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeoutWaitForEvents):
+		require.Fail(t, "producer still blocked")
+	}
+
+	numReceived := 0
+	for range sub.Events() {
+		numReceived++
+	}
+	assert.Equal(t, 2, numReceived)
+}
+
+func TestChangeFeed_CloseShouldCloseAllSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	cf := changefeed.NewChangeFeed()
+	config := createSubscriptionConfig(common.DropEventsPolicy)
+	sub1, _ := cf.Subscribe(config)
+	config.ID = "another subscription"
+	sub2, _ := cf.Subscribe(config)
+
+	cf.Close()
+
+	_, ok := <-sub1.Events()
+	assert.False(t, ok)
+	_, ok = <-sub2.Events()
+	assert.False(t, ok)
+}
@@ -0,0 +1,84 @@
+package changefeed
+
+import (
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/core/atomic"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+var _ types.ChangeSubscription = (*subscription)(nil)
+
+type subscription struct {
+	id         string
+	policy     common.SlowConsumerPolicy
+	events     chan types.ChangeEvent
+	closing    chan struct{}
+	closeOnce  sync.Once
+	numDropped atomic.Counter
+}
+
+func newSubscription(config common.SubscriptionConfig) *subscription {
+	return &subscription{
+		id:      config.ID,
+		policy:  config.SlowConsumerPolicy,
+		events:  make(chan types.ChangeEvent, config.BufferSize),
+		closing: make(chan struct{}),
+	}
+}
+
+// deliver sends the event according to the slow consumer policy. It returns false if the subscription
+// should be disconnected. Must be called under the feed's publish lock.
+func (sub *subscription) deliver(event types.ChangeEvent) bool {
+	switch sub.policy {
+	case common.BlockPolicy:
+		select {
+		case sub.events <- event:
+		case <-sub.closing:
+		}
+		return true
+	case common.DisconnectPolicy:
+		select {
+		case sub.events <- event:
+			return true
+		default:
+			return false
+		}
+	default:
+		select {
+		case sub.events <- event:
+		default:
+			sub.numDropped.Increment()
+		}
+		return true
+	}
+}
+
+// markClosing unblocks a producer that might wait on this subscription
+func (sub *subscription) markClosing() {
+	sub.closeOnce.Do(func() {
+		close(sub.closing)
+	})
+}
+
+// ID returns the subscription ID
+func (sub *subscription) ID() string {
+	return sub.id
+}
+
+// Events returns the channel on which the change events are delivered. The channel is closed
+// when the subscription is removed or disconnected.
+func (sub *subscription) Events() <-chan types.ChangeEvent {
+	return sub.events
+}
+
+// NumDropped returns the number of events dropped because the consumer's buffer was full
+func (sub *subscription) NumDropped() uint64 {
+	return sub.numDropped.GetUint64()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (sub *subscription) IsInterfaceNil() bool {
+	return sub == nil
+}
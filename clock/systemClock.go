@@ -0,0 +1,37 @@
+package clock
+
+import (
+	"time"
+
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+var _ types.Clock = (*systemClock)(nil)
+
+// systemClock is the clock backed by the system time
+type systemClock struct{}
+
+// NewSystemClock creates a clock backed by the system time
+func NewSystemClock() *systemClock {
+	return &systemClock{}
+}
+
+// Now returns the current system time
+func (sc *systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Since returns the time elapsed since the provided time
+func (sc *systemClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+// After waits for the provided duration to elapse and then sends the current time on the returned channel
+func (sc *systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (sc *systemClock) IsInterfaceNil() bool {
+	return sc == nil
+}
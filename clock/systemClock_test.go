@@ -0,0 +1,27 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSystemClock(t *testing.T) {
+	t.Parallel()
+
+	sc := NewSystemClock()
+	assert.False(t, check.IfNil(sc))
+
+	before := time.Now()
+	now := sc.Now()
+	assert.False(t, now.Before(before))
+	assert.True(t, sc.Since(before) >= 0)
+
+	select {
+	case <-sc.After(time.Millisecond):
+	case <-time.After(time.Second):
+		assert.Fail(t, "timeout waiting for the clock")
+	}
+}
@@ -17,6 +17,17 @@ type CacheConfig struct {
 	Capacity             uint32
 	SizePerSender        uint32
 	Shards               uint32
+	Priority             CachePriority
+	DumpFilePath         string
+	DumpValues           bool
+	WarmUpMode           CacheWarmUpMode
+	// TTLInSeconds and MaxIdleInSeconds are the absolute and the idle expiry of the items of the expiring caches,
+	// zero disabling the respective expiry
+	TTLInSeconds     uint32
+	MaxIdleInSeconds uint32
+	// ExpiryCleanupIntervalInSeconds is the period of the background cleanup of the expired items, zero meaning the
+	// smallest of the two expiry spans
+	ExpiryCleanupIntervalInSeconds uint32
 }
 
 // String returns a readable representation of the object
@@ -36,4 +47,19 @@ type DBConfig struct {
 	BatchDelaySeconds int
 	MaxBatchSize      int
 	MaxOpenFiles      int
+	// EnableMonitoring records the metrics of the persister in the default registry, under its file path
+	EnableMonitoring bool
+}
+
+// SubscriptionConfig holds the configurable elements of a change feed subscription
+type SubscriptionConfig struct {
+	ID                 string
+	BufferSize         uint32
+	SlowConsumerPolicy SlowConsumerPolicy
+}
+
+// MemoryBudgetConfig holds the configurable elements of the memory budget coordinator
+type MemoryBudgetConfig struct {
+	MaxSizeInBytes uint64
+	MinSizePercent uint32
 }
@@ -5,11 +5,19 @@ import "time"
 // CacheType represents the type of the supported caches
 type CacheType string
 
-// Cache types that are currently supported
+// Cache types that are currently supported. The 2Q caches are scan resistant: the keys used only once
+// do not flush the frequently used ones. The TinyLFU cache admits a new key only if it is accessed
+// more often than the key it would evict. The sharded LRU splits its capacity between independently locked shards.
+// The expiring LRU drops the items after a TTL or a max idle time, being optionally bounded in bytes as well.
 const (
-	LRUCache         CacheType = "LRU"
-	SizeLRUCache     CacheType = "SizeLRU"
-	FIFOShardedCache CacheType = "FIFOSharded"
+	LRUCache            CacheType = "LRU"
+	SizeLRUCache        CacheType = "SizeLRU"
+	FIFOShardedCache    CacheType = "FIFOSharded"
+	TwoQueueCache       CacheType = "2Q"
+	SizeTwoQueueCache   CacheType = "Size2Q"
+	SizeTinyLFUCache    CacheType = "SizeTinyLFU"
+	ShardedSizeLRUCache CacheType = "ShardedSizeLRU"
+	ExpiringLRUCache    CacheType = "ExpiringLRU"
 )
 
 // DBType represents the type of the supported databases
@@ -35,3 +43,42 @@ const MaxRetriesToCreateDB = 10
 
 // SleepTimeBetweenCreateDBRetries represents the number of seconds to sleep between DB creates
 const SleepTimeBetweenCreateDBRetries = 5 * time.Second
+
+// SlowConsumerPolicy represents the behaviour of a change feed when a consumer does not keep up with the events
+type SlowConsumerPolicy string
+
+// Slow consumer policies that are currently supported
+const (
+	// DropEventsPolicy drops the events that do not fit in the consumer's buffer
+	DropEventsPolicy SlowConsumerPolicy = "Drop"
+	// BlockPolicy blocks the producer until the consumer's buffer has room for the event
+	BlockPolicy SlowConsumerPolicy = "Block"
+	// DisconnectPolicy closes the consumer's subscription as soon as an event does not fit in its buffer
+	DisconnectPolicy SlowConsumerPolicy = "Disconnect"
+)
+
+// CachePriority represents how reluctant the memory budget coordinator is to shrink a cache
+type CachePriority string
+
+// Cache priorities that are currently supported. An empty priority is handled as NormalCachePriority.
+const (
+	// LowCachePriority caches are the first to be shrunk
+	LowCachePriority CachePriority = "Low"
+	// NormalCachePriority caches are shrunk only if shrinking the low priority caches is not enough
+	NormalCachePriority CachePriority = "Normal"
+	// HighCachePriority caches are shrunk only as a last resort
+	HighCachePriority CachePriority = "High"
+)
+
+// CacheWarmUpMode represents how a storage unit fills its cache from a previously dumped cache on start
+type CacheWarmUpMode string
+
+// Cache warm-up modes that are currently supported. An empty mode disables the warm-up.
+const (
+	// NoCacheWarmUp leaves the cache empty on start
+	NoCacheWarmUp CacheWarmUpMode = ""
+	// EagerCacheWarmUp fills the cache before the storage unit is returned, using the dumped values if available
+	EagerCacheWarmUp CacheWarmUpMode = "Eager"
+	// LazyCacheWarmUp fills the cache in background, fetching the dumped keys from the persistence medium
+	LazyCacheWarmUp CacheWarmUpMode = "Lazy"
+)
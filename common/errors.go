@@ -51,6 +51,9 @@ var ErrFailedCacheEviction = errors.New("failed eviction within cache")
 // ErrImmuneItemsCapacityReached signals that capacity for immune items is reached
 var ErrImmuneItemsCapacityReached = errors.New("capacity reached for immune items")
 
+// ErrInvalidImmunityLevel signals that an invalid immunity level has been provided
+var ErrInvalidImmunityLevel = errors.New("invalid immunity level")
+
 // ErrCacheSizeInvalid signals that size of cache is less than 1
 var ErrCacheSizeInvalid = errors.New("cache size is less than 1")
 
@@ -69,6 +72,12 @@ var ErrNegativeSizeInBytes = errors.New("negative size in bytes")
 // ErrNilTimeCache signals that a nil time cache has been provided
 var ErrNilTimeCache = errors.New("nil time cache")
 
+// ErrNilRateLimiter signals that a nil rate limiter has been provided
+var ErrNilRateLimiter = errors.New("nil rate limiter")
+
+// ErrRateLimitExceeded signals that a key exceeded the allowed number of occurrences or is banned
+var ErrRateLimitExceeded = errors.New("rate limit exceeded")
+
 // ErrNilStoredDataFactory signals that a nil stored data factory has been provided
 var ErrNilStoredDataFactory = errors.New("nil stored data factory")
 
@@ -78,5 +87,101 @@ var ErrInvalidDefaultSpan = errors.New("invalid default span")
 // ErrInvalidCacheExpiry signals that an invalid cache expiry was provided
 var ErrInvalidCacheExpiry = errors.New("invalid cache expiry")
 
+// ErrInvalidSpan signals that an invalid span was provided
+var ErrInvalidSpan = errors.New("invalid span")
+
 // ErrDBIsClosed is raised when the DB is closed
 var ErrDBIsClosed = core.ErrDBIsClosed
+
+// ErrEmptyFilePath signals that an empty file path has been provided
+var ErrEmptyFilePath = errors.New("empty file path")
+
+// ErrInvalidBloomFilterCapacity signals that an invalid bloom filter capacity has been provided
+var ErrInvalidBloomFilterCapacity = errors.New("invalid bloom filter capacity")
+
+// ErrInvalidFalsePositiveRate signals that an invalid false positive rate has been provided
+var ErrInvalidFalsePositiveRate = errors.New("invalid false positive rate")
+
+// ErrCorruptedBloomFilterData signals that the serialized bloom filter data is corrupted
+var ErrCorruptedBloomFilterData = errors.New("corrupted bloom filter data")
+
+// ErrEmptySubscriptionID signals that an empty subscription ID has been provided
+var ErrEmptySubscriptionID = errors.New("empty subscription ID")
+
+// ErrInvalidBufferSize signals that an invalid buffer size has been provided
+var ErrInvalidBufferSize = errors.New("invalid buffer size")
+
+// ErrNotSupportedSlowConsumerPolicy signals that an unsupported slow consumer policy has been provided
+var ErrNotSupportedSlowConsumerPolicy = errors.New("not supported slow consumer policy")
+
+// ErrSubscriptionAlreadyExists signals that a subscription with the same ID already exists
+var ErrSubscriptionAlreadyExists = errors.New("subscription already exists")
+
+// ErrInvalidEpochRange signals that an invalid epoch range has been provided
+var ErrInvalidEpochRange = errors.New("invalid epoch range")
+
+// ErrEpochRangeNotSupported signals that the storer does not support epoch range operations
+var ErrEpochRangeNotSupported = errors.New("epoch range operations not supported")
+
+// ErrNilStorer signals that a nil storer has been provided
+var ErrNilStorer = errors.New("nil storer")
+
+// ErrNilMetricsRegistry signals that a nil metrics registry has been provided
+var ErrNilMetricsRegistry = errors.New("nil metrics registry")
+
+// ErrNilMetricsExporter signals that a nil metrics exporter has been provided
+var ErrNilMetricsExporter = errors.New("nil metrics exporter")
+
+// ErrEmptyUnitName signals that an empty unit name has been provided
+var ErrEmptyUnitName = errors.New("empty unit name")
+
+// ErrUnitAlreadyRegistered signals that a unit with the same name is already registered
+var ErrUnitAlreadyRegistered = errors.New("unit already registered")
+
+// ErrCacheAlreadyRegistered signals that a cache with the same name is already registered
+var ErrCacheAlreadyRegistered = errors.New("cache already registered")
+
+// ErrNilCacheStatsProvider signals that a nil cache stats provider has been provided
+var ErrNilCacheStatsProvider = errors.New("nil cache stats provider")
+
+// ErrNotSupportedCachePriority signals that an unsupported cache priority has been provided
+var ErrNotSupportedCachePriority = errors.New("not supported cache priority")
+
+// ErrInvalidMinSizePercent signals that an invalid minimum size percent has been provided
+var ErrInvalidMinSizePercent = errors.New("invalid minimum size percent")
+
+// ErrMemoryBudgetOverCommitted signals that the caches can not fit in the memory budget, even after shrinking them
+var ErrMemoryBudgetOverCommitted = errors.New("memory budget over-committed")
+
+// ErrNilMemoryBudgetHandler signals that a nil memory budget handler has been provided
+var ErrNilMemoryBudgetHandler = errors.New("nil memory budget handler")
+
+// ErrResizeNotSupported signals that the cache can not be resized
+var ErrResizeNotSupported = errors.New("resize not supported")
+
+// ErrEmptyDumpFilePath signals that an empty cache dump file path has been provided
+var ErrEmptyDumpFilePath = errors.New("empty cache dump file path")
+
+// ErrInvalidCacheDump signals that the cache dump file is malformed
+var ErrInvalidCacheDump = errors.New("invalid cache dump")
+
+// ErrNilCacheDumper signals that a nil cache dumper has been provided
+var ErrNilCacheDumper = errors.New("nil cache dumper")
+
+// ErrNotSupportedCacheWarmUpMode signals that an unsupported cache warm-up mode has been provided
+var ErrNotSupportedCacheWarmUpMode = errors.New("not supported cache warm-up mode")
+
+// ErrInvalidNumberOfShards signals that an invalid number of shards has been provided
+var ErrInvalidNumberOfShards = errors.New("invalid number of shards")
+
+// ErrNilKeyHasher signals that a nil key hasher has been provided
+var ErrNilKeyHasher = errors.New("nil key hasher")
+
+// ErrWrongTypeAssertion signals that a value of an unexpected type has been provided
+var ErrWrongTypeAssertion = errors.New("wrong type assertion")
+
+// ErrNilLoader signals that a nil loader function has been provided
+var ErrNilLoader = errors.New("nil loader")
+
+// ErrLoaderPanicked signals that the loader function panicked
+var ErrLoaderPanicked = errors.New("loader panicked")
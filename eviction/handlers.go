@@ -0,0 +1,68 @@
+package eviction
+
+import (
+	"sync"
+
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+var log = logger.GetOrCreate("storage/eviction")
+
+// Handlers holds the eviction handlers registered on a cache. The zero value is ready to use.
+type Handlers struct {
+	mut      sync.RWMutex
+	handlers map[string]types.EvictionHandlerFunc
+}
+
+// Register adds a handler, replacing the one already registered under the same id
+func (h *Handlers) Register(handler types.EvictionHandlerFunc, id string) {
+	if handler == nil {
+		log.Error("attempt to register a nil eviction handler to a cacher object", "id", id)
+		return
+	}
+
+	h.mut.Lock()
+	defer h.mut.Unlock()
+
+	if h.handlers == nil {
+		h.handlers = make(map[string]types.EvictionHandlerFunc)
+	}
+	h.handlers[id] = handler
+}
+
+// UnRegister removes the handler registered under the provided id
+func (h *Handlers) UnRegister(id string) {
+	h.mut.Lock()
+	delete(h.handlers, id)
+	h.mut.Unlock()
+}
+
+// HasHandlers returns true if at least one handler is registered, so that the caches can skip collecting
+// the removed items when nobody listens
+func (h *Handlers) HasHandlers() bool {
+	h.mut.RLock()
+	defer h.mut.RUnlock()
+
+	return len(h.handlers) > 0
+}
+
+// Notify calls the registered handlers, in no particular order. The handlers may register or unregister
+// other handlers, as they are called without holding the lock.
+func (h *Handlers) Notify(key []byte, value interface{}, reason types.EvictionReason) {
+	h.mut.RLock()
+	if len(h.handlers) == 0 {
+		h.mut.RUnlock()
+		return
+	}
+
+	handlers := make([]types.EvictionHandlerFunc, 0, len(h.handlers))
+	for _, handler := range h.handlers {
+		handlers = append(handlers, handler)
+	}
+	h.mut.RUnlock()
+
+	for _, handler := range handlers {
+		handler(key, value, reason)
+	}
+}
@@ -0,0 +1,96 @@
+package eviction_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/multiversx/mx-chain-storage-go/eviction"
+	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlers_RegisterAndNotify(t *testing.T) {
+	t.Parallel()
+
+	handlers := eviction.Handlers{}
+	assert.False(t, handlers.HasHandlers())
+	handlers.Notify([]byte("key"), "value", types.EvictedByCapacity)
+
+	handlers.Register(nil, "nil")
+	assert.False(t, handlers.HasHandlers())
+
+	notified := make(map[string][]string)
+	createHandler := func(id string) types.EvictionHandlerFunc {
+		return func(key []byte, value interface{}, reason types.EvictionReason) {
+			notified[id] = append(notified[id], fmt.Sprintf("%s:%v:%s", key, value, reason))
+		}
+	}
+	handlers.Register(createHandler("a"), "a")
+	handlers.Register(createHandler("b"), "b")
+	assert.True(t, handlers.HasHandlers())
+
+	handlers.Notify([]byte("key"), "value", types.EvictedByExpiry)
+	assert.Equal(t, []string{"key:value:expiry"}, notified["a"])
+	assert.Equal(t, []string{"key:value:expiry"}, notified["b"])
+
+	handlers.UnRegister("a")
+	handlers.Notify([]byte("other"), 1, types.EvictedByClear)
+	assert.Equal(t, 1, len(notified["a"]))
+	assert.Equal(t, []string{"key:value:expiry", "other:1:clear"}, notified["b"])
+
+	handlers.UnRegister("b")
+	assert.False(t, handlers.HasHandlers())
+}
+
+func TestHandlers_HandlerMayUnRegisterItself(t *testing.T) {
+	t.Parallel()
+
+	handlers := eviction.Handlers{}
+	numCalls := 0
+	handlers.Register(func(_ []byte, _ interface{}, _ types.EvictionReason) {
+		numCalls++
+		handlers.UnRegister("once")
+	}, "once")
+
+	handlers.Notify([]byte("key1"), nil, types.EvictedByRemoval)
+	handlers.Notify([]byte("key2"), nil, types.EvictedByRemoval)
+	assert.Equal(t, 1, numCalls)
+}
+
+func TestHandlers_ConcurrentOperations(t *testing.T) {
+	t.Parallel()
+
+	handlers := eviction.Handlers{}
+	numOperations := 1000
+	wg := sync.WaitGroup{}
+	wg.Add(numOperations)
+	for i := 0; i < numOperations; i++ {
+		go func(idx int) {
+			defer wg.Done()
+
+			id := fmt.Sprintf("id%d", idx%10)
+			switch idx % 4 {
+			case 0:
+				handlers.Register(func(_ []byte, _ interface{}, _ types.EvictionReason) {}, id)
+			case 1:
+				handlers.UnRegister(id)
+			case 2:
+				_ = handlers.HasHandlers()
+			case 3:
+				handlers.Notify([]byte(id), idx, types.EvictedByCapacity)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestEvictionReason_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "capacity", types.EvictedByCapacity.String())
+	assert.Equal(t, "removal", types.EvictedByRemoval.String())
+	assert.Equal(t, "expiry", types.EvictedByExpiry.String())
+	assert.Equal(t, "clear", types.EvictedByClear.String())
+	assert.Equal(t, "unknown", types.EvictionReason(100).String())
+}
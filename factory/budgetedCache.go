@@ -0,0 +1,51 @@
+package factory
+
+import (
+	"github.com/multiversx/mx-chain-storage-go/monitoring"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+var _ types.ResizableCacher = (*budgetedCache)(nil)
+var _ types.CacheStatsProvider = (*budgetedCache)(nil)
+
+// budgetedCache is a cache that leaves its memory budget, and the monitoring registry if it joined it, when closed
+type budgetedCache struct {
+	types.ResizableCacher
+	name      string
+	budget    types.MemoryBudgetHandler
+	monitored bool
+}
+
+func newBudgetedCache(cache types.ResizableCacher, name string, budget types.MemoryBudgetHandler, monitored bool) *budgetedCache {
+	return &budgetedCache{
+		ResizableCacher: cache,
+		name:            name,
+		budget:          budget,
+		monitored:       monitored,
+	}
+}
+
+// CacheStats returns the statistics of the wrapped cache
+func (bc *budgetedCache) CacheStats() types.CacheStats {
+	statsProvider, ok := bc.ResizableCacher.(types.CacheStatsProvider)
+	if !ok {
+		return types.CacheStats{}
+	}
+
+	return statsProvider.CacheStats()
+}
+
+// Close removes the cache from its memory budget and from the monitoring registry and closes the wrapped cache
+func (bc *budgetedCache) Close() error {
+	bc.budget.UnregisterCache(bc.name)
+	if bc.monitored {
+		monitoring.UnregisterCache(bc.name)
+	}
+
+	return bc.ResizableCacher.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (bc *budgetedCache) IsInterfaceNil() bool {
+	return bc == nil
+}
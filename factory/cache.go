@@ -2,7 +2,9 @@ package factory
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/multiversx/mx-chain-storage-go/common"
 	"github.com/multiversx/mx-chain-storage-go/fifocache"
 	"github.com/multiversx/mx-chain-storage-go/lrucache"
@@ -12,10 +14,82 @@ import (
 
 const minimumSizeForLRUCache = 1024
 
-// NewCache creates a new cache from a cache config
+// NewCache creates a new cache from a cache config. A named cache joins the monitoring registry and leaves it when closed.
 func NewCache(config common.CacheConfig) (types.Cacher, error) {
 	monitoring.MonitorNewCache(config.Name, config.SizeInBytes)
 
+	cache, err := createCache(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return monitorCache(config.Name, cache), nil
+}
+
+// NewCacheWithMemoryBudget creates a new cache from a cache config. A named cache bounded in bytes joins the provided
+// memory budget and leaves it when closed.
+func NewCacheWithMemoryBudget(config common.CacheConfig, budget types.MemoryBudgetHandler) (types.Cacher, error) {
+	if check.IfNil(budget) {
+		return nil, common.ErrNilMemoryBudgetHandler
+	}
+
+	// only the caches bounded in bytes take part in the memory budget
+	if !isBoundedInBytes(config.Type) || config.SizeInBytes == 0 || len(config.Name) == 0 {
+		return NewCache(config)
+	}
+
+	monitoring.MonitorNewCache(config.Name, config.SizeInBytes)
+
+	cache, err := createCache(config)
+	if err != nil {
+		return nil, err
+	}
+
+	resizableCache, ok := cache.(types.ResizableCacher)
+	if !ok {
+		return monitorCache(config.Name, cache), nil
+	}
+
+	err = budget.RegisterCache(config.Name, resizableCache, config.SizeInBytes, config.Priority)
+	if err != nil {
+		// the cache did not join the monitoring registry yet, so closing it is enough
+		_ = cache.Close()
+		return nil, err
+	}
+
+	monitored := registerForMonitoring(config.Name, cache)
+
+	return newBudgetedCache(resizableCache, config.Name, budget, monitored), nil
+}
+
+func monitorCache(name string, cache types.Cacher) types.Cacher {
+	if !registerForMonitoring(name, cache) {
+		return cache
+	}
+
+	return newMonitoredCache(cache, name)
+}
+
+// registerForMonitoring returns true if the cache joined the monitoring registry
+func registerForMonitoring(name string, cache types.Cacher) bool {
+	statsProvider, ok := cache.(types.CacheStatsProvider)
+	if !ok {
+		return false
+	}
+
+	return monitoring.RegisterCache(name, statsProvider)
+}
+
+func isBoundedInBytes(cacheType common.CacheType) bool {
+	switch cacheType {
+	case common.SizeLRUCache, common.SizeTwoQueueCache, common.SizeTinyLFUCache, common.ShardedSizeLRUCache, common.FIFOShardedCache, common.ExpiringLRUCache:
+		return true
+	default:
+		return false
+	}
+}
+
+func createCache(config common.CacheConfig) (types.Cacher, error) {
 	cacheType := config.Type
 	capacity := config.Capacity
 	shards := config.Shards
@@ -38,8 +112,64 @@ func NewCache(config common.CacheConfig) (types.Cacher, error) {
 		}
 
 		return lrucache.NewCacheWithSizeInBytes(int(capacity), int64(sizeInBytes))
+	case common.TwoQueueCache:
+		if sizeInBytes != 0 {
+			return nil, common.ErrLRUCacheWithProvidedSize
+		}
+
+		return lrucache.NewTwoQueueCache(int(capacity))
+	case common.SizeTwoQueueCache:
+		if sizeInBytes < minimumSizeForLRUCache {
+			return nil, fmt.Errorf("%w, provided %d, minimum %d",
+				common.ErrLRUCacheInvalidSize,
+				sizeInBytes,
+				minimumSizeForLRUCache,
+			)
+		}
+
+		return lrucache.NewTwoQueueCacheWithSizeInBytes(int(capacity), int64(sizeInBytes))
+	case common.SizeTinyLFUCache:
+		if sizeInBytes < minimumSizeForLRUCache {
+			return nil, fmt.Errorf("%w, provided %d, minimum %d",
+				common.ErrLRUCacheInvalidSize,
+				sizeInBytes,
+				minimumSizeForLRUCache,
+			)
+		}
+
+		return lrucache.NewTinyLFUCacheWithSizeInBytes(int(capacity), int64(sizeInBytes))
+	case common.ShardedSizeLRUCache:
+		if sizeInBytes < minimumSizeForLRUCache {
+			return nil, fmt.Errorf("%w, provided %d, minimum %d",
+				common.ErrLRUCacheInvalidSize,
+				sizeInBytes,
+				minimumSizeForLRUCache,
+			)
+		}
+
+		return lrucache.NewShardedCacheWithSizeInBytes(int(shards), int(capacity), int64(sizeInBytes))
+	case common.ExpiringLRUCache:
+		if sizeInBytes != 0 && sizeInBytes < minimumSizeForLRUCache {
+			return nil, fmt.Errorf("%w, provided %d, minimum %d",
+				common.ErrLRUCacheInvalidSize,
+				sizeInBytes,
+				minimumSizeForLRUCache,
+			)
+		}
+
+		expiry := lrucache.ExpiryConfig{
+			TTL:             time.Duration(config.TTLInSeconds) * time.Second,
+			MaxIdle:         time.Duration(config.MaxIdleInSeconds) * time.Second,
+			CleanupInterval: time.Duration(config.ExpiryCleanupIntervalInSeconds) * time.Second,
+		}
+
+		return lrucache.NewExpiringCache(int(capacity), int64(sizeInBytes), expiry)
 	case common.FIFOShardedCache:
-		return fifocache.NewShardedCache(int(capacity), int(shards))
+		if sizeInBytes == 0 {
+			return fifocache.NewShardedCache(int(capacity), int(shards))
+		}
+
+		return fifocache.NewShardedCacheWithSizeInBytes(int(capacity), int(shards), int64(sizeInBytes))
 	default:
 		return nil, common.ErrNotSupportedCacheType
 	}
@@ -7,9 +7,23 @@ import (
 
 	"github.com/multiversx/mx-chain-storage-go/common"
 	"github.com/multiversx/mx-chain-storage-go/factory"
+	"github.com/multiversx/mx-chain-storage-go/memorybudget"
+	"github.com/multiversx/mx-chain-storage-go/monitoring"
+	"github.com/multiversx/mx-chain-storage-go/testscommon"
+	"github.com/multiversx/mx-chain-storage-go/types"
 	"github.com/stretchr/testify/require"
 )
 
+func isMonitored(name string) bool {
+	for _, cache := range monitoring.DefaultRegistry().Snapshot().Caches {
+		if cache.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
 func TestNewCache(t *testing.T) {
 	t.Parallel()
 
@@ -53,6 +67,157 @@ func TestNewCache(t *testing.T) {
 		require.Equal(t, "*lrucache.lruCache", fmt.Sprintf("%T", cacher))
 	})
 
+	t.Run("named cache should be monitored", func(t *testing.T) {
+		t.Parallel()
+
+		cacheConf := common.CacheConfig{
+			Name:     "factory test cache",
+			Type:     common.LRUCache,
+			Capacity: 100,
+			Shards:   1,
+		}
+		cacher, err := factory.NewCache(cacheConf)
+		require.Nil(t, err)
+		_ = cacher.Put([]byte("key"), "value", 0)
+
+		found := false
+		for _, cache := range monitoring.DefaultRegistry().Snapshot().Caches {
+			if cache.Name == cacheConf.Name {
+				found = true
+				require.Equal(t, 1, cache.Stats.NumItems)
+			}
+		}
+		require.True(t, found)
+	})
+
+	t.Run("closed named cache should leave the monitoring registry", func(t *testing.T) {
+		t.Parallel()
+
+		cacheConf := common.CacheConfig{
+			Name:     "factory test recreated cache",
+			Type:     common.LRUCache,
+			Capacity: 100,
+			Shards:   1,
+		}
+		cacher, err := factory.NewCache(cacheConf)
+		require.Nil(t, err)
+		require.Nil(t, cacher.Close())
+		require.False(t, isMonitored(cacheConf.Name))
+
+		cacher, err = factory.NewCache(cacheConf)
+		require.Nil(t, err)
+		_ = cacher.Put([]byte("key"), "value", 0)
+		require.True(t, isMonitored(cacheConf.Name))
+		require.Equal(t, "*factory.monitoredCache", fmt.Sprintf("%T", cacher))
+
+		_ = cacher.Close()
+	})
+
+	t.Run("closed budgeted cache should leave the monitoring registry", func(t *testing.T) {
+		t.Parallel()
+
+		budget, _ := memorybudget.NewCoordinator(common.MemoryBudgetConfig{MinSizePercent: 10})
+		cacheConf := common.CacheConfig{
+			Name:        "factory test recreated sized cache",
+			Type:        common.SizeLRUCache,
+			Capacity:    100,
+			Shards:      1,
+			SizeInBytes: 2048,
+		}
+		cacher, err := factory.NewCacheWithMemoryBudget(cacheConf, budget)
+		require.Nil(t, err)
+		require.True(t, isMonitored(cacheConf.Name))
+		require.Nil(t, cacher.Close())
+		require.False(t, isMonitored(cacheConf.Name))
+
+		cacher, err = factory.NewCacheWithMemoryBudget(cacheConf, budget)
+		require.Nil(t, err)
+		require.True(t, isMonitored(cacheConf.Name))
+
+		_ = cacher.Close()
+	})
+
+	t.Run("named cache with size in bytes should join the memory budget until closed", func(t *testing.T) {
+		t.Parallel()
+
+		budget, _ := memorybudget.NewCoordinator(common.MemoryBudgetConfig{MinSizePercent: 10})
+		cacheConf := common.CacheConfig{
+			Name:        "factory test sized cache",
+			Type:        common.SizeLRUCache,
+			Capacity:    100,
+			Shards:      1,
+			SizeInBytes: 2048,
+			Priority:    common.LowCachePriority,
+		}
+		cacher, err := factory.NewCacheWithMemoryBudget(cacheConf, budget)
+		require.Nil(t, err)
+
+		caches := budget.Report().Caches
+		require.Equal(t, 1, len(caches))
+		require.Equal(t, cacheConf.Name, caches[0].Name)
+		require.Equal(t, common.LowCachePriority, caches[0].Priority)
+		require.Equal(t, uint64(2048), caches[0].RequestedSizeInBytes)
+
+		_, isResizable := cacher.(types.ResizableCacher)
+		require.True(t, isResizable)
+
+		_ = cacher.Close()
+		require.Empty(t, budget.Report().Caches)
+	})
+
+	t.Run("memory budget rejecting the cache should fail and release the cache", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		budget := &testscommon.MemoryBudgetHandlerStub{
+			RegisterCacheCalled: func(name string, cache types.ResizableCacher, sizeInBytes uint64, priority common.CachePriority) error {
+				return expectedErr
+			},
+		}
+		cacheConf := common.CacheConfig{
+			Name:                           "factory test rejected cache",
+			Type:                           common.ExpiringLRUCache,
+			Capacity:                       100,
+			SizeInBytes:                    2048,
+			TTLInSeconds:                   60,
+			ExpiryCleanupIntervalInSeconds: 1,
+		}
+		cacher, err := factory.NewCacheWithMemoryBudget(cacheConf, budget)
+		require.Equal(t, expectedErr, err)
+		require.Nil(t, cacher)
+		require.False(t, isMonitored(cacheConf.Name))
+
+		cacher, err = factory.NewCacheWithMemoryBudget(cacheConf, &testscommon.MemoryBudgetHandlerStub{})
+		require.Nil(t, err)
+		require.True(t, isMonitored(cacheConf.Name))
+
+		_ = cacher.Close()
+	})
+
+	t.Run("cache not bounded in bytes should not join the memory budget", func(t *testing.T) {
+		t.Parallel()
+
+		budget, _ := memorybudget.NewCoordinator(common.MemoryBudgetConfig{MinSizePercent: 10})
+		cacheConf := common.CacheConfig{
+			Name:     "factory test unbounded cache",
+			Type:     common.LRUCache,
+			Capacity: 100,
+			Shards:   1,
+		}
+		cacher, err := factory.NewCacheWithMemoryBudget(cacheConf, budget)
+		require.Nil(t, err)
+		require.Equal(t, "*factory.monitoredCache", fmt.Sprintf("%T", cacher))
+		require.Empty(t, budget.Report().Caches)
+	})
+
+	t.Run("nil memory budget should fail", func(t *testing.T) {
+		t.Parallel()
+
+		cacher, err := factory.NewCacheWithMemoryBudget(common.CacheConfig{Type: common.LRUCache, Capacity: 100}, nil)
+		require.Equal(t, common.ErrNilMemoryBudgetHandler, err)
+		require.Nil(t, cacher)
+	})
+
 	t.Run("SizeLRUCache type, invalid size, should fail", func(t *testing.T) {
 		t.Parallel()
 
@@ -81,6 +246,152 @@ func TestNewCache(t *testing.T) {
 		require.Equal(t, "*lrucache.lruCache", fmt.Sprintf("%T", cacher))
 	})
 
+	t.Run("TwoQueueCache type, with provided size, should fail", func(t *testing.T) {
+		t.Parallel()
+
+		cacheConf := common.CacheConfig{
+			Type:        common.TwoQueueCache,
+			Capacity:    100,
+			SizeInBytes: 1024,
+		}
+		cacher, err := factory.NewCache(cacheConf)
+		require.Nil(t, cacher)
+		require.Equal(t, common.ErrLRUCacheWithProvidedSize, err)
+	})
+
+	t.Run("TwoQueueCache type, should work", func(t *testing.T) {
+		t.Parallel()
+
+		cacheConf := common.CacheConfig{
+			Type:     common.TwoQueueCache,
+			Capacity: 100,
+		}
+		cacher, err := factory.NewCache(cacheConf)
+		require.Nil(t, err)
+		require.Equal(t, "*lrucache.lruCache", fmt.Sprintf("%T", cacher))
+	})
+
+	t.Run("SizeTwoQueueCache type, invalid size, should fail", func(t *testing.T) {
+		t.Parallel()
+
+		cacheConf := common.CacheConfig{
+			Type:        common.SizeTwoQueueCache,
+			Capacity:    100,
+			SizeInBytes: 1,
+		}
+		cacher, err := factory.NewCache(cacheConf)
+		require.Nil(t, cacher)
+		require.True(t, errors.Is(err, common.ErrLRUCacheInvalidSize))
+	})
+
+	t.Run("SizeTwoQueueCache type, should work", func(t *testing.T) {
+		t.Parallel()
+
+		cacheConf := common.CacheConfig{
+			Type:        common.SizeTwoQueueCache,
+			Capacity:    100,
+			SizeInBytes: 1024,
+		}
+		cacher, err := factory.NewCache(cacheConf)
+		require.Nil(t, err)
+		require.Equal(t, "*lrucache.lruCache", fmt.Sprintf("%T", cacher))
+	})
+
+	t.Run("SizeTinyLFUCache type, invalid size, should fail", func(t *testing.T) {
+		t.Parallel()
+
+		cacheConf := common.CacheConfig{
+			Type:        common.SizeTinyLFUCache,
+			Capacity:    100,
+			SizeInBytes: 1,
+		}
+		cacher, err := factory.NewCache(cacheConf)
+		require.Nil(t, cacher)
+		require.True(t, errors.Is(err, common.ErrLRUCacheInvalidSize))
+	})
+
+	t.Run("SizeTinyLFUCache type, should work", func(t *testing.T) {
+		t.Parallel()
+
+		cacheConf := common.CacheConfig{
+			Type:        common.SizeTinyLFUCache,
+			Capacity:    100,
+			SizeInBytes: 1024,
+		}
+		cacher, err := factory.NewCache(cacheConf)
+		require.Nil(t, err)
+		require.Equal(t, "*lrucache.lruCache", fmt.Sprintf("%T", cacher))
+	})
+
+	t.Run("ShardedSizeLRUCache type, invalid shards, should fail", func(t *testing.T) {
+		t.Parallel()
+
+		cacheConf := common.CacheConfig{
+			Type:        common.ShardedSizeLRUCache,
+			Capacity:    100,
+			SizeInBytes: 1024,
+		}
+		cacher, err := factory.NewCache(cacheConf)
+		require.Nil(t, cacher)
+		require.Equal(t, common.ErrInvalidNumberOfShards, err)
+	})
+
+	t.Run("ShardedSizeLRUCache type, should work", func(t *testing.T) {
+		t.Parallel()
+
+		cacheConf := common.CacheConfig{
+			Type:        common.ShardedSizeLRUCache,
+			Capacity:    100,
+			Shards:      4,
+			SizeInBytes: 1024,
+		}
+		cacher, err := factory.NewCache(cacheConf)
+		require.Nil(t, err)
+		require.Equal(t, "*lrucache.lruCache", fmt.Sprintf("%T", cacher))
+	})
+
+	t.Run("ExpiringLRUCache type, without expiry, should fail", func(t *testing.T) {
+		t.Parallel()
+
+		cacheConf := common.CacheConfig{
+			Type:     common.ExpiringLRUCache,
+			Capacity: 100,
+		}
+		cacher, err := factory.NewCache(cacheConf)
+		require.True(t, errors.Is(err, common.ErrInvalidConfig))
+		require.Nil(t, cacher)
+	})
+
+	t.Run("ExpiringLRUCache type, invalid size, should fail", func(t *testing.T) {
+		t.Parallel()
+
+		cacheConf := common.CacheConfig{
+			Type:         common.ExpiringLRUCache,
+			Capacity:     100,
+			SizeInBytes:  512,
+			TTLInSeconds: 60,
+		}
+		cacher, err := factory.NewCache(cacheConf)
+		require.True(t, errors.Is(err, common.ErrLRUCacheInvalidSize))
+		require.Nil(t, cacher)
+	})
+
+	t.Run("ExpiringLRUCache type, should work", func(t *testing.T) {
+		t.Parallel()
+
+		cacheConf := common.CacheConfig{
+			Type:             common.ExpiringLRUCache,
+			Capacity:         100,
+			SizeInBytes:      1024,
+			TTLInSeconds:     60,
+			MaxIdleInSeconds: 10,
+		}
+		cacher, err := factory.NewCache(cacheConf)
+		require.Nil(t, err)
+		require.Equal(t, "*lrucache.lruCache", fmt.Sprintf("%T", cacher))
+		require.Nil(t, cacher.Close())
+	})
+
 	t.Run("FIFOShardedCache type, should work", func(t *testing.T) {
 		t.Parallel()
 
@@ -94,4 +405,17 @@ func TestNewCache(t *testing.T) {
 		require.Nil(t, err)
 		require.Equal(t, "*fifocache.FIFOShardedCache", fmt.Sprintf("%T", cacher))
 	})
+	t.Run("FIFOShardedCache type without size in bytes, should work", func(t *testing.T) {
+		t.Parallel()
+
+		cacheConf := common.CacheConfig{
+			Type:     common.FIFOShardedCache,
+			Capacity: 100,
+			Shards:   4,
+		}
+		cacher, err := factory.NewCache(cacheConf)
+		require.Nil(t, err)
+		require.Equal(t, "*fifocache.FIFOShardedCache", fmt.Sprintf("%T", cacher))
+		require.Equal(t, 100, cacher.MaxSize())
+	})
 }
@@ -0,0 +1,44 @@
+package factory
+
+import (
+	"github.com/multiversx/mx-chain-storage-go/monitoring"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+var _ types.Cacher = (*monitoredCache)(nil)
+var _ types.CacheStatsProvider = (*monitoredCache)(nil)
+
+// monitoredCache is a cache that leaves the monitoring registry when closed
+type monitoredCache struct {
+	types.Cacher
+	name string
+}
+
+func newMonitoredCache(cache types.Cacher, name string) *monitoredCache {
+	return &monitoredCache{
+		Cacher: cache,
+		name:   name,
+	}
+}
+
+// CacheStats returns the statistics of the wrapped cache
+func (mc *monitoredCache) CacheStats() types.CacheStats {
+	statsProvider, ok := mc.Cacher.(types.CacheStatsProvider)
+	if !ok {
+		return types.CacheStats{}
+	}
+
+	return statsProvider.CacheStats()
+}
+
+// Close removes the cache from the monitoring registry and closes the wrapped cache
+func (mc *monitoredCache) Close() error {
+	monitoring.UnregisterCache(mc.name)
+
+	return mc.Cacher.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (mc *monitoredCache) IsInterfaceNil() bool {
+	return mc == nil
+}
@@ -1,11 +1,20 @@
 package factory
 
 import (
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-storage-go/cachedump"
 	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/monitoring"
 	"github.com/multiversx/mx-chain-storage-go/storageUnit"
+	"github.com/multiversx/mx-chain-storage-go/types"
 )
 
-// NewStorageUnitFromConf creates a new storage unit from a storage unit config
+var log = logger.GetOrCreate("storage/factory")
+
+// NewStorageUnitFromConf creates a new storage unit from a storage unit config.
+// If monitoring is enabled, the persister is monitored under its file path and, if a dump file path is configured,
+// the cache contents are dumped on Close and restored on start.
 func NewStorageUnitFromConf(cacheConf common.CacheConfig, dbConf common.DBConfig) (*storageUnit.Unit, error) {
 	if dbConf.MaxBatchSize > int(cacheConf.Capacity) {
 		return nil, common.ErrCacheSizeIsLowerThanBatchSize
@@ -28,5 +37,62 @@ func NewStorageUnitFromConf(cacheConf common.CacheConfig, dbConf common.DBConfig
 		return nil, err
 	}
 
-	return storageUnit.NewStorageUnit(cache, db)
+	persister := db
+	if dbConf.EnableMonitoring {
+		persister = monitorPersister(dbConf.FilePath, db)
+	}
+	if len(cacheConf.DumpFilePath) == 0 {
+		return storageUnit.NewStorageUnit(cache, persister)
+	}
+
+	argsCacheDumper := cachedump.ArgsCacheDumper{
+		FilePath:    cacheConf.DumpFilePath,
+		Marshalizer: &marshal.JsonMarshalizer{},
+		DumpValues:  cacheConf.DumpValues,
+	}
+	cacheDumper, err := cachedump.NewCacheDumper(argsCacheDumper)
+	if err != nil {
+		return nil, err
+	}
+
+	argsStorageUnit := storageUnit.ArgsStorageUnitWithCacheDump{
+		Cacher:      cache,
+		Persister:   persister,
+		CacheDumper: cacheDumper,
+		WarmUpMode:  cacheConf.WarmUpMode,
+	}
+
+	return storageUnit.NewStorageUnitWithCacheDump(argsStorageUnit)
+}
+
+func monitorPersister(path string, persister types.Persister) types.Persister {
+	if len(path) == 0 {
+		return persister
+	}
+
+	argsInstrumentedPersister := monitoring.ArgsInstrumentedPersister{
+		Persister: persister,
+		Registry:  monitoring.DefaultRegistry(),
+		Name:      path,
+	}
+
+	// the multi get ability of the persister is kept, as the storage unit uses it for the bulk reads
+	_, isMultiGetter := persister.(types.PersisterWithMultiGet)
+	if isMultiGetter {
+		instrumentedPersister, err := monitoring.NewInstrumentedMultiGetPersister(argsInstrumentedPersister)
+		if err != nil {
+			log.Debug("persister will not be monitored", "path", path, "error", err)
+			return persister
+		}
+
+		return instrumentedPersister
+	}
+
+	instrumentedPersister, err := monitoring.NewInstrumentedPersister(argsInstrumentedPersister)
+	if err != nil {
+		log.Debug("persister will not be monitored", "path", path, "error", err)
+		return persister
+	}
+
+	return instrumentedPersister
 }
@@ -1,11 +1,15 @@
 package factory_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/multiversx/mx-chain-storage-go/common"
 	"github.com/multiversx/mx-chain-storage-go/factory"
+	"github.com/multiversx/mx-chain-storage-go/monitoring"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewStorageUnitFromConf_WrongCacheSizeVsBatchSize(t *testing.T) {
@@ -103,3 +107,82 @@ func TestNewStorageUnitFromConf_ShouldWorkLvlDB(t *testing.T) {
 	err = storer.DestroyUnit()
 	assert.Nil(t, err, "no error expected destroying the persister")
 }
+
+func TestNewStorageUnitFromConf_WithCacheDumpShouldWarmUp(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cacheConf := common.CacheConfig{
+		Capacity:     10,
+		Type:         common.LRUCache,
+		DumpFilePath: filepath.Join(dir, "cache.dump"),
+		DumpValues:   true,
+		WarmUpMode:   common.EagerCacheWarmUp,
+	}
+	dbConf := common.DBConfig{
+		FilePath:          filepath.Join(dir, "db"),
+		Type:              common.LvlDB,
+		BatchDelaySeconds: 1,
+		MaxBatchSize:      1,
+		MaxOpenFiles:      10,
+	}
+
+	storer, err := factory.NewStorageUnitFromConf(cacheConf, dbConf)
+	require.Nil(t, err)
+	_ = storer.Put([]byte("key"), []byte("value"))
+	err = storer.Close()
+	require.Nil(t, err)
+
+	_, err = os.Stat(cacheConf.DumpFilePath)
+	assert.Nil(t, err)
+
+	storer, err = factory.NewStorageUnitFromConf(cacheConf, dbConf)
+	require.Nil(t, err)
+	value, err := storer.Get([]byte("key"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("value"), value)
+	_ = storer.Close()
+}
+
+func TestNewStorageUnitFromConf_MonitoringShouldBeOptIn(t *testing.T) {
+	t.Parallel()
+
+	isPersisterMonitored := func(path string) bool {
+		for _, unit := range monitoring.DefaultRegistry().Snapshot().Units {
+			if unit.Name == path {
+				return true
+			}
+		}
+
+		return false
+	}
+	createDBConfig := func(enableMonitoring bool) common.DBConfig {
+		return common.DBConfig{
+			FilePath:          filepath.Join(t.TempDir(), "db"),
+			Type:              common.LvlDB,
+			BatchDelaySeconds: 1,
+			MaxBatchSize:      1,
+			MaxOpenFiles:      10,
+			EnableMonitoring:  enableMonitoring,
+		}
+	}
+	cacheConf := common.CacheConfig{
+		Capacity: 10,
+		Type:     common.LRUCache,
+	}
+
+	dbConf := createDBConfig(false)
+	storer, err := factory.NewStorageUnitFromConf(cacheConf, dbConf)
+	require.Nil(t, err)
+	assert.False(t, isPersisterMonitored(dbConf.FilePath))
+	_ = storer.Close()
+
+	dbConf = createDBConfig(true)
+	storer, err = factory.NewStorageUnitFromConf(cacheConf, dbConf)
+	require.Nil(t, err)
+	_ = storer.Put([]byte("key"), []byte("value"))
+	assert.True(t, isPersisterMonitored(dbConf.FilePath))
+
+	_ = storer.Close()
+	assert.False(t, isPersisterMonitored(dbConf.FilePath))
+}
@@ -0,0 +1,177 @@
+package fifocache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// fifoEntry is used to hold a value in the insertion order queue of a shard. The size bounds the capacity, while the
+// entry size, that includes the key and the overhead of the entry, is only reported.
+type fifoEntry struct {
+	key       string
+	value     interface{}
+	size      int64
+	entrySize int64
+	sequence  uint64
+}
+
+// fifoShard holds a part of the keys in insertion order, bounded by a number of items and, optionally,
+// by their size in bytes
+type fifoShard struct {
+	mut              sync.Mutex
+	queue            *list.List
+	items            map[string]*list.Element
+	sizeInBytes      int64
+	entrySizeInBytes int64
+	maxNumItems      int
+	maxSizeInBytes   int64
+}
+
+func newFIFOShard(maxNumItems int, maxSizeInBytes int64) *fifoShard {
+	return &fifoShard{
+		queue:          list.New(),
+		items:          make(map[string]*list.Element),
+		maxNumItems:    maxNumItems,
+		maxSizeInBytes: maxSizeInBytes,
+	}
+}
+
+// put adds or replaces the entry, an update moving the key at the end of the queue. It returns the evicted entries.
+func (shard *fifoShard) put(ent *fifoEntry) []*fifoEntry {
+	shard.mut.Lock()
+	defer shard.mut.Unlock()
+
+	element, ok := shard.items[ent.key]
+	if ok {
+		shard.removeElement(element)
+	}
+	shard.pushBack(ent)
+
+	return shard.evictIfNeeded()
+}
+
+// putIfMissing adds the entry only if the key is not contained. It returns whether the key was contained
+// and the evicted entries.
+func (shard *fifoShard) putIfMissing(ent *fifoEntry) (bool, []*fifoEntry) {
+	shard.mut.Lock()
+	defer shard.mut.Unlock()
+
+	_, ok := shard.items[ent.key]
+	if ok {
+		return true, nil
+	}
+	shard.pushBack(ent)
+
+	return false, shard.evictIfNeeded()
+}
+
+func (shard *fifoShard) pushBack(ent *fifoEntry) {
+	shard.items[ent.key] = shard.queue.PushBack(ent)
+	shard.sizeInBytes += ent.size
+	shard.entrySizeInBytes += ent.entrySize
+}
+
+func (shard *fifoShard) get(key string) (interface{}, bool) {
+	shard.mut.Lock()
+	defer shard.mut.Unlock()
+
+	element, ok := shard.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	return element.Value.(*fifoEntry).value, true
+}
+
+func (shard *fifoShard) has(key string) bool {
+	shard.mut.Lock()
+	defer shard.mut.Unlock()
+
+	_, ok := shard.items[key]
+
+	return ok
+}
+
+// remove returns the removed entry, if the key was contained
+func (shard *fifoShard) remove(key string) (*fifoEntry, bool) {
+	shard.mut.Lock()
+	defer shard.mut.Unlock()
+
+	element, ok := shard.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	return shard.removeElement(element), true
+}
+
+func (shard *fifoShard) removeElement(element *list.Element) *fifoEntry {
+	ent := shard.queue.Remove(element).(*fifoEntry)
+	delete(shard.items, ent.key)
+	shard.sizeInBytes -= ent.size
+	shard.entrySizeInBytes -= ent.entrySize
+
+	return ent
+}
+
+// clear returns the queue of the dropped entries, which is no longer used by the shard
+func (shard *fifoShard) clear() *list.List {
+	shard.mut.Lock()
+	defer shard.mut.Unlock()
+
+	dropped := shard.queue
+	shard.queue = list.New()
+	shard.items = make(map[string]*list.Element)
+	shard.sizeInBytes = 0
+	shard.entrySizeInBytes = 0
+
+	return dropped
+}
+
+// entries returns the contained entries, from the oldest to the newest
+func (shard *fifoShard) entries() []*fifoEntry {
+	shard.mut.Lock()
+	defer shard.mut.Unlock()
+
+	entries := make([]*fifoEntry, 0, len(shard.items))
+	for element := shard.queue.Front(); element != nil; element = element.Next() {
+		entries = append(entries, element.Value.(*fifoEntry))
+	}
+
+	return entries
+}
+
+func (shard *fifoShard) len() int {
+	shard.mut.Lock()
+	defer shard.mut.Unlock()
+
+	return len(shard.items)
+}
+
+func (shard *fifoShard) entriesSize() int64 {
+	shard.mut.Lock()
+	defer shard.mut.Unlock()
+
+	return shard.entrySizeInBytes
+}
+
+func (shard *fifoShard) shouldEvict() bool {
+	if len(shard.items) <= 1 {
+		// keep at least one element, no matter how large it is
+		return false
+	}
+
+	isBoundedInBytes := shard.maxSizeInBytes > 0
+
+	return len(shard.items) > shard.maxNumItems || (isBoundedInBytes && shard.sizeInBytes > shard.maxSizeInBytes)
+}
+
+// evictIfNeeded removes the oldest entries while the limits are exceeded
+func (shard *fifoShard) evictIfNeeded() []*fifoEntry {
+	var evicted []*fifoEntry
+	for shard.shouldEvict() {
+		evicted = append(evicted, shard.removeElement(shard.queue.Front()))
+	}
+
+	return evicted
+}
@@ -1,54 +1,203 @@
 package fifocache
 
 import (
+	"container/list"
+	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
 
-	cmap "github.com/multiversx/concurrent-map"
 	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/eviction"
+	"github.com/multiversx/mx-chain-storage-go/monitoring"
 	"github.com/multiversx/mx-chain-storage-go/types"
 )
 
 var _ types.Cacher = (*FIFOShardedCache)(nil)
+var _ types.CacheStatsProvider = (*FIFOShardedCache)(nil)
+var _ types.ResizableCacher = (*FIFOShardedCache)(nil)
+var _ types.EvictionNotifier = (*FIFOShardedCache)(nil)
 
 var log = logger.GetOrCreate("storage/fifocache")
 
-// FIFOShardedCache implements a First In First Out eviction cache
+const (
+	fnvOffset32 = 2166136261
+	fnvPrime32  = 16777619
+)
+
+// FIFOShardedCache implements a First In First Out eviction cache. The keys are partitioned, by their hash, between
+// independently locked shards, each one holding its keys in insertion order. The number of items and the optional
+// size in bytes are split evenly between the shards, hence a shard may evict before the whole cache is full.
 type FIFOShardedCache struct {
-	cache   *cmap.ConcurrentMap
-	maxsize int
+	// mutCache guards the shards and the limits, as the shards are rebuilt when the cache is resized
+	mutCache       sync.RWMutex
+	shards         []*fifoShard
+	maxsize        int
+	maxSizeInBytes int64
+	numShards      int
+	lastSequence   uint64
+	onEvicted      func(key []byte, value interface{})
+	counters       monitoring.CacheCounters
+
+	evictionHandlers eviction.Handlers
 
 	mutAddedDataHandlers sync.RWMutex
 	mapDataHandlers      map[string]func(key []byte, value interface{})
 }
 
-// NewShardedCache creates a new cache instance
+// NewShardedCache creates a new cache instance bounded by the number of items
 func NewShardedCache(size int, shards int) (*FIFOShardedCache, error) {
-	cache := cmap.New(size, shards)
+	return newShardedCache(size, shards, 0, nil)
+}
+
+// NewShardedCacheWithSizeInBytes creates a new cache instance bounded by both the number of items and their size in bytes
+func NewShardedCacheWithSizeInBytes(size int, shards int, sizeInBytes int64) (*FIFOShardedCache, error) {
+	if sizeInBytes < 1 {
+		return nil, common.ErrCacheCapacityInvalid
+	}
+
+	return newShardedCache(size, shards, sizeInBytes, nil)
+}
+
+// NewShardedCacheWithSizeInBytesAndEviction creates a new cache instance bounded by both the number of items and
+// their size in bytes. The optional onEvicted callback is called, outside the cache locks, for each item evicted
+// due to the capacity.
+func NewShardedCacheWithSizeInBytesAndEviction(
+	size int,
+	shards int,
+	sizeInBytes int64,
+	onEvicted func(key []byte, value interface{}),
+) (*FIFOShardedCache, error) {
+	if sizeInBytes < 1 {
+		return nil, common.ErrCacheCapacityInvalid
+	}
+
+	return newShardedCache(size, shards, sizeInBytes, onEvicted)
+}
+
+func newShardedCache(size int, shards int, sizeInBytes int64, onEvicted func(key []byte, value interface{})) (*FIFOShardedCache, error) {
+	if size < 1 {
+		return nil, common.ErrCacheSizeInvalid
+	}
+	if shards < 1 {
+		return nil, common.ErrInvalidNumberOfShards
+	}
+
 	fifoShardedCache := &FIFOShardedCache{
-		cache:                cache,
 		maxsize:              size,
+		maxSizeInBytes:       sizeInBytes,
+		numShards:            shards,
+		onEvicted:            onEvicted,
 		mutAddedDataHandlers: sync.RWMutex{},
 		mapDataHandlers:      make(map[string]func(key []byte, value interface{})),
 	}
+	fifoShardedCache.shards = fifoShardedCache.createShards()
 
 	return fifoShardedCache, nil
 }
 
+// createShards splits the limits between the shards, the remainders going to the first shards. There are no
+// more shards than items or bytes, so that each shard can hold at least one item within the limits.
+func (c *FIFOShardedCache) createShards() []*fifoShard {
+	numShards := c.numShards
+	if numShards > c.maxsize {
+		numShards = c.maxsize
+	}
+	isBoundedInBytes := c.maxSizeInBytes > 0
+	if isBoundedInBytes && int64(numShards) > c.maxSizeInBytes {
+		numShards = int(c.maxSizeInBytes)
+	}
+
+	shards := make([]*fifoShard, numShards)
+	for i := range shards {
+		maxNumItems := c.maxsize / numShards
+		if i < c.maxsize%numShards {
+			maxNumItems++
+		}
+
+		var maxSizeInBytes int64
+		if isBoundedInBytes {
+			maxSizeInBytes = c.maxSizeInBytes / int64(numShards)
+			if int64(i) < c.maxSizeInBytes%int64(numShards) {
+				maxSizeInBytes++
+			}
+		}
+
+		shards[i] = newFIFOShard(maxNumItems, maxSizeInBytes)
+	}
+
+	return shards
+}
+
+func (c *FIFOShardedCache) getShard(key string) *fifoShard {
+	return c.shards[fnv32(key)%uint32(len(c.shards))]
+}
+
+func fnv32(key string) uint32 {
+	hash := uint32(fnvOffset32)
+	for i := 0; i < len(key); i++ {
+		hash ^= uint32(key[i])
+		hash *= fnvPrime32
+	}
+
+	return hash
+}
+
+// newEntry bounds the capacity by the size provided by the caller, the key and the overhead of the entry being
+// only reported in the statistics
+func (c *FIFOShardedCache) newEntry(key []byte, value interface{}, sizeInBytes int) *fifoEntry {
+	return &fifoEntry{
+		key:       string(key),
+		value:     value,
+		size:      int64(sizeInBytes),
+		entrySize: int64(types.ComputeEntrySizeInBytes(key, value, sizeInBytes)),
+		sequence:  atomic.AddUint64(&c.lastSequence, 1),
+	}
+}
+
 // Clear is used to completely clear the cache.
 func (c *FIFOShardedCache) Clear() {
-	keys := c.cache.Keys()
-	for _, key := range keys {
-		c.cache.Remove(key)
+	c.mutCache.RLock()
+	dropped := make([]*list.List, 0, len(c.shards))
+	for _, shard := range c.shards {
+		dropped = append(dropped, shard.clear())
+	}
+	c.mutCache.RUnlock()
+
+	if !c.evictionHandlers.HasHandlers() {
+		return
+	}
+
+	for _, queue := range dropped {
+		for element := queue.Front(); element != nil; element = element.Next() {
+			ent := element.Value.(*fifoEntry)
+			c.evictionHandlers.Notify([]byte(ent.key), ent.value, types.EvictedByClear)
+		}
 	}
 }
 
 // Put adds a value to the cache.  Returns true if an eviction occurred.
-// the int parameter for size is not used as, for now, fifo sharded cache can not count for its contained data size
-func (c *FIFOShardedCache) Put(key []byte, value interface{}, _ int) (evicted bool) {
-	c.cache.Set(string(key), value)
+// Updating a contained key moves it after the newest keys.
+func (c *FIFOShardedCache) Put(key []byte, value interface{}, sizeInBytes int) (evicted bool) {
+	if sizeInBytes < 0 {
+		log.Error("fifo cache put error",
+			"key", key,
+			"value", fmt.Sprintf("%v", value),
+			"error", common.ErrNegativeSizeInBytes,
+		)
+
+		return false
+	}
+
+	c.mutCache.RLock()
+	evictedEntries := c.getShard(string(key)).put(c.newEntry(key, value, sizeInBytes))
+	c.mutCache.RUnlock()
+
 	c.callAddedDataHandlers(key, value)
+	c.notifyEvicted(evictedEntries)
 
-	return true
+	return len(evictedEntries) > 0
 }
 
 // RegisterHandler registers a new handler to be called when a new data is added
@@ -72,32 +221,85 @@ func (c *FIFOShardedCache) UnRegisterHandler(id string) {
 
 // Get looks up a key's value from the cache.
 func (c *FIFOShardedCache) Get(key []byte) (value interface{}, ok bool) {
-	return c.cache.Get(string(key))
+	c.mutCache.RLock()
+	defer c.mutCache.RUnlock()
+
+	value, ok = c.getShard(string(key)).get(string(key))
+	c.counters.RecordLookup(ok)
+
+	return value, ok
 }
 
 // Has checks if a key is in the cache, without updating the
 // recent-ness or deleting it for being stale.
 func (c *FIFOShardedCache) Has(key []byte) bool {
-	return c.cache.Has(string(key))
+	c.mutCache.RLock()
+	defer c.mutCache.RUnlock()
+
+	ok := c.getShard(string(key)).has(string(key))
+	c.counters.RecordLookup(ok)
+
+	return ok
 }
 
 // Peek returns the key value (or undefined if not found) without updating
-// the "recently used"-ness of the key.
+// the "recently used"-ness of the key or the hit and miss statistics.
 func (c *FIFOShardedCache) Peek(key []byte) (value interface{}, ok bool) {
-	return c.cache.Get(string(key))
+	c.mutCache.RLock()
+	defer c.mutCache.RUnlock()
+
+	return c.getShard(string(key)).get(string(key))
 }
 
 // HasOrAdd checks if a key is in the cache without updating the
 // recent-ness or deleting it for being stale, and if not, adds the value.
 // Returns whether the item existed before and whether it has been added.
-func (c *FIFOShardedCache) HasOrAdd(key []byte, value interface{}, _ int) (has, added bool) {
-	added = c.cache.SetIfAbsent(string(key), value)
+func (c *FIFOShardedCache) HasOrAdd(key []byte, value interface{}, sizeInBytes int) (has, added bool) {
+	if sizeInBytes < 0 {
+		log.Error("fifo cache has or add error",
+			"key", key,
+			"value", fmt.Sprintf("%v", value),
+			"error", common.ErrNegativeSizeInBytes,
+		)
+
+		return false, false
+	}
+
+	c.mutCache.RLock()
+	has, evictedEntries := c.getShard(string(key)).putIfMissing(c.newEntry(key, value, sizeInBytes))
+	c.mutCache.RUnlock()
+
+	if has {
+		return true, false
+	}
+
+	c.callAddedDataHandlers(key, value)
+	c.notifyEvicted(evictedEntries)
 
-	if added {
-		c.callAddedDataHandlers(key, value)
+	return false, true
+}
+
+// notifyEvicted accounts the evicted entries and calls the eviction callback and handlers. It is called outside
+// the cache locks, so that the callback can use the cache.
+func (c *FIFOShardedCache) notifyEvicted(evicted []*fifoEntry) {
+	c.counters.RecordEvictions(len(evicted))
+	for _, ent := range evicted {
+		key := []byte(ent.key)
+		if c.onEvicted != nil {
+			c.onEvicted(key, ent.value)
+		}
+		c.evictionHandlers.Notify(key, ent.value, types.EvictedByCapacity)
 	}
+}
 
-	return !added, added
+// RegisterEvictionHandler registers a new handler to be called for each item leaving the cache
+func (c *FIFOShardedCache) RegisterEvictionHandler(handler types.EvictionHandlerFunc, id string) {
+	c.evictionHandlers.Register(handler, id)
+}
+
+// UnRegisterEvictionHandler removes the eviction handler registered under the provided id
+func (c *FIFOShardedCache) UnRegisterEvictionHandler(id string) {
+	c.evictionHandlers.UnRegister(id)
 }
 
 func (c *FIFOShardedCache) callAddedDataHandlers(key []byte, value interface{}) {
@@ -110,36 +312,118 @@ func (c *FIFOShardedCache) callAddedDataHandlers(key []byte, value interface{})
 
 // Remove removes the provided key from the cache.
 func (c *FIFOShardedCache) Remove(key []byte) {
-	c.cache.Remove(string(key))
+	c.mutCache.RLock()
+	removed, ok := c.getShard(string(key)).remove(string(key))
+	c.mutCache.RUnlock()
+
+	if ok {
+		c.evictionHandlers.Notify(key, removed.value, types.EvictedByRemoval)
+	}
 }
 
 // Keys returns a slice of the keys in the cache, from oldest to newest.
 func (c *FIFOShardedCache) Keys() [][]byte {
-	res := c.cache.Keys()
-	r := make([][]byte, len(res))
+	c.mutCache.RLock()
+	defer c.mutCache.RUnlock()
+
+	entries := c.sortedEntries()
+	keys := make([][]byte, len(entries))
+	for i, ent := range entries {
+		keys[i] = []byte(ent.key)
+	}
+
+	return keys
+}
 
-	for i := 0; i < len(res); i++ {
-		r[i] = []byte(res[i])
+// sortedEntries returns the entries of all the shards, from the oldest to the newest. It should be called under the cache lock.
+func (c *FIFOShardedCache) sortedEntries() []*fifoEntry {
+	entries := make([]*fifoEntry, 0)
+	for _, shard := range c.shards {
+		entries = append(entries, shard.entries()...)
 	}
 
-	return r
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].sequence < entries[j].sequence
+	})
+
+	return entries
 }
 
 // Len returns the number of items in the cache.
 func (c *FIFOShardedCache) Len() int {
-	return c.cache.Count()
+	c.mutCache.RLock()
+	defer c.mutCache.RUnlock()
+
+	numItems := 0
+	for _, shard := range c.shards {
+		numItems += shard.len()
+	}
+
+	return numItems
 }
 
-// SizeInBytesContained returns 0
+// SizeInBytesContained returns the size in bytes of all contained elements, including the keys and the overhead of
+// the entries, a value implementing types.Sizer reporting its own size. The capacity is bounded by the sizes
+// provided by the callers.
 func (c *FIFOShardedCache) SizeInBytesContained() uint64 {
-	return 0
+	c.mutCache.RLock()
+	defer c.mutCache.RUnlock()
+
+	sizeInBytes := int64(0)
+	for _, shard := range c.shards {
+		sizeInBytes += shard.entriesSize()
+	}
+
+	return uint64(sizeInBytes)
 }
 
 // MaxSize returns the maximum number of items which can be stored in cache.
 func (c *FIFOShardedCache) MaxSize() int {
+	c.mutCache.RLock()
+	defer c.mutCache.RUnlock()
+
 	return c.maxsize
 }
 
+// Resize changes the limits of the cache, evicting the oldest items if the new limits are exceeded.
+// The size in bytes is ignored if the cache is bounded only by the number of items.
+func (c *FIFOShardedCache) Resize(maxNumItems int, maxSizeInBytes int64) error {
+	if maxNumItems < 1 {
+		return common.ErrCacheSizeInvalid
+	}
+
+	c.mutCache.Lock()
+	isBoundedInBytes := c.maxSizeInBytes > 0
+	if isBoundedInBytes && maxSizeInBytes < 1 {
+		c.mutCache.Unlock()
+		return common.ErrCacheCapacityInvalid
+	}
+
+	entries := c.sortedEntries()
+	c.maxsize = maxNumItems
+	if isBoundedInBytes {
+		c.maxSizeInBytes = maxSizeInBytes
+	}
+
+	// the number of shards may change with the limits, so all the entries are added again, from the oldest
+	// to the newest, in the new shards
+	c.shards = c.createShards()
+	var evictedEntries []*fifoEntry
+	for _, ent := range entries {
+		evictedEntries = append(evictedEntries, c.getShard(ent.key).put(ent)...)
+	}
+	c.mutCache.Unlock()
+
+	c.notifyEvicted(evictedEntries)
+
+	return nil
+}
+
+// CacheStats returns the statistics of the cache
+func (c *FIFOShardedCache) CacheStats() types.CacheStats {
+	return c.counters.Stats(c.Len(), c.SizeInBytesContained())
+}
+
 // Close does nothing for this cacher implementation
 func (c *FIFOShardedCache) Close() error {
 	return nil
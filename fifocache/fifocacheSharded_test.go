@@ -7,7 +7,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/common"
 	"github.com/multiversx/mx-chain-storage-go/fifocache"
+	"github.com/multiversx/mx-chain-storage-go/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -374,6 +377,53 @@ func TestFIFOShardedCache_CacherRegisterHasOrAddAddedDataHandlerNotAddedShouldNo
 	assert.Equal(t, 1, len(c.AddedDataHandlers()))
 }
 
+func TestFIFOShardedCache_Resize(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid size should error", func(t *testing.T) {
+		t.Parallel()
+
+		c, _ := fifocache.NewShardedCache(10, 1)
+		err := c.Resize(0, 0)
+		assert.Equal(t, common.ErrCacheSizeInvalid, err)
+		assert.Equal(t, 10, c.MaxSize())
+	})
+	t.Run("shrinking should evict the oldest items", func(t *testing.T) {
+		t.Parallel()
+
+		c, _ := fifocache.NewShardedCache(10, 1)
+		for i := 0; i < 10; i++ {
+			c.Put([]byte(fmt.Sprintf("key%d", i)), i, 0)
+		}
+
+		numItemsBefore := c.Len()
+		err := c.Resize(3, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, c.MaxSize())
+		assert.True(t, c.Len() <= 3)
+		assert.False(t, c.Has([]byte("key0")))
+		assert.True(t, c.Has([]byte("key9")))
+		assert.Equal(t, []byte("key9"), c.Keys()[c.Len()-1])
+		assert.Equal(t, uint64(numItemsBefore-c.Len()), c.CacheStats().NumEvictions)
+	})
+	t.Run("growing should keep the items", func(t *testing.T) {
+		t.Parallel()
+
+		c, _ := fifocache.NewShardedCache(3, 1)
+		c.Put([]byte("key0"), 0, 0)
+
+		err := c.Resize(10, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, 10, c.MaxSize())
+		for i := 1; i < 5; i++ {
+			c.Put([]byte(fmt.Sprintf("key%d", i)), i, 0)
+		}
+		assert.Equal(t, 5, c.Len())
+		assert.True(t, c.Has([]byte("key0")))
+		assert.Equal(t, uint64(0), c.CacheStats().NumEvictions)
+	})
+}
+
 func TestFifoShardedCache_ConcurrentOperation(t *testing.T) {
 	fifoCacher, _ := fifocache.NewShardedCache(10000, 3)
 
@@ -382,7 +432,7 @@ func TestFifoShardedCache_ConcurrentOperation(t *testing.T) {
 	wg.Add(numOperations)
 	for i := 0; i < numOperations; i++ {
 		go func(idx int) {
-			switch idx % 16 {
+			switch idx % 17 {
 			case 0:
 				fifoCacher.AddedDataHandlers()
 			case 1:
@@ -415,6 +465,8 @@ func TestFifoShardedCache_ConcurrentOperation(t *testing.T) {
 				fifoCacher.SizeInBytesContained()
 			case 15:
 				fifoCacher.UnRegisterHandler("id")
+			case 16:
+				_ = fifoCacher.Resize(idx%100+1, 0)
 			}
 
 			wg.Done()
@@ -423,3 +475,263 @@ func TestFifoShardedCache_ConcurrentOperation(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestNewShardedCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid size should error", func(t *testing.T) {
+		t.Parallel()
+
+		c, err := fifocache.NewShardedCache(0, 2)
+		assert.True(t, check.IfNil(c))
+		assert.Equal(t, common.ErrCacheSizeInvalid, err)
+	})
+	t.Run("invalid number of shards should error", func(t *testing.T) {
+		t.Parallel()
+
+		c, err := fifocache.NewShardedCache(10, 0)
+		assert.True(t, check.IfNil(c))
+		assert.Equal(t, common.ErrInvalidNumberOfShards, err)
+	})
+	t.Run("invalid size in bytes should error", func(t *testing.T) {
+		t.Parallel()
+
+		c, err := fifocache.NewShardedCacheWithSizeInBytes(10, 2, 0)
+		assert.True(t, check.IfNil(c))
+		assert.Equal(t, common.ErrCacheCapacityInvalid, err)
+
+		c, err = fifocache.NewShardedCacheWithSizeInBytesAndEviction(10, 2, 0, nil)
+		assert.True(t, check.IfNil(c))
+		assert.Equal(t, common.ErrCacheCapacityInvalid, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		c, err := fifocache.NewShardedCacheWithSizeInBytes(10, 2, 100)
+		assert.False(t, check.IfNil(c))
+		assert.Nil(t, err)
+	})
+}
+
+func TestFIFOShardedCache_PutShouldEvictTheOldestItems(t *testing.T) {
+	t.Parallel()
+
+	c, _ := fifocache.NewShardedCache(10, 1)
+	for i := 0; i < 10; i++ {
+		evicted := c.Put([]byte(fmt.Sprintf("key%d", i)), i, 0)
+		assert.False(t, evicted)
+	}
+
+	evicted := c.Put([]byte("key10"), 10, 0)
+	assert.True(t, evicted)
+	assert.Equal(t, 10, c.Len())
+	assert.False(t, c.Has([]byte("key0")))
+	assert.Equal(t, []byte("key1"), c.Keys()[0])
+	assert.Equal(t, []byte("key10"), c.Keys()[9])
+	assert.Equal(t, uint64(1), c.CacheStats().NumEvictions)
+
+	// an update moves the key after the newest ones
+	evicted = c.Put([]byte("key1"), 1, 0)
+	assert.False(t, evicted)
+	assert.Equal(t, []byte("key2"), c.Keys()[0])
+	assert.Equal(t, []byte("key1"), c.Keys()[9])
+}
+
+func TestFIFOShardedCache_ShouldRespectSizeInBytes(t *testing.T) {
+	t.Parallel()
+
+	entrySize := func(key string, sizeInBytes int) uint64 {
+		return uint64(sizeInBytes + len(key) + types.EntryOverheadInBytes)
+	}
+
+	c, _ := fifocache.NewShardedCacheWithSizeInBytes(100, 1, 100)
+	for i := 0; i < 10; i++ {
+		c.Put([]byte(fmt.Sprintf("key%d", i)), i, 10)
+	}
+	assert.Equal(t, 10*entrySize("key0", 10), c.SizeInBytesContained())
+	assert.Equal(t, 10, c.Len())
+
+	// the capacity is bounded by the sizes provided by the caller
+	evicted := c.Put([]byte("key10"), 10, 25)
+	assert.True(t, evicted)
+	assert.Equal(t, 7*entrySize("key3", 10)+entrySize("key10", 25), c.SizeInBytesContained())
+	assert.False(t, c.Has([]byte("key0")))
+	assert.False(t, c.Has([]byte("key1")))
+	assert.False(t, c.Has([]byte("key2")))
+
+	has, added := c.HasOrAdd([]byte("key11"), 11, 10)
+	assert.False(t, has)
+	assert.True(t, added)
+	assert.False(t, c.Has([]byte("key3")))
+	assert.Equal(t, 6*entrySize("key4", 10)+entrySize("key10", 25)+entrySize("key11", 10), c.SizeInBytesContained())
+
+	// an item larger than the capacity is kept alone
+	c.Put([]byte("huge"), "value", 500)
+	assert.Equal(t, [][]byte{[]byte("huge")}, c.Keys())
+	assert.Equal(t, entrySize("huge", 500), c.SizeInBytesContained())
+
+	c.Remove([]byte("huge"))
+	assert.Zero(t, c.SizeInBytesContained())
+}
+
+func TestFIFOShardedCache_CacheStatsShouldReportTheEntriesSize(t *testing.T) {
+	t.Parallel()
+
+	c, _ := fifocache.NewShardedCacheWithSizeInBytes(10, 1, 2000)
+	c.Put([]byte("bytes"), []byte("value"), 1000)
+	c.Put([]byte("sizer"), &sizerStub{size: 300}, 1)
+	expectedSize := uint64(len("value") + 300 + 2*(5+types.EntryOverheadInBytes))
+	assert.Equal(t, expectedSize, c.SizeInBytesContained())
+	assert.Equal(t, expectedSize, c.CacheStats().SizeInBytes)
+
+	c.Remove([]byte("sizer"))
+	expectedSize -= uint64(300 + 5 + types.EntryOverheadInBytes)
+	assert.Equal(t, expectedSize, c.CacheStats().SizeInBytes)
+
+	c.Clear()
+	assert.Zero(t, c.CacheStats().SizeInBytes)
+}
+
+func TestFIFOShardedCache_CacheStatsShouldCountTheLookups(t *testing.T) {
+	t.Parallel()
+
+	c, _ := fifocache.NewShardedCache(10, 2)
+	c.Put([]byte("key"), "value", 0)
+
+	_, _ = c.Get([]byte("key"))
+	_, _ = c.Get([]byte("missing"))
+	_ = c.Has([]byte("key"))
+	// peeking does not count as a lookup
+	_, _ = c.Peek([]byte("key"))
+	_, _ = c.Peek([]byte("missing"))
+
+	stats := c.CacheStats()
+	assert.Equal(t, uint64(2), stats.NumHits)
+	assert.Equal(t, uint64(1), stats.NumMisses)
+}
+
+func TestFIFOShardedCache_ShardsShouldShareTheLimits(t *testing.T) {
+	t.Parallel()
+
+	c, _ := fifocache.NewShardedCacheWithSizeInBytes(20, 4, 200)
+	for i := 0; i < 1000; i++ {
+		c.Put([]byte(fmt.Sprintf("key%d", i)), i, 10)
+	}
+	assert.True(t, c.Len() <= 20)
+	// the capacity is bounded by the sizes provided by the caller
+	assert.True(t, c.Len()*10 <= 200)
+	assert.Equal(t, uint64(1000-c.Len()), c.CacheStats().NumEvictions)
+
+	// more shards than items should still respect the limits
+	c, _ = fifocache.NewShardedCache(2, 16)
+	for i := 0; i < 100; i++ {
+		c.Put([]byte(fmt.Sprintf("key%d", i)), i, 0)
+	}
+	assert.True(t, c.Len() <= 2)
+}
+
+func TestFIFOShardedCache_NegativeSizeShouldNotAdd(t *testing.T) {
+	t.Parallel()
+
+	c, _ := fifocache.NewShardedCache(10, 2)
+	evicted := c.Put([]byte("key"), "value", -1)
+	assert.False(t, evicted)
+
+	has, added := c.HasOrAdd([]byte("key"), "value", -1)
+	assert.False(t, has)
+	assert.False(t, added)
+	assert.Zero(t, c.Len())
+}
+
+func TestFIFOShardedCache_EvictionCallback(t *testing.T) {
+	t.Parallel()
+
+	evictedKeys := make([][]byte, 0)
+	evictedValues := make([]interface{}, 0)
+	var c *fifocache.FIFOShardedCache
+	c, _ = fifocache.NewShardedCacheWithSizeInBytesAndEviction(3, 1, 1000, func(key []byte, value interface{}) {
+		evictedKeys = append(evictedKeys, key)
+		evictedValues = append(evictedValues, value)
+		// the callback is called outside the cache locks
+		_ = c.Len()
+	})
+
+	for i := 0; i < 5; i++ {
+		c.Put([]byte(fmt.Sprintf("key%d", i)), i, 1)
+	}
+	assert.Equal(t, [][]byte{[]byte("key0"), []byte("key1")}, evictedKeys)
+	assert.Equal(t, []interface{}{0, 1}, evictedValues)
+
+	c.Remove([]byte("key4"))
+	c.Clear()
+	assert.Equal(t, 2, len(evictedKeys))
+
+	c.Put([]byte("key5"), 5, 1)
+	_ = c.Resize(3, 1)
+	c.Put([]byte("key6"), 6, 1)
+	assert.Equal(t, []byte("key5"), evictedKeys[2])
+	assert.Equal(t, uint64(3), c.CacheStats().NumEvictions)
+}
+
+func TestFIFOShardedCache_ResizeSizeInBytes(t *testing.T) {
+	t.Parallel()
+
+	c, _ := fifocache.NewShardedCacheWithSizeInBytes(10, 2, 100)
+	err := c.Resize(10, 0)
+	assert.Equal(t, common.ErrCacheCapacityInvalid, err)
+
+	for i := 0; i < 10; i++ {
+		c.Put([]byte(fmt.Sprintf("key%d", i)), i, 10)
+	}
+
+	err = c.Resize(10, 30)
+	assert.Nil(t, err)
+	assert.True(t, c.Len()*10 <= 30)
+	assert.True(t, c.Has([]byte("key9")))
+	assert.Equal(t, uint64(10-c.Len()), c.CacheStats().NumEvictions)
+
+	// growing to more shards than before the shrink keeps the entries, from the oldest to the newest
+	keys := c.Keys()
+	err = c.Resize(10, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, keys, c.Keys())
+}
+
+func TestFIFOShardedCache_EvictionHandlersShouldReceiveTheReason(t *testing.T) {
+	t.Parallel()
+
+	reasons := make(map[string]types.EvictionReason)
+	mut := sync.Mutex{}
+	c, _ := fifocache.NewShardedCache(2, 1)
+	c.RegisterEvictionHandler(func(key []byte, _ interface{}, reason types.EvictionReason) {
+		mut.Lock()
+		reasons[string(key)] = reason
+		mut.Unlock()
+	}, "id")
+
+	c.Put([]byte("key0"), 0, 0)
+	c.Put([]byte("key1"), 1, 0)
+	c.Put([]byte("key2"), 2, 0)
+	c.Remove([]byte("key1"))
+	c.Remove([]byte("missing"))
+	c.Clear()
+
+	assert.Equal(t, map[string]types.EvictionReason{
+		"key0": types.EvictedByCapacity,
+		"key1": types.EvictedByRemoval,
+		"key2": types.EvictedByClear,
+	}, reasons)
+
+	c.UnRegisterEvictionHandler("id")
+	c.Put([]byte("key3"), 3, 0)
+	c.Remove([]byte("key3"))
+	assert.Equal(t, 3, len(reasons))
+}
+
+type sizerStub struct {
+	size int
+}
+
+func (stub *sizerStub) Size() int {
+	return stub.size
+}
@@ -0,0 +1,163 @@
+package genericcache
+
+import (
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+var _ types.Cacher = (*cacherAdapter[[]byte])(nil)
+
+// cacherAdapter exposes a generic cache keyed by strings through the types.Cacher interface, so that the generic
+// caches can be used by the existing code. The values of another type than V are rejected.
+type cacherAdapter[V any] struct {
+	cache types.Cache[string, V]
+
+	mutAddedDataHandlers sync.RWMutex
+	mapDataHandlers      map[string]func(key []byte, value interface{})
+}
+
+// NewCacherAdapter creates a new types.Cacher backed by the provided generic cache
+func NewCacherAdapter[V any](cache types.Cache[string, V]) (*cacherAdapter[V], error) {
+	if check.IfNil(cache) {
+		return nil, common.ErrNilCacher
+	}
+
+	return &cacherAdapter[V]{
+		cache:           cache,
+		mapDataHandlers: make(map[string]func(key []byte, value interface{})),
+	}, nil
+}
+
+// Clear is used to completely clear the cache.
+func (adapter *cacherAdapter[V]) Clear() {
+	adapter.cache.Clear()
+}
+
+// Put adds a value to the cache. Returns true if an eviction occurred.
+func (adapter *cacherAdapter[V]) Put(key []byte, value interface{}, sizeInBytes int) (evicted bool) {
+	typedValue, ok := value.(V)
+	if !ok {
+		log.Error("cacherAdapter.Put", "key", key, "error", common.ErrWrongTypeAssertion)
+		return false
+	}
+
+	evicted = adapter.cache.Put(string(key), typedValue, sizeInBytes)
+	adapter.callAddedDataHandlers(key, value)
+
+	return evicted
+}
+
+// Get looks up a key's value from the cache.
+func (adapter *cacherAdapter[V]) Get(key []byte) (value interface{}, ok bool) {
+	typedValue, ok := adapter.cache.Get(string(key))
+	if !ok {
+		return nil, false
+	}
+
+	return typedValue, true
+}
+
+// Has checks if a key is in the cache, without updating the
+// recent-ness or deleting it for being stale.
+func (adapter *cacherAdapter[V]) Has(key []byte) bool {
+	return adapter.cache.Has(string(key))
+}
+
+// Peek returns the key value (or undefined if not found) without updating
+// the "recently used"-ness of the key.
+func (adapter *cacherAdapter[V]) Peek(key []byte) (value interface{}, ok bool) {
+	typedValue, ok := adapter.cache.Peek(string(key))
+	if !ok {
+		return nil, false
+	}
+
+	return typedValue, true
+}
+
+// HasOrAdd checks if a key is in the cache without updating the
+// recent-ness or deleting it for being stale, and if not adds the value.
+func (adapter *cacherAdapter[V]) HasOrAdd(key []byte, value interface{}, sizeInBytes int) (has, added bool) {
+	typedValue, ok := value.(V)
+	if !ok {
+		log.Error("cacherAdapter.HasOrAdd", "key", key, "error", common.ErrWrongTypeAssertion)
+		return false, false
+	}
+
+	has, added = adapter.cache.HasOrAdd(string(key), typedValue, sizeInBytes)
+	if added {
+		adapter.callAddedDataHandlers(key, value)
+	}
+
+	return has, added
+}
+
+// Remove removes the provided key from the cache.
+func (adapter *cacherAdapter[V]) Remove(key []byte) {
+	adapter.cache.Remove(string(key))
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest.
+func (adapter *cacherAdapter[V]) Keys() [][]byte {
+	keys := adapter.cache.Keys()
+	byteKeys := make([][]byte, len(keys))
+	for i, key := range keys {
+		byteKeys[i] = []byte(key)
+	}
+
+	return byteKeys
+}
+
+// Len returns the number of items in the cache.
+func (adapter *cacherAdapter[V]) Len() int {
+	return adapter.cache.Len()
+}
+
+// SizeInBytesContained returns the size in bytes of all contained elements
+func (adapter *cacherAdapter[V]) SizeInBytesContained() uint64 {
+	return adapter.cache.SizeInBytesContained()
+}
+
+// MaxSize returns the maximum number of items which can be stored in the cache.
+func (adapter *cacherAdapter[V]) MaxSize() int {
+	return adapter.cache.MaxSize()
+}
+
+// RegisterHandler registers a new handler to be called when a new data is added
+func (adapter *cacherAdapter[V]) RegisterHandler(handler func(key []byte, value interface{}), id string) {
+	if handler == nil {
+		log.Error("attempt to register a nil handler to a cacher object")
+		return
+	}
+
+	adapter.mutAddedDataHandlers.Lock()
+	adapter.mapDataHandlers[id] = handler
+	adapter.mutAddedDataHandlers.Unlock()
+}
+
+// UnRegisterHandler removes the handler from the list
+func (adapter *cacherAdapter[V]) UnRegisterHandler(id string) {
+	adapter.mutAddedDataHandlers.Lock()
+	delete(adapter.mapDataHandlers, id)
+	adapter.mutAddedDataHandlers.Unlock()
+}
+
+func (adapter *cacherAdapter[V]) callAddedDataHandlers(key []byte, value interface{}) {
+	adapter.mutAddedDataHandlers.RLock()
+	for _, handler := range adapter.mapDataHandlers {
+		go handler(key, value)
+	}
+	adapter.mutAddedDataHandlers.RUnlock()
+}
+
+// Close does nothing for this cacher implementation
+func (adapter *cacherAdapter[V]) Close() error {
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (adapter *cacherAdapter[V]) IsInterfaceNil() bool {
+	return adapter == nil
+}
@@ -0,0 +1,137 @@
+package genericcache_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/genericcache"
+	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createCacherAdapter(t *testing.T) types.Cacher {
+	cache, err := genericcache.NewSizedLRUCache[string, []byte](10, 1000)
+	require.Nil(t, err)
+
+	adapter, err := genericcache.NewCacherAdapter[[]byte](cache)
+	require.Nil(t, err)
+
+	return adapter
+}
+
+func TestNewCacherAdapter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil cache should error", func(t *testing.T) {
+		t.Parallel()
+
+		adapter, err := genericcache.NewCacherAdapter[[]byte](nil)
+		assert.Nil(t, adapter)
+		assert.Equal(t, common.ErrNilCacher, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		adapter := createCacherAdapter(t)
+		assert.False(t, adapter.IsInterfaceNil())
+		assert.Equal(t, 10, adapter.MaxSize())
+		assert.Nil(t, adapter.Close())
+	})
+}
+
+func TestCacherAdapter_PutGet(t *testing.T) {
+	t.Parallel()
+
+	adapter := createCacherAdapter(t)
+
+	evicted := adapter.Put([]byte("key"), []byte("value"), 5)
+	assert.False(t, evicted)
+	assert.True(t, adapter.Has([]byte("key")))
+	assert.Equal(t, uint64(len("value")+len("key")+types.EntryOverheadInBytes), adapter.SizeInBytesContained())
+
+	value, ok := adapter.Get([]byte("key"))
+	assert.True(t, ok)
+	assert.Equal(t, []byte("value"), value)
+
+	value, ok = adapter.Peek([]byte("key"))
+	assert.True(t, ok)
+	assert.Equal(t, []byte("value"), value)
+
+	value, ok = adapter.Get([]byte("missing"))
+	assert.False(t, ok)
+	assert.Nil(t, value)
+	value, ok = adapter.Peek([]byte("missing"))
+	assert.False(t, ok)
+	assert.Nil(t, value)
+}
+
+func TestCacherAdapter_WrongValueTypeShouldNotAdd(t *testing.T) {
+	t.Parallel()
+
+	adapter := createCacherAdapter(t)
+
+	evicted := adapter.Put([]byte("key"), "not a byte slice", 5)
+	assert.False(t, evicted)
+	assert.False(t, adapter.Has([]byte("key")))
+
+	has, added := adapter.HasOrAdd([]byte("key"), 7, 5)
+	assert.False(t, has)
+	assert.False(t, added)
+	assert.Equal(t, 0, adapter.Len())
+}
+
+func TestCacherAdapter_HasOrAddKeysRemoveAndClear(t *testing.T) {
+	t.Parallel()
+
+	adapter := createCacherAdapter(t)
+
+	has, added := adapter.HasOrAdd([]byte("a"), []byte("1"), 1)
+	assert.False(t, has)
+	assert.True(t, added)
+	has, added = adapter.HasOrAdd([]byte("a"), []byte("2"), 1)
+	assert.True(t, has)
+	assert.False(t, added)
+
+	adapter.Put([]byte("b"), []byte("2"), 1)
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("b")}, adapter.Keys())
+
+	adapter.Remove([]byte("a"))
+	assert.Equal(t, 1, adapter.Len())
+
+	adapter.Clear()
+	assert.Equal(t, 0, adapter.Len())
+}
+
+func TestCacherAdapter_RegisterHandlerShouldBeCalledOnAdd(t *testing.T) {
+	t.Parallel()
+
+	adapter := createCacherAdapter(t)
+	adapter.RegisterHandler(nil, "nil")
+
+	mut := sync.Mutex{}
+	addedKeys := make([]string, 0)
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	adapter.RegisterHandler(func(key []byte, value interface{}) {
+		mut.Lock()
+		addedKeys = append(addedKeys, string(key))
+		mut.Unlock()
+		wg.Done()
+	}, "id")
+
+	adapter.Put([]byte("a"), []byte("1"), 1)
+	_, _ = adapter.HasOrAdd([]byte("b"), []byte("2"), 1)
+	_, _ = adapter.HasOrAdd([]byte("b"), []byte("2"), 1)
+	wg.Wait()
+
+	adapter.UnRegisterHandler("id")
+	adapter.Put([]byte("c"), []byte("3"), 1)
+	time.Sleep(50 * time.Millisecond)
+
+	mut.Lock()
+	defer mut.Unlock()
+	assert.ElementsMatch(t, []string{"a", "b"}, addedKeys)
+}
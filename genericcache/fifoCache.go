@@ -0,0 +1,324 @@
+package genericcache
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+var _ types.Cache[string, []byte] = (*fifoCache[string, []byte])(nil)
+
+// fifoShard holds a part of the keys in insertion order
+type fifoShard[K comparable, V any] struct {
+	mut                sync.Mutex
+	items              map[K]*node[K, V]
+	queue              linkedList[K, V]
+	sizeInBytes        int64
+	entriesSizeInBytes int64
+	maxNumItems        int
+	maxSizeInBytes     int64
+}
+
+// fifoCache implements a thread safe, type safe FIFO cache, sharded by the hash of the keys. It is bounded by the
+// number of items and, optionally, by the size in bytes of the items.
+type fifoCache[K comparable, V any] struct {
+	shards         []*fifoShard[K, V]
+	hasher         KeyHasher[K]
+	maxSize        int
+	maxSizeInBytes int64
+	lastSequence   uint64
+}
+
+// NewFIFOCache creates a new FIFO cache bounded only by the number of items. The sizes in bytes of the items are only
+// reported.
+func NewFIFOCache[K comparable, V any](size int, shards int, hasher KeyHasher[K]) (*fifoCache[K, V], error) {
+	return newFIFOCache[K, V](size, shards, 0, hasher)
+}
+
+// NewSizedFIFOCache creates a new FIFO cache bounded both by the number of items and by their size in bytes
+func NewSizedFIFOCache[K comparable, V any](size int, shards int, sizeInBytes int64, hasher KeyHasher[K]) (*fifoCache[K, V], error) {
+	if sizeInBytes < 1 {
+		return nil, common.ErrCacheCapacityInvalid
+	}
+
+	return newFIFOCache[K, V](size, shards, sizeInBytes, hasher)
+}
+
+func newFIFOCache[K comparable, V any](size int, shards int, sizeInBytes int64, hasher KeyHasher[K]) (*fifoCache[K, V], error) {
+	if size < 1 {
+		return nil, common.ErrCacheSizeInvalid
+	}
+	if shards < 1 {
+		return nil, common.ErrInvalidNumberOfShards
+	}
+	if hasher == nil {
+		return nil, common.ErrNilKeyHasher
+	}
+
+	c := &fifoCache[K, V]{
+		hasher:         hasher,
+		maxSize:        size,
+		maxSizeInBytes: sizeInBytes,
+	}
+	c.shards = c.createShards(shards)
+
+	return c, nil
+}
+
+// createShards splits the limits between the shards, the remainders going to the first shards. There are no
+// more shards than items or bytes, so that each shard can hold at least one item within the limits.
+func (c *fifoCache[K, V]) createShards(numShards int) []*fifoShard[K, V] {
+	if numShards > c.maxSize {
+		numShards = c.maxSize
+	}
+	isBoundedInBytes := c.maxSizeInBytes > 0
+	if isBoundedInBytes && int64(numShards) > c.maxSizeInBytes {
+		numShards = int(c.maxSizeInBytes)
+	}
+
+	shards := make([]*fifoShard[K, V], numShards)
+	for i := range shards {
+		maxNumItems := c.maxSize / numShards
+		if i < c.maxSize%numShards {
+			maxNumItems++
+		}
+
+		var maxSizeInBytes int64
+		if isBoundedInBytes {
+			maxSizeInBytes = c.maxSizeInBytes / int64(numShards)
+			if int64(i) < c.maxSizeInBytes%int64(numShards) {
+				maxSizeInBytes++
+			}
+		}
+
+		shards[i] = &fifoShard[K, V]{
+			items:          make(map[K]*node[K, V]),
+			maxNumItems:    maxNumItems,
+			maxSizeInBytes: maxSizeInBytes,
+		}
+	}
+
+	return shards
+}
+
+func (c *fifoCache[K, V]) getShard(key K) *fifoShard[K, V] {
+	return c.shards[c.hasher(key)%uint32(len(c.shards))]
+}
+
+func (c *fifoCache[K, V]) newNode(key K, value V, sizeInBytes int) *node[K, V] {
+	return &node[K, V]{
+		key:       key,
+		value:     value,
+		size:      int64(sizeInBytes),
+		entrySize: computeEntrySize(key, value, sizeInBytes),
+		sequence:  atomic.AddUint64(&c.lastSequence, 1),
+	}
+}
+
+// Clear is used to completely clear the cache.
+func (c *fifoCache[K, V]) Clear() {
+	for _, shard := range c.shards {
+		shard.mut.Lock()
+		shard.items = make(map[K]*node[K, V])
+		shard.queue = linkedList[K, V]{}
+		shard.sizeInBytes = 0
+		shard.entriesSizeInBytes = 0
+		shard.mut.Unlock()
+	}
+}
+
+// Put adds or replaces a value, an update moving the key at the end of the queue. Returns true if an eviction occurred.
+func (c *fifoCache[K, V]) Put(key K, value V, sizeInBytes int) (evicted bool) {
+	if sizeInBytes < 0 {
+		log.Error("generic FIFO cache put error",
+			"key", fmt.Sprintf("%v", key),
+			"error", common.ErrNegativeSizeInBytes,
+		)
+
+		return false
+	}
+
+	n := c.newNode(key, value, sizeInBytes)
+	shard := c.getShard(key)
+
+	shard.mut.Lock()
+	defer shard.mut.Unlock()
+
+	existing, ok := shard.items[key]
+	if ok {
+		shard.removeNode(existing)
+	}
+	shard.pushBack(n)
+
+	return shard.evictIfNeeded() > 0
+}
+
+// Get looks up a key's value from the cache.
+func (c *fifoCache[K, V]) Get(key K) (value V, ok bool) {
+	return c.Peek(key)
+}
+
+// Has checks if a key is in the cache.
+func (c *fifoCache[K, V]) Has(key K) bool {
+	shard := c.getShard(key)
+
+	shard.mut.Lock()
+	defer shard.mut.Unlock()
+
+	_, ok := shard.items[key]
+
+	return ok
+}
+
+// Peek returns the key value (or the zero value if not found)
+func (c *fifoCache[K, V]) Peek(key K) (value V, ok bool) {
+	shard := c.getShard(key)
+
+	shard.mut.Lock()
+	defer shard.mut.Unlock()
+
+	n, ok := shard.items[key]
+	if !ok {
+		return value, false
+	}
+
+	return n.value, true
+}
+
+// HasOrAdd checks if a key is in the cache, and if not adds the value.
+func (c *fifoCache[K, V]) HasOrAdd(key K, value V, sizeInBytes int) (has, added bool) {
+	if sizeInBytes < 0 {
+		log.Error("generic FIFO cache has or add error",
+			"key", fmt.Sprintf("%v", key),
+			"error", common.ErrNegativeSizeInBytes,
+		)
+
+		return false, false
+	}
+
+	shard := c.getShard(key)
+
+	shard.mut.Lock()
+	defer shard.mut.Unlock()
+
+	_, ok := shard.items[key]
+	if ok {
+		return true, false
+	}
+
+	shard.pushBack(c.newNode(key, value, sizeInBytes))
+	_ = shard.evictIfNeeded()
+
+	return false, true
+}
+
+// Remove removes the provided key from the cache.
+func (c *fifoCache[K, V]) Remove(key K) {
+	shard := c.getShard(key)
+
+	shard.mut.Lock()
+	defer shard.mut.Unlock()
+
+	n, ok := shard.items[key]
+	if ok {
+		shard.removeNode(n)
+	}
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest.
+func (c *fifoCache[K, V]) Keys() []K {
+	nodes := make([]*node[K, V], 0, c.Len())
+	for _, shard := range c.shards {
+		shard.mut.Lock()
+		for n := shard.queue.front; n != nil; n = n.next {
+			nodes = append(nodes, n)
+		}
+		shard.mut.Unlock()
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].sequence < nodes[j].sequence
+	})
+
+	keys := make([]K, len(nodes))
+	for i, n := range nodes {
+		keys[i] = n.key
+	}
+
+	return keys
+}
+
+// Len returns the number of items in the cache.
+func (c *fifoCache[K, V]) Len() int {
+	numItems := 0
+	for _, shard := range c.shards {
+		shard.mut.Lock()
+		numItems += shard.queue.length
+		shard.mut.Unlock()
+	}
+
+	return numItems
+}
+
+// SizeInBytesContained returns the size in bytes of all contained items, including the keys and the overhead of the
+// entries. The capacity is bounded by the sizes provided by the callers.
+func (c *fifoCache[K, V]) SizeInBytesContained() uint64 {
+	sizeInBytes := int64(0)
+	for _, shard := range c.shards {
+		shard.mut.Lock()
+		sizeInBytes += shard.entriesSizeInBytes
+		shard.mut.Unlock()
+	}
+
+	return uint64(sizeInBytes)
+}
+
+// MaxSize returns the maximum number of items which can be stored in the cache.
+func (c *fifoCache[K, V]) MaxSize() int {
+	return c.maxSize
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (c *fifoCache[K, V]) IsInterfaceNil() bool {
+	return c == nil
+}
+
+func (shard *fifoShard[K, V]) pushBack(n *node[K, V]) {
+	shard.queue.pushBack(n)
+	shard.items[n.key] = n
+	shard.sizeInBytes += n.size
+	shard.entriesSizeInBytes += n.entrySize
+}
+
+func (shard *fifoShard[K, V]) removeNode(n *node[K, V]) {
+	shard.queue.remove(n)
+	delete(shard.items, n.key)
+	shard.sizeInBytes -= n.size
+	shard.entriesSizeInBytes -= n.entrySize
+}
+
+func (shard *fifoShard[K, V]) shouldEvict() bool {
+	if shard.queue.length <= 1 {
+		// keep at least one element, no matter how large it is
+		return false
+	}
+
+	isBoundedInBytes := shard.maxSizeInBytes > 0
+
+	return shard.queue.length > shard.maxNumItems || (isBoundedInBytes && shard.sizeInBytes > shard.maxSizeInBytes)
+}
+
+// evictIfNeeded removes the oldest items while the limits are exceeded, returning their number
+func (shard *fifoShard[K, V]) evictIfNeeded() int {
+	numEvicted := 0
+	for shard.shouldEvict() {
+		shard.removeNode(shard.queue.front)
+		numEvicted++
+	}
+
+	return numEvicted
+}
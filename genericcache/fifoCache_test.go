@@ -0,0 +1,163 @@
+package genericcache_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/genericcache"
+	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFIFOCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid size should error", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := genericcache.NewFIFOCache[string, int](0, 2, genericcache.StringHasher)
+		assert.Nil(t, cache)
+		assert.Equal(t, common.ErrCacheSizeInvalid, err)
+	})
+	t.Run("invalid number of shards should error", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := genericcache.NewFIFOCache[string, int](10, 0, genericcache.StringHasher)
+		assert.Nil(t, cache)
+		assert.Equal(t, common.ErrInvalidNumberOfShards, err)
+	})
+	t.Run("nil hasher should error", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := genericcache.NewFIFOCache[string, int](10, 2, nil)
+		assert.Nil(t, cache)
+		assert.Equal(t, common.ErrNilKeyHasher, err)
+	})
+	t.Run("invalid size in bytes should error", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := genericcache.NewSizedFIFOCache[string, int](10, 2, 0, genericcache.StringHasher)
+		assert.Nil(t, cache)
+		assert.Equal(t, common.ErrCacheCapacityInvalid, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := genericcache.NewSizedFIFOCache[string, int](10, 2, 100, genericcache.StringHasher)
+		assert.Nil(t, err)
+		assert.False(t, cache.IsInterfaceNil())
+		assert.Equal(t, 10, cache.MaxSize())
+	})
+}
+
+func TestFIFOCache_PutShouldEvictOldest(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := genericcache.NewFIFOCache[string, int](3, 1, genericcache.StringHasher)
+	cache.Put("a", 1, 0)
+	cache.Put("b", 2, 0)
+	cache.Put("c", 3, 0)
+
+	// reads do not change the order of a FIFO cache
+	_, _ = cache.Get("a")
+	evicted := cache.Put("d", 4, 0)
+	assert.True(t, evicted)
+	assert.Equal(t, []string{"b", "c", "d"}, cache.Keys())
+
+	// an update moves the key at the end of the queue
+	cache.Put("b", 5, 0)
+	cache.Put("e", 6, 0)
+	assert.Equal(t, []string{"d", "b", "e"}, cache.Keys())
+
+	value, ok := cache.Peek("b")
+	assert.True(t, ok)
+	assert.Equal(t, 5, value)
+}
+
+func TestFIFOCache_SizedShouldEvictOnBytes(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := genericcache.NewSizedFIFOCache[string, int](10, 1, 100, genericcache.StringHasher)
+	cache.Put("a", 1, 60)
+	evicted := cache.Put("b", 2, 60)
+	assert.True(t, evicted)
+	assert.Equal(t, []string{"b"}, cache.Keys())
+	assert.Equal(t, uint64(60+len("b")+types.EntryOverheadInBytes), cache.SizeInBytesContained())
+
+	evicted = cache.Put("c", 3, -1)
+	assert.False(t, evicted)
+	assert.False(t, cache.Has("c"))
+}
+
+func TestFIFOCache_KeysShouldBeInInsertionOrderAcrossShards(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := genericcache.NewFIFOCache[string, int](100, 4, genericcache.StringHasher)
+	expectedKeys := make([]string, 0, 20)
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("key%d", i)
+		cache.Put(key, i, 0)
+		expectedKeys = append(expectedKeys, key)
+	}
+
+	assert.Equal(t, expectedKeys, cache.Keys())
+	assert.Equal(t, 20, cache.Len())
+}
+
+func TestFIFOCache_HasOrAddRemoveAndClear(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := genericcache.NewSizedFIFOCache[string, int](10, 2, 100, genericcache.StringHasher)
+
+	has, added := cache.HasOrAdd("a", 1, 10)
+	assert.False(t, has)
+	assert.True(t, added)
+	has, added = cache.HasOrAdd("a", 2, 10)
+	assert.True(t, has)
+	assert.False(t, added)
+	has, added = cache.HasOrAdd("b", 2, -1)
+	assert.False(t, has)
+	assert.False(t, added)
+
+	cache.Put("c", 3, 20)
+	cache.Remove("a")
+	assert.Equal(t, []string{"c"}, cache.Keys())
+	assert.Equal(t, uint64(20+len("c")+types.EntryOverheadInBytes), cache.SizeInBytesContained())
+
+	cache.Clear()
+	assert.Equal(t, 0, cache.Len())
+	assert.Equal(t, uint64(0), cache.SizeInBytesContained())
+}
+
+func TestFIFOCache_ConcurrentOperations(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := genericcache.NewSizedFIFOCache[string, int](100, 4, 1000, genericcache.StringHasher)
+	numOperations := 1000
+	wg := sync.WaitGroup{}
+	wg.Add(numOperations)
+	for i := 0; i < numOperations; i++ {
+		go func(idx int) {
+			defer wg.Done()
+
+			key := fmt.Sprintf("key%d", idx%150)
+			switch idx % 5 {
+			case 0:
+				cache.Put(key, idx, 5)
+			case 1:
+				_, _ = cache.Get(key)
+			case 2:
+				_, _ = cache.HasOrAdd(key, idx, 5)
+			case 3:
+				cache.Remove(key)
+			default:
+				_ = cache.Keys()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, cache.Len(), 100)
+}
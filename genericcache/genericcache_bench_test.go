@@ -0,0 +1,85 @@
+package genericcache_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/multiversx/mx-chain-storage-go/genericcache"
+	"github.com/multiversx/mx-chain-storage-go/immunitycache"
+	"github.com/multiversx/mx-chain-storage-go/lrucache"
+)
+
+const benchmarkNumKeys = 1000
+
+func createBenchmarkKeys() []string {
+	keys := make([]string, benchmarkNumKeys)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key%d", i)
+	}
+
+	return keys
+}
+
+func BenchmarkLRUCache_Get(b *testing.B) {
+	keys := createBenchmarkKeys()
+	cache, _ := genericcache.NewLRUCache[string, []byte](benchmarkNumKeys)
+	for _, key := range keys {
+		cache.Put(key, []byte(key), len(key))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		value, _ := cache.Get(keys[i%benchmarkNumKeys])
+		_ = value
+	}
+}
+
+func BenchmarkLRUCacher_Get(b *testing.B) {
+	keys := createBenchmarkKeys()
+	byteKeys := make([][]byte, len(keys))
+	cacher, _ := lrucache.NewCache(benchmarkNumKeys)
+	for i, key := range keys {
+		byteKeys[i] = []byte(key)
+		cacher.Put(byteKeys[i], []byte(key), len(key))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		value, _ := cacher.Get(byteKeys[i%benchmarkNumKeys])
+		_ = value.([]byte)
+	}
+}
+
+func BenchmarkImmunityCache_Get(b *testing.B) {
+	keys := createBenchmarkKeys()
+	cache, _ := genericcache.NewImmunityCache[[]byte](createImmunityCacheConfig(4, benchmarkNumKeys))
+	for _, key := range keys {
+		cache.Put(key, []byte(key), len(key))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		value, _ := cache.Get(keys[i%benchmarkNumKeys])
+		_ = value
+	}
+}
+
+func BenchmarkImmunityCacher_Get(b *testing.B) {
+	keys := createBenchmarkKeys()
+	byteKeys := make([][]byte, len(keys))
+	cacher, _ := immunitycache.NewImmunityCache(createImmunityCacheConfig(4, benchmarkNumKeys))
+	for i, key := range keys {
+		byteKeys[i] = []byte(key)
+		cacher.Put(byteKeys[i], []byte(key), len(key))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		value, _ := cacher.Get(byteKeys[i%benchmarkNumKeys])
+		_ = value.([]byte)
+	}
+}
@@ -0,0 +1,18 @@
+package genericcache
+
+// KeyHasher computes the hash used to distribute the keys between the shards (or chunks) of a cache
+type KeyHasher[K comparable] func(key K) uint32
+
+const fnvOffset32 = uint32(2166136261)
+const fnvPrime32 = uint32(16777619)
+
+// StringHasher implements the FNV-1a hash function for 32 bits, without allocations
+func StringHasher(key string) uint32 {
+	hash := fnvOffset32
+	for i := 0; i < len(key); i++ {
+		hash ^= uint32(key[i])
+		hash *= fnvPrime32
+	}
+
+	return hash
+}
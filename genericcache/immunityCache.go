@@ -0,0 +1,140 @@
+package genericcache
+
+import (
+	"github.com/multiversx/mx-chain-storage-go/immunitycache"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+var _ types.Cache[string, []byte] = (*immunityCache[[]byte])(nil)
+
+// immunityCache is a compatibility shim offering the typed API over the immunitycache.ImmunityCache, which holds
+// the items. Unlike the other generic caches, it still converts the keys to bytes, allocating them when stored, and
+// type-asserts the values it returns.
+type immunityCache[V any] struct {
+	cache *immunitycache.ImmunityCache
+}
+
+// NewImmunityCache creates a new immunity cache keyed by strings, holding values of type V
+func NewImmunityCache[V any](config immunitycache.CacheConfig) (*immunityCache[V], error) {
+	cache, err := immunitycache.NewImmunityCache(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &immunityCache[V]{
+		cache: cache,
+	}, nil
+}
+
+// ImmunizeKeys marks items as immune to eviction, the keys not yet contained being immunized when added
+func (c *immunityCache[V]) ImmunizeKeys(keys []string) (numNowTotal, numFutureTotal int) {
+	return c.cache.ImmunizeKeys(stringsToBytes(keys))
+}
+
+// ImmunizeKeysWithLevel marks items as immune to eviction with the provided level, the keys not yet contained being
+// immunized when added
+func (c *immunityCache[V]) ImmunizeKeysWithLevel(keys []string, level immunitycache.ImmunityLevel) (numNowTotal, numFutureTotal int) {
+	return c.cache.ImmunizeKeysWithLevel(stringsToBytes(keys), level)
+}
+
+// RevokeImmunity revokes the immunity of the provided keys, returning the number of revoked keys
+func (c *immunityCache[V]) RevokeImmunity(keys []string) int {
+	return c.cache.RevokeImmunity(stringsToBytes(keys))
+}
+
+// Clear clears the cache, the immune keys included
+func (c *immunityCache[V]) Clear() {
+	c.cache.Clear()
+}
+
+// Put adds an item in the cache, if not already contained. Returns true if an eviction occurred.
+func (c *immunityCache[V]) Put(key string, value V, sizeInBytes int) (evicted bool) {
+	return c.cache.Put([]byte(key), value, sizeInBytes)
+}
+
+// HasOrAdd adds an item in the cache, if not already contained
+func (c *immunityCache[V]) HasOrAdd(key string, value V, sizeInBytes int) (has, added bool) {
+	return c.cache.HasOrAdd([]byte(key), value, sizeInBytes)
+}
+
+// Get gets an item by key
+func (c *immunityCache[V]) Get(key string) (value V, ok bool) {
+	return c.Peek(key)
+}
+
+// Has checks is an item exists
+func (c *immunityCache[V]) Has(key string) bool {
+	return c.cache.Has([]byte(key))
+}
+
+// Peek gets an item by key
+func (c *immunityCache[V]) Peek(key string) (value V, ok bool) {
+	untypedValue, ok := c.cache.Peek([]byte(key))
+	if !ok {
+		return value, false
+	}
+
+	value, ok = untypedValue.(V)
+
+	return value, ok
+}
+
+// Remove removes an item, forgetting its immunity as well
+func (c *immunityCache[V]) Remove(key string) {
+	c.cache.Remove([]byte(key))
+}
+
+// RemoveWithResult removes an item, returning whether it was contained
+func (c *immunityCache[V]) RemoveWithResult(key string) bool {
+	return c.cache.RemoveWithResult([]byte(key))
+}
+
+// Keys returns all keys
+func (c *immunityCache[V]) Keys() []string {
+	byteKeys := c.cache.Keys()
+	keys := make([]string, len(byteKeys))
+	for i, key := range byteKeys {
+		keys[i] = string(key)
+	}
+
+	return keys
+}
+
+// Len returns the number of items in the cache
+func (c *immunityCache[V]) Len() int {
+	return c.cache.Len()
+}
+
+// CountImmune returns the number of immunized (current or future) keys
+func (c *immunityCache[V]) CountImmune() int {
+	return c.cache.CountImmune()
+}
+
+// SizeInBytesContained returns the size in bytes of all contained items
+func (c *immunityCache[V]) SizeInBytesContained() uint64 {
+	return c.cache.SizeInBytesContained()
+}
+
+// MaxSize returns the capacity of the cache
+func (c *immunityCache[V]) MaxSize() int {
+	return c.cache.MaxSize()
+}
+
+// Close closes the underlying immunity cache
+func (c *immunityCache[V]) Close() error {
+	return c.cache.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (c *immunityCache[V]) IsInterfaceNil() bool {
+	return c == nil
+}
+
+func stringsToBytes(keys []string) [][]byte {
+	byteKeys := make([][]byte, len(keys))
+	for i, key := range keys {
+		byteKeys[i] = []byte(key)
+	}
+
+	return byteKeys
+}
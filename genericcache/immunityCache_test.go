@@ -0,0 +1,160 @@
+package genericcache_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/genericcache"
+	"github.com/multiversx/mx-chain-storage-go/immunitycache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createImmunityCacheConfig(numChunks uint32, maxNumItems uint32) immunitycache.CacheConfig {
+	return immunitycache.CacheConfig{
+		Name:                        "test",
+		NumChunks:                   numChunks,
+		MaxNumItems:                 maxNumItems,
+		MaxNumBytes:                 maxNumItems * 1000,
+		NumItemsToPreemptivelyEvict: numChunks,
+	}
+}
+
+func TestNewImmunityCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid config should error", func(t *testing.T) {
+		t.Parallel()
+
+		config := createImmunityCacheConfig(1, 4)
+		config.Name = ""
+		cache, err := genericcache.NewImmunityCache[int](config)
+		assert.Nil(t, cache)
+		assert.True(t, errors.Is(err, common.ErrInvalidConfig))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := genericcache.NewImmunityCache[int](createImmunityCacheConfig(4, 16))
+		assert.Nil(t, err)
+		assert.False(t, cache.IsInterfaceNil())
+		assert.Equal(t, 16, cache.MaxSize())
+		assert.Nil(t, cache.Close())
+	})
+}
+
+func TestImmunityCache_ImmuneItemsShouldNotBeEvicted(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := genericcache.NewImmunityCache[int](createImmunityCacheConfig(1, 4))
+	cache.Put("a", 1, 1)
+	cache.Put("b", 2, 1)
+
+	numNow, numFuture := cache.ImmunizeKeys([]string{"a", "c"})
+	assert.Equal(t, 1, numNow)
+	assert.Equal(t, 1, numFuture)
+	assert.Equal(t, 2, cache.CountImmune())
+
+	cache.Put("c", 3, 1)
+	cache.Put("d", 4, 1)
+	evicted := cache.Put("e", 5, 1)
+	assert.True(t, evicted)
+
+	// "b" is the oldest item which is not immune
+	assert.ElementsMatch(t, []string{"a", "c", "d", "e"}, cache.Keys())
+}
+
+func TestImmunityCache_AddShouldFailWhenOnlyImmuneItemsAreLeft(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := genericcache.NewImmunityCache[int](createImmunityCacheConfig(1, 4))
+	keys := []string{"a", "b", "c", "d"}
+	_, _ = cache.ImmunizeKeys(keys)
+	for i, key := range keys {
+		cache.Put(key, i, 1)
+	}
+
+	has, added := cache.HasOrAdd("e", 5, 1)
+	assert.False(t, has)
+	assert.False(t, added)
+	assert.ElementsMatch(t, keys, cache.Keys())
+}
+
+func TestImmunityCache_ImmunizeKeysOverCapacityShouldNotImmunize(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := genericcache.NewImmunityCache[int](createImmunityCacheConfig(1, 4))
+
+	numNow, numFuture := cache.ImmunizeKeys([]string{"a", "b", "c", "d", "e"})
+	assert.Equal(t, 0, numNow)
+	assert.Equal(t, 0, numFuture)
+	assert.Equal(t, 0, cache.CountImmune())
+}
+
+func TestImmunityCache_PutShouldNotReplaceExisting(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := genericcache.NewImmunityCache[[]byte](createImmunityCacheConfig(2, 8))
+	cache.Put("a", []byte("first"), 5)
+	sizeInBytes := cache.SizeInBytesContained()
+	cache.Put("a", []byte("second"), 6)
+
+	value, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("first"), value)
+	assert.Equal(t, sizeInBytes, cache.SizeInBytesContained())
+}
+
+func TestImmunityCache_ProtectedItemsShouldBeEvictedAfterTheOthers(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := genericcache.NewImmunityCache[int](createImmunityCacheConfig(1, 4))
+	_, _ = cache.ImmunizeKeysWithLevel([]string{"a", "b", "c"}, immunitycache.LevelProtected)
+	cache.Put("a", 1, 1)
+	cache.Put("b", 2, 1)
+	cache.Put("c", 3, 1)
+	cache.Put("d", 4, 1)
+
+	// "d" is the only item without immunity
+	cache.Put("e", 5, 1)
+	assert.ElementsMatch(t, []string{"a", "b", "c", "e"}, cache.Keys())
+
+	// the oldest protected item is evicted when there is no other choice
+	_, _ = cache.ImmunizeKeys([]string{"e"})
+	cache.Put("f", 6, 1)
+	assert.ElementsMatch(t, []string{"b", "c", "e", "f"}, cache.Keys())
+
+	assert.Equal(t, 1, cache.RevokeImmunity([]string{"c"}))
+	assert.Equal(t, 2, cache.CountImmune())
+}
+
+func TestImmunityCache_RemoveShouldForgetImmunity(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := genericcache.NewImmunityCache[int](createImmunityCacheConfig(1, 4))
+	cache.Put("a", 1, 1)
+	_, _ = cache.ImmunizeKeys([]string{"a", "b"})
+
+	assert.True(t, cache.RemoveWithResult("a"))
+	assert.False(t, cache.RemoveWithResult("b"))
+	assert.Equal(t, 0, cache.CountImmune())
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestImmunityCache_Clear(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := genericcache.NewImmunityCache[int](createImmunityCacheConfig(4, 16))
+	for i := 0; i < 10; i++ {
+		cache.Put(fmt.Sprintf("key%d", i), i, 1)
+	}
+	_, _ = cache.ImmunizeKeys([]string{"key0"})
+	require.Equal(t, 10, cache.Len())
+
+	cache.Clear()
+	assert.Equal(t, 0, cache.Len())
+	assert.Equal(t, 0, cache.CountImmune())
+	assert.Equal(t, uint64(0), cache.SizeInBytesContained())
+}
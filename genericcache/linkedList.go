@@ -0,0 +1,88 @@
+package genericcache
+
+import "github.com/multiversx/mx-chain-storage-go/types"
+
+// node is an element of the intrusive doubly linked list used by the generic caches. Holding the typed key and
+// value directly in the node avoids the boxing into interface{} done by container/list.
+type node[K comparable, V any] struct {
+	key   K
+	value V
+	size  int64
+	// entrySize is the reported size, including the key and the overhead of the entry
+	entrySize int64
+	// sequence records the insertion order across the shards of a FIFO cache
+	sequence uint64
+	prev     *node[K, V]
+	next     *node[K, V]
+}
+
+// computeEntrySize is the counterpart of types.ComputeEntrySizeInBytes for the typed keys, only the length of the
+// string keys being accounted
+func computeEntrySize[K comparable, V any](key K, value V, sizeInBytes int) int64 {
+	keyLength := 0
+	stringKey, ok := any(key).(string)
+	if ok {
+		keyLength = len(stringKey)
+	}
+
+	// the byte slice values are checked apart, as boxing them for the Sizer check would allocate on every put
+	bytesValue, ok := any(value).([]byte)
+	if ok {
+		return int64(len(bytesValue) + keyLength + types.EntryOverheadInBytes)
+	}
+
+	return int64(types.ComputeValueSizeInBytes(value, sizeInBytes) + keyLength + types.EntryOverheadInBytes)
+}
+
+// linkedList keeps the nodes from the oldest (front) to the newest (back). It is not concurrent safe.
+type linkedList[K comparable, V any] struct {
+	front  *node[K, V]
+	back   *node[K, V]
+	length int
+}
+
+func (l *linkedList[K, V]) pushBack(n *node[K, V]) {
+	n.prev = l.back
+	n.next = nil
+	if l.back != nil {
+		l.back.next = n
+	} else {
+		l.front = n
+	}
+	l.back = n
+	l.length++
+}
+
+func (l *linkedList[K, V]) remove(n *node[K, V]) {
+	if n.prev != nil {
+		n.prev.next = n.next
+	} else {
+		l.front = n.next
+	}
+	if n.next != nil {
+		n.next.prev = n.prev
+	} else {
+		l.back = n.prev
+	}
+	n.prev = nil
+	n.next = nil
+	l.length--
+}
+
+func (l *linkedList[K, V]) moveToBack(n *node[K, V]) {
+	if l.back == n {
+		return
+	}
+
+	l.remove(n)
+	l.pushBack(n)
+}
+
+func (l *linkedList[K, V]) keys() []K {
+	keys := make([]K, 0, l.length)
+	for n := l.front; n != nil; n = n.next {
+		keys = append(keys, n.key)
+	}
+
+	return keys
+}
@@ -0,0 +1,277 @@
+package genericcache
+
+import (
+	"fmt"
+	"sync"
+
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+var _ types.Cache[string, []byte] = (*lruCache[string, []byte])(nil)
+
+var log = logger.GetOrCreate("storage/genericcache")
+
+// lruCache implements a thread safe, type safe LRU cache bounded by the number of items and, optionally,
+// by the size in bytes of the items
+type lruCache[K comparable, V any] struct {
+	mut   sync.Mutex
+	items map[K]*node[K, V]
+	// the front of the list holds the least recently used item
+	evictList          linkedList[K, V]
+	sizeInBytes        int64
+	entriesSizeInBytes int64
+	maxSize            int
+	maxSizeInBytes     int64
+}
+
+// NewLRUCache creates a new LRU cache bounded only by the number of items. The sizes in bytes of the items are only
+// reported.
+func NewLRUCache[K comparable, V any](size int) (*lruCache[K, V], error) {
+	if size < 1 {
+		return nil, common.ErrCacheSizeInvalid
+	}
+
+	return newLRUCache[K, V](size, 0), nil
+}
+
+// NewSizedLRUCache creates a new LRU cache bounded both by the number of items and by their size in bytes
+func NewSizedLRUCache[K comparable, V any](size int, sizeInBytes int64) (*lruCache[K, V], error) {
+	if size < 1 {
+		return nil, common.ErrCacheSizeInvalid
+	}
+	if sizeInBytes < 1 {
+		return nil, common.ErrCacheCapacityInvalid
+	}
+
+	return newLRUCache[K, V](size, sizeInBytes), nil
+}
+
+func newLRUCache[K comparable, V any](size int, sizeInBytes int64) *lruCache[K, V] {
+	return &lruCache[K, V]{
+		items:          make(map[K]*node[K, V]),
+		maxSize:        size,
+		maxSizeInBytes: sizeInBytes,
+	}
+}
+
+// Clear is used to completely clear the cache.
+func (c *lruCache[K, V]) Clear() {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	c.items = make(map[K]*node[K, V])
+	c.evictList = linkedList[K, V]{}
+	c.sizeInBytes = 0
+	c.entriesSizeInBytes = 0
+}
+
+// Put adds a value to the cache. Returns true if an eviction occurred.
+func (c *lruCache[K, V]) Put(key K, value V, sizeInBytes int) (evicted bool) {
+	if sizeInBytes < 0 {
+		log.Error("generic LRU cache put error",
+			"key", fmt.Sprintf("%v", key),
+			"error", common.ErrNegativeSizeInBytes,
+		)
+
+		return false
+	}
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	n, ok := c.items[key]
+	if ok {
+		entrySize := computeEntrySize(key, value, sizeInBytes)
+		c.sizeInBytes += int64(sizeInBytes) - n.size
+		c.entriesSizeInBytes += entrySize - n.entrySize
+		n.value = value
+		n.size = int64(sizeInBytes)
+		n.entrySize = entrySize
+		c.evictList.moveToBack(n)
+	} else {
+		c.addNew(key, value, sizeInBytes)
+	}
+
+	return c.evictIfNeeded() > 0
+}
+
+func (c *lruCache[K, V]) addNew(key K, value V, sizeInBytes int) {
+	n := &node[K, V]{
+		key:       key,
+		value:     value,
+		size:      int64(sizeInBytes),
+		entrySize: computeEntrySize(key, value, sizeInBytes),
+	}
+	c.evictList.pushBack(n)
+	c.items[key] = n
+	c.sizeInBytes += n.size
+	c.entriesSizeInBytes += n.entrySize
+}
+
+func (c *lruCache[K, V]) shouldEvict() bool {
+	if c.evictList.length <= 1 {
+		// keep at least one element, no matter how large it is
+		return false
+	}
+
+	isBoundedInBytes := c.maxSizeInBytes > 0
+
+	return c.evictList.length > c.maxSize || (isBoundedInBytes && c.sizeInBytes > c.maxSizeInBytes)
+}
+
+// evictIfNeeded removes the least recently used items while the limits are exceeded, returning their number
+func (c *lruCache[K, V]) evictIfNeeded() int {
+	numEvicted := 0
+	for c.shouldEvict() {
+		c.removeNode(c.evictList.front)
+		numEvicted++
+	}
+
+	return numEvicted
+}
+
+func (c *lruCache[K, V]) removeNode(n *node[K, V]) {
+	c.evictList.remove(n)
+	delete(c.items, n.key)
+	c.sizeInBytes -= n.size
+	c.entriesSizeInBytes -= n.entrySize
+}
+
+// Get looks up a key's value from the cache, marking it as the most recently used.
+func (c *lruCache[K, V]) Get(key K) (value V, ok bool) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	n, ok := c.items[key]
+	if !ok {
+		return value, false
+	}
+
+	c.evictList.moveToBack(n)
+
+	return n.value, true
+}
+
+// Has checks if a key is in the cache, without updating the recent-ness.
+func (c *lruCache[K, V]) Has(key K) bool {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	_, ok := c.items[key]
+
+	return ok
+}
+
+// Peek returns the key value (or the zero value if not found) without updating
+// the "recently used"-ness of the key.
+func (c *lruCache[K, V]) Peek(key K) (value V, ok bool) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	n, ok := c.items[key]
+	if !ok {
+		return value, false
+	}
+
+	return n.value, true
+}
+
+// HasOrAdd checks if a key is in the cache without updating the
+// recent-ness, and if not adds the value.
+func (c *lruCache[K, V]) HasOrAdd(key K, value V, sizeInBytes int) (has, added bool) {
+	if sizeInBytes < 0 {
+		log.Error("generic LRU cache has or add error",
+			"key", fmt.Sprintf("%v", key),
+			"error", common.ErrNegativeSizeInBytes,
+		)
+
+		return false, false
+	}
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	_, ok := c.items[key]
+	if ok {
+		return true, false
+	}
+
+	c.addNew(key, value, sizeInBytes)
+	_ = c.evictIfNeeded()
+
+	return false, true
+}
+
+// Remove removes the provided key from the cache.
+func (c *lruCache[K, V]) Remove(key K) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	n, ok := c.items[key]
+	if ok {
+		c.removeNode(n)
+	}
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest.
+func (c *lruCache[K, V]) Keys() []K {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	return c.evictList.keys()
+}
+
+// Len returns the number of items in the cache.
+func (c *lruCache[K, V]) Len() int {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	return c.evictList.length
+}
+
+// SizeInBytesContained returns the size in bytes of all contained items, including the keys and the overhead of the
+// entries. The capacity is bounded by the sizes provided by the callers.
+func (c *lruCache[K, V]) SizeInBytesContained() uint64 {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	return uint64(c.entriesSizeInBytes)
+}
+
+// MaxSize returns the maximum number of items which can be stored in the cache.
+func (c *lruCache[K, V]) MaxSize() int {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	return c.maxSize
+}
+
+// Resize changes the limits of the cache, evicting the least recently used items if the new limits are exceeded.
+// The size in bytes parameter is ignored for the caches bounded only by the number of items.
+func (c *lruCache[K, V]) Resize(size int, sizeInBytes int64) error {
+	if size < 1 {
+		return common.ErrCacheSizeInvalid
+	}
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	isBoundedInBytes := c.maxSizeInBytes > 0
+	if isBoundedInBytes {
+		if sizeInBytes < 1 {
+			return common.ErrCacheCapacityInvalid
+		}
+		c.maxSizeInBytes = sizeInBytes
+	}
+	c.maxSize = size
+	_ = c.evictIfNeeded()
+
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (c *lruCache[K, V]) IsInterfaceNil() bool {
+	return c == nil
+}
@@ -0,0 +1,232 @@
+package genericcache_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/genericcache"
+	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLRUCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid size should error", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := genericcache.NewLRUCache[string, int](0)
+		assert.Nil(t, cache)
+		assert.Equal(t, common.ErrCacheSizeInvalid, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := genericcache.NewLRUCache[string, int](10)
+		assert.Nil(t, err)
+		assert.False(t, cache.IsInterfaceNil())
+		assert.Equal(t, 10, cache.MaxSize())
+	})
+}
+
+func TestNewSizedLRUCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid size should error", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := genericcache.NewSizedLRUCache[string, int](0, 100)
+		assert.Nil(t, cache)
+		assert.Equal(t, common.ErrCacheSizeInvalid, err)
+	})
+	t.Run("invalid size in bytes should error", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := genericcache.NewSizedLRUCache[string, int](10, 0)
+		assert.Nil(t, cache)
+		assert.Equal(t, common.ErrCacheCapacityInvalid, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := genericcache.NewSizedLRUCache[string, int](10, 100)
+		assert.Nil(t, err)
+		assert.False(t, cache.IsInterfaceNil())
+	})
+}
+
+func TestLRUCache_PutGetShouldReturnTypedValues(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := genericcache.NewLRUCache[string, []byte](10)
+
+	evicted := cache.Put("key", []byte("value"), 5)
+	assert.False(t, evicted)
+
+	value, ok := cache.Get("key")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("value"), value)
+
+	value, ok = cache.Get("missing")
+	assert.False(t, ok)
+	assert.Nil(t, value)
+}
+
+func TestLRUCache_PutShouldEvictLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := genericcache.NewLRUCache[int, int](3)
+	cache.Put(1, 1, 0)
+	cache.Put(2, 2, 0)
+	cache.Put(3, 3, 0)
+
+	_, _ = cache.Get(1)
+	evicted := cache.Put(4, 4, 0)
+	assert.True(t, evicted)
+	assert.False(t, cache.Has(2))
+	assert.Equal(t, []int{3, 1, 4}, cache.Keys())
+}
+
+func TestLRUCache_PeekShouldNotUpdateRecentness(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := genericcache.NewLRUCache[int, int](2)
+	cache.Put(1, 1, 0)
+	cache.Put(2, 2, 0)
+
+	value, ok := cache.Peek(1)
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	cache.Put(3, 3, 0)
+	assert.False(t, cache.Has(1))
+	assert.Equal(t, []int{2, 3}, cache.Keys())
+}
+
+func TestLRUCache_HasOrAdd(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := genericcache.NewLRUCache[string, int](10)
+
+	has, added := cache.HasOrAdd("key", 1, 0)
+	assert.False(t, has)
+	assert.True(t, added)
+
+	has, added = cache.HasOrAdd("key", 2, 0)
+	assert.True(t, has)
+	assert.False(t, added)
+
+	value, _ := cache.Get("key")
+	assert.Equal(t, 1, value)
+
+	has, added = cache.HasOrAdd("negative", 1, -1)
+	assert.False(t, has)
+	assert.False(t, added)
+}
+
+func TestLRUCache_SizedShouldEvictOnBytes(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := genericcache.NewSizedLRUCache[string, int](10, 100)
+	cache.Put("a", 1, 40)
+	cache.Put("b", 2, 40)
+	assert.Equal(t, uint64(2*(40+1+types.EntryOverheadInBytes)), cache.SizeInBytesContained())
+
+	// the capacity is bounded by the sizes provided by the caller
+	evicted := cache.Put("c", 3, 40)
+	assert.True(t, evicted)
+	assert.Equal(t, []string{"b", "c"}, cache.Keys())
+	assert.Equal(t, uint64(2*(40+1+types.EntryOverheadInBytes)), cache.SizeInBytesContained())
+
+	cache.Put("b", 2, 10)
+	assert.Equal(t, uint64(50+2*(1+types.EntryOverheadInBytes)), cache.SizeInBytesContained())
+
+	// an item larger than the capacity is kept alone
+	cache.Put("large", 4, 1000)
+	assert.Equal(t, []string{"large"}, cache.Keys())
+}
+
+func TestLRUCache_PutNegativeSizeShouldNotAdd(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := genericcache.NewSizedLRUCache[string, int](10, 100)
+
+	evicted := cache.Put("key", 1, -1)
+	assert.False(t, evicted)
+	assert.False(t, cache.Has("key"))
+}
+
+func TestLRUCache_RemoveAndClear(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := genericcache.NewSizedLRUCache[string, int](10, 100)
+	cache.Put("a", 1, 10)
+	cache.Put("b", 2, 20)
+
+	cache.Remove("a")
+	cache.Remove("missing")
+	assert.Equal(t, 1, cache.Len())
+	assert.Equal(t, uint64(20+len("b")+types.EntryOverheadInBytes), cache.SizeInBytesContained())
+
+	cache.Clear()
+	assert.Equal(t, 0, cache.Len())
+	assert.Equal(t, uint64(0), cache.SizeInBytesContained())
+	assert.Empty(t, cache.Keys())
+}
+
+func TestLRUCache_Resize(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := genericcache.NewSizedLRUCache[int, int](10, 100)
+	for i := 0; i < 5; i++ {
+		cache.Put(i, i, 10)
+	}
+
+	err := cache.Resize(0, 100)
+	assert.Equal(t, common.ErrCacheSizeInvalid, err)
+	err = cache.Resize(10, 0)
+	assert.Equal(t, common.ErrCacheCapacityInvalid, err)
+
+	err = cache.Resize(3, 100)
+	require.Nil(t, err)
+	assert.Equal(t, []int{2, 3, 4}, cache.Keys())
+
+	err = cache.Resize(3, 15)
+	require.Nil(t, err)
+	assert.Equal(t, []int{4}, cache.Keys())
+	assert.Equal(t, 3, cache.MaxSize())
+}
+
+func TestLRUCache_ConcurrentOperations(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := genericcache.NewSizedLRUCache[string, int](100, 1000)
+	numOperations := 1000
+	wg := sync.WaitGroup{}
+	wg.Add(numOperations)
+	for i := 0; i < numOperations; i++ {
+		go func(idx int) {
+			defer wg.Done()
+
+			key := fmt.Sprintf("key%d", idx%150)
+			switch idx % 5 {
+			case 0:
+				cache.Put(key, idx, 5)
+			case 1:
+				_, _ = cache.Get(key)
+			case 2:
+				_, _ = cache.HasOrAdd(key, idx, 5)
+			case 3:
+				cache.Remove(key)
+			default:
+				_ = cache.Keys()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, cache.Len(), 100)
+}
@@ -1,16 +1,25 @@
 package immunitycache
 
 import (
+	"fmt"
+	"math"
 	"sync"
+	"time"
 
 	"github.com/multiversx/mx-chain-core-go/core/atomic"
+	"github.com/multiversx/mx-chain-core-go/core/check"
 	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-storage-go/clock"
 	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/eviction"
 	"github.com/multiversx/mx-chain-storage-go/monitoring"
 	"github.com/multiversx/mx-chain-storage-go/types"
 )
 
 var _ types.Cacher = (*ImmunityCache)(nil)
+var _ types.CacheStatsProvider = (*ImmunityCache)(nil)
+var _ types.ResizableCacher = (*ImmunityCache)(nil)
+var _ types.EvictionNotifier = (*ImmunityCache)(nil)
 
 var log = logger.GetOrCreate("storage/immunitycache")
 
@@ -24,6 +33,10 @@ type ImmunityCache struct {
 	chunks                        []*immunityChunk
 	hospitality                   atomic.Counter
 	numCapacityReachedOccurrences atomic.Counter
+	counters                      monitoring.CacheCounters
+	pendingCounters               pendingImmunityCounters
+	isMonitored                   bool
+	evictionHandlers              eviction.Handlers
 	mutex                         sync.RWMutex
 }
 
@@ -36,34 +49,69 @@ func NewImmunityCache(config CacheConfig) (*ImmunityCache, error) {
 	if err != nil {
 		return nil, err
 	}
+	if check.IfNil(config.Clock) {
+		config.Clock = clock.NewSystemClock()
+	}
 
 	cache := ImmunityCache{
 		config: config,
 	}
 
 	cache.initializeChunksWithLock()
+	if !check.IfNil(config.MemoryBudget) {
+		err = config.MemoryBudget.RegisterCache(config.Name, &cache, uint64(config.MaxNumBytes), config.Priority)
+		if err != nil {
+			return nil, err
+		}
+	}
+	cache.isMonitored = monitoring.RegisterCache(config.Name, &cache)
+
 	return &cache, nil
 }
 
-func (ic *ImmunityCache) initializeChunksWithLock() {
+// initializeChunksWithLock replaces the chunks with empty ones and returns the discarded chunks
+func (ic *ImmunityCache) initializeChunksWithLock() []*immunityChunk {
 	ic.mutex.Lock()
 	defer ic.mutex.Unlock()
 
+	// the evictions and the pending immunity metrics of the discarded chunks are kept in the cache's counters
+	for _, chunk := range ic.chunks {
+		ic.counters.RecordEvictions(chunk.NumEvicted())
+		ic.pendingCounters.add(&chunk.pendingCounters)
+	}
+
 	config := ic.config
 	chunkConfig := config.getChunkConfig()
 
+	discardedChunks := ic.chunks
 	ic.chunks = make([]*immunityChunk, config.NumChunks)
 	for i := uint32(0); i < config.NumChunks; i++ {
 		ic.chunks[i] = newImmunityChunk(chunkConfig)
 	}
+
+	return discardedChunks
 }
 
 // ImmunizeKeys marks items as immune to eviction
 func (ic *ImmunityCache) ImmunizeKeys(keys [][]byte) (numNowTotal, numFutureTotal int) {
-	immuneItemsCapacityReached := ic.CountImmune()+len(keys) > int(ic.config.MaxNumItems)
-	if immuneItemsCapacityReached {
+	return ic.ImmunizeKeysWithLevel(keys, LevelImmune)
+}
+
+// ImmunizeKeysWithLevel protects items against eviction at the provided level, replacing their previous level, if any.
+// The keys not yet contained are protected once added. Nothing is protected if either the capacity of the cache or the
+// quota of the level would be exceeded.
+func (ic *ImmunityCache) ImmunizeKeysWithLevel(keys [][]byte, level ImmunityLevel) (numNowTotal, numFutureTotal int) {
+	if !level.isImmunity() {
+		log.Error("ImmunityCache.ImmunizeKeysWithLevel(): will not immunize", "level", level, "err", common.ErrInvalidImmunityLevel)
+		return
+	}
+
+	// the pending immunities are left out, as their items do not take room yet
+	immuneItemsCapacityReached := ic.countImmuneItems()+len(keys) > ic.MaxSize()
+	levelQuotaReached := ic.countImmuneItemsByLevel(level)+len(keys) > ic.maxNumImmuneItemsOfLevel(level)
+	if immuneItemsCapacityReached || levelQuotaReached {
 		logLevel := ic.decideLogLevelOnCapacityReached()
-		log.Log(logLevel, "ImmunityCache.ImmunizeKeys(): will not immunize", "err", common.ErrImmuneItemsCapacityReached)
+		log.Log(logLevel, "ImmunityCache.ImmunizeKeysWithLevel(): will not immunize", "level", level, "err", common.ErrImmuneItemsCapacityReached)
 		return
 	}
 
@@ -74,7 +122,7 @@ func (ic *ImmunityCache) ImmunizeKeys(keys [][]byte) (numNowTotal, numFutureTota
 	for chunkIndex, chunkKeys := range groups {
 		chunk := ic.getChunkByIndexWithLock(chunkIndex)
 
-		numNow, numFuture := chunk.ImmunizeKeys(chunkKeys)
+		numNow, numFuture := chunk.ImmunizeKeysWithLevel(chunkKeys, level)
 		numNowTotal += numNow
 		numFutureTotal += numFuture
 	}
@@ -82,6 +130,41 @@ func (ic *ImmunityCache) ImmunizeKeys(keys [][]byte) (numNowTotal, numFutureTota
 	return
 }
 
+func (ic *ImmunityCache) maxNumImmuneItemsOfLevel(level ImmunityLevel) int {
+	ic.mutex.RLock()
+	defer ic.mutex.RUnlock()
+
+	return int(ic.config.getMaxNumImmuneItemsOfLevel(level))
+}
+
+// RevokeImmunity revokes the immunity of the provided keys, whatever their level, and returns the number of keys
+// which were immune
+func (ic *ImmunityCache) RevokeImmunity(keys [][]byte) int {
+	numRevoked := 0
+	for chunkIndex, chunkKeys := range ic.groupKeysByChunk(keys) {
+		numRevoked += ic.getChunkByIndexWithLock(chunkIndex).RevokeImmunity(chunkKeys)
+	}
+
+	return numRevoked
+}
+
+// DemoteImmunity moves all the keys protected at the level "from" to the lower level "to", LevelNormal meaning their
+// immunity is revoked. The quotas are not checked, the keys being moved to a lower level. It returns the number of
+// demoted keys.
+func (ic *ImmunityCache) DemoteImmunity(from ImmunityLevel, to ImmunityLevel) int {
+	if !from.isImmunity() || to >= from {
+		log.Error("ImmunityCache.DemoteImmunity()", "from", from, "to", to, "err", common.ErrInvalidImmunityLevel)
+		return 0
+	}
+
+	numDemoted := 0
+	for _, chunk := range ic.getChunksWithLock() {
+		numDemoted += chunk.DemoteImmunity(from, to)
+	}
+
+	return numDemoted
+}
+
 func (ic *ImmunityCache) decideLogLevelOnCapacityReached() logger.LogLevel {
 	logLevel := logger.LogDebug
 	if ic.numCapacityReachedOccurrences.GetUint64()%capacityReachedWarningPeriod == 0 {
@@ -139,6 +222,7 @@ func (ic *ImmunityCache) getChunkByKeyWithLock(key string) *immunityChunk {
 // Get gets an item (payload) by key
 func (ic *ImmunityCache) Get(key []byte) (value interface{}, ok bool) {
 	item, ok := ic.getItem(key)
+	ic.counters.RecordLookup(ok)
 	if ok {
 		return item.payload, true
 	}
@@ -167,8 +251,14 @@ func (ic *ImmunityCache) Peek(key []byte) (value interface{}, ok bool) {
 // HasOrAdd adds an item in the cache
 func (ic *ImmunityCache) HasOrAdd(key []byte, value interface{}, sizeInBytes int) (has, added bool) {
 	item := newCacheItem(value, string(key), sizeInBytes)
-	chunk := ic.getChunkByKeyWithLock(string(key))
-	has, added = chunk.AddItem(item)
+	has, added, _ = ic.addItem(item)
+
+	return has, added
+}
+
+func (ic *ImmunityCache) addItem(item *cacheItem) (has, added bool, evicted []*cacheItem) {
+	chunk := ic.getChunkByKeyWithLock(item.key)
+	has, added, evicted = chunk.AddItemReturnEvicted(item)
 	if !has {
 		if added {
 			ic.hospitality.Increment()
@@ -177,13 +267,33 @@ func (ic *ImmunityCache) HasOrAdd(key []byte, value interface{}, sizeInBytes int
 		}
 	}
 
-	return has, added
+	ic.notifyEvicted(evicted, types.EvictedByCapacity)
+
+	return has, added, evicted
 }
 
-// Put adds an item in the cache
+// Put adds an item in the cache. Returns true if an eviction occurred.
 func (ic *ImmunityCache) Put(key []byte, value interface{}, sizeInBytes int) (evicted bool) {
-	ic.HasOrAdd(key, value, sizeInBytes)
-	return false
+	item := newCacheItem(value, string(key), sizeInBytes)
+	_, _, evictedItems := ic.addItem(item)
+
+	return len(evictedItems) > 0
+}
+
+func (ic *ImmunityCache) notifyEvicted(items []*cacheItem, reason types.EvictionReason) {
+	for _, item := range items {
+		ic.evictionHandlers.Notify([]byte(item.key), item.payload, reason)
+	}
+}
+
+// RegisterEvictionHandler registers a new handler to be called for each item leaving the cache
+func (ic *ImmunityCache) RegisterEvictionHandler(handler types.EvictionHandlerFunc, id string) {
+	ic.evictionHandlers.Register(handler, id)
+}
+
+// UnRegisterEvictionHandler removes the eviction handler registered under the provided id
+func (ic *ImmunityCache) UnRegisterEvictionHandler(id string) {
+	ic.evictionHandlers.UnRegister(id)
 }
 
 // Remove removes an item
@@ -195,7 +305,12 @@ func (ic *ImmunityCache) Remove(key []byte) {
 // TODO: In the future, add this method to the "storage.Cacher" interface. EN-6739.
 func (ic *ImmunityCache) RemoveWithResult(key []byte) bool {
 	chunk := ic.getChunkByKeyWithLock(string(key))
-	return chunk.RemoveItem(string(key))
+	item, ok := chunk.RemoveItemReturnRemoved(string(key))
+	if ok {
+		ic.evictionHandlers.Notify(key, item.payload, types.EvictedByRemoval)
+	}
+
+	return ok
 }
 
 // RemoveOldest is not implemented
@@ -207,22 +322,68 @@ func (ic *ImmunityCache) RemoveOldest() {
 func (ic *ImmunityCache) Clear() {
 	// There is no need to explicitly remove each item for each chunk
 	// The garbage collector will remove the data from memory
-	ic.initializeChunksWithLock()
+	discardedChunks := ic.initializeChunksWithLock()
+	if !ic.evictionHandlers.HasHandlers() {
+		return
+	}
+
+	for _, chunk := range discardedChunks {
+		ic.notifyEvicted(chunk.ItemsInOrder(), types.EvictedByClear)
+	}
 }
 
 // MaxSize returns the capacity of the cache
 func (ic *ImmunityCache) MaxSize() int {
+	ic.mutex.RLock()
+	defer ic.mutex.RUnlock()
+
 	return int(ic.config.MaxNumItems)
 }
 
+// Resize changes the limits of the cache, evicting the oldest items that are not immune if the new limits are exceeded
+func (ic *ImmunityCache) Resize(maxNumItems int, maxSizeInBytes int64) error {
+	if maxNumItems < 0 || maxNumItems > math.MaxUint32 || maxSizeInBytes < 0 || maxSizeInBytes > math.MaxUint32 {
+		return fmt.Errorf("%w: invalid limits for resize", common.ErrInvalidConfig)
+	}
+
+	ic.mutex.Lock()
+	config := ic.config
+	config.MaxNumItems = uint32(maxNumItems)
+	config.MaxNumBytes = uint32(maxSizeInBytes)
+	err := config.Verify()
+	if err != nil {
+		ic.mutex.Unlock()
+		return err
+	}
+
+	ic.config = config
+	chunkConfig := config.getChunkConfig()
+	var evicted []*cacheItem
+	for _, chunk := range ic.chunks {
+		evicted = append(evicted, chunk.Resize(chunkConfig)...)
+	}
+	ic.mutex.Unlock()
+
+	log.Debug("ImmunityCache.Resize", "name", config.Name, "maxNumItems", maxNumItems, "maxNumBytes", maxSizeInBytes)
+	ic.notifyEvicted(evicted, types.EvictedByCapacity)
+
+	return nil
+}
+
 // Len is an alias for Count
 func (ic *ImmunityCache) Len() int {
 	return ic.Count()
 }
 
-// SizeInBytesContained returns 0
+// SizeInBytesContained returns the size in bytes of all contained items, including the keys and the overhead
+// of the entries
 func (ic *ImmunityCache) SizeInBytesContained() uint64 {
-	return 0
+	numEntryBytes := 0
+	for _, chunk := range ic.getChunksWithLock() {
+		numEntryBytes += chunk.NumEntryBytes()
+	}
+
+	return uint64(numEntryBytes)
 }
 
 // Count returns the number of elements within the map
@@ -240,7 +401,7 @@ func (ic *ImmunityCache) getChunksWithLock() []*immunityChunk {
 	return ic.chunks
 }
 
-// CountImmune returns the number of immunized (current or future) elements within the map
+// CountImmune returns the number of immunized (current or future) elements within the map, of any level
 func (ic *ImmunityCache) CountImmune() int {
 	count := 0
 	for _, chunk := range ic.getChunksWithLock() {
@@ -249,6 +410,73 @@ func (ic *ImmunityCache) CountImmune() int {
 	return count
 }
 
+func (ic *ImmunityCache) countImmuneItems() int {
+	count := 0
+	for _, chunk := range ic.getChunksWithLock() {
+		count += chunk.CountImmuneItems()
+	}
+	return count
+}
+
+func (ic *ImmunityCache) countImmuneItemsByLevel(level ImmunityLevel) int {
+	count := 0
+	for _, chunk := range ic.getChunksWithLock() {
+		count += chunk.CountImmuneItemsByLevel(level)
+	}
+	return count
+}
+
+// CountImmuneByLevel returns the number of immunized (current or future) elements within the map, of the provided level
+func (ic *ImmunityCache) CountImmuneByLevel(level ImmunityLevel) int {
+	if !level.isImmunity() {
+		return 0
+	}
+
+	count := 0
+	for _, chunk := range ic.getChunksWithLock() {
+		count += chunk.CountImmuneByLevel(level)
+	}
+	return count
+}
+
+// PendingImmunityStats returns the metrics of the immunities granted to keys not yet contained by the cache
+func (ic *ImmunityCache) PendingImmunityStats() PendingImmunityStats {
+	// the lock is held so that a concurrent Clear does not account the metrics twice
+	ic.mutex.RLock()
+	defer ic.mutex.RUnlock()
+
+	stats := PendingImmunityStats{}
+	for _, chunk := range ic.chunks {
+		chunk.AddPendingImmunityStats(&stats)
+	}
+	ic.pendingCounters.addToStats(&stats)
+
+	return stats
+}
+
+// StaleImmuneKeys returns the keys immunized at least minAge ago whose items have not been added yet
+func (ic *ImmunityCache) StaleImmuneKeys(minAge time.Duration) [][]byte {
+	keys := make([][]byte, 0)
+	for _, chunk := range ic.getChunksWithLock() {
+		keys = append(keys, chunk.StaleImmuneKeys(minAge)...)
+	}
+
+	return keys
+}
+
+// ClearStaleImmuneKeys revokes the immunity of the keys immunized at least minAge ago whose items have not been added
+// yet, returning the number of revoked keys
+func (ic *ImmunityCache) ClearStaleImmuneKeys(minAge time.Duration) int {
+	numCleared := 0
+	for _, chunk := range ic.getChunksWithLock() {
+		numCleared += chunk.ClearStaleImmuneKeys(minAge)
+	}
+
+	log.Debug("ImmunityCache.ClearStaleImmuneKeys", "name", ic.config.Name, "minAge", minAge, "numCleared", numCleared)
+
+	return numCleared
+}
+
 // NumBytes estimates the size of the cache, in bytes
 func (ic *ImmunityCache) NumBytes() int {
 	numBytes := 0
@@ -258,6 +486,25 @@ func (ic *ImmunityCache) NumBytes() int {
 	return numBytes
 }
 
+// CacheStats returns the statistics of the cache
+func (ic *ImmunityCache) CacheStats() types.CacheStats {
+	// the lock is held so that a concurrent Clear does not account the evictions twice
+	ic.mutex.RLock()
+	defer ic.mutex.RUnlock()
+
+	numItems, numBytes, numEvicted := 0, 0, 0
+	for _, chunk := range ic.chunks {
+		numItems += chunk.Count()
+		numBytes += chunk.NumEntryBytes()
+		numEvicted += chunk.NumEvicted()
+	}
+
+	stats := ic.counters.Stats(numItems, uint64(numBytes))
+	stats.NumEvictions += uint64(numEvicted)
+
+	return stats
+}
+
 // Keys returns all keys
 func (ic *ImmunityCache) Keys() [][]byte {
 	count := ic.Count()
@@ -291,6 +538,8 @@ func (ic *ImmunityCache) ForEachItem(function types.ForEachItem) {
 func (ic *ImmunityCache) Diagnose(_ bool) {
 	count := ic.Count()
 	countImmune := ic.CountImmune()
+	countProtected := ic.CountImmuneByLevel(LevelProtected)
+	countPending := ic.PendingImmunityStats().NumPending
 	numBytes := ic.NumBytes()
 	hospitality := ic.hospitality.Get()
 
@@ -301,6 +550,8 @@ func (ic *ImmunityCache) Diagnose(_ bool) {
 			"name", ic.config.Name,
 			"count", count,
 			"countImmune", countImmune,
+			"countProtected", countProtected,
+			"countPending", countPending,
 			"numBytes", numBytes,
 			"hospitality", hospitality,
 		)
@@ -316,13 +567,22 @@ func (ic *ImmunityCache) Diagnose(_ bool) {
 		"name", ic.config.Name,
 		"count", count,
 		"countImmune", countImmune,
+		"countProtected", countProtected,
+		"countPending", countPending,
 		"numBytes", numBytes,
 		"hospitality", hospitality,
 	)
 }
 
-// Close does nothing for this cacher implementation
+// Close removes the cache from the monitoring registry and from its memory budget
 func (ic *ImmunityCache) Close() error {
+	if ic.isMonitored {
+		monitoring.UnregisterCache(ic.config.Name)
+	}
+	if !check.IfNil(ic.config.MemoryBudget) {
+		ic.config.MemoryBudget.UnregisterCache(ic.config.Name)
+	}
+
 	return nil
 }
 
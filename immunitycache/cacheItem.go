@@ -1,28 +1,27 @@
 package immunitycache
 
-import (
-	"github.com/multiversx/mx-chain-core-go/core/atomic"
-)
+import "github.com/multiversx/mx-chain-storage-go/types"
 
 type cacheItem struct {
-	payload  interface{}
-	key      string
-	size     int
-	isImmune atomic.Flag
+	payload interface{}
+	key     string
+	// size is the size provided by the caller, bounding the capacity of the cache
+	size int
+	// entrySize is the reported size, that includes the key and the overhead of the entry
+	entrySize int
+	// immunityLevel is guarded by the mutex of the chunk holding the item
+	immunityLevel ImmunityLevel
 }
 
 func newCacheItem(payload interface{}, key string, size int) *cacheItem {
 	return &cacheItem{
-		payload: payload,
-		key:     key,
-		size:    size,
+		payload:   payload,
+		key:       key,
+		size:      size,
+		entrySize: types.ComputeEntrySizeInBytes(key, payload, size),
 	}
 }
 
-func (item *cacheItem) isImmuneToEviction() bool {
-	return item.isImmune.IsSet()
-}
-
-func (item *cacheItem) immunizeAgainstEviction() {
-	_ = item.isImmune.SetReturningPrevious()
+func (item *cacheItem) isEvictableUpToLevel(maxLevel ImmunityLevel) bool {
+	return item.immunityLevel <= maxLevel
 }
@@ -6,9 +6,14 @@ import (
 	"math"
 	"sync"
 	"testing"
+	"time"
 
 	logger "github.com/multiversx/mx-chain-logger-go"
 	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/memorybudget"
+	"github.com/multiversx/mx-chain-storage-go/monitoring"
+	"github.com/multiversx/mx-chain-storage-go/testscommon"
+	"github.com/multiversx/mx-chain-storage-go/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -80,9 +85,10 @@ func TestImmunityCache_ImmunizeAgainstEviction(t *testing.T) {
 func TestImmunityCache_ImmunizeDoesNothingIfCapacityReached(t *testing.T) {
 	cache := newCacheToTest(1, 4, maxNumBytesUpperBound)
 
+	cache.addTestItems("a", "b", "c", "d")
 	numNow, numFuture := cache.ImmunizeKeys(keysAsBytes([]string{"a", "b", "c", "d"}))
-	require.Equal(t, 0, numNow)
-	require.Equal(t, 4, numFuture)
+	require.Equal(t, 4, numNow)
+	require.Equal(t, 0, numFuture)
 	require.Equal(t, 4, cache.CountImmune())
 
 	numNow, numFuture = cache.ImmunizeKeys(keysAsBytes([]string{"e", "f", "g", "h"}))
@@ -91,6 +97,261 @@ func TestImmunityCache_ImmunizeDoesNothingIfCapacityReached(t *testing.T) {
 	require.Equal(t, 4, cache.CountImmune())
 }
 
+func TestImmunityCache_ProtectedItemsShouldBeEvictedAfterTheOthers(t *testing.T) {
+	cache := newCacheToTest(1, 8, maxNumBytesUpperBound)
+
+	cache.addTestItems("a", "b", "c", "d", "e", "f", "g", "h")
+	numNow, numFuture := cache.ImmunizeKeysWithLevel(keysAsBytes([]string{"a", "b", "c"}), LevelProtected)
+	require.Equal(t, 3, numNow)
+	require.Equal(t, 0, numFuture)
+	_, _ = cache.ImmunizeKeys(keysAsBytes([]string{"d", "e"}))
+	require.Equal(t, 5, cache.CountImmune())
+	require.Equal(t, 3, cache.CountImmuneByLevel(LevelProtected))
+	require.Equal(t, 2, cache.CountImmuneByLevel(LevelImmune))
+
+	cache.addTestItems("i", "j", "k")
+	require.ElementsMatch(t, []string{"a", "b", "c", "d", "e", "i", "j", "k"}, keysAsStrings(cache.Keys()))
+
+	// only protected items can make room for new ones, the oldest first
+	_, _ = cache.ImmunizeKeys(keysAsBytes([]string{"i", "j", "k"}))
+	cache.addTestItems("l")
+	require.ElementsMatch(t, []string{"b", "c", "d", "e", "i", "j", "k", "l"}, keysAsStrings(cache.Keys()))
+	require.Equal(t, 2, cache.CountImmuneByLevel(LevelProtected))
+}
+
+func TestImmunityCache_ImmunizeKeysWithLevel(t *testing.T) {
+	t.Run("invalid level should not immunize", func(t *testing.T) {
+		cache := newCacheToTest(1, 8, maxNumBytesUpperBound)
+
+		numNow, numFuture := cache.ImmunizeKeysWithLevel(keysAsBytes([]string{"a"}), LevelNormal)
+		require.Equal(t, 0, numNow+numFuture)
+		numNow, numFuture = cache.ImmunizeKeysWithLevel(keysAsBytes([]string{"a"}), LevelImmune+1)
+		require.Equal(t, 0, numNow+numFuture)
+		require.Equal(t, 0, cache.CountImmune())
+		require.Equal(t, 0, cache.CountImmuneByLevel(LevelNormal))
+	})
+	t.Run("quota of the level reached should not immunize", func(t *testing.T) {
+		config := createCacheConfigToTest()
+		config.MaxNumProtectedItems = 3
+		config.MaxNumImmuneItems = 2
+		cache, _ := NewImmunityCache(config)
+
+		cache.addTestItems("a", "b", "c", "d", "e")
+		numNow, _ := cache.ImmunizeKeysWithLevel(keysAsBytes([]string{"a", "b"}), LevelProtected)
+		require.Equal(t, 2, numNow)
+		numNow, _ = cache.ImmunizeKeysWithLevel(keysAsBytes([]string{"c", "d"}), LevelProtected)
+		require.Equal(t, 0, numNow)
+		numNow, _ = cache.ImmunizeKeys(keysAsBytes([]string{"c", "d", "e"}))
+		require.Equal(t, 0, numNow)
+		numNow, _ = cache.ImmunizeKeys(keysAsBytes([]string{"c", "d"}))
+		require.Equal(t, 2, numNow)
+
+		require.Equal(t, 2, cache.CountImmuneByLevel(LevelProtected))
+		require.Equal(t, 2, cache.CountImmuneByLevel(LevelImmune))
+
+		// the pending immunities do not count against the quota
+		require.Equal(t, 1, cache.RevokeImmunity(keysAsBytes([]string{"c"})))
+		_, numFuture := cache.ImmunizeKeys(keysAsBytes([]string{"x"}))
+		require.Equal(t, 1, numFuture)
+		_, numFuture = cache.ImmunizeKeys(keysAsBytes([]string{"y"}))
+		require.Equal(t, 1, numFuture)
+		require.Equal(t, 3, cache.CountImmuneByLevel(LevelImmune))
+	})
+	t.Run("should replace the previous level", func(t *testing.T) {
+		cache := newCacheToTest(1, 5, maxNumBytesUpperBound)
+
+		cache.addTestItems("a", "b", "c", "d", "e")
+		_, _ = cache.ImmunizeKeys(keysAsBytes([]string{"a", "b", "c", "d"}))
+		numNow, _ := cache.ImmunizeKeysWithLevel(keysAsBytes([]string{"b"}), LevelProtected)
+		require.Equal(t, 1, numNow)
+		require.Equal(t, 4, cache.CountImmune())
+		require.Equal(t, 1, cache.CountImmuneByLevel(LevelProtected))
+		require.Equal(t, 3, cache.CountImmuneByLevel(LevelImmune))
+
+		_, _ = cache.ImmunizeKeys(keysAsBytes([]string{"e"}))
+		cache.addTestItems("f")
+		require.ElementsMatch(t, []string{"a", "c", "d", "e", "f"}, keysAsStrings(cache.Keys()))
+		require.Equal(t, 4, cache.CountImmune())
+	})
+}
+
+func TestImmunityCache_RevokeAndDemoteImmunity(t *testing.T) {
+	cache := newCacheToTest(1, 8, maxNumBytesUpperBound)
+
+	cache.addTestItems("a", "b", "c", "d")
+	_, _ = cache.ImmunizeKeys(keysAsBytes([]string{"a", "b", "x"}))
+	_, _ = cache.ImmunizeKeysWithLevel(keysAsBytes([]string{"c", "y"}), LevelProtected)
+
+	require.Equal(t, 2, cache.RevokeImmunity(keysAsBytes([]string{"b", "y", "d", "missing"})))
+	require.Equal(t, 2, cache.CountImmuneByLevel(LevelImmune))
+	require.Equal(t, 1, cache.CountImmuneByLevel(LevelProtected))
+
+	require.Equal(t, 0, cache.DemoteImmunity(LevelProtected, LevelImmune))
+	require.Equal(t, 0, cache.DemoteImmunity(LevelNormal, LevelNormal))
+	require.Equal(t, 2, cache.DemoteImmunity(LevelImmune, LevelProtected))
+	require.Equal(t, 0, cache.CountImmuneByLevel(LevelImmune))
+	require.Equal(t, 3, cache.CountImmuneByLevel(LevelProtected))
+
+	// the items without immunity are evicted first, then the protected ones
+	cache.addTestItems("e", "f", "g", "h", "i", "j")
+	require.ElementsMatch(t, []string{"a", "c", "e", "f", "g", "h", "i", "j"}, keysAsStrings(cache.Keys()))
+
+	require.Equal(t, 3, cache.DemoteImmunity(LevelProtected, LevelNormal))
+	require.Equal(t, 0, cache.CountImmune())
+	cache.addTestItems("k")
+	require.ElementsMatch(t, []string{"c", "e", "f", "g", "h", "i", "j", "k"}, keysAsStrings(cache.Keys()))
+}
+
+func TestImmunityCache_ImmunityShouldExpire(t *testing.T) {
+	fakeClock := testscommon.NewFakeClock(time.Now())
+	config := createCacheConfigToTest()
+	config.MaxNumItems = 4
+	config.ImmunitySpanInSeconds = 60
+	config.Clock = fakeClock
+	cache, _ := NewImmunityCache(config)
+
+	cache.addTestItems("a", "b", "c", "d")
+	_, _ = cache.ImmunizeKeys(keysAsBytes([]string{"a", "future"}))
+	fakeClock.Advance(time.Second * 30)
+	_, _ = cache.ImmunizeKeysWithLevel(keysAsBytes([]string{"b"}), LevelProtected)
+	require.Equal(t, 3, cache.CountImmune())
+
+	fakeClock.Advance(time.Second*30 - time.Nanosecond)
+	require.Equal(t, 3, cache.CountImmune())
+
+	fakeClock.Advance(time.Nanosecond)
+	require.Equal(t, 1, cache.CountImmune())
+	require.Equal(t, 1, cache.CountImmuneByLevel(LevelProtected))
+
+	// the expired immunity no longer prevents the eviction
+	cache.addTestItems("e")
+	require.ElementsMatch(t, []string{"b", "c", "d", "e"}, keysAsStrings(cache.Keys()))
+	cache.addTestItems("future")
+	require.ElementsMatch(t, []string{"b", "d", "e", "future"}, keysAsStrings(cache.Keys()))
+
+	fakeClock.Advance(time.Second * 30)
+	require.Equal(t, 0, cache.CountImmune())
+}
+
+func TestImmunityCache_PendingImmunityShouldExpire(t *testing.T) {
+	fakeClock := testscommon.NewFakeClock(time.Now())
+	config := createCacheConfigToTest()
+	config.PendingImmunitySpanInSeconds = 10
+	config.Clock = fakeClock
+	cache, _ := NewImmunityCache(config)
+
+	_, numFuture := cache.ImmunizeKeys(keysAsBytes([]string{"a", "b", "c"}))
+	require.Equal(t, 3, numFuture)
+	fakeClock.Advance(time.Second * 5)
+	cache.addTestItems("a")
+	_, _ = cache.ImmunizeKeys(keysAsBytes([]string{"b"}))
+	require.Equal(t, PendingImmunityStats{NumPending: 2, NumFulfilled: 1}, cache.PendingImmunityStats())
+
+	fakeClock.Advance(time.Second * 5)
+	require.Equal(t, 2, cache.CountImmune())
+	require.Equal(t, PendingImmunityStats{NumPending: 1, NumFulfilled: 1, NumExpired: 1}, cache.PendingImmunityStats())
+
+	// the immunity of the added item does not expire
+	fakeClock.Advance(time.Second * 5)
+	require.Equal(t, 1, cache.CountImmune())
+	require.Equal(t, PendingImmunityStats{NumFulfilled: 1, NumExpired: 2}, cache.PendingImmunityStats())
+	cache.addTestItems("b", "c")
+	require.Equal(t, 1, cache.CountImmune())
+}
+
+func TestImmunityCache_PendingImmunitiesShouldBeBounded(t *testing.T) {
+	config := createCacheConfigToTest()
+	config.MaxNumItems = 4
+	config.MaxNumPendingImmunities = 2
+	cache, _ := NewImmunityCache(config)
+
+	_, numFuture := cache.ImmunizeKeys(keysAsBytes([]string{"a", "b", "c", "d"}))
+	require.Equal(t, 4, numFuture)
+	require.Equal(t, 2, cache.CountImmune())
+	require.Equal(t, PendingImmunityStats{NumPending: 2, NumDropped: 2}, cache.PendingImmunityStats())
+
+	// the items which never arrive no longer block the immunization
+	_, numFuture = cache.ImmunizeKeys(keysAsBytes([]string{"e", "f"}))
+	require.Equal(t, 2, numFuture)
+	require.ElementsMatch(t, []string{"e", "f"}, keysAsStrings(cache.StaleImmuneKeys(0)))
+
+	cache.addTestItems("e")
+	_, _ = cache.ImmunizeKeys(keysAsBytes([]string{"g"}))
+	require.ElementsMatch(t, []string{"f", "g"}, keysAsStrings(cache.StaleImmuneKeys(0)))
+	require.Equal(t, PendingImmunityStats{NumPending: 2, NumFulfilled: 1, NumDropped: 4}, cache.PendingImmunityStats())
+
+	require.Nil(t, cache.Resize(4, maxNumBytesUpperBound))
+	require.Equal(t, uint64(2), cache.PendingImmunityStats().NumPending)
+}
+
+func TestImmunityCache_PendingImmunitiesShouldNotBlockTheImmunization(t *testing.T) {
+	config := createCacheConfigToTest()
+	config.MaxNumItems = 8
+	config.MaxNumPendingImmunities = 8
+	cache, _ := NewImmunityCache(config)
+
+	keys := make([]string, 0, config.MaxNumItems)
+	for i := 0; i < int(config.MaxNumItems); i++ {
+		keys = append(keys, fmt.Sprintf("missing%d", i))
+	}
+	_, numFuture := cache.ImmunizeKeys(keysAsBytes(keys))
+	require.Equal(t, 8, numFuture)
+	require.Equal(t, 8, cache.CountImmune())
+
+	// the oldest pending immunity makes room for the new one
+	_, numFuture = cache.ImmunizeKeys(keysAsBytes([]string{"new"}))
+	require.Equal(t, 1, numFuture)
+	require.Equal(t, 8, cache.CountImmune())
+	require.Equal(t, PendingImmunityStats{NumPending: 8, NumDropped: 1}, cache.PendingImmunityStats())
+
+	// the pending immunities demoted to another level are still left out of the capacity check
+	require.Equal(t, 8, cache.DemoteImmunity(LevelImmune, LevelProtected))
+	cache.addTestItems("a")
+	numNow, _ := cache.ImmunizeKeysWithLevel(keysAsBytes([]string{"a"}), LevelProtected)
+	require.Equal(t, 1, numNow)
+}
+
+func TestImmunityCache_PendingImmunitiesShouldBeUnboundedByDefault(t *testing.T) {
+	config := createCacheConfigToTest()
+	config.MaxNumItems = 8
+	cache, _ := NewImmunityCache(config)
+
+	numFutureTotal := 0
+	for i := 0; i < 2*int(config.MaxNumItems); i++ {
+		_, numFuture := cache.ImmunizeKeys(keysAsBytes([]string{fmt.Sprintf("missing%d", i)}))
+		numFutureTotal += numFuture
+	}
+	require.Equal(t, 16, numFutureTotal)
+	require.Equal(t, 16, cache.CountImmune())
+	require.Equal(t, PendingImmunityStats{NumPending: 16}, cache.PendingImmunityStats())
+}
+
+func TestImmunityCache_StaleImmuneKeys(t *testing.T) {
+	fakeClock := testscommon.NewFakeClock(time.Now())
+	config := createCacheConfigToTest()
+	config.NumChunks = 2
+	config.Clock = fakeClock
+	cache, _ := NewImmunityCache(config)
+
+	_, _ = cache.ImmunizeKeys(keysAsBytes([]string{"a", "b", "c"}))
+	fakeClock.Advance(time.Minute)
+	_, _ = cache.ImmunizeKeysWithLevel(keysAsBytes([]string{"d", "b"}), LevelProtected)
+	cache.addTestItems("c", "d")
+
+	require.ElementsMatch(t, []string{"a"}, keysAsStrings(cache.StaleImmuneKeys(time.Minute)))
+	require.ElementsMatch(t, []string{"a", "b"}, keysAsStrings(cache.StaleImmuneKeys(0)))
+	require.Equal(t, 0, len(cache.StaleImmuneKeys(time.Hour)))
+
+	require.Equal(t, 1, cache.ClearStaleImmuneKeys(time.Minute))
+	require.Equal(t, 3, cache.CountImmune())
+	require.Equal(t, PendingImmunityStats{NumPending: 1, NumFulfilled: 2, NumCleared: 1}, cache.PendingImmunityStats())
+
+	// the metrics survive a clear
+	cache.Clear()
+	require.Equal(t, 0, cache.CountImmune())
+	require.Equal(t, PendingImmunityStats{NumFulfilled: 2, NumCleared: 1}, cache.PendingImmunityStats())
+}
+
 func TestImmunityCache_AddThenRemove(t *testing.T) {
 	cache := newCacheToTest(1, 8, maxNumBytesUpperBound)
 
@@ -199,6 +460,22 @@ func TestImmunityCache_AddThenRemove_ChangesNumBytes(t *testing.T) {
 	require.Equal(t, 1100, cache.NumBytes())
 }
 
+func TestImmunityCache_SizeInBytesContained(t *testing.T) {
+	cache := newCacheToTest(1, 8, 100000)
+
+	_, _ = cache.HasOrAdd([]byte("a"), []byte("bytes"), 1000)
+	_ = cache.Put([]byte("b"), &sizerStub{size: 300}, 1)
+	_ = cache.Put([]byte("c"), "foo-c", 50)
+
+	expectedSize := len("bytes") + 300 + 50 + 3*(1+types.EntryOverheadInBytes)
+	require.Equal(t, uint64(expectedSize), cache.SizeInBytesContained())
+	// the capacity is bounded by the sizes provided by the caller
+	require.Equal(t, 1000+1+50, cache.NumBytes())
+
+	cache.Remove([]byte("b"))
+	require.Equal(t, uint64(expectedSize-300-1-types.EntryOverheadInBytes), cache.SizeInBytesContained())
+}
+
 func TestImmunityCache_AddDoesNotWork_WhenFullWithImmune(t *testing.T) {
 	cache := newCacheToTest(1, 4, 1000)
 
@@ -336,6 +613,62 @@ func TestImmunityCache_ForgetCapacityHadBeenReachedInThePast(t *testing.T) {
 	require.Equal(t, uint64(0), cache.numCapacityReachedOccurrences.GetUint64())
 }
 
+func TestImmunityCache_CloseShouldUnregisterFromMonitoring(t *testing.T) {
+	isMonitored := func(name string) bool {
+		for _, cache := range monitoring.DefaultRegistry().Snapshot().Caches {
+			if cache.Name == name {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	config := createCacheConfigToTest()
+	config.Name = "immunity cache monitoring test"
+	cache, err := NewImmunityCache(config)
+	require.Nil(t, err)
+	require.True(t, isMonitored(config.Name))
+
+	// a cache under the same name is not registered, so closing it keeps the first one monitored
+	duplicate, err := NewImmunityCache(config)
+	require.Nil(t, err)
+	_ = duplicate.Close()
+	require.True(t, isMonitored(config.Name))
+
+	_ = cache.Close()
+	require.False(t, isMonitored(config.Name))
+
+	recreated, err := NewImmunityCache(config)
+	require.Nil(t, err)
+	require.True(t, isMonitored(config.Name))
+	_ = recreated.Close()
+}
+
+func TestImmunityCache_MemoryBudget(t *testing.T) {
+	budget, _ := memorybudget.NewCoordinator(common.MemoryBudgetConfig{MinSizePercent: 10})
+
+	config := createCacheConfigToTest()
+	config.MaxNumBytes = 1000
+	config.Priority = common.HighCachePriority
+	config.MemoryBudget = budget
+	cache, err := NewImmunityCache(config)
+	require.Nil(t, err)
+
+	caches := budget.Report().Caches
+	require.Equal(t, 1, len(caches))
+	require.Equal(t, config.Name, caches[0].Name)
+	require.Equal(t, common.HighCachePriority, caches[0].Priority)
+	require.Equal(t, uint64(1000), caches[0].RequestedSizeInBytes)
+
+	duplicate, err := NewImmunityCache(config)
+	require.Nil(t, duplicate)
+	require.Equal(t, common.ErrCacheAlreadyRegistered, err)
+
+	_ = cache.Close()
+	require.Empty(t, budget.Report().Caches)
+}
+
 func newCacheToTest(numChunks uint32, maxNumItems uint32, numMaxBytes uint32) *ImmunityCache {
 	cache, err := NewImmunityCache(CacheConfig{
 		Name:                        "test",
@@ -351,8 +684,106 @@ func newCacheToTest(numChunks uint32, maxNumItems uint32, numMaxBytes uint32) *I
 	return cache
 }
 
+func createCacheConfigToTest() CacheConfig {
+	return CacheConfig{
+		Name:                        "test",
+		NumChunks:                   1,
+		MaxNumItems:                 8,
+		MaxNumBytes:                 maxNumBytesUpperBound,
+		NumItemsToPreemptivelyEvict: 1,
+	}
+}
+
 func (ic *ImmunityCache) addTestItems(keys ...string) {
 	for _, key := range keys {
 		_, _ = ic.HasOrAdd([]byte(key), fmt.Sprintf("foo-%s", key), 100)
 	}
 }
+
+func TestImmunityCache_CacheStats(t *testing.T) {
+	cache := newCacheToTest(1, 4, maxNumBytesUpperBound)
+
+	cache.addTestItems("a", "b", "c", "d", "e", "f")
+	_, _ = cache.Get([]byte("a"))
+	_, _ = cache.Peek([]byte("c"))
+	_, _ = cache.Get([]byte("f"))
+
+	stats := cache.CacheStats()
+	require.Equal(t, 4, stats.NumItems)
+	require.Equal(t, uint64(4*(100+1+types.EntryOverheadInBytes)), stats.SizeInBytes)
+	require.Equal(t, uint64(2), stats.NumHits)
+	require.Equal(t, uint64(1), stats.NumMisses)
+	require.Equal(t, uint64(2), stats.NumEvictions)
+
+	// the evictions are kept after clearing the cache
+	cache.Clear()
+	stats = cache.CacheStats()
+	require.Equal(t, 0, stats.NumItems)
+	require.Equal(t, uint64(2), stats.NumEvictions)
+}
+
+func TestImmunityCache_Resize(t *testing.T) {
+	cache := newCacheToTest(1, 8, maxNumBytesUpperBound)
+	cache.addTestItems("a", "b", "c", "d", "e", "f")
+	cache.ImmunizeKeys(keysAsBytes([]string{"a", "b"}))
+
+	err := cache.Resize(4, maxNumBytesUpperBound)
+	require.Nil(t, err)
+	require.Equal(t, 4, cache.MaxSize())
+	require.ElementsMatch(t, []string{"a", "b", "e", "f"}, keysAsStrings(cache.Keys()))
+	require.Equal(t, uint64(2), cache.CacheStats().NumEvictions)
+
+	err = cache.Resize(8, 300)
+	require.Nil(t, err)
+	require.ElementsMatch(t, []string{"a", "b", "f"}, keysAsStrings(cache.Keys()))
+
+	// only immune items are left, they are not evicted
+	err = cache.Resize(8, 100)
+	require.Nil(t, err)
+	require.ElementsMatch(t, []string{"a", "b"}, keysAsStrings(cache.Keys()))
+
+	err = cache.Resize(0, 100)
+	require.ErrorIs(t, err, common.ErrInvalidConfig)
+	require.Equal(t, 8, cache.MaxSize())
+}
+
+func TestImmunityCache_EvictionHandlersShouldReceiveTheReason(t *testing.T) {
+	cache := newCacheToTest(1, 4, maxNumBytesUpperBound)
+	reasons := make(map[string]types.EvictionReason)
+	cache.RegisterEvictionHandler(func(key []byte, value interface{}, reason types.EvictionReason) {
+		require.Equal(t, fmt.Sprintf("foo-%s", key), value)
+		reasons[string(key)] = reason
+	}, "id")
+
+	cache.addTestItems("a", "b", "c", "d")
+	evicted := cache.Put([]byte("e"), "foo-e", 100)
+	require.True(t, evicted)
+	require.Equal(t, map[string]types.EvictionReason{"a": types.EvictedByCapacity}, reasons)
+
+	cache.ImmunizeKeys(keysAsBytes([]string{"e"}))
+	err := cache.Resize(4, 250)
+	require.Nil(t, err)
+	require.Equal(t, types.EvictedByCapacity, reasons["b"])
+	require.Equal(t, types.EvictedByCapacity, reasons["c"])
+
+	cache.Remove([]byte("d"))
+	cache.Remove([]byte("missing"))
+	require.Equal(t, types.EvictedByRemoval, reasons["d"])
+
+	cache.Clear()
+	require.Equal(t, types.EvictedByClear, reasons["e"])
+	require.Equal(t, 5, len(reasons))
+
+	cache.UnRegisterEvictionHandler("id")
+	cache.addTestItems("f")
+	cache.Remove([]byte("f"))
+	require.Equal(t, 5, len(reasons))
+}
+
+type sizerStub struct {
+	size int
+}
+
+func (stub *sizerStub) Size() int {
+	return stub.size
+}
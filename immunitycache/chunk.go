@@ -3,21 +3,32 @@ package immunitycache
 import (
 	"container/list"
 	"sync"
+	"time"
 
 	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/atomic"
 	"github.com/multiversx/mx-chain-storage-go/common"
 	"github.com/multiversx/mx-chain-storage-go/types"
 )
 
-var emptyStruct struct{}
-
 type immunityChunk struct {
-	config      immunityChunkConfig
-	items       map[string]chunkItemWrapper
-	itemsAsList *list.List
-	immuneKeys  map[string]struct{}
-	numBytes    int
-	mutex       sync.RWMutex
+	config               immunityChunkConfig
+	items                map[string]chunkItemWrapper
+	itemsAsList          *list.List
+	immuneKeys           map[string]immunity
+	numImmuneKeysByLevel [numImmunityLevels]int
+	// numPendingKeysByLevel counts, out of the immune keys, the ones whose items have not been added yet
+	numPendingKeysByLevel [numImmunityLevels]int
+	// pendingKeys holds the immune keys whose items have not been added yet, in the order of their immunization
+	pendingKeys     *list.List
+	pendingCounters pendingImmunityCounters
+	// nextImmunityExpiry is the earliest expiry of the immune keys, zero if none of them expires
+	nextImmunityExpiry time.Time
+	numBytes           int
+	// numEntryBytes is the reported size of the items, that includes the keys and the overhead of the entries
+	numEntryBytes int
+	numEvicted    atomic.Counter
+	mutex         sync.RWMutex
 }
 
 type chunkItemWrapper struct {
@@ -32,34 +43,184 @@ func newImmunityChunk(config immunityChunkConfig) *immunityChunk {
 		config:      config,
 		items:       make(map[string]chunkItemWrapper),
 		itemsAsList: list.New(),
-		immuneKeys:  make(map[string]struct{}),
+		immuneKeys:  make(map[string]immunity),
+		pendingKeys: list.New(),
 	}
 }
 
 // ImmunizeKeys marks keys as immune to eviction
 func (chunk *immunityChunk) ImmunizeKeys(keys [][]byte) (numNow, numFuture int) {
+	return chunk.ImmunizeKeysWithLevel(keys, LevelImmune)
+}
+
+// ImmunizeKeysWithLevel protects keys against eviction at the provided level, replacing their previous level, if any
+func (chunk *immunityChunk) ImmunizeKeysWithLevel(keys [][]byte, level ImmunityLevel) (numNow, numFuture int) {
 	chunk.mutex.Lock()
 	defer chunk.mutex.Unlock()
 
+	chunk.pruneExpiredImmunityNoLock()
+
+	now := chunk.config.clock.Now()
+	expiresAt := chunk.computeImmunityExpiryNoLock(now)
+
 	for _, key := range keys {
+		keyImmunity := immunity{
+			level:       level,
+			expiresAt:   expiresAt,
+			immunizedAt: now,
+		}
+
+		// A key immunized again moves to the end of the pending set
+		previous, wasImmune := chunk.immuneKeys[string(key)]
+		if wasImmune {
+			chunk.removePendingNoLock(&previous)
+		}
+
 		item, ok := chunk.getItemNoLock(string(key))
 
 		if ok {
 			// Item exists, immunize now!
-			item.immunizeAgainstEviction()
+			item.immunityLevel = level
 			numNow++
 		} else {
 			// Item not yet in cache, will be immunized in the future
+			keyImmunity.pendingElement = chunk.addPendingNoLock(string(key), level)
 			numFuture++
 		}
 
 		// Disregarding the items presence, we hold the immune key
-		chunk.immuneKeys[string(key)] = emptyStruct
+		chunk.setImmunityNoLock(string(key), keyImmunity)
 	}
 
 	return
 }
 
+func (chunk *immunityChunk) computeImmunityExpiryNoLock(now time.Time) time.Time {
+	if chunk.config.immunitySpan == 0 {
+		return time.Time{}
+	}
+
+	return now.Add(chunk.config.immunitySpan)
+}
+
+func (chunk *immunityChunk) setImmunityNoLock(key string, keyImmunity immunity) {
+	previous, ok := chunk.immuneKeys[key]
+	if ok {
+		chunk.numImmuneKeysByLevel[previous.level]--
+	}
+
+	chunk.immuneKeys[key] = keyImmunity
+	chunk.numImmuneKeysByLevel[keyImmunity.level]++
+	chunk.trackImmunityExpiryNoLock(keyImmunity.expiresAt)
+}
+
+func (chunk *immunityChunk) trackImmunityExpiryNoLock(expiresAt time.Time) {
+	if expiresAt.IsZero() {
+		return
+	}
+	if chunk.nextImmunityExpiry.IsZero() || expiresAt.Before(chunk.nextImmunityExpiry) {
+		chunk.nextImmunityExpiry = expiresAt
+	}
+}
+
+// revokeImmunityNoLock forgets the immune key and makes its item, if contained, evictable
+func (chunk *immunityChunk) revokeImmunityNoLock(key string) bool {
+	keyImmunity, ok := chunk.immuneKeys[key]
+	if !ok {
+		return false
+	}
+
+	chunk.removePendingNoLock(&keyImmunity)
+	delete(chunk.immuneKeys, key)
+	chunk.numImmuneKeysByLevel[keyImmunity.level]--
+
+	item, ok := chunk.getItemNoLock(key)
+	if ok {
+		item.immunityLevel = LevelNormal
+	}
+
+	return true
+}
+
+// pruneExpiredImmunityNoLock revokes the expired immunities, including the pending ones. The immune keys are only
+// visited once the earliest expiry has been reached.
+func (chunk *immunityChunk) pruneExpiredImmunityNoLock() {
+	chunk.pruneExpiredPendingNoLock()
+
+	if chunk.nextImmunityExpiry.IsZero() {
+		return
+	}
+
+	now := chunk.config.clock.Now()
+	if now.Before(chunk.nextImmunityExpiry) {
+		return
+	}
+
+	chunk.nextImmunityExpiry = time.Time{}
+	for key, keyImmunity := range chunk.immuneKeys {
+		if keyImmunity.expiresAt.IsZero() {
+			continue
+		}
+		if !now.Before(keyImmunity.expiresAt) {
+			_ = chunk.revokeImmunityNoLock(key)
+			continue
+		}
+
+		chunk.trackImmunityExpiryNoLock(keyImmunity.expiresAt)
+	}
+}
+
+// RevokeImmunity revokes the immunity of the provided keys, whatever their level, and returns the number of keys
+// which were immune
+func (chunk *immunityChunk) RevokeImmunity(keys [][]byte) int {
+	chunk.mutex.Lock()
+	defer chunk.mutex.Unlock()
+
+	numRevoked := 0
+	for _, key := range keys {
+		if chunk.revokeImmunityNoLock(string(key)) {
+			numRevoked++
+		}
+	}
+
+	return numRevoked
+}
+
+// DemoteImmunity moves the keys protected at the level "from" to the lower level "to", LevelNormal meaning their
+// immunity is revoked. The expiry of the immunity is kept. It returns the number of demoted keys.
+func (chunk *immunityChunk) DemoteImmunity(from ImmunityLevel, to ImmunityLevel) int {
+	chunk.mutex.Lock()
+	defer chunk.mutex.Unlock()
+
+	chunk.pruneExpiredImmunityNoLock()
+
+	numDemoted := 0
+	for key, keyImmunity := range chunk.immuneKeys {
+		if keyImmunity.level != from {
+			continue
+		}
+
+		numDemoted++
+		if to == LevelNormal {
+			_ = chunk.revokeImmunityNoLock(key)
+			continue
+		}
+
+		if keyImmunity.pendingElement != nil {
+			chunk.numPendingKeysByLevel[from]--
+			chunk.numPendingKeysByLevel[to]++
+		}
+		keyImmunity.level = to
+		chunk.setImmunityNoLock(key, keyImmunity)
+		item, ok := chunk.getItemNoLock(key)
+		if ok {
+			item.immunityLevel = to
+		}
+	}
+
+	return numDemoted
+}
+
 func (chunk *immunityChunk) getItemNoLock(key string) (*cacheItem, bool) {
 	wrapper, ok := chunk.items[key]
 	if !ok {
@@ -71,34 +232,43 @@ func (chunk *immunityChunk) getItemNoLock(key string) (*cacheItem, bool) {
 
 // AddItem add an item to the chunk
 func (chunk *immunityChunk) AddItem(item *cacheItem) (has, added bool) {
+	has, added, _ = chunk.AddItemReturnEvicted(item)
+	return has, added
+}
+
+// AddItemReturnEvicted adds an item to the chunk and returns the items evicted in order to make room for it
+func (chunk *immunityChunk) AddItemReturnEvicted(item *cacheItem) (has, added bool, evicted []*cacheItem) {
 	chunk.mutex.Lock()
 	defer chunk.mutex.Unlock()
 
-	err := chunk.evictItemsIfCapacityExceededNoLock()
+	chunk.pruneExpiredImmunityNoLock()
+
+	evicted, err := chunk.evictItemsIfCapacityExceededNoLock()
 	if err != nil {
 		// No more room for the new item
-		return false, false
+		return false, false, evicted
 	}
 
 	// Discard duplicates
 	if chunk.itemExistsNoLock(item) {
-		return true, false
+		return true, false, evicted
 	}
 
 	chunk.addItemNoLock(item)
 	chunk.immunizeItemOnAddNoLock(item)
 	chunk.trackNumBytesOnAddNoLock(item)
-	return false, true
+	return false, true, evicted
 }
 
-func (chunk *immunityChunk) evictItemsIfCapacityExceededNoLock() error {
+func (chunk *immunityChunk) evictItemsIfCapacityExceededNoLock() ([]*cacheItem, error) {
 	if !chunk.isCapacityExceededNoLock() {
-		return nil
+		return nil, nil
 	}
 
-	numRemoved, err := chunk.evictItemsNoLock()
-	chunk.monitorEvictionNoLock(numRemoved, err)
-	return err
+	evicted, err := chunk.evictItemsNoLock()
+	chunk.numEvicted.Add(int64(len(evicted)))
+	chunk.monitorEvictionNoLock(len(evicted), err)
+	return evicted, err
 }
 
 func (chunk *immunityChunk) isCapacityExceededNoLock() bool {
@@ -107,33 +277,48 @@ func (chunk *immunityChunk) isCapacityExceededNoLock() bool {
 	return tooManyItems || tooManyBytes
 }
 
-func (chunk *immunityChunk) evictItemsNoLock() (numRemoved int, err error) {
-	numToRemoveEachStep := int(chunk.config.numItemsToPreemptivelyEvict)
-
-	// We perform the first step out of the loop in order to detect & return error
-	numRemovedInStep := chunk.removeOldestNoLock(numToRemoveEachStep)
-	numRemoved += numRemovedInStep
+func (chunk *immunityChunk) evictItemsNoLock() (removed []*cacheItem, err error) {
+	removed = chunk.evictItemsUpToLevelNoLock(LevelNormal, removed)
 
-	if numRemovedInStep == 0 {
-		return 0, common.ErrFailedCacheEviction
+	// the protected items are evicted only if evicting the items without immunity does not suffice
+	if chunk.isCapacityExceededNoLock() {
+		removed = chunk.evictItemsUpToLevelNoLock(LevelProtected, removed)
 	}
 
-	for chunk.isCapacityExceededNoLock() && numRemovedInStep == numToRemoveEachStep {
-		numRemovedInStep = chunk.removeOldestNoLock(numToRemoveEachStep)
-		numRemoved += numRemovedInStep
+	if len(removed) == 0 {
+		return nil, common.ErrFailedCacheEviction
 	}
 
-	return numRemoved, nil
+	return removed, nil
 }
 
-func (chunk *immunityChunk) removeOldestNoLock(numToRemove int) int {
+// evictItemsUpToLevelNoLock removes, in steps, the oldest items not protected above the provided level, until the
+// capacity is no longer exceeded or there are no more such items
+func (chunk *immunityChunk) evictItemsUpToLevelNoLock(maxLevel ImmunityLevel, removed []*cacheItem) []*cacheItem {
+	numToRemoveEachStep := int(chunk.config.numItemsToPreemptivelyEvict)
+
+	for {
+		numRemovedBefore := len(removed)
+		removed = chunk.removeOldestNoLock(numToRemoveEachStep, maxLevel, removed)
+		numRemovedInStep := len(removed) - numRemovedBefore
+
+		isStepComplete := numRemovedInStep > 0 && numRemovedInStep == numToRemoveEachStep
+		if !isStepComplete || !chunk.isCapacityExceededNoLock() {
+			return removed
+		}
+	}
+}
+
+// removeOldestNoLock removes the oldest items not protected above the provided level, appending them to the
+// provided slice
+func (chunk *immunityChunk) removeOldestNoLock(numToRemove int, maxLevel ImmunityLevel, removed []*cacheItem) []*cacheItem {
 	numRemoved := 0
 	element := chunk.itemsAsList.Front()
 
 	for element != nil && numRemoved < numToRemove {
 		item := element.Value.(*cacheItem)
 
-		if item.isImmuneToEviction() {
+		if !item.isEvictableUpToLevel(maxLevel) {
 			element = element.Next()
 			continue
 		}
@@ -141,11 +326,16 @@ func (chunk *immunityChunk) removeOldestNoLock(numToRemove int) int {
 		elementToRemove := element
 		element = element.Next()
 
+		if item.immunityLevel != LevelNormal {
+			// the key of an evicted protected item is no longer protected
+			_ = chunk.revokeImmunityNoLock(item.key)
+		}
 		chunk.removeNoLock(elementToRemove)
+		removed = append(removed, item)
 		numRemoved++
 	}
 
-	return numRemoved
+	return removed
 }
 
 func (chunk *immunityChunk) removeNoLock(element *list.Element) {
@@ -174,14 +364,16 @@ func (chunk *immunityChunk) addItemNoLock(item *cacheItem) {
 }
 
 func (chunk *immunityChunk) immunizeItemOnAddNoLock(item *cacheItem) {
-	if _, immunize := chunk.immuneKeys[item.key]; immunize {
-		item.immunizeAgainstEviction()
-		// We do not remove the key from "immuneKeys", we hold it there until item's removal.
+	if keyImmunity, immunize := chunk.immuneKeys[item.key]; immunize {
+		item.immunityLevel = keyImmunity.level
+		chunk.fulfillPendingNoLock(item.key)
+		// We do not remove the key from "immuneKeys", we hold it there until item's removal or immunity expiry.
 	}
 }
 
 func (chunk *immunityChunk) trackNumBytesOnAddNoLock(item *cacheItem) {
 	chunk.numBytes += item.size
+	chunk.numEntryBytes += item.entrySize
 }
 
 // GetItem gets an item from the chunk
@@ -195,30 +387,40 @@ func (chunk *immunityChunk) GetItem(key string) (*cacheItem, bool) {
 // In order to improve the robustness of the cache, we'll also remove from "keysToImmunizeFuture",
 // even if the item does not actually exist in the cache - to allow un-doing immunization intent (perhaps useful for rollbacks).
 func (chunk *immunityChunk) RemoveItem(key string) bool {
+	_, ok := chunk.RemoveItemReturnRemoved(key)
+	return ok
+}
+
+// RemoveItemReturnRemoved removes an item from the chunk and returns it, if it was contained
+func (chunk *immunityChunk) RemoveItemReturnRemoved(key string) (*cacheItem, bool) {
 	chunk.mutex.Lock()
 	defer chunk.mutex.Unlock()
 
-	delete(chunk.immuneKeys, key)
+	_ = chunk.revokeImmunityNoLock(key)
 
 	wrapper, ok := chunk.items[key]
 	if !ok {
-		return false
+		return nil, false
 	}
 
 	chunk.removeNoLock(wrapper.listElement)
-	return true
+	return wrapper.item, true
 }
 
 func (chunk *immunityChunk) trackNumBytesOnRemoveNoLock(item *cacheItem) {
 	chunk.numBytes -= item.size
 	chunk.numBytes = core.MaxInt(chunk.numBytes, 0)
+	chunk.numEntryBytes -= item.entrySize
+	chunk.numEntryBytes = core.MaxInt(chunk.numEntryBytes, 0)
 }
 
-// RemoveOldest removes a number of old items
+// RemoveOldest removes a number of old items, without immunity
 func (chunk *immunityChunk) RemoveOldest(numToRemove int) int {
 	chunk.mutex.Lock()
 	defer chunk.mutex.Unlock()
-	return chunk.removeOldestNoLock(numToRemove)
+
+	chunk.pruneExpiredImmunityNoLock()
+	return len(chunk.removeOldestNoLock(numToRemove, LevelNormal, nil))
 }
 
 // Count counts the items
@@ -228,13 +430,44 @@ func (chunk *immunityChunk) Count() int {
 	return len(chunk.items)
 }
 
-// CountImmune counts the immune items
+// CountImmune counts the immune keys, of any level. The expired immunities are revoked beforehand.
 func (chunk *immunityChunk) CountImmune() int {
-	chunk.mutex.RLock()
-	defer chunk.mutex.RUnlock()
+	chunk.mutex.Lock()
+	defer chunk.mutex.Unlock()
+
+	chunk.pruneExpiredImmunityNoLock()
 	return len(chunk.immuneKeys)
 }
 
+// CountImmuneByLevel counts the immune keys of the provided level. The expired immunities are revoked beforehand.
+func (chunk *immunityChunk) CountImmuneByLevel(level ImmunityLevel) int {
+	chunk.mutex.Lock()
+	defer chunk.mutex.Unlock()
+
+	chunk.pruneExpiredImmunityNoLock()
+	return chunk.numImmuneKeysByLevel[level]
+}
+
+// CountImmuneItems counts the immune keys whose items are contained, the pending immunities being left out. The
+// expired immunities are revoked beforehand.
+func (chunk *immunityChunk) CountImmuneItems() int {
+	chunk.mutex.Lock()
+	defer chunk.mutex.Unlock()
+
+	chunk.pruneExpiredImmunityNoLock()
+	return len(chunk.immuneKeys) - chunk.pendingKeys.Len()
+}
+
+// CountImmuneItemsByLevel counts the immune keys of the provided level whose items are contained, the pending
+// immunities being left out. The expired immunities are revoked beforehand.
+func (chunk *immunityChunk) CountImmuneItemsByLevel(level ImmunityLevel) int {
+	chunk.mutex.Lock()
+	defer chunk.mutex.Unlock()
+
+	chunk.pruneExpiredImmunityNoLock()
+	return chunk.numImmuneKeysByLevel[level] - chunk.numPendingKeysByLevel[level]
+}
+
 // NumBytes gets the number of bytes stored
 func (chunk *immunityChunk) NumBytes() int {
 	chunk.mutex.RLock()
@@ -242,6 +475,13 @@ func (chunk *immunityChunk) NumBytes() int {
 	return chunk.numBytes
 }
 
+// NumEntryBytes gets the number of bytes stored, including the keys and the overhead of the entries
+func (chunk *immunityChunk) NumEntryBytes() int {
+	chunk.mutex.RLock()
+	defer chunk.mutex.RUnlock()
+	return chunk.numEntryBytes
+}
+
 // KeysInOrder gets the keys, in order
 func (chunk *immunityChunk) KeysInOrder() [][]byte {
 	chunk.mutex.RLock()
@@ -268,6 +508,19 @@ func (chunk *immunityChunk) AppendKeys(keysAccumulator [][]byte) [][]byte {
 	return keysAccumulator
 }
 
+// ItemsInOrder gets the items, in order
+func (chunk *immunityChunk) ItemsInOrder() []*cacheItem {
+	chunk.mutex.RLock()
+	defer chunk.mutex.RUnlock()
+
+	items := make([]*cacheItem, 0, chunk.itemsAsList.Len())
+	for element := chunk.itemsAsList.Front(); element != nil; element = element.Next() {
+		items = append(items, element.Value.(*cacheItem))
+	}
+
+	return items
+}
+
 // ForEachItem iterates over the items in the chunk
 func (chunk *immunityChunk) ForEachItem(function types.ForEachItem) {
 	chunk.mutex.RLock()
@@ -278,6 +531,43 @@ func (chunk *immunityChunk) ForEachItem(function types.ForEachItem) {
 	}
 }
 
+// Resize changes the limits of the chunk, evicting the oldest items that are not immune if the new limits are exceeded.
+// It returns the evicted items.
+func (chunk *immunityChunk) Resize(config immunityChunkConfig) []*cacheItem {
+	chunk.mutex.Lock()
+	defer chunk.mutex.Unlock()
+
+	chunk.config = config
+	chunk.pruneExpiredImmunityNoLock()
+	chunk.dropPendingOverLimitNoLock(int(config.maxNumPendingImmunities))
+
+	var evicted []*cacheItem
+	maxLevel := LevelNormal
+	for len(chunk.items) > int(config.maxNumItems) || chunk.numBytes > int(config.maxNumBytes) {
+		numToRemove := core.MaxInt(len(chunk.items)-int(config.maxNumItems), 1)
+		numEvictedBefore := len(evicted)
+		evicted = chunk.removeOldestNoLock(numToRemove, maxLevel, evicted)
+		numRemoved := len(evicted) - numEvictedBefore
+		chunk.numEvicted.Add(int64(numRemoved))
+		if numRemoved == 0 {
+			if maxLevel == LevelProtected {
+				// only immune items are left
+				break
+			}
+
+			// the protected items are evicted only if evicting the items without immunity does not suffice
+			maxLevel = LevelProtected
+		}
+	}
+
+	return evicted
+}
+
+// NumEvicted returns the number of items evicted from the chunk so far
+func (chunk *immunityChunk) NumEvicted() int {
+	return int(chunk.numEvicted.Get())
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (chunk *immunityChunk) IsInterfaceNil() bool {
 	return chunk == nil
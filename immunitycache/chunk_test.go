@@ -4,6 +4,7 @@ import (
 	"math"
 	"testing"
 
+	"github.com/multiversx/mx-chain-storage-go/clock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -76,11 +77,57 @@ func TestImmunityChunk_AddItemDoesNotEvictImmuneItems(t *testing.T) {
 	require.Equal(t, []string{"x", "y", "b"}, keysAsStrings(chunk.KeysInOrder()))
 }
 
+func TestImmunityChunk_AddItemEvictsProtectedItemsOnlyIfNeeded(t *testing.T) {
+	chunk := newChunkToTest(3, math.MaxUint32)
+	chunk.addTestItems("x", "y", "z")
+
+	_, _ = chunk.ImmunizeKeysWithLevel(keysAsBytes([]string{"x"}), LevelProtected)
+	_, _ = chunk.ImmunizeKeys(keysAsBytes([]string{"y"}))
+
+	chunk.addTestItems("a")
+	require.Equal(t, []string{"x", "y", "a"}, keysAsStrings(chunk.KeysInOrder()))
+
+	_, _ = chunk.ImmunizeKeysWithLevel(keysAsBytes([]string{"a"}), LevelProtected)
+	chunk.addTestItems("b")
+	require.Equal(t, []string{"y", "a", "b"}, keysAsStrings(chunk.KeysInOrder()))
+
+	// the key of the evicted item is no longer protected
+	require.Equal(t, 1, chunk.CountImmuneByLevel(LevelProtected))
+	require.Equal(t, 0, chunk.RevokeImmunity(keysAsBytes([]string{"x"})))
+}
+
+func TestImmunityChunk_ResizeEvictsProtectedItemsOnlyIfNeeded(t *testing.T) {
+	chunk := newUnconstrainedChunkToTest()
+	chunk.addTestItems("a", "b", "c", "d", "e")
+
+	_, _ = chunk.ImmunizeKeysWithLevel(keysAsBytes([]string{"a", "b"}), LevelProtected)
+	_, _ = chunk.ImmunizeKeys(keysAsBytes([]string{"c"}))
+
+	evicted := chunk.Resize(newResizedChunkConfigToTest(2))
+	require.Equal(t, 3, len(evicted))
+	require.Equal(t, []string{"b", "c"}, keysAsStrings(chunk.KeysInOrder()))
+
+	evicted = chunk.Resize(newResizedChunkConfigToTest(0))
+	require.Equal(t, 1, len(evicted))
+	require.Equal(t, []string{"c"}, keysAsStrings(chunk.KeysInOrder()))
+}
+
+func newResizedChunkConfigToTest(maxNumItems uint32) immunityChunkConfig {
+	return immunityChunkConfig{
+		maxNumItems:             maxNumItems,
+		maxNumBytes:             maxNumBytesUpperBound,
+		maxNumPendingImmunities: math.MaxUint32,
+		clock:                   clock.NewSystemClock(),
+	}
+}
+
 func newUnconstrainedChunkToTest() *immunityChunk {
 	chunk := newImmunityChunk(immunityChunkConfig{
 		maxNumItems:                 math.MaxUint32,
 		maxNumBytes:                 maxNumBytesUpperBound,
 		numItemsToPreemptivelyEvict: math.MaxUint32,
+		maxNumPendingImmunities:     math.MaxUint32,
+		clock:                       clock.NewSystemClock(),
 	})
 
 	return chunk
@@ -91,6 +138,8 @@ func newChunkToTest(maxNumItems uint32, numMaxBytes uint32) *immunityChunk {
 		maxNumItems:                 maxNumItems,
 		maxNumBytes:                 numMaxBytes,
 		numItemsToPreemptivelyEvict: 1,
+		maxNumPendingImmunities:     math.MaxUint32,
+		clock:                       clock.NewSystemClock(),
 	})
 
 	return chunk
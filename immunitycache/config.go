@@ -3,9 +3,11 @@ package immunitycache
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/multiversx/mx-chain-core-go/core"
 	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
 )
 
 const numChunksLowerBound = 1
@@ -22,6 +24,24 @@ type CacheConfig struct {
 	MaxNumItems                 uint32
 	MaxNumBytes                 uint32
 	NumItemsToPreemptivelyEvict uint32
+	Priority                    common.CachePriority
+	// MaxNumProtectedItems and MaxNumImmuneItems are the quotas of the immunity levels. Zero, or a value above
+	// MaxNumItems, means MaxNumItems.
+	MaxNumProtectedItems uint32
+	MaxNumImmuneItems    uint32
+	// ImmunitySpanInSeconds is the time after which the immunity granted to a key is revoked. Zero means never.
+	ImmunitySpanInSeconds uint32
+	// MaxNumPendingImmunities bounds the number of immune keys whose items have not been added yet, the oldest ones
+	// being dropped when it is reached. Zero means unbounded.
+	MaxNumPendingImmunities uint32
+	// PendingImmunitySpanInSeconds is the time after which the immunity of a key whose item has not been added yet is
+	// revoked. Zero means never.
+	PendingImmunitySpanInSeconds uint32
+	// Clock is the source of time for the expiry of the immunities. Nil means the system clock.
+	Clock types.Clock `json:"-"`
+	// MemoryBudget is the budget the cache joins with its MaxNumBytes, under its name and priority, until it is
+	// closed. Nil means the cache is not part of a memory budget.
+	MemoryBudget types.MemoryBudgetHandler `json:"-"`
 }
 
 // Verify verifies the validity of the configuration
@@ -48,14 +68,36 @@ func (config *CacheConfig) Verify() error {
 func (config *CacheConfig) getChunkConfig() immunityChunkConfig {
 	numChunks := core.MaxUint32(config.NumChunks, 1)
 
+	maxNumPendingImmunities := uint32(0)
+	if config.MaxNumPendingImmunities > 0 {
+		maxNumPendingImmunities = core.MaxUint32(config.MaxNumPendingImmunities/numChunks, 1)
+	}
+
 	return immunityChunkConfig{
 		cacheName:                   config.Name,
 		maxNumItems:                 config.MaxNumItems / numChunks,
 		maxNumBytes:                 config.MaxNumBytes / numChunks,
 		numItemsToPreemptivelyEvict: config.NumItemsToPreemptivelyEvict / numChunks,
+		immunitySpan:                time.Duration(config.ImmunitySpanInSeconds) * time.Second,
+		maxNumPendingImmunities:     maxNumPendingImmunities,
+		pendingImmunitySpan:         time.Duration(config.PendingImmunitySpanInSeconds) * time.Second,
+		clock:                       config.Clock,
 	}
 }
 
+// getMaxNumImmuneItemsOfLevel returns the quota of the provided immunity level
+func (config *CacheConfig) getMaxNumImmuneItemsOfLevel(level ImmunityLevel) uint32 {
+	quota := config.MaxNumImmuneItems
+	if level == LevelProtected {
+		quota = config.MaxNumProtectedItems
+	}
+	if quota == 0 || quota > config.MaxNumItems {
+		return config.MaxNumItems
+	}
+
+	return quota
+}
+
 // String returns a readable representation of the object
 func (config *CacheConfig) String() string {
 	bytes, err := json.Marshal(config)
@@ -71,14 +113,22 @@ type immunityChunkConfig struct {
 	maxNumItems                 uint32
 	maxNumBytes                 uint32
 	numItemsToPreemptivelyEvict uint32
+	immunitySpan                time.Duration
+	// maxNumPendingImmunities is zero for unbounded pending immunities
+	maxNumPendingImmunities uint32
+	pendingImmunitySpan     time.Duration
+	clock                   types.Clock
 }
 
 // String returns a readable representation of the object
 func (config *immunityChunkConfig) String() string {
 	return fmt.Sprintf(
-		"maxNumItems: %d, maxNumBytes: %d, numItemsToPreemptivelyEvict: %d",
+		"maxNumItems: %d, maxNumBytes: %d, numItemsToPreemptivelyEvict: %d, immunitySpan: %s, maxNumPendingImmunities: %d, pendingImmunitySpan: %s",
 		config.maxNumItems,
 		config.maxNumBytes,
 		config.numItemsToPreemptivelyEvict,
+		config.immunitySpan,
+		config.maxNumPendingImmunities,
+		config.pendingImmunitySpan,
 	)
 }
@@ -0,0 +1,34 @@
+package immunitycache
+
+import (
+	"container/list"
+	"time"
+)
+
+// ImmunityLevel defines how strongly an item is protected against eviction
+type ImmunityLevel uint8
+
+const (
+	// LevelNormal is the level of the items without immunity, which are evicted first
+	LevelNormal ImmunityLevel = iota
+	// LevelProtected is the level of the items evicted only if evicting the items without immunity does not suffice
+	LevelProtected
+	// LevelImmune is the level of the items which are never evicted
+	LevelImmune
+)
+
+const numImmunityLevels = int(LevelImmune) + 1
+
+// isImmunity returns true for the levels which can be granted to the keys
+func (level ImmunityLevel) isImmunity() bool {
+	return level == LevelProtected || level == LevelImmune
+}
+
+// immunity is the protection granted to a key, expiring at the provided time. A zero time means it never expires.
+type immunity struct {
+	level       ImmunityLevel
+	expiresAt   time.Time
+	immunizedAt time.Time
+	// pendingElement is the position of the key in the pending set, nil if its item has been added
+	pendingElement *list.Element
+}
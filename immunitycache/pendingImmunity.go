@@ -0,0 +1,157 @@
+package immunitycache
+
+import (
+	"container/list"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/atomic"
+)
+
+// PendingImmunityStats holds the metrics of the immunities granted to keys not yet contained by the cache
+type PendingImmunityStats struct {
+	NumPending   uint64
+	NumFulfilled uint64
+	NumExpired   uint64
+	NumDropped   uint64
+	NumCleared   uint64
+}
+
+// pendingImmunityCounters counts how the pending immunities have ended: the item has been added, the pending span
+// has passed, the pending set was full or the key has been cleared as stale
+type pendingImmunityCounters struct {
+	numFulfilled atomic.Counter
+	numExpired   atomic.Counter
+	numDropped   atomic.Counter
+	numCleared   atomic.Counter
+}
+
+func (counters *pendingImmunityCounters) add(other *pendingImmunityCounters) {
+	counters.numFulfilled.Add(other.numFulfilled.Get())
+	counters.numExpired.Add(other.numExpired.Get())
+	counters.numDropped.Add(other.numDropped.Get())
+	counters.numCleared.Add(other.numCleared.Get())
+}
+
+func (counters *pendingImmunityCounters) addToStats(stats *PendingImmunityStats) {
+	stats.NumFulfilled += counters.numFulfilled.GetUint64()
+	stats.NumExpired += counters.numExpired.GetUint64()
+	stats.NumDropped += counters.numDropped.GetUint64()
+	stats.NumCleared += counters.numCleared.GetUint64()
+}
+
+// addPendingNoLock appends the key to the pending set, dropping the oldest pending keys if the set is bounded and full
+func (chunk *immunityChunk) addPendingNoLock(key string, level ImmunityLevel) *list.Element {
+	chunk.dropPendingOverLimitNoLock(int(chunk.config.maxNumPendingImmunities) - 1)
+	chunk.numPendingKeysByLevel[level]++
+
+	return chunk.pendingKeys.PushBack(key)
+}
+
+// dropPendingOverLimitNoLock revokes the oldest pending immunities until at most the provided number is left. It does
+// nothing if the pending immunities are unbounded.
+func (chunk *immunityChunk) dropPendingOverLimitNoLock(limit int) {
+	if chunk.config.maxNumPendingImmunities == 0 {
+		return
+	}
+
+	for chunk.pendingKeys.Len() > 0 && chunk.pendingKeys.Len() > limit {
+		_ = chunk.revokeImmunityNoLock(chunk.pendingKeys.Front().Value.(string))
+		chunk.pendingCounters.numDropped.Increment()
+	}
+}
+
+// removePendingNoLock takes the key out of the pending set, if it was pending
+func (chunk *immunityChunk) removePendingNoLock(keyImmunity *immunity) {
+	if keyImmunity.pendingElement == nil {
+		return
+	}
+
+	chunk.pendingKeys.Remove(keyImmunity.pendingElement)
+	chunk.numPendingKeysByLevel[keyImmunity.level]--
+	keyImmunity.pendingElement = nil
+}
+
+// fulfillPendingNoLock marks the pending immunity of a key whose item has just been added as fulfilled
+func (chunk *immunityChunk) fulfillPendingNoLock(key string) {
+	keyImmunity, ok := chunk.immuneKeys[key]
+	if !ok || keyImmunity.pendingElement == nil {
+		return
+	}
+
+	chunk.removePendingNoLock(&keyImmunity)
+	chunk.immuneKeys[key] = keyImmunity
+	chunk.pendingCounters.numFulfilled.Increment()
+}
+
+// pruneExpiredPendingNoLock revokes the pending immunities granted more than the pending span ago. The pending set
+// being ordered by the immunization time, only the expired keys are visited.
+func (chunk *immunityChunk) pruneExpiredPendingNoLock() {
+	if chunk.config.pendingImmunitySpan == 0 || chunk.pendingKeys.Len() == 0 {
+		return
+	}
+
+	minImmunizedAt := chunk.config.clock.Now().Add(-chunk.config.pendingImmunitySpan)
+	for _, key := range chunk.getPendingImmunizedUntilNoLock(minImmunizedAt) {
+		_ = chunk.revokeImmunityNoLock(key)
+		chunk.pendingCounters.numExpired.Increment()
+	}
+}
+
+// getPendingImmunizedUntilNoLock returns the pending keys immunized not later than the provided time
+func (chunk *immunityChunk) getPendingImmunizedUntilNoLock(until time.Time) []string {
+	keys := make([]string, 0)
+	for element := chunk.pendingKeys.Front(); element != nil; element = element.Next() {
+		key := element.Value.(string)
+		if chunk.immuneKeys[key].immunizedAt.After(until) {
+			break
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// StaleImmuneKeys returns the keys immunized at least minAge ago whose items have not been added yet
+func (chunk *immunityChunk) StaleImmuneKeys(minAge time.Duration) [][]byte {
+	chunk.mutex.Lock()
+	defer chunk.mutex.Unlock()
+
+	chunk.pruneExpiredImmunityNoLock()
+
+	staleKeys := chunk.getPendingImmunizedUntilNoLock(chunk.config.clock.Now().Add(-minAge))
+	keys := make([][]byte, 0, len(staleKeys))
+	for _, key := range staleKeys {
+		keys = append(keys, []byte(key))
+	}
+
+	return keys
+}
+
+// ClearStaleImmuneKeys revokes the immunity of the keys immunized at least minAge ago whose items have not been added
+// yet, returning the number of revoked keys
+func (chunk *immunityChunk) ClearStaleImmuneKeys(minAge time.Duration) int {
+	chunk.mutex.Lock()
+	defer chunk.mutex.Unlock()
+
+	chunk.pruneExpiredImmunityNoLock()
+
+	staleKeys := chunk.getPendingImmunizedUntilNoLock(chunk.config.clock.Now().Add(-minAge))
+	for _, key := range staleKeys {
+		_ = chunk.revokeImmunityNoLock(key)
+	}
+	chunk.pendingCounters.numCleared.Add(int64(len(staleKeys)))
+
+	return len(staleKeys)
+}
+
+// AddPendingImmunityStats accumulates the metrics of the pending immunities of the chunk into the provided stats
+func (chunk *immunityChunk) AddPendingImmunityStats(stats *PendingImmunityStats) {
+	chunk.mutex.Lock()
+	defer chunk.mutex.Unlock()
+
+	chunk.pruneExpiredImmunityNoLock()
+
+	stats.NumPending += uint64(chunk.pendingKeys.Len())
+	chunk.pendingCounters.addToStats(stats)
+}
@@ -0,0 +1,330 @@
+package loadingcache
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+var _ types.LoadingCacher = (*loadingCache)(nil)
+
+var log = logger.GetOrCreate("storage/loadingcache")
+
+// ArgsLoadingCache holds the arguments needed to create a loading cache
+type ArgsLoadingCache struct {
+	// Cacher holds the loaded entries. It should be used exclusively by the loading cache.
+	Cacher types.Cacher
+	Loader types.LoaderFunc
+	// FreshnessSpan is the time after which a loaded value becomes stale and is refreshed in background, while still
+	// being served. A failed refresh is retried after the ErrorCachingSpan, or after the FreshnessSpan if the load
+	// errors are not cached. Zero means the values never become stale.
+	FreshnessSpan time.Duration
+	// ErrorCachingSpan is the time for which a load error is served without calling the loader again. Zero means the
+	// load errors are not cached.
+	ErrorCachingSpan time.Duration
+}
+
+// loadedEntry is the value held by the underlying cacher, either a loaded value or a load error
+type loadedEntry struct {
+	value           interface{}
+	sizeInBytes     int
+	err             error
+	loadedAt        time.Time
+	failedRefreshAt time.Time
+}
+
+// loadCall is a load in progress, shared by all the callers missing the same key. A load superseded by a Put,
+// Remove or Clear of the key still answers its callers, but its outcome is not stored.
+type loadCall struct {
+	done         chan struct{}
+	value        interface{}
+	err          error
+	isSuperseded bool
+}
+
+type loadingCache struct {
+	cacher           types.Cacher
+	loader           types.LoaderFunc
+	freshnessSpan    time.Duration
+	errorCachingSpan time.Duration
+
+	mutInFlight sync.Mutex
+	inFlight    map[string]*loadCall
+	closed      bool
+	wgRefreshes sync.WaitGroup
+}
+
+// NewLoadingCache creates a cache which loads the missing keys through the provided loader, the concurrent loads
+// of the same key being coalesced into a single call of the loader
+func NewLoadingCache(args ArgsLoadingCache) (*loadingCache, error) {
+	err := checkArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &loadingCache{
+		cacher:           args.Cacher,
+		loader:           args.Loader,
+		freshnessSpan:    args.FreshnessSpan,
+		errorCachingSpan: args.ErrorCachingSpan,
+		inFlight:         make(map[string]*loadCall),
+	}, nil
+}
+
+func checkArgs(args ArgsLoadingCache) error {
+	if check.IfNil(args.Cacher) {
+		return common.ErrNilCacher
+	}
+	if args.Loader == nil {
+		return common.ErrNilLoader
+	}
+	if args.FreshnessSpan < 0 {
+		return fmt.Errorf("%w: negative freshness span", common.ErrInvalidConfig)
+	}
+	if args.ErrorCachingSpan < 0 {
+		return fmt.Errorf("%w: negative error caching span", common.ErrInvalidConfig)
+	}
+
+	return nil
+}
+
+// Get returns the value of the key. A missing key, or a key whose cached load error has expired, is loaded
+// synchronously. A stale value is returned right away, while being refreshed in background.
+func (lc *loadingCache) Get(key []byte) (interface{}, error) {
+	entry, ok := lc.getEntry(key)
+	if !ok {
+		return lc.load(key)
+	}
+
+	if entry.err != nil {
+		if time.Since(entry.loadedAt) < lc.errorCachingSpan {
+			return nil, entry.err
+		}
+
+		return lc.load(key)
+	}
+
+	if lc.isStale(entry) {
+		lc.refreshInBackground(key)
+	}
+
+	return entry.value, nil
+}
+
+func (lc *loadingCache) getEntry(key []byte) (*loadedEntry, bool) {
+	value, ok := lc.cacher.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	entry, ok := value.(*loadedEntry)
+	if !ok {
+		log.Warn("loadingCache.getEntry: unexpected value in the underlying cacher", "key", key)
+		return nil, false
+	}
+
+	return entry, true
+}
+
+func (lc *loadingCache) isStale(entry *loadedEntry) bool {
+	if lc.freshnessSpan == 0 || time.Since(entry.loadedAt) < lc.freshnessSpan {
+		return false
+	}
+
+	return entry.failedRefreshAt.IsZero() || time.Since(entry.failedRefreshAt) >= lc.refreshRetrySpan()
+}
+
+func (lc *loadingCache) refreshRetrySpan() time.Duration {
+	if lc.errorCachingSpan > 0 {
+		return lc.errorCachingSpan
+	}
+
+	return lc.freshnessSpan
+}
+
+// load calls the loader, or waits for the load of the same key which is already in progress
+func (lc *loadingCache) load(key []byte) (interface{}, error) {
+	lc.mutInFlight.Lock()
+	call, ok := lc.inFlight[string(key)]
+	if ok {
+		lc.mutInFlight.Unlock()
+		<-call.done
+
+		return call.value, call.err
+	}
+
+	call = &loadCall{
+		done: make(chan struct{}),
+	}
+	lc.inFlight[string(key)] = call
+	lc.mutInFlight.Unlock()
+
+	lc.doLoad(key, call, false)
+
+	return call.value, call.err
+}
+
+// refreshInBackground starts the refresh of a stale key, unless a load of the key is already in progress
+func (lc *loadingCache) refreshInBackground(key []byte) {
+	lc.mutInFlight.Lock()
+	defer lc.mutInFlight.Unlock()
+
+	if lc.closed {
+		return
+	}
+	_, ok := lc.inFlight[string(key)]
+	if ok {
+		return
+	}
+
+	call := &loadCall{
+		done: make(chan struct{}),
+	}
+	lc.inFlight[string(key)] = call
+	lc.wgRefreshes.Add(1)
+
+	keyCopy := []byte(string(key))
+	go func() {
+		defer lc.wgRefreshes.Done()
+
+		lc.doLoad(keyCopy, call, true)
+	}()
+}
+
+// doLoad calls the loader and stores its outcome, unless the load was superseded in the meantime. On a failed
+// refresh the stale value is kept, so that it is served until the next refresh attempt, which is delayed.
+func (lc *loadingCache) doLoad(key []byte, call *loadCall, isRefresh bool) {
+	value, sizeInBytes, err := lc.callLoader(key)
+	call.value, call.err = value, err
+
+	lc.mutInFlight.Lock()
+	lc.storeLoadOutcomeNoLock(key, call, sizeInBytes, isRefresh)
+	delete(lc.inFlight, string(key))
+	lc.mutInFlight.Unlock()
+
+	close(call.done)
+}
+
+// callLoader calls the loader, a panic of the loader being returned as an error
+func (lc *loadingCache) callLoader(key []byte) (value interface{}, sizeInBytes int, err error) {
+	defer func() {
+		r := recover()
+		if r != nil {
+			value, sizeInBytes = nil, 0
+			err = fmt.Errorf("%w: %v", common.ErrLoaderPanicked, r)
+		}
+	}()
+
+	return lc.loader(key)
+}
+
+func (lc *loadingCache) storeLoadOutcomeNoLock(key []byte, call *loadCall, sizeInBytes int, isRefresh bool) {
+	if call.isSuperseded {
+		return
+	}
+
+	if call.err != nil {
+		log.Debug("loadingCache.doLoad", "key", key, "refresh", isRefresh, "error", call.err)
+		if isRefresh {
+			lc.recordFailedRefreshNoLock(key)
+			return
+		}
+		if lc.errorCachingSpan == 0 {
+			return
+		}
+
+		_ = lc.cacher.Put(key, &loadedEntry{err: call.err, loadedAt: time.Now()}, 0)
+		return
+	}
+
+	lc.putNoLock(key, call.value, sizeInBytes)
+}
+
+// recordFailedRefreshNoLock marks the stale entry, so that its next refresh is delayed
+func (lc *loadingCache) recordFailedRefreshNoLock(key []byte) {
+	value, ok := lc.cacher.Peek(key)
+	if !ok {
+		return
+	}
+	entry, ok := value.(*loadedEntry)
+	if !ok || entry.err != nil {
+		return
+	}
+
+	markedEntry := *entry
+	markedEntry.failedRefreshAt = time.Now()
+	_ = lc.cacher.Put(key, &markedEntry, entry.sizeInBytes)
+}
+
+// Put adds or replaces a fresh value. A load of the key in progress will not overwrite it.
+func (lc *loadingCache) Put(key []byte, value interface{}, sizeInBytes int) {
+	lc.mutInFlight.Lock()
+	defer lc.mutInFlight.Unlock()
+
+	lc.supersedeLoadNoLock(key)
+	lc.putNoLock(key, value, sizeInBytes)
+}
+
+func (lc *loadingCache) putNoLock(key []byte, value interface{}, sizeInBytes int) {
+	entry := &loadedEntry{
+		value:       value,
+		sizeInBytes: sizeInBytes,
+		loadedAt:    time.Now(),
+	}
+
+	_ = lc.cacher.Put(key, entry, sizeInBytes)
+}
+
+func (lc *loadingCache) supersedeLoadNoLock(key []byte) {
+	call, ok := lc.inFlight[string(key)]
+	if ok {
+		call.isSuperseded = true
+	}
+}
+
+// Remove removes the provided key from the cache. A load of the key in progress will not add it back.
+func (lc *loadingCache) Remove(key []byte) {
+	lc.mutInFlight.Lock()
+	defer lc.mutInFlight.Unlock()
+
+	lc.supersedeLoadNoLock(key)
+	lc.cacher.Remove(key)
+}
+
+// Clear is used to completely clear the cache. The loads in progress will not add their keys back.
+func (lc *loadingCache) Clear() {
+	lc.mutInFlight.Lock()
+	defer lc.mutInFlight.Unlock()
+
+	for _, call := range lc.inFlight {
+		call.isSuperseded = true
+	}
+	lc.cacher.Clear()
+}
+
+// Len returns the number of items in the cache, the cached load errors included
+func (lc *loadingCache) Len() int {
+	return lc.cacher.Len()
+}
+
+// Close stops the background refreshes, waits for the ones in progress and closes the underlying cacher. The
+// missing keys are still loaded synchronously after closing.
+func (lc *loadingCache) Close() error {
+	lc.mutInFlight.Lock()
+	lc.closed = true
+	lc.mutInFlight.Unlock()
+
+	lc.wgRefreshes.Wait()
+
+	return lc.cacher.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (lc *loadingCache) IsInterfaceNil() bool {
+	return lc == nil
+}
@@ -0,0 +1,438 @@
+package loadingcache_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/loadingcache"
+	"github.com/multiversx/mx-chain-storage-go/lrucache"
+	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errLoad = errors.New("load error")
+
+// countingLoader returns the key as value and counts the calls, failing while the fail flag is set
+type countingLoader struct {
+	numCalls atomic.Int64
+	fail     atomic.Bool
+	delay    time.Duration
+	version  atomic.Int64
+}
+
+func (cl *countingLoader) load(key []byte) (interface{}, int, error) {
+	cl.numCalls.Add(1)
+	time.Sleep(cl.delay)
+	if cl.fail.Load() {
+		return nil, 0, errLoad
+	}
+
+	value := string(key) + string(rune('0'+cl.version.Load()))
+
+	return value, len(value), nil
+}
+
+func createArgs(loader *countingLoader) loadingcache.ArgsLoadingCache {
+	cacher, _ := lrucache.NewCache(100)
+
+	return loadingcache.ArgsLoadingCache{
+		Cacher: cacher,
+		Loader: loader.load,
+	}
+}
+
+func TestNewLoadingCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil cacher should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs(&countingLoader{})
+		args.Cacher = nil
+		cache, err := loadingcache.NewLoadingCache(args)
+		assert.Nil(t, cache)
+		assert.Equal(t, common.ErrNilCacher, err)
+	})
+	t.Run("nil loader should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs(&countingLoader{})
+		args.Loader = nil
+		cache, err := loadingcache.NewLoadingCache(args)
+		assert.Nil(t, cache)
+		assert.Equal(t, common.ErrNilLoader, err)
+	})
+	t.Run("negative freshness span should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs(&countingLoader{})
+		args.FreshnessSpan = -time.Second
+		cache, err := loadingcache.NewLoadingCache(args)
+		assert.Nil(t, cache)
+		assert.True(t, errors.Is(err, common.ErrInvalidConfig))
+	})
+	t.Run("negative error caching span should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs(&countingLoader{})
+		args.ErrorCachingSpan = -time.Second
+		cache, err := loadingcache.NewLoadingCache(args)
+		assert.Nil(t, cache)
+		assert.True(t, errors.Is(err, common.ErrInvalidConfig))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := loadingcache.NewLoadingCache(createArgs(&countingLoader{}))
+		assert.Nil(t, err)
+		assert.False(t, cache.IsInterfaceNil())
+	})
+}
+
+func TestLoadingCache_GetShouldLoadOnceAndServeFromCache(t *testing.T) {
+	t.Parallel()
+
+	loader := &countingLoader{}
+	cache, _ := loadingcache.NewLoadingCache(createArgs(loader))
+
+	value, err := cache.Get([]byte("a"))
+	assert.Nil(t, err)
+	assert.Equal(t, "a0", value)
+
+	value, err = cache.Get([]byte("a"))
+	assert.Nil(t, err)
+	assert.Equal(t, "a0", value)
+	assert.Equal(t, int64(1), loader.numCalls.Load())
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestLoadingCache_ConcurrentMissesShouldBeCoalesced(t *testing.T) {
+	t.Parallel()
+
+	loader := &countingLoader{delay: 50 * time.Millisecond}
+	cache, _ := loadingcache.NewLoadingCache(createArgs(loader))
+
+	numGoroutines := 50
+	wg := sync.WaitGroup{}
+	wg.Add(numGoroutines)
+	for i := 0; i < numGoroutines; i++ {
+		go func() {
+			defer wg.Done()
+
+			value, err := cache.Get([]byte("a"))
+			assert.Nil(t, err)
+			assert.Equal(t, "a0", value)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(1), loader.numCalls.Load())
+}
+
+func TestLoadingCache_StaleValueShouldBeServedWhileRefreshing(t *testing.T) {
+	t.Parallel()
+
+	loader := &countingLoader{}
+	args := createArgs(loader)
+	args.FreshnessSpan = 20 * time.Millisecond
+	cache, _ := loadingcache.NewLoadingCache(args)
+
+	value, _ := cache.Get([]byte("a"))
+	assert.Equal(t, "a0", value)
+
+	time.Sleep(30 * time.Millisecond)
+	loader.version.Store(1)
+
+	value, err := cache.Get([]byte("a"))
+	assert.Nil(t, err)
+	assert.Equal(t, "a0", value)
+
+	assert.Eventually(t, func() bool {
+		value, _ = cache.Get([]byte("a"))
+		return value == "a1"
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int64(2), loader.numCalls.Load())
+}
+
+func TestLoadingCache_FailedRefreshShouldKeepStaleValue(t *testing.T) {
+	t.Parallel()
+
+	loader := &countingLoader{}
+	args := createArgs(loader)
+	args.FreshnessSpan = 10 * time.Millisecond
+	args.ErrorCachingSpan = time.Hour
+	cache, _ := loadingcache.NewLoadingCache(args)
+
+	_, _ = cache.Get([]byte("a"))
+	time.Sleep(20 * time.Millisecond)
+	loader.fail.Store(true)
+
+	value, err := cache.Get([]byte("a"))
+	assert.Nil(t, err)
+	assert.Equal(t, "a0", value)
+
+	require.Nil(t, cache.Close())
+	value, err = cache.Get([]byte("a"))
+	assert.Nil(t, err)
+	assert.Equal(t, "a0", value)
+	assert.Equal(t, int64(2), loader.numCalls.Load())
+}
+
+func TestLoadingCache_FailedRefreshShouldDelayTheNextRefresh(t *testing.T) {
+	t.Parallel()
+
+	t.Run("by the error caching span", func(t *testing.T) {
+		t.Parallel()
+
+		loader := &countingLoader{}
+		args := createArgs(loader)
+		args.FreshnessSpan = 10 * time.Millisecond
+		args.ErrorCachingSpan = time.Hour
+		cache, _ := loadingcache.NewLoadingCache(args)
+
+		_, _ = cache.Get([]byte("a"))
+		time.Sleep(20 * time.Millisecond)
+		loader.fail.Store(true)
+
+		_, _ = cache.Get([]byte("a"))
+		require.Nil(t, cache.Close())
+		assert.Equal(t, int64(2), loader.numCalls.Load())
+
+		for i := 0; i < 10; i++ {
+			value, err := cache.Get([]byte("a"))
+			assert.Nil(t, err)
+			assert.Equal(t, "a0", value)
+		}
+		assert.Equal(t, int64(2), loader.numCalls.Load())
+	})
+	t.Run("by the freshness span if the errors are not cached", func(t *testing.T) {
+		t.Parallel()
+
+		loader := &countingLoader{}
+		args := createArgs(loader)
+		args.FreshnessSpan = 50 * time.Millisecond
+		cache, _ := loadingcache.NewLoadingCache(args)
+
+		_, _ = cache.Get([]byte("a"))
+		time.Sleep(60 * time.Millisecond)
+		loader.fail.Store(true)
+
+		_, _ = cache.Get([]byte("a"))
+		assert.Eventually(t, func() bool {
+			return loader.numCalls.Load() == 2
+		}, time.Second, time.Millisecond)
+		// until recorded, the failed refresh is still in progress
+		value, _ := cache.Get([]byte("a"))
+		assert.Equal(t, "a0", value)
+		assert.Equal(t, int64(2), loader.numCalls.Load())
+
+		time.Sleep(60 * time.Millisecond)
+		loader.fail.Store(false)
+		loader.version.Store(1)
+
+		_, _ = cache.Get([]byte("a"))
+		assert.Eventually(t, func() bool {
+			value, _ = cache.Get([]byte("a"))
+			return value == "a1"
+		}, time.Second, 5*time.Millisecond)
+		assert.Equal(t, int64(3), loader.numCalls.Load())
+		require.Nil(t, cache.Close())
+	})
+}
+
+func TestLoadingCache_ErrorCaching(t *testing.T) {
+	t.Parallel()
+
+	t.Run("errors not cached should call the loader each time", func(t *testing.T) {
+		t.Parallel()
+
+		loader := &countingLoader{}
+		loader.fail.Store(true)
+		cache, _ := loadingcache.NewLoadingCache(createArgs(loader))
+
+		for i := 0; i < 3; i++ {
+			value, err := cache.Get([]byte("a"))
+			assert.Nil(t, value)
+			assert.Equal(t, errLoad, err)
+		}
+		assert.Equal(t, int64(3), loader.numCalls.Load())
+		assert.Equal(t, 0, cache.Len())
+	})
+	t.Run("cached errors should be served until expired", func(t *testing.T) {
+		t.Parallel()
+
+		loader := &countingLoader{}
+		loader.fail.Store(true)
+		args := createArgs(loader)
+		args.ErrorCachingSpan = 30 * time.Millisecond
+		cache, _ := loadingcache.NewLoadingCache(args)
+
+		for i := 0; i < 3; i++ {
+			_, err := cache.Get([]byte("a"))
+			assert.Equal(t, errLoad, err)
+		}
+		assert.Equal(t, int64(1), loader.numCalls.Load())
+
+		time.Sleep(40 * time.Millisecond)
+		loader.fail.Store(false)
+		value, err := cache.Get([]byte("a"))
+		assert.Nil(t, err)
+		assert.Equal(t, "a0", value)
+		assert.Equal(t, int64(2), loader.numCalls.Load())
+	})
+}
+
+func TestLoadingCache_PutRemoveAndClear(t *testing.T) {
+	t.Parallel()
+
+	loader := &countingLoader{}
+	cache, _ := loadingcache.NewLoadingCache(createArgs(loader))
+
+	cache.Put([]byte("a"), "explicit", 8)
+	value, err := cache.Get([]byte("a"))
+	assert.Nil(t, err)
+	assert.Equal(t, "explicit", value)
+	assert.Equal(t, int64(0), loader.numCalls.Load())
+
+	cache.Remove([]byte("a"))
+	value, _ = cache.Get([]byte("a"))
+	assert.Equal(t, "a0", value)
+
+	cache.Clear()
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestLoadingCache_UnexpectedValueInCacherShouldBeReloaded(t *testing.T) {
+	t.Parallel()
+
+	loader := &countingLoader{}
+	args := createArgs(loader)
+	args.Cacher.Put([]byte("a"), "raw value", 9)
+	cache, _ := loadingcache.NewLoadingCache(args)
+
+	value, err := cache.Get([]byte("a"))
+	assert.Nil(t, err)
+	assert.Equal(t, "a0", value)
+	assert.Equal(t, int64(1), loader.numCalls.Load())
+}
+
+// blockingLoader signals each call on started, then waits for release before returning the outcome of the loadFunc
+type blockingLoader struct {
+	started  chan struct{}
+	release  chan struct{}
+	loadFunc func(key []byte) (interface{}, int, error)
+}
+
+func newBlockingLoader(loadFunc func(key []byte) (interface{}, int, error)) *blockingLoader {
+	return &blockingLoader{
+		started:  make(chan struct{}, 10),
+		release:  make(chan struct{}),
+		loadFunc: loadFunc,
+	}
+}
+
+func (bl *blockingLoader) load(key []byte) (interface{}, int, error) {
+	bl.started <- struct{}{}
+	<-bl.release
+
+	return bl.loadFunc(key)
+}
+
+func TestLoadingCache_SupersededLoadShouldNotBeStored(t *testing.T) {
+	t.Parallel()
+
+	loadValue := func(key []byte) (interface{}, int, error) {
+		return "loaded", 6, nil
+	}
+	startLoad := func(t *testing.T, cache types.LoadingCacher) chan interface{} {
+		results := make(chan interface{}, 1)
+		go func() {
+			value, err := cache.Get([]byte("a"))
+			assert.Nil(t, err)
+			results <- value
+		}()
+
+		return results
+	}
+
+	t.Run("remove during load", func(t *testing.T) {
+		t.Parallel()
+
+		loader := newBlockingLoader(loadValue)
+		cacher, _ := lrucache.NewCache(100)
+		cache, _ := loadingcache.NewLoadingCache(loadingcache.ArgsLoadingCache{Cacher: cacher, Loader: loader.load})
+
+		results := startLoad(t, cache)
+		<-loader.started
+		cache.Remove([]byte("a"))
+		close(loader.release)
+
+		// the caller of the load still gets the loaded value, but the removed key is not added back
+		assert.Equal(t, "loaded", <-results)
+		assert.Equal(t, 0, cache.Len())
+	})
+	t.Run("put during load", func(t *testing.T) {
+		t.Parallel()
+
+		loader := newBlockingLoader(loadValue)
+		cacher, _ := lrucache.NewCache(100)
+		cache, _ := loadingcache.NewLoadingCache(loadingcache.ArgsLoadingCache{Cacher: cacher, Loader: loader.load})
+
+		results := startLoad(t, cache)
+		<-loader.started
+		cache.Put([]byte("a"), "explicit", 8)
+		close(loader.release)
+
+		assert.Equal(t, "loaded", <-results)
+		value, err := cache.Get([]byte("a"))
+		assert.Nil(t, err)
+		assert.Equal(t, "explicit", value)
+	})
+	t.Run("clear during load", func(t *testing.T) {
+		t.Parallel()
+
+		loader := newBlockingLoader(loadValue)
+		cacher, _ := lrucache.NewCache(100)
+		cache, _ := loadingcache.NewLoadingCache(loadingcache.ArgsLoadingCache{Cacher: cacher, Loader: loader.load})
+
+		results := startLoad(t, cache)
+		<-loader.started
+		cache.Clear()
+		close(loader.release)
+
+		assert.Equal(t, "loaded", <-results)
+		assert.Equal(t, 0, cache.Len())
+	})
+}
+
+func TestLoadingCache_PanickingLoaderShouldReturnErrorToAllCallers(t *testing.T) {
+	t.Parallel()
+
+	loader := newBlockingLoader(func(key []byte) (interface{}, int, error) {
+		panic("loader failure")
+	})
+	cacher, _ := lrucache.NewCache(100)
+	cache, _ := loadingcache.NewLoadingCache(loadingcache.ArgsLoadingCache{Cacher: cacher, Loader: loader.load})
+
+	numCallers := 5
+	errs := make(chan error, numCallers)
+	for i := 0; i < numCallers; i++ {
+		go func() {
+			value, err := cache.Get([]byte("a"))
+			assert.Nil(t, value)
+			errs <- err
+		}()
+	}
+	<-loader.started
+	close(loader.release)
+
+	for i := 0; i < numCallers; i++ {
+		assert.True(t, errors.Is(<-errs, common.ErrLoaderPanicked))
+	}
+	assert.Equal(t, 0, cache.Len())
+}
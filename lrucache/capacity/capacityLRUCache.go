@@ -7,6 +7,7 @@ import (
 
 	logger "github.com/multiversx/mx-chain-logger-go"
 	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
 )
 
 var log = logger.GetOrCreate("storage/lrucache/capacity")
@@ -17,9 +18,13 @@ type capacityLRU struct {
 	size                   int
 	maxCapacityInBytes     int64
 	currentCapacityInBytes int64
+	entriesSizeInBytes     int64
 	//TODO investigate if we can replace this list with a binary tree. Check also the other implementation lruCache
 	evictList *list.List
 	items     map[interface{}]*list.Element
+	// evicted buffers the entries evicted during an operation, so that onEvicted is called outside the lock
+	evicted   []*entry
+	onEvicted func(key interface{}, value interface{})
 }
 
 // entry is used to hold a value in the evictList
@@ -27,10 +32,31 @@ type entry struct {
 	key   interface{}
 	value interface{}
 	size  int64
+	// entrySize is the reported size, including the key and the overhead of the entry
+	entrySize int64
+}
+
+func newEntry(key interface{}, value interface{}, sizeInBytes int64) entry {
+	return entry{
+		key:       key,
+		value:     value,
+		size:      sizeInBytes,
+		entrySize: computeEntrySize(key, value, sizeInBytes),
+	}
+}
+
+func computeEntrySize(key interface{}, value interface{}, sizeInBytes int64) int64 {
+	return int64(types.ComputeAnyKeyEntrySizeInBytes(key, value, int(sizeInBytes)))
 }
 
 // NewCapacityLRU constructs an CapacityLRU of the given size with a byte size capacity
 func NewCapacityLRU(size int, byteCapacity int64) (*capacityLRU, error) {
+	return NewCapacityLRUWithEviction(size, byteCapacity, nil)
+}
+
+// NewCapacityLRUWithEviction constructs an CapacityLRU of the given size with a byte size capacity. The optional
+// onEvicted callback is called, outside the cache lock, for each item evicted due to the capacity.
+func NewCapacityLRUWithEviction(size int, byteCapacity int64, onEvicted func(key interface{}, value interface{})) (*capacityLRU, error) {
 	if size < 1 {
 		return nil, common.ErrCacheSizeInvalid
 	}
@@ -42,10 +68,32 @@ func NewCapacityLRU(size int, byteCapacity int64) (*capacityLRU, error) {
 		maxCapacityInBytes: byteCapacity,
 		evictList:          list.New(),
 		items:              make(map[interface{}]*list.Element),
+		onEvicted:          onEvicted,
 	}
 	return c, nil
 }
 
+// Resize changes the limits of the cache, evicting the oldest items if the new limits are exceeded
+func (c *capacityLRU) Resize(size int, byteCapacity int64) error {
+	if size < 1 {
+		return common.ErrCacheSizeInvalid
+	}
+	if byteCapacity < 1 {
+		return common.ErrCacheCapacityInvalid
+	}
+
+	c.lock.Lock()
+	c.size = size
+	c.maxCapacityInBytes = byteCapacity
+	c.evictIfNeeded()
+	evicted := c.takeEvicted()
+	c.lock.Unlock()
+
+	notifyEvicted(c.onEvicted, evicted)
+
+	return nil
+}
+
 // Purge is used to completely clear the cache.
 func (c *capacityLRU) Purge() {
 	c.lock.Lock()
@@ -54,16 +102,20 @@ func (c *capacityLRU) Purge() {
 	c.items = make(map[interface{}]*list.Element)
 	c.evictList.Init()
 	c.currentCapacityInBytes = 0
+	c.entriesSizeInBytes = 0
 }
 
 // AddSized adds a value to the cache.  Returns true if an eviction occurred.
 func (c *capacityLRU) AddSized(key, value interface{}, sizeInBytes int64) bool {
 	c.lock.Lock()
-	defer c.lock.Unlock()
-
 	c.addSized(key, value, sizeInBytes)
+	c.evictIfNeeded()
+	evicted := c.takeEvicted()
+	c.lock.Unlock()
 
-	return c.evictIfNeeded()
+	notifyEvicted(c.onEvicted, evicted)
+
+	return len(evicted) > 0
 }
 
 func (c *capacityLRU) addSized(key interface{}, value interface{}, sizeInBytes int64) {
@@ -88,23 +140,15 @@ func (c *capacityLRU) addSized(key interface{}, value interface{}, sizeInBytes i
 // AddSizedAndReturnEvicted adds the given key-value pair to the cache, and returns the evicted values
 func (c *capacityLRU) AddSizedAndReturnEvicted(key, value interface{}, sizeInBytes int64) map[interface{}]interface{} {
 	c.lock.Lock()
-	defer c.lock.Unlock()
-
 	c.addSized(key, value, sizeInBytes)
+	c.evictIfNeeded()
+	evicted := c.takeEvicted()
+	c.lock.Unlock()
 
-	evictedValues := make(map[interface{}]interface{})
-	for c.shouldEvict() {
-		evicted := c.evictList.Back()
-		if evicted == nil {
-			continue
-		}
-
-		c.removeElement(evicted)
-		evictedEntry, ok := evicted.Value.(*entry)
-		if !ok {
-			continue
-		}
+	notifyEvicted(c.onEvicted, evicted)
 
+	evictedValues := make(map[interface{}]interface{}, len(evicted))
+	for _, evictedEntry := range evicted {
 		evictedValues[evictedEntry.key] = evictedEntry.value
 	}
 
@@ -112,14 +156,11 @@ func (c *capacityLRU) AddSizedAndReturnEvicted(key, value interface{}, sizeInByt
 }
 
 func (c *capacityLRU) addNew(key interface{}, value interface{}, sizeInBytes int64) {
-	ent := &entry{
-		key:   key,
-		value: value,
-		size:  sizeInBytes,
-	}
-	e := c.evictList.PushFront(ent)
+	ent := newEntry(key, value, sizeInBytes)
+	e := c.evictList.PushFront(&ent)
 	c.items[key] = e
 	c.currentCapacityInBytes += sizeInBytes
+	c.entriesSizeInBytes += ent.entrySize
 }
 
 func (c *capacityLRU) update(key interface{}, value interface{}, sizeInBytes int64, ent *list.Element) {
@@ -127,8 +168,11 @@ func (c *capacityLRU) update(key interface{}, value interface{}, sizeInBytes int
 
 	e := ent.Value.(*entry)
 	sizeDiff := sizeInBytes - e.size
+	entrySize := computeEntrySize(key, value, sizeInBytes)
+	c.entriesSizeInBytes += entrySize - e.entrySize
 	e.value = value
 	e.size = sizeInBytes
+	e.entrySize = entrySize
 	c.currentCapacityInBytes += sizeDiff
 
 	c.adjustSize(key, sizeInBytes)
@@ -177,16 +221,19 @@ func (c *capacityLRU) AddSizedIfMissing(key, value interface{}, sizeInBytes int6
 	}
 
 	c.lock.Lock()
-	defer c.lock.Unlock()
-
 	_, ok := c.items[key]
 	if ok {
+		c.lock.Unlock()
 		return true, false
 	}
 	c.addNew(key, value, sizeInBytes)
-	evicted := c.evictIfNeeded()
+	c.evictIfNeeded()
+	evicted := c.takeEvicted()
+	c.lock.Unlock()
+
+	notifyEvicted(c.onEvicted, evicted)
 
-	return false, evicted
+	return false, len(evicted) > 0
 }
 
 // Peek returns the key value (or undefined if not found) without updating
@@ -215,6 +262,21 @@ func (c *capacityLRU) Remove(key interface{}) bool {
 	return false
 }
 
+// RemoveIfSame removes the provided key from the cache only if it still holds the provided value, returning if the
+// key was removed. The values are compared by identity, hence they should be comparable.
+func (c *capacityLRU) RemoveIfSame(key interface{}, value interface{}) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	ent, ok := c.items[key]
+	if !ok || ent.Value.(*entry).value != value {
+		return false
+	}
+
+	c.removeElement(ent)
+	return true
+}
+
 // Keys returns a slice of the keys in the cache, from oldest to newest.
 func (c *capacityLRU) Keys() []interface{} {
 	c.lock.Lock()
@@ -245,11 +307,21 @@ func (c *capacityLRU) SizeInBytesContained() uint64 {
 	return uint64(c.currentCapacityInBytes)
 }
 
-// removeOldest removes the oldest item from the cache.
+// EntriesSizeInBytes returns the size in bytes of all contained entries, including the keys and the overhead of
+// the entries
+func (c *capacityLRU) EntriesSizeInBytes() uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return uint64(c.entriesSizeInBytes)
+}
+
+// removeOldest removes the oldest item from the cache, buffering it as evicted.
 func (c *capacityLRU) removeOldest() {
 	ent := c.evictList.Back()
 	if ent != nil {
 		c.removeElement(ent)
+		c.evicted = append(c.evicted, ent.Value.(*entry))
 	}
 }
 
@@ -259,6 +331,7 @@ func (c *capacityLRU) removeElement(e *list.Element) {
 	kv := e.Value.(*entry)
 	delete(c.items, kv.key)
 	c.currentCapacityInBytes -= kv.size
+	c.entriesSizeInBytes -= kv.entrySize
 }
 
 func (c *capacityLRU) adjustSize(key interface{}, sizeInBytes int64) {
@@ -284,16 +357,29 @@ func (c *capacityLRU) shouldEvict() bool {
 	return c.evictList.Len() > c.size || c.currentCapacityInBytes > c.maxCapacityInBytes
 }
 
-func (c *capacityLRU) evictIfNeeded() bool {
-	evicted := false
+func (c *capacityLRU) evictIfNeeded() {
 	for c.shouldEvict() {
 		c.removeOldest()
-		evicted = true
 	}
+}
+
+func (c *capacityLRU) takeEvicted() []*entry {
+	evicted := c.evicted
+	c.evicted = nil
 
 	return evicted
 }
 
+func notifyEvicted(onEvicted func(key interface{}, value interface{}), evicted []*entry) {
+	if onEvicted == nil {
+		return
+	}
+
+	for _, ent := range evicted {
+		onEvicted(ent.key, ent.value)
+	}
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (c *capacityLRU) IsInterfaceNil() bool {
 	return c == nil
@@ -0,0 +1,117 @@
+package capacity
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+const (
+	benchCacheSize     = 1000
+	benchCacheBytes    = benchCacheSize * 100
+	benchItemSize      = 100
+	benchNumKeys       = 100000
+	benchZipfSkew      = 1.1
+	benchScanLen       = 5 * benchCacheSize
+	benchScanFrequency = 20000
+)
+
+type benchCache interface {
+	AddSized(key, value interface{}, sizeInBytes int64) bool
+	Get(key interface{}) (interface{}, bool)
+}
+
+// createSkewedWorkload returns keys following a Zipf distribution, interrupted by sequential scans
+// through keys that are accessed only once
+func createSkewedWorkload(numAccesses int, withScans bool) []string {
+	zipf := rand.NewZipf(rand.New(rand.NewSource(0)), benchZipfSkew, 1, benchNumKeys-1)
+
+	keys := make([]string, 0, numAccesses)
+	scanIndex := 0
+	for len(keys) < numAccesses {
+		if withScans && len(keys)%benchScanFrequency == 0 {
+			for i := 0; i < benchScanLen && len(keys) < numAccesses; i++ {
+				keys = append(keys, "scan"+strconv.Itoa(scanIndex))
+				scanIndex++
+			}
+		}
+
+		keys = append(keys, strconv.FormatUint(zipf.Uint64(), 10))
+	}
+
+	return keys
+}
+
+// runWorkload reads each key, adding it on miss, as a storage unit does
+func runWorkload(b *testing.B, cache benchCache, keys []string) {
+	numHits := 0
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		key := keys[i%len(keys)]
+		_, ok := cache.Get(key)
+		if ok {
+			numHits++
+			continue
+		}
+
+		cache.AddSized(key, key, benchItemSize)
+	}
+	b.StopTimer()
+
+	b.ReportMetric(float64(numHits)/float64(b.N), "hit-ratio")
+}
+
+func benchmarkSkewedWorkload(b *testing.B, createCache func() benchCache, withScans bool) {
+	keys := createSkewedWorkload(1000000, withScans)
+	runWorkload(b, createCache(), keys)
+}
+
+func createBenchCapacityLRU() benchCache {
+	cache, _ := NewCapacityLRU(benchCacheSize, benchCacheBytes)
+	return cache
+}
+
+func createBenchTinyLFUCapacityLRU() benchCache {
+	cache, _ := NewTinyLFUCapacityLRU(benchCacheSize, benchCacheBytes)
+	return cache
+}
+
+func BenchmarkCapacityLRU_Zipf(b *testing.B) {
+	benchmarkSkewedWorkload(b, createBenchCapacityLRU, false)
+}
+
+func BenchmarkTinyLFUCapacityLRU_Zipf(b *testing.B) {
+	benchmarkSkewedWorkload(b, createBenchTinyLFUCapacityLRU, false)
+}
+
+func BenchmarkCapacityLRU_ZipfWithScans(b *testing.B) {
+	benchmarkSkewedWorkload(b, createBenchCapacityLRU, true)
+}
+
+func BenchmarkTinyLFUCapacityLRU_ZipfWithScans(b *testing.B) {
+	benchmarkSkewedWorkload(b, createBenchTinyLFUCapacityLRU, true)
+}
+
+func benchmarkParallelGet(b *testing.B, cache benchCache) {
+	for i := 0; i < benchCacheSize; i++ {
+		cache.AddSized(strconv.Itoa(i), i, benchItemSize)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			_, _ = cache.Get(strconv.Itoa(i % benchCacheSize))
+			i++
+		}
+	})
+}
+
+func BenchmarkCapacityLRU_ParallelGet(b *testing.B) {
+	benchmarkParallelGet(b, createBenchCapacityLRU())
+}
+
+func BenchmarkShardedCapacityLRU_ParallelGet(b *testing.B) {
+	cache, _ := NewShardedCapacityLRU(16, benchCacheSize, benchCacheBytes)
+	benchmarkParallelGet(b, cache)
+}
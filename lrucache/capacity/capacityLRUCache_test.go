@@ -497,3 +497,93 @@ func TestCapacityLRUCache_AddSizedAndReturnEvictedEvictionBySizeInBytesOneLargeE
 	assert.True(t, c.Contains(keys[1]))
 	assert.True(t, c.Contains(keys[2]))
 }
+
+func TestCapacityLRUCache_Resize(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid limits should error", func(t *testing.T) {
+		t.Parallel()
+
+		c := createDefaultCache()
+		assert.Equal(t, common.ErrCacheSizeInvalid, c.Resize(0, 100))
+		assert.Equal(t, common.ErrCacheCapacityInvalid, c.Resize(100, 0))
+	})
+	t.Run("shrinking should evict the oldest items", func(t *testing.T) {
+		t.Parallel()
+
+		c, _ := NewCapacityLRU(10, 1000)
+		for i := 0; i < 10; i++ {
+			c.AddSized(i, i, 100)
+		}
+
+		err := c.Resize(10, 500)
+		assert.Nil(t, err)
+		assert.Equal(t, 5, c.Len())
+		assert.Equal(t, uint64(500), c.SizeInBytesContained())
+		assert.False(t, c.Contains(4))
+		assert.True(t, c.Contains(5))
+
+		err = c.Resize(2, 500)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, c.Len())
+		assert.True(t, c.Contains(8))
+		assert.True(t, c.Contains(9))
+
+		// growing allows new items without evictions
+		err = c.Resize(5, 500)
+		assert.Nil(t, err)
+		evicted := c.AddSized(10, 10, 100)
+		assert.False(t, evicted)
+		assert.Equal(t, 3, c.Len())
+	})
+}
+
+func TestCapacityLRUCache_EvictionCallbackShouldBeCalledOutsideTheLock(t *testing.T) {
+	t.Parallel()
+
+	evicted := make(map[interface{}]interface{})
+	var cache *capacityLRU
+	cache, _ = NewCapacityLRUWithEviction(3, 100, func(key interface{}, value interface{}) {
+		evicted[key] = value
+		// would deadlock if called under the lock
+		_ = cache.Len()
+	})
+
+	for i := 0; i < 4; i++ {
+		cache.AddSized(i, i*10, 1)
+	}
+	assert.Equal(t, map[interface{}]interface{}{0: 0}, evicted)
+
+	_, isEvicted := cache.AddSizedIfMissing(4, 40, 1)
+	assert.True(t, isEvicted)
+	evictedValues := cache.AddSizedAndReturnEvicted(5, 50, 1)
+	assert.Equal(t, map[interface{}]interface{}{2: 20}, evictedValues)
+
+	// the removals are not evictions
+	cache.Remove(5)
+	cache.Purge()
+	assert.Equal(t, map[interface{}]interface{}{0: 0, 1: 10, 2: 20}, evicted)
+
+	cache.AddSized(6, 60, 1)
+	cache.AddSized(7, 70, 1)
+	_ = cache.Resize(1, 100)
+	assert.Equal(t, 60, evicted[6])
+}
+
+func TestCapacityLRUCache_RemoveIfSame(t *testing.T) {
+	t.Parallel()
+
+	c, _ := NewCapacityLRU(100000, 1000)
+	oldValue := &struct{ id int }{id: 1}
+	newValue := &struct{ id int }{id: 2}
+
+	c.AddSized("key", oldValue, 10)
+	c.AddSized("key", newValue, 10)
+	assert.False(t, c.RemoveIfSame("key", oldValue))
+	assert.True(t, c.Contains("key"))
+	assert.False(t, c.RemoveIfSame("missing", newValue))
+
+	assert.True(t, c.RemoveIfSame("key", newValue))
+	assert.False(t, c.Contains("key"))
+	assert.Zero(t, c.SizeInBytesContained())
+}
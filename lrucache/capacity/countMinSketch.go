@@ -0,0 +1,119 @@
+package capacity
+
+import (
+	"hash/fnv"
+	"math/bits"
+)
+
+const (
+	sketchDepth = 4
+	// widthMultiplier keeps the rows sparse enough for the collisions to rarely inflate the estimates
+	widthMultiplier  = 8
+	maxCounterValue  = 15
+	minSketchWidth   = 16
+	sampleMultiplier = 10
+)
+
+// sketchSeeds are used to derive the per row indexes from a single hash of the key
+var sketchSeeds = [sketchDepth]uint64{0xc3a5c85c97cb3127, 0xb492b66fbe98f273, 0x9ae16a3b2f90404f, 0xcbf29ce484222325}
+
+// countMinSketch estimates the access frequency of the keys using 4 rows of small saturating counters.
+// After a sample of increments, all the counters are halved, so that the old popularity fades away. It is not
+// concurrent safe.
+type countMinSketch struct {
+	rows          [sketchDepth][]uint8
+	mask          uint64
+	numIncrements uint64
+	sampleSize    uint64
+}
+
+// newCountMinSketch creates a sketch dimensioned for the provided number of cached items. The counters are
+// halved after a sample of accesses ten times larger than the number of items.
+func newCountMinSketch(numItems int) *countMinSketch {
+	width := nextPowerOfTwo(numItems * widthMultiplier)
+	if width < minSketchWidth {
+		width = minSketchWidth
+	}
+
+	sketch := &countMinSketch{
+		mask:       uint64(width - 1),
+		sampleSize: uint64(numItems) * sampleMultiplier,
+	}
+	for i := range sketch.rows {
+		sketch.rows[i] = make([]uint8, width)
+	}
+
+	return sketch
+}
+
+func nextPowerOfTwo(value int) int {
+	if value <= 1 {
+		return 1
+	}
+
+	return 1 << bits.Len(uint(value-1))
+}
+
+func hashKey(key []byte) uint64 {
+	hasher := fnv.New64a()
+	_, _ = hasher.Write(key)
+
+	return hasher.Sum64()
+}
+
+func (sketch *countMinSketch) index(hash uint64, row int) uint64 {
+	mixed := (hash ^ sketchSeeds[row]) * sketchSeeds[(row+1)%sketchDepth]
+	mixed ^= mixed >> 32
+
+	return mixed & sketch.mask
+}
+
+// increment records an access of the key and returns true if the sketch was aged
+func (sketch *countMinSketch) increment(hash uint64) bool {
+	for row := range sketch.rows {
+		idx := sketch.index(hash, row)
+		if sketch.rows[row][idx] < maxCounterValue {
+			sketch.rows[row][idx]++
+		}
+	}
+
+	sketch.numIncrements++
+	if sketch.numIncrements < sketch.sampleSize {
+		return false
+	}
+
+	sketch.age()
+
+	return true
+}
+
+// estimate returns the lowest counter of the key, which is an upper bound of its recent accesses
+func (sketch *countMinSketch) estimate(hash uint64) uint8 {
+	minValue := uint8(maxCounterValue)
+	for row := range sketch.rows {
+		value := sketch.rows[row][sketch.index(hash, row)]
+		if value < minValue {
+			minValue = value
+		}
+	}
+
+	return minValue
+}
+
+func (sketch *countMinSketch) age() {
+	for row := range sketch.rows {
+		for i := range sketch.rows[row] {
+			sketch.rows[row][i] >>= 1
+		}
+	}
+	sketch.numIncrements /= 2
+}
+
+func (sketch *countMinSketch) reset() {
+	for row := range sketch.rows {
+		for i := range sketch.rows[row] {
+			sketch.rows[row][i] = 0
+		}
+	}
+	sketch.numIncrements = 0
+}
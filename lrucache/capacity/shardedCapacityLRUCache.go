@@ -0,0 +1,208 @@
+package capacity
+
+import (
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+var _ types.AdaptedSizedLRUCache = (*shardedCapacityLRU)(nil)
+
+const (
+	fnvOffset32 = 2166136261
+	fnvPrime32  = 16777619
+)
+
+// shardedCapacityLRU partitions the keys, by their hash, between independently locked capacity LRU caches, so that
+// the concurrent operations on different keys do not contend on a single lock. The number of items and the size
+// in bytes are split evenly between the shards, hence a shard may evict before the whole cache is full.
+type shardedCapacityLRU struct {
+	shards []*capacityLRU
+}
+
+// NewShardedCapacityLRU constructs a sharded capacity LRU cache of the given size, with a byte size capacity
+// split between the shards
+func NewShardedCapacityLRU(numShards int, size int, byteCapacity int64) (*shardedCapacityLRU, error) {
+	return NewShardedCapacityLRUWithEviction(numShards, size, byteCapacity, nil)
+}
+
+// NewShardedCapacityLRUWithEviction constructs a sharded capacity LRU cache of the given size, with a byte size
+// capacity split between the shards. The optional onEvicted callback is called, outside the shard locks, for each
+// item evicted due to the capacity.
+func NewShardedCapacityLRUWithEviction(
+	numShards int,
+	size int,
+	byteCapacity int64,
+	onEvicted func(key interface{}, value interface{}),
+) (*shardedCapacityLRU, error) {
+	if numShards < 1 {
+		return nil, common.ErrInvalidNumberOfShards
+	}
+	if size < 1 {
+		return nil, common.ErrCacheSizeInvalid
+	}
+	if byteCapacity < 1 {
+		return nil, common.ErrCacheCapacityInvalid
+	}
+
+	shardSize, shardByteCapacity := computeShardLimits(numShards, size, byteCapacity)
+	c := &shardedCapacityLRU{
+		shards: make([]*capacityLRU, numShards),
+	}
+	for i := range c.shards {
+		shard, err := NewCapacityLRUWithEviction(shardSize, shardByteCapacity, onEvicted)
+		if err != nil {
+			return nil, err
+		}
+
+		c.shards[i] = shard
+	}
+
+	return c, nil
+}
+
+// computeShardLimits rounds up the number of items, for the small caches to remain usable, and rounds down
+// the size in bytes, for the whole cache to stay within its byte budget
+func computeShardLimits(numShards int, size int, byteCapacity int64) (int, int64) {
+	shardSize := (size + numShards - 1) / numShards
+	shardByteCapacity := byteCapacity / int64(numShards)
+	if shardByteCapacity < 1 {
+		shardByteCapacity = 1
+	}
+
+	return shardSize, shardByteCapacity
+}
+
+func (c *shardedCapacityLRU) getShard(key interface{}) *capacityLRU {
+	keyString, ok := key.(string)
+	if !ok {
+		keyString = string(keyToBytes(key))
+	}
+
+	return c.shards[fnv32(keyString)%uint32(len(c.shards))]
+}
+
+// fnv32 hashes the key without allocating, as it is called on every operation
+func fnv32(key string) uint32 {
+	hash := uint32(fnvOffset32)
+	for i := 0; i < len(key); i++ {
+		hash ^= uint32(key[i])
+		hash *= fnvPrime32
+	}
+
+	return hash
+}
+
+// Resize changes the limits of the cache, splitting them between the shards. Each shard evicts its oldest items
+// if its new limits are exceeded.
+func (c *shardedCapacityLRU) Resize(size int, byteCapacity int64) error {
+	if size < 1 {
+		return common.ErrCacheSizeInvalid
+	}
+	if byteCapacity < 1 {
+		return common.ErrCacheCapacityInvalid
+	}
+
+	shardSize, shardByteCapacity := computeShardLimits(len(c.shards), size, byteCapacity)
+	for _, shard := range c.shards {
+		err := shard.Resize(shardSize, shardByteCapacity)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Purge is used to completely clear the cache.
+func (c *shardedCapacityLRU) Purge() {
+	for _, shard := range c.shards {
+		shard.Purge()
+	}
+}
+
+// AddSized adds a value to the cache. Returns true if an eviction occurred.
+func (c *shardedCapacityLRU) AddSized(key, value interface{}, sizeInBytes int64) bool {
+	return c.getShard(key).AddSized(key, value, sizeInBytes)
+}
+
+// AddSizedAndReturnEvicted adds the given key-value pair to the cache, and returns the evicted values.
+// Only the shard of the key may evict values.
+func (c *shardedCapacityLRU) AddSizedAndReturnEvicted(key, value interface{}, sizeInBytes int64) map[interface{}]interface{} {
+	return c.getShard(key).AddSizedAndReturnEvicted(key, value, sizeInBytes)
+}
+
+// Get looks up a key's value from the cache.
+func (c *shardedCapacityLRU) Get(key interface{}) (interface{}, bool) {
+	return c.getShard(key).Get(key)
+}
+
+// Contains checks if a key is in the cache, without updating the recent-ness
+// or deleting it for being stale.
+func (c *shardedCapacityLRU) Contains(key interface{}) bool {
+	return c.getShard(key).Contains(key)
+}
+
+// AddSizedIfMissing checks if a key is in the cache without updating the
+// recent-ness or deleting it for being stale, and if not, adds the value.
+// Returns whether found and whether an eviction occurred.
+func (c *shardedCapacityLRU) AddSizedIfMissing(key, value interface{}, sizeInBytes int64) (bool, bool) {
+	return c.getShard(key).AddSizedIfMissing(key, value, sizeInBytes)
+}
+
+// Peek returns the key value (or undefined if not found) without updating
+// the "recently used"-ness of the key.
+func (c *shardedCapacityLRU) Peek(key interface{}) (interface{}, bool) {
+	return c.getShard(key).Peek(key)
+}
+
+// Remove removes the provided key from the cache, returning if the
+// key was contained.
+func (c *shardedCapacityLRU) Remove(key interface{}) bool {
+	return c.getShard(key).Remove(key)
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest within each shard, one shard after the other.
+func (c *shardedCapacityLRU) Keys() []interface{} {
+	keys := make([]interface{}, 0, c.Len())
+	for _, shard := range c.shards {
+		keys = append(keys, shard.Keys()...)
+	}
+
+	return keys
+}
+
+// Len returns the number of items in the cache.
+func (c *shardedCapacityLRU) Len() int {
+	length := 0
+	for _, shard := range c.shards {
+		length += shard.Len()
+	}
+
+	return length
+}
+
+// SizeInBytesContained returns the size in bytes of all contained elements
+func (c *shardedCapacityLRU) SizeInBytesContained() uint64 {
+	size := uint64(0)
+	for _, shard := range c.shards {
+		size += shard.SizeInBytesContained()
+	}
+
+	return size
+}
+
+// EntriesSizeInBytes returns the size in bytes of all contained entries, including the keys and the overhead of
+// the entries
+func (c *shardedCapacityLRU) EntriesSizeInBytes() uint64 {
+	size := uint64(0)
+	for _, shard := range c.shards {
+		size += shard.EntriesSizeInBytes()
+	}
+
+	return size
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (c *shardedCapacityLRU) IsInterfaceNil() bool {
+	return c == nil
+}
@@ -0,0 +1,182 @@
+package capacity
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/stretchr/testify/assert"
+)
+
+//------- NewShardedCapacityLRU
+
+func TestNewShardedCapacityLRU(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid number of shards should error", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := NewShardedCapacityLRU(0, 10, 100)
+		assert.True(t, check.IfNil(cache))
+		assert.Equal(t, common.ErrInvalidNumberOfShards, err)
+	})
+	t.Run("invalid size should error", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := NewShardedCapacityLRU(4, 0, 100)
+		assert.True(t, check.IfNil(cache))
+		assert.Equal(t, common.ErrCacheSizeInvalid, err)
+	})
+	t.Run("invalid capacity should error", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := NewShardedCapacityLRU(4, 10, 0)
+		assert.True(t, check.IfNil(cache))
+		assert.Equal(t, common.ErrCacheCapacityInvalid, err)
+	})
+	t.Run("should split the limits between shards", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := NewShardedCapacityLRU(4, 10, 100)
+		assert.False(t, check.IfNil(cache))
+		assert.Nil(t, err)
+		assert.Equal(t, 4, len(cache.shards))
+		for _, shard := range cache.shards {
+			assert.Equal(t, 3, shard.size)
+			assert.Equal(t, int64(25), shard.maxCapacityInBytes)
+		}
+	})
+	t.Run("capacity lower than the number of shards should give each shard one byte", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := NewShardedCapacityLRU(4, 10, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), cache.shards[0].maxCapacityInBytes)
+	})
+}
+
+//------- operations
+
+func TestShardedCapacityLRU_Operations(t *testing.T) {
+	t.Parallel()
+
+	c, _ := NewShardedCapacityLRU(4, 100, 10000)
+	for i := 0; i < 50; i++ {
+		c.AddSized(fmt.Sprintf("key%d", i), i, 10)
+	}
+
+	assert.Equal(t, 50, c.Len())
+	assert.Equal(t, 50, len(c.Keys()))
+	assert.Equal(t, uint64(500), c.SizeInBytesContained())
+
+	value, ok := c.Get("key7")
+	assert.True(t, ok)
+	assert.Equal(t, 7, value)
+	value, ok = c.Peek("key8")
+	assert.True(t, ok)
+	assert.Equal(t, 8, value)
+	assert.True(t, c.Contains("key9"))
+
+	found, evicted := c.AddSizedIfMissing("key9", 99, 10)
+	assert.True(t, found)
+	assert.False(t, evicted)
+
+	assert.True(t, c.Remove("key9"))
+	assert.False(t, c.Contains("key9"))
+	assert.Equal(t, 49, c.Len())
+
+	c.Purge()
+	assert.Zero(t, c.Len())
+	assert.Zero(t, c.SizeInBytesContained())
+}
+
+func TestShardedCapacityLRU_AddSizedAndReturnEvicted(t *testing.T) {
+	t.Parallel()
+
+	c, _ := NewShardedCapacityLRU(2, 100, 200)
+	allEvicted := make(map[interface{}]interface{})
+	for i := 0; i < 50; i++ {
+		evicted := c.AddSizedAndReturnEvicted(fmt.Sprintf("key%d", i), i, 10)
+		for key, value := range evicted {
+			allEvicted[key] = value
+		}
+	}
+
+	assert.True(t, c.SizeInBytesContained() <= 200)
+	assert.Equal(t, 50, c.Len()+len(allEvicted))
+	for key := range allEvicted {
+		assert.False(t, c.Contains(key))
+	}
+}
+
+func TestShardedCapacityLRU_Resize(t *testing.T) {
+	t.Parallel()
+
+	c, _ := NewShardedCapacityLRU(2, 100, 1000)
+	assert.Equal(t, common.ErrCacheSizeInvalid, c.Resize(0, 100))
+	assert.Equal(t, common.ErrCacheCapacityInvalid, c.Resize(10, 0))
+
+	for i := 0; i < 100; i++ {
+		c.AddSized(fmt.Sprintf("key%d", i), i, 10)
+	}
+
+	err := c.Resize(10, 60)
+	assert.Nil(t, err)
+	assert.Equal(t, 6, c.Len())
+	assert.Equal(t, uint64(60), c.SizeInBytesContained())
+}
+
+func TestShardedCapacityLRU_ConcurrentOperations(t *testing.T) {
+	t.Parallel()
+
+	c, _ := NewShardedCapacityLRU(8, 100, 1000)
+	numOperations := 1000
+	wg := sync.WaitGroup{}
+	wg.Add(numOperations)
+	for i := 0; i < numOperations; i++ {
+		go func(idx int) {
+			defer wg.Done()
+
+			key := fmt.Sprintf("key%d", idx%150)
+			switch idx % 6 {
+			case 0:
+				c.AddSized(key, idx, 10)
+			case 1:
+				_ = c.AddSizedAndReturnEvicted(key, idx, 10)
+			case 2:
+				_, _ = c.Get(key)
+			case 3:
+				c.Remove(key)
+			case 4:
+				_ = c.Keys()
+			case 5:
+				_ = c.Resize(idx%100+8, 1000)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.True(t, c.SizeInBytesContained() <= 1000)
+}
+
+func TestShardedCapacityLRU_EvictionCallback(t *testing.T) {
+	t.Parallel()
+
+	numEvicted := 0
+	mut := sync.Mutex{}
+	c, _ := NewShardedCapacityLRUWithEviction(4, 8, 1000, func(_ interface{}, _ interface{}) {
+		mut.Lock()
+		numEvicted++
+		mut.Unlock()
+	})
+
+	for i := 0; i < 100; i++ {
+		c.AddSized(fmt.Sprintf("key%d", i), i, 1)
+	}
+
+	mut.Lock()
+	defer mut.Unlock()
+	assert.Equal(t, 100-c.Len(), numEvicted)
+}
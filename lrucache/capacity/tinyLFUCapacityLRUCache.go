@@ -0,0 +1,514 @@
+package capacity
+
+import (
+	"container/list"
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-storage-go/bloomfilter"
+	"github.com/multiversx/mx-chain-storage-go/common"
+)
+
+const (
+	windowPercent        = 1
+	protectedPercent     = 80
+	doorkeeperFalseRatio = 0.01
+)
+
+type segment int
+
+const (
+	windowSegment segment = iota
+	probationSegment
+	protectedSegment
+)
+
+// TinyLFUStats holds the hit rate and the admission statistics of a TinyLFU cache
+type TinyLFUStats struct {
+	NumHits     uint64
+	NumMisses   uint64
+	NumAdmitted uint64
+	NumRejected uint64
+	HitRate     float64
+}
+
+type doorkeeper interface {
+	Add(key []byte)
+	Contains(key []byte) bool
+	Reset()
+}
+
+// tinyLFUCapacityLRU implements a thread safe, size aware, W-TinyLFU cache. New items enter a small LRU window.
+// The items leaving the window are admitted in the main LRU, split in a probation and a protected segment, only
+// if they were accessed more often than the item they would evict. The access frequencies are estimated by a
+// count-min sketch, periodically aged, in front of which a doorkeeper bloom filter absorbs the keys seen once.
+type tinyLFUCapacityLRU struct {
+	lock                   sync.Mutex
+	size                   int
+	maxCapacityInBytes     int64
+	currentCapacityInBytes int64
+	entriesSizeInBytes     int64
+	maxWindowLen           int
+	maxProtectedLen        int
+	window                 *list.List
+	probation              *list.List
+	protected              *list.List
+	items                  map[interface{}]*list.Element
+	sketch                 *countMinSketch
+	doorkeeper             doorkeeper
+	stats                  TinyLFUStats
+	evicted                []*entry
+	onEvicted              func(key interface{}, value interface{})
+}
+
+// tinyLFUEntry is used to hold a value in one of the segments
+type tinyLFUEntry struct {
+	entry
+	hash    uint64
+	segment segment
+}
+
+// NewTinyLFUCapacityLRU constructs a W-TinyLFU cache of the given size with a byte size capacity
+func NewTinyLFUCapacityLRU(size int, byteCapacity int64) (*tinyLFUCapacityLRU, error) {
+	return NewTinyLFUCapacityLRUWithEviction(size, byteCapacity, nil)
+}
+
+// NewTinyLFUCapacityLRUWithEviction constructs a W-TinyLFU cache of the given size with a byte size capacity.
+// The optional onEvicted callback is called, outside the cache lock, for each item evicted or not admitted due
+// to the capacity.
+func NewTinyLFUCapacityLRUWithEviction(
+	size int,
+	byteCapacity int64,
+	onEvicted func(key interface{}, value interface{}),
+) (*tinyLFUCapacityLRU, error) {
+	if size < 1 {
+		return nil, common.ErrCacheSizeInvalid
+	}
+	if byteCapacity < 1 {
+		return nil, common.ErrCacheCapacityInvalid
+	}
+
+	sketch := newCountMinSketch(size)
+	filter, err := bloomfilter.NewBloomFilter(sketch.sampleSize, doorkeeperFalseRatio)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &tinyLFUCapacityLRU{
+		maxCapacityInBytes: byteCapacity,
+		window:             list.New(),
+		probation:          list.New(),
+		protected:          list.New(),
+		items:              make(map[interface{}]*list.Element),
+		sketch:             sketch,
+		doorkeeper:         filter,
+		onEvicted:          onEvicted,
+	}
+	c.setSize(size)
+
+	return c, nil
+}
+
+func (c *tinyLFUCapacityLRU) setSize(size int) {
+	c.size = size
+	c.maxWindowLen = core.MaxInt(size*windowPercent/100, 1)
+	c.maxProtectedLen = (size - c.maxWindowLen) * protectedPercent / 100
+}
+
+// Resize changes the limits of the cache, evicting items if the new limits are exceeded.
+// The frequency sketch keeps its initial dimensions.
+func (c *tinyLFUCapacityLRU) Resize(size int, byteCapacity int64) error {
+	if size < 1 {
+		return common.ErrCacheSizeInvalid
+	}
+	if byteCapacity < 1 {
+		return common.ErrCacheCapacityInvalid
+	}
+
+	c.lock.Lock()
+	c.setSize(size)
+	c.maxCapacityInBytes = byteCapacity
+	c.evictIfNeeded()
+	c.demoteProtectedIfNeeded()
+	evicted := c.takeEvicted()
+	c.lock.Unlock()
+
+	notifyEvicted(c.onEvicted, evicted)
+
+	return nil
+}
+
+// Purge is used to completely clear the cache. The access frequencies are forgotten as well.
+func (c *tinyLFUCapacityLRU) Purge() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.window.Init()
+	c.probation.Init()
+	c.protected.Init()
+	c.items = make(map[interface{}]*list.Element)
+	c.currentCapacityInBytes = 0
+	c.entriesSizeInBytes = 0
+	c.sketch.reset()
+	c.doorkeeper.Reset()
+}
+
+// AddSized adds a value to the cache. Returns true if an eviction occurred.
+func (c *tinyLFUCapacityLRU) AddSized(key, value interface{}, sizeInBytes int64) bool {
+	if sizeInBytes < 0 {
+		log.Error("TinyLFU cache add error",
+			"key", fmt.Sprintf("%v", key),
+			"value", fmt.Sprintf("%v", value),
+			"error", common.ErrNegativeSizeInBytes,
+		)
+
+		return false
+	}
+
+	c.lock.Lock()
+	element, ok := c.items[key]
+	if ok {
+		ent := element.Value.(*tinyLFUEntry)
+		entrySize := computeEntrySize(key, value, sizeInBytes)
+		c.currentCapacityInBytes += sizeInBytes - ent.size
+		c.entriesSizeInBytes += entrySize - ent.entrySize
+		ent.value = value
+		ent.size = sizeInBytes
+		ent.entrySize = entrySize
+		c.recordAccess(ent.hash)
+		c.onHit(element)
+	} else {
+		c.addNew(key, value, sizeInBytes)
+	}
+	c.evictIfNeeded()
+	evicted := c.takeEvicted()
+	c.lock.Unlock()
+
+	notifyEvicted(c.onEvicted, evicted)
+
+	return len(evicted) > 0
+}
+
+// AddSizedIfMissing checks if a key is in the cache without updating the
+// recent-ness or deleting it for being stale, and if not, adds the value.
+// Returns whether found and whether an eviction occurred.
+func (c *tinyLFUCapacityLRU) AddSizedIfMissing(key, value interface{}, sizeInBytes int64) (bool, bool) {
+	if sizeInBytes < 0 {
+		log.Error("TinyLFU cache contains or add error",
+			"key", fmt.Sprintf("%v", key),
+			"value", fmt.Sprintf("%v", value),
+			"error", common.ErrNegativeSizeInBytes,
+		)
+
+		return false, false
+	}
+
+	c.lock.Lock()
+	_, ok := c.items[key]
+	if ok {
+		c.lock.Unlock()
+		return true, false
+	}
+
+	c.addNew(key, value, sizeInBytes)
+	c.evictIfNeeded()
+	evicted := c.takeEvicted()
+	c.lock.Unlock()
+
+	notifyEvicted(c.onEvicted, evicted)
+
+	return false, len(evicted) > 0
+}
+
+func (c *tinyLFUCapacityLRU) addNew(key interface{}, value interface{}, sizeInBytes int64) {
+	ent := &tinyLFUEntry{
+		entry:   newEntry(key, value, sizeInBytes),
+		hash:    hashKey(keyToBytes(key)),
+		segment: windowSegment,
+	}
+	c.recordAccess(ent.hash)
+
+	c.items[key] = c.window.PushFront(ent)
+	c.currentCapacityInBytes += sizeInBytes
+	c.entriesSizeInBytes += ent.entrySize
+}
+
+func keyToBytes(key interface{}) []byte {
+	switch k := key.(type) {
+	case string:
+		return []byte(k)
+	case []byte:
+		return k
+	default:
+		return []byte(fmt.Sprintf("%v", k))
+	}
+}
+
+// recordAccess lets the doorkeeper absorb the first access of a key, the following ones being counted by the sketch
+func (c *tinyLFUCapacityLRU) recordAccess(hash uint64) {
+	hashBytes := hashToBytes(hash)
+	if !c.doorkeeper.Contains(hashBytes) {
+		c.doorkeeper.Add(hashBytes)
+		return
+	}
+
+	aged := c.sketch.increment(hash)
+	if aged {
+		c.doorkeeper.Reset()
+	}
+}
+
+func (c *tinyLFUCapacityLRU) frequency(hash uint64) int {
+	frequency := int(c.sketch.estimate(hash))
+	if c.doorkeeper.Contains(hashToBytes(hash)) {
+		frequency++
+	}
+
+	return frequency
+}
+
+func hashToBytes(hash uint64) []byte {
+	buff := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buff, hash)
+
+	return buff
+}
+
+func (c *tinyLFUCapacityLRU) onHit(element *list.Element) {
+	ent := element.Value.(*tinyLFUEntry)
+	switch ent.segment {
+	case windowSegment:
+		c.window.MoveToFront(element)
+	case probationSegment:
+		c.probation.Remove(element)
+		ent.segment = protectedSegment
+		c.items[ent.key] = c.protected.PushFront(ent)
+		c.demoteProtectedIfNeeded()
+	case protectedSegment:
+		c.protected.MoveToFront(element)
+	}
+}
+
+func (c *tinyLFUCapacityLRU) demoteProtectedIfNeeded() {
+	for c.protected.Len() > c.maxProtectedLen {
+		element := c.protected.Back()
+		ent := element.Value.(*tinyLFUEntry)
+		c.protected.Remove(element)
+		ent.segment = probationSegment
+		c.items[ent.key] = c.probation.PushFront(ent)
+	}
+}
+
+// Get looks up a key's value from the cache.
+func (c *tinyLFUCapacityLRU) Get(key interface{}) (interface{}, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		c.stats.NumMisses++
+		c.recordAccess(hashKey(keyToBytes(key)))
+		return nil, false
+	}
+
+	c.stats.NumHits++
+	ent := element.Value.(*tinyLFUEntry)
+	c.recordAccess(ent.hash)
+	c.onHit(element)
+
+	return ent.value, true
+}
+
+// Contains checks if a key is in the cache, without updating the recent-ness
+// or deleting it for being stale.
+func (c *tinyLFUCapacityLRU) Contains(key interface{}) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	_, ok := c.items[key]
+
+	return ok
+}
+
+// Peek returns the key value (or undefined if not found) without updating
+// the "recently used"-ness of the key.
+func (c *tinyLFUCapacityLRU) Peek(key interface{}) (interface{}, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	return element.Value.(*tinyLFUEntry).value, true
+}
+
+// Remove removes the provided key from the cache, returning if the
+// key was contained.
+func (c *tinyLFUCapacityLRU) Remove(key interface{}) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return false
+	}
+
+	c.removeElement(element)
+
+	return true
+}
+
+// Keys returns a slice of the keys in the cache, from the oldest to the newest in the protected segment,
+// then in the probation segment and, last, in the window.
+func (c *tinyLFUCapacityLRU) Keys() []interface{} {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	keys := make([]interface{}, 0, len(c.items))
+	for _, segmentList := range []*list.List{c.protected, c.probation, c.window} {
+		for element := segmentList.Back(); element != nil; element = element.Prev() {
+			keys = append(keys, element.Value.(*tinyLFUEntry).key)
+		}
+	}
+
+	return keys
+}
+
+// Len returns the number of items in the cache.
+func (c *tinyLFUCapacityLRU) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return len(c.items)
+}
+
+// SizeInBytesContained returns the size in bytes of all contained elements
+func (c *tinyLFUCapacityLRU) SizeInBytesContained() uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return uint64(c.currentCapacityInBytes)
+}
+
+// EntriesSizeInBytes returns the size in bytes of all contained entries, including the keys and the overhead of
+// the entries
+func (c *tinyLFUCapacityLRU) EntriesSizeInBytes() uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return uint64(c.entriesSizeInBytes)
+}
+
+// Stats returns the hit rate of the Get calls and the number of window items admitted in, or rejected from,
+// the main segments
+func (c *tinyLFUCapacityLRU) Stats() TinyLFUStats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	stats := c.stats
+	numLookups := stats.NumHits + stats.NumMisses
+	if numLookups > 0 {
+		stats.HitRate = float64(stats.NumHits) / float64(numLookups)
+	}
+
+	return stats
+}
+
+func (c *tinyLFUCapacityLRU) segmentList(seg segment) *list.List {
+	switch seg {
+	case windowSegment:
+		return c.window
+	case probationSegment:
+		return c.probation
+	default:
+		return c.protected
+	}
+}
+
+func (c *tinyLFUCapacityLRU) removeElement(element *list.Element) {
+	ent := element.Value.(*tinyLFUEntry)
+	c.segmentList(ent.segment).Remove(element)
+	delete(c.items, ent.key)
+	c.currentCapacityInBytes -= ent.size
+	c.entriesSizeInBytes -= ent.entrySize
+}
+
+func (c *tinyLFUCapacityLRU) shouldEvict() bool {
+	if len(c.items) == 1 {
+		// keep at least one element, no matter how large it is
+		return false
+	}
+
+	return len(c.items) > c.size || c.currentCapacityInBytes > c.maxCapacityInBytes
+}
+
+// evictIfNeeded evicts items while the limits are exceeded, then moves the items overflowing the window
+// in the probation segment
+func (c *tinyLFUCapacityLRU) evictIfNeeded() {
+	for c.shouldEvict() {
+		c.evictOne()
+	}
+
+	for c.window.Len() > c.maxWindowLen {
+		element := c.window.Back()
+		ent := element.Value.(*tinyLFUEntry)
+		c.window.Remove(element)
+		ent.segment = probationSegment
+		c.items[ent.key] = c.probation.PushFront(ent)
+		c.stats.NumAdmitted++
+	}
+}
+
+func (c *tinyLFUCapacityLRU) takeEvicted() []*entry {
+	evicted := c.evicted
+	c.evicted = nil
+
+	return evicted
+}
+
+// evictOne makes the oldest window item, if it overflows the window, compete with the main segments' victim.
+// The least frequently accessed one is evicted, the window item losing the ties.
+func (c *tinyLFUCapacityLRU) evictOne() {
+	victim := c.probation.Back()
+	if victim == nil {
+		victim = c.protected.Back()
+	}
+
+	candidate := c.window.Back()
+	if c.window.Len() <= c.maxWindowLen && victim != nil {
+		candidate = nil
+	}
+
+	if candidate == nil {
+		c.evictElement(victim)
+		return
+	}
+	if victim == nil {
+		c.evictElement(candidate)
+		return
+	}
+
+	candidateFrequency := c.frequency(candidate.Value.(*tinyLFUEntry).hash)
+	victimFrequency := c.frequency(victim.Value.(*tinyLFUEntry).hash)
+	if candidateFrequency > victimFrequency {
+		c.evictElement(victim)
+		return
+	}
+
+	c.evictElement(candidate)
+	c.stats.NumRejected++
+}
+
+func (c *tinyLFUCapacityLRU) evictElement(element *list.Element) {
+	c.removeElement(element)
+	c.evicted = append(c.evicted, &element.Value.(*tinyLFUEntry).entry)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (c *tinyLFUCapacityLRU) IsInterfaceNil() bool {
+	return c == nil
+}
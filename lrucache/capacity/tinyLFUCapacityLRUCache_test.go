@@ -0,0 +1,269 @@
+package capacity
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func createDefaultTinyLFUCache() *tinyLFUCapacityLRU {
+	cache, _ := NewTinyLFUCapacityLRU(100, 1000)
+	return cache
+}
+
+//------- NewTinyLFUCapacityLRU
+
+func TestNewTinyLFUCapacityLRU(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid size should error", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := NewTinyLFUCapacityLRU(0, 1)
+		assert.True(t, check.IfNil(cache))
+		assert.Equal(t, common.ErrCacheSizeInvalid, err)
+	})
+	t.Run("invalid capacity should error", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := NewTinyLFUCapacityLRU(1, 0)
+		assert.True(t, check.IfNil(cache))
+		assert.Equal(t, common.ErrCacheCapacityInvalid, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := NewTinyLFUCapacityLRU(200, 1000)
+		assert.False(t, check.IfNil(cache))
+		assert.Nil(t, err)
+		assert.Equal(t, 2, cache.maxWindowLen)
+		assert.Equal(t, 158, cache.maxProtectedLen)
+	})
+}
+
+//------- countMinSketch
+
+func TestCountMinSketch_IncrementAndEstimate(t *testing.T) {
+	t.Parallel()
+
+	sketch := newCountMinSketch(100)
+	hash := hashKey([]byte("key"))
+	for i := 0; i < 5; i++ {
+		sketch.increment(hash)
+	}
+	assert.Equal(t, uint8(5), sketch.estimate(hash))
+
+	for i := 0; i < 100; i++ {
+		sketch.increment(hash)
+	}
+	assert.Equal(t, uint8(maxCounterValue), sketch.estimate(hash))
+}
+
+func TestCountMinSketch_ShouldAgeAfterSample(t *testing.T) {
+	t.Parallel()
+
+	sketch := newCountMinSketch(10)
+	hotHash := hashKey([]byte("hot"))
+	for i := uint64(1); i < sketch.sampleSize; i++ {
+		aged := sketch.increment(hotHash)
+		assert.False(t, aged)
+	}
+	assert.Equal(t, uint8(maxCounterValue), sketch.estimate(hotHash))
+
+	aged := sketch.increment(hotHash)
+	assert.True(t, aged)
+	assert.Equal(t, uint8(maxCounterValue/2), sketch.estimate(hotHash))
+	assert.Equal(t, sketch.sampleSize/2, sketch.numIncrements)
+}
+
+//------- admission
+
+func TestTinyLFUCapacityLRU_OneHitWondersShouldNotDisplaceHotItems(t *testing.T) {
+	t.Parallel()
+
+	c := createDefaultTinyLFUCache()
+	for i := 0; i < 90; i++ {
+		key := fmt.Sprintf("hot%d", i)
+		c.AddSized(key, i, 1)
+		for j := 0; j < 3; j++ {
+			_, _ = c.Get(key)
+		}
+	}
+
+	for i := 0; i < 1000; i++ {
+		c.AddSized(fmt.Sprintf("burst%d", i), i, 1)
+	}
+
+	for i := 0; i < 90; i++ {
+		assert.True(t, c.Contains(fmt.Sprintf("hot%d", i)))
+	}
+	assert.Equal(t, 100, c.Len())
+	assert.True(t, c.Stats().NumRejected > 0)
+}
+
+func TestTinyLFUCapacityLRU_FrequentNewKeyShouldBeAdmitted(t *testing.T) {
+	t.Parallel()
+
+	c, _ := NewTinyLFUCapacityLRU(10, 1000)
+	for i := 0; i < 10; i++ {
+		c.AddSized(fmt.Sprintf("key%d", i), i, 1)
+	}
+
+	for i := 0; i < 5; i++ {
+		_, _ = c.Get("newcomer")
+	}
+	c.AddSized("newcomer", "value", 1)
+	c.AddSized("other", "value", 1)
+
+	assert.True(t, c.Contains("newcomer"))
+	assert.Equal(t, 10, c.Len())
+}
+
+func TestTinyLFUCapacityLRU_ShouldRespectCapacityInBytes(t *testing.T) {
+	t.Parallel()
+
+	c, _ := NewTinyLFUCapacityLRU(100, 100)
+	for i := 0; i < 20; i++ {
+		c.AddSized(fmt.Sprintf("key%d", i), i, 10)
+	}
+	assert.Equal(t, uint64(100), c.SizeInBytesContained())
+	assert.Equal(t, 10, c.Len())
+
+	c.AddSized("key19", "bigger", 50)
+	assert.True(t, c.SizeInBytesContained() <= 100)
+
+	// an item larger than the capacity is kept alone
+	c.AddSized("huge", "value", 500)
+	assert.Equal(t, []interface{}{"huge"}, c.Keys())
+}
+
+//------- other operations
+
+func TestTinyLFUCapacityLRU_Operations(t *testing.T) {
+	t.Parallel()
+
+	c := createDefaultTinyLFUCache()
+	assert.False(t, c.AddSized("key", "value", -1))
+	found, evicted := c.AddSizedIfMissing("key", "value", -1)
+	assert.False(t, found)
+	assert.False(t, evicted)
+
+	found, evicted = c.AddSizedIfMissing("key", "value", 10)
+	assert.False(t, found)
+	assert.False(t, evicted)
+	found, _ = c.AddSizedIfMissing("key", "other", 10)
+	assert.True(t, found)
+
+	value, ok := c.Peek("key")
+	assert.True(t, ok)
+	assert.Equal(t, "value", value)
+	_, ok = c.Peek("missing")
+	assert.False(t, ok)
+
+	c.AddSized("key", "updated", 20)
+	value, ok = c.Get("key")
+	assert.True(t, ok)
+	assert.Equal(t, "updated", value)
+	assert.Equal(t, uint64(20), c.SizeInBytesContained())
+
+	_, ok = c.Get("missing")
+	assert.False(t, ok)
+	stats := c.Stats()
+	assert.Equal(t, uint64(1), stats.NumHits)
+	assert.Equal(t, uint64(1), stats.NumMisses)
+	assert.Equal(t, 0.5, stats.HitRate)
+
+	assert.True(t, c.Remove("key"))
+	assert.False(t, c.Remove("key"))
+	assert.Zero(t, c.Len())
+	assert.Zero(t, c.SizeInBytesContained())
+
+	c.AddSized("key", "value", 10)
+	c.Purge()
+	assert.Zero(t, c.Len())
+	assert.Zero(t, c.SizeInBytesContained())
+	assert.Empty(t, c.Keys())
+}
+
+func TestTinyLFUCapacityLRU_Resize(t *testing.T) {
+	t.Parallel()
+
+	c := createDefaultTinyLFUCache()
+	assert.Equal(t, common.ErrCacheSizeInvalid, c.Resize(0, 100))
+	assert.Equal(t, common.ErrCacheCapacityInvalid, c.Resize(10, 0))
+
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("key%d", i)
+		c.AddSized(key, i, 10)
+		_, _ = c.Get(key)
+	}
+
+	err := c.Resize(10, 50)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, c.Len())
+	assert.Equal(t, uint64(50), c.SizeInBytesContained())
+	assert.True(t, c.protected.Len() <= c.maxProtectedLen)
+}
+
+func TestTinyLFUCapacityLRU_ConcurrentOperations(t *testing.T) {
+	t.Parallel()
+
+	c := createDefaultTinyLFUCache()
+	numOperations := 1000
+	wg := sync.WaitGroup{}
+	wg.Add(numOperations)
+	for i := 0; i < numOperations; i++ {
+		go func(idx int) {
+			defer wg.Done()
+
+			key := fmt.Sprintf("key%d", idx%150)
+			switch idx % 6 {
+			case 0:
+				c.AddSized(key, idx, 10)
+			case 1:
+				_, _ = c.AddSizedIfMissing(key, idx, 10)
+			case 2:
+				_, _ = c.Get(key)
+			case 3:
+				c.Remove(key)
+			case 4:
+				_ = c.Stats()
+			case 5:
+				_ = c.Resize(idx%100+1, 1000)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.True(t, c.Len() <= 100)
+	assert.True(t, c.SizeInBytesContained() <= 1000)
+}
+
+func TestTinyLFUCapacityLRU_EvictionCallbackShouldReportRejectedItems(t *testing.T) {
+	t.Parallel()
+
+	evictedKeys := make([]interface{}, 0)
+	c, _ := NewTinyLFUCapacityLRUWithEviction(10, 1000, func(key interface{}, _ interface{}) {
+		evictedKeys = append(evictedKeys, key)
+	})
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("hot%d", i)
+		c.AddSized(key, i, 1)
+		_, _ = c.Get(key)
+		_, _ = c.Get(key)
+	}
+
+	// the item leaving the window loses the tie against the probation victim and is not admitted
+	evicted := c.AddSized("cold", "value", 1)
+	assert.True(t, evicted)
+	assert.Equal(t, []interface{}{"hot9"}, evictedKeys)
+	assert.Equal(t, uint64(1), c.Stats().NumRejected)
+	assert.True(t, c.Contains("cold"))
+
+	_ = c.Resize(5, 1000)
+	assert.Equal(t, 6, len(evictedKeys))
+}
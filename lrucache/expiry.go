@@ -0,0 +1,226 @@
+package lrucache
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/clock"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+// ExpiryConfig holds the expiry settings of an LRU cache. The expired items are removed lazily, when accessed, and
+// periodically, by a background cleanup.
+type ExpiryConfig struct {
+	// TTL is the time an item lives after being put, no matter how often it is read. Zero means no absolute expiry.
+	TTL time.Duration
+	// MaxIdle is the time after which an item not read through Get expires. Zero means no idle expiry.
+	MaxIdle time.Duration
+	// CleanupInterval is the period of the background cleanup. Zero means the smallest of TTL and MaxIdle.
+	CleanupInterval time.Duration
+	// Clock is the source of time for the expiry of the items and for the cleanup. Nil means the system clock.
+	Clock types.Clock
+}
+
+func (config ExpiryConfig) check() error {
+	if config.TTL < 0 {
+		return fmt.Errorf("%w: negative TTL", common.ErrInvalidConfig)
+	}
+	if config.MaxIdle < 0 {
+		return fmt.Errorf("%w: negative max idle", common.ErrInvalidConfig)
+	}
+	if config.CleanupInterval < 0 {
+		return fmt.Errorf("%w: negative cleanup interval", common.ErrInvalidConfig)
+	}
+	if config.TTL == 0 && config.MaxIdle == 0 {
+		return fmt.Errorf("%w: neither TTL nor max idle provided", common.ErrInvalidConfig)
+	}
+
+	return nil
+}
+
+func (config ExpiryConfig) isEnabled() bool {
+	return config.TTL > 0 || config.MaxIdle > 0
+}
+
+func (config ExpiryConfig) cleanupInterval() time.Duration {
+	if config.CleanupInterval > 0 {
+		return config.CleanupInterval
+	}
+	if config.TTL == 0 {
+		return config.MaxIdle
+	}
+	if config.MaxIdle == 0 {
+		return config.TTL
+	}
+
+	return min(config.TTL, config.MaxIdle)
+}
+
+func clockOrDefault(providedClock types.Clock) types.Clock {
+	if check.IfNil(providedClock) {
+		return clock.NewSystemClock()
+	}
+
+	return providedClock
+}
+
+// compareAndRemover is implemented by the caches backing the LRU caches with expiry, so that an expired item is not
+// removed if it was replaced concurrently with its expiry
+type compareAndRemover interface {
+	RemoveIfSame(key interface{}, value interface{}) bool
+}
+
+// expiringValue wraps the values held by the caches with expiry. The times are in unix nanoseconds. It reports the
+// size of the wrapped value, so that the underlying cache accounts for the value and not for its wrapper.
+type expiringValue struct {
+	value     interface{}
+	valueSize int
+	// expireAt is zero for the caches without TTL
+	expireAt   int64
+	lastAccess atomic.Int64
+}
+
+// Size returns the size in bytes of the wrapped value
+func (ev *expiringValue) Size() int {
+	return ev.valueSize
+}
+
+func (ev *expiringValue) isExpired(now int64, maxIdle time.Duration) bool {
+	if ev.expireAt > 0 && now >= ev.expireAt {
+		return true
+	}
+
+	return maxIdle > 0 && now-ev.lastAccess.Load() >= int64(maxIdle)
+}
+
+func unwrapExpiringValue(value interface{}) interface{} {
+	ev, ok := value.(*expiringValue)
+	if !ok {
+		return value
+	}
+
+	return ev.value
+}
+
+func (c *lruCache) wrapValue(value interface{}, sizeInBytes int) interface{} {
+	if !c.expiry.isEnabled() {
+		return value
+	}
+
+	return c.newExpiringValue(value, sizeInBytes, c.expiry.TTL)
+}
+
+// newExpiringValue wraps the value with the provided TTL, zero meaning no absolute expiry
+func (c *lruCache) newExpiringValue(value interface{}, sizeInBytes int, ttl time.Duration) *expiringValue {
+	now := c.clock.Now().UnixNano()
+	ev := &expiringValue{
+		value:     value,
+		valueSize: types.ComputeValueSizeInBytes(value, sizeInBytes),
+	}
+	if ttl > 0 {
+		ev.expireAt = now + int64(ttl)
+	}
+	ev.lastAccess.Store(now)
+
+	return ev
+}
+
+// PutWithTTL adds a value to the cache, which expires after the provided TTL instead of the configured one, the
+// configured max idle still applying. The cache should have been created with expiry. Returns true if an eviction
+// occurred.
+func (c *lruCache) PutWithTTL(key []byte, value interface{}, sizeInBytes int, ttl time.Duration) (evicted bool) {
+	if !c.expiry.isEnabled() || ttl <= 0 {
+		log.Error("lruCache.PutWithTTL",
+			"key", key,
+			"ttl", ttl,
+			"expiry enabled", c.expiry.isEnabled(),
+			"error", common.ErrInvalidCacheExpiry,
+		)
+
+		return false
+	}
+
+	evicted = c.cache.AddSized(string(key), c.newExpiringValue(value, sizeInBytes, ttl), int64(sizeInBytes))
+	c.callAddedDataHandlers(key, value)
+
+	return evicted
+}
+
+// checkExpiry returns the value held under the key, or false if it has expired, in which case the item is removed.
+// A read through Get should touch the item, postponing its idle expiry.
+func (c *lruCache) checkExpiry(key string, value interface{}, touch bool) (interface{}, bool) {
+	ev, ok := value.(*expiringValue)
+	if !ok {
+		return value, true
+	}
+
+	now := c.clock.Now().UnixNano()
+	if ev.isExpired(now, c.expiry.MaxIdle) {
+		c.removeExpired(key, ev)
+		return nil, false
+	}
+	if touch {
+		ev.lastAccess.Store(now)
+	}
+
+	return ev.value, true
+}
+
+// removeExpired removes an expired item, unless it was replaced concurrently with its expiry
+func (c *lruCache) removeExpired(key string, ev *expiringValue) {
+	remover, ok := c.cache.(compareAndRemover)
+	if !ok {
+		log.Error("lruCache.removeExpired", "error", common.ErrWrongTypeAssertion)
+		return
+	}
+
+	removed := remover.RemoveIfSame(key, ev)
+	if removed {
+		c.counters.RecordEvictions(1)
+		c.notifyEvicted(key, ev.value, types.EvictedByExpiry)
+	}
+}
+
+// sweepExpired removes all the expired items, returning their number
+func (c *lruCache) sweepExpired() int {
+	now := c.clock.Now().UnixNano()
+	numRemoved := 0
+	for _, key := range c.cache.Keys() {
+		value, ok := c.cache.Peek(key)
+		if !ok {
+			continue
+		}
+
+		ev, ok := value.(*expiringValue)
+		if ok && ev.isExpired(now, c.expiry.MaxIdle) {
+			c.removeExpired(key.(string), ev)
+			numRemoved++
+		}
+	}
+
+	return numRemoved
+}
+
+func (c *lruCache) startCleanup() {
+	var ctx context.Context
+	ctx, c.cancelCleanup = context.WithCancel(context.Background())
+
+	go c.cleanupLoop(ctx, c.expiry.cleanupInterval())
+}
+
+func (c *lruCache) cleanupLoop(ctx context.Context, interval time.Duration) {
+	for {
+		select {
+		case <-c.clock.After(interval):
+			numRemoved := c.sweepExpired()
+			log.Trace("lruCache.cleanupLoop", "num expired", numRemoved)
+		case <-ctx.Done():
+			log.Debug("closing lruCache's expiry cleanup go routine...")
+			return
+		}
+	}
+}
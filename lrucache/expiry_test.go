@@ -0,0 +1,321 @@
+package lrucache_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/lrucache"
+	"github.com/multiversx/mx-chain-storage-go/testscommon"
+	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createExpiringCacheWithFakeClock(t *testing.T, sizeInBytes int64, ttl time.Duration, maxIdle time.Duration) (types.Cacher, *testscommon.FakeClock) {
+	fakeClock := testscommon.NewFakeClock(time.Now())
+	expiry := lrucache.ExpiryConfig{
+		TTL:             ttl,
+		MaxIdle:         maxIdle,
+		CleanupInterval: time.Hour,
+		Clock:           fakeClock,
+	}
+	cache, err := lrucache.NewExpiringCache(10, sizeInBytes, expiry)
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		_ = cache.Close()
+	})
+
+	return cache, fakeClock
+}
+
+func TestNewExpiringCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid expiry config should error", func(t *testing.T) {
+		t.Parallel()
+
+		configs := []lrucache.ExpiryConfig{
+			{},
+			{TTL: -time.Second},
+			{MaxIdle: -time.Second},
+			{TTL: time.Second, CleanupInterval: -time.Second},
+		}
+		for _, expiry := range configs {
+			cache, err := lrucache.NewExpiringCache(10, 0, expiry)
+			assert.True(t, errors.Is(err, common.ErrInvalidConfig))
+			assert.Nil(t, cache)
+		}
+	})
+	t.Run("invalid size should error", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := lrucache.NewExpiringCache(0, 0, lrucache.ExpiryConfig{TTL: time.Second})
+		assert.NotNil(t, err)
+		assert.Nil(t, cache)
+
+		cache, err = lrucache.NewExpiringCache(10, -1, lrucache.ExpiryConfig{TTL: time.Second})
+		assert.Equal(t, common.ErrCacheCapacityInvalid, err)
+		assert.Nil(t, cache)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := lrucache.NewExpiringCache(10, 1000, lrucache.ExpiryConfig{MaxIdle: time.Second})
+		assert.Nil(t, err)
+		assert.False(t, cache.IsInterfaceNil())
+		assert.Nil(t, cache.Close())
+		assert.Nil(t, cache.Close())
+	})
+}
+
+func TestLRUCache_TTLExpiryShouldBeCheckedOnAccess(t *testing.T) {
+	t.Parallel()
+
+	for _, sizeInBytes := range []int64{0, 10000} {
+		cache, fakeClock := createExpiringCacheWithFakeClock(t, sizeInBytes, time.Minute, 0)
+
+		_ = cache.Put([]byte("key1"), "value1", 10)
+		fakeClock.Advance(30 * time.Second)
+		_ = cache.Put([]byte("key2"), "value2", 10)
+
+		value, ok := cache.Get([]byte("key1"))
+		assert.True(t, ok)
+		assert.Equal(t, "value1", value)
+
+		// reading does not extend the TTL
+		fakeClock.Advance(30 * time.Second)
+		assert.False(t, cache.Has([]byte("key1")))
+		_, ok = cache.Peek([]byte("key1"))
+		assert.False(t, ok)
+		value, ok = cache.Peek([]byte("key2"))
+		assert.True(t, ok)
+		assert.Equal(t, "value2", value)
+		assert.Equal(t, 1, cache.Len())
+
+		// putting again restarts the TTL
+		fakeClock.Advance(20 * time.Second)
+		_ = cache.Put([]byte("key2"), "value2", 10)
+		fakeClock.Advance(50 * time.Second)
+		assert.True(t, cache.Has([]byte("key2")))
+	}
+}
+
+func TestLRUCache_MaxIdleExpiryShouldBePostponedByGet(t *testing.T) {
+	t.Parallel()
+
+	cache, fakeClock := createExpiringCacheWithFakeClock(t, 0, 0, 10*time.Second)
+
+	_ = cache.Put([]byte("read"), "value", 0)
+	_ = cache.Put([]byte("peeked"), "value", 0)
+
+	for i := 0; i < 5; i++ {
+		fakeClock.Advance(5 * time.Second)
+		_, ok := cache.Get([]byte("read"))
+		assert.True(t, ok)
+		_, ok = cache.Peek([]byte("peeked"))
+		assert.Equal(t, i == 0, ok)
+	}
+
+	fakeClock.Advance(10 * time.Second)
+	_, ok := cache.Get([]byte("read"))
+	assert.False(t, ok)
+	assert.Equal(t, 0, cache.Len())
+	assert.Equal(t, uint64(0), cache.SizeInBytesContained())
+}
+
+func TestLRUCache_TTLAndMaxIdleShouldBothApply(t *testing.T) {
+	t.Parallel()
+
+	cache, fakeClock := createExpiringCacheWithFakeClock(t, 0, 30*time.Second, 10*time.Second)
+
+	_ = cache.Put([]byte("key"), "value", 0)
+	for i := 0; i < 3; i++ {
+		fakeClock.Advance(9 * time.Second)
+		_, ok := cache.Get([]byte("key"))
+		assert.True(t, ok)
+	}
+
+	fakeClock.Advance(3 * time.Second)
+	_, ok := cache.Get([]byte("key"))
+	assert.False(t, ok)
+}
+
+func TestLRUCache_HasOrAddShouldReplaceExpiredItem(t *testing.T) {
+	t.Parallel()
+
+	cache, fakeClock := createExpiringCacheWithFakeClock(t, 0, time.Minute, 0)
+
+	has, added := cache.HasOrAdd([]byte("key"), "old", 0)
+	assert.False(t, has)
+	assert.True(t, added)
+
+	has, added = cache.HasOrAdd([]byte("key"), "new", 0)
+	assert.True(t, has)
+	assert.False(t, added)
+
+	fakeClock.Advance(time.Minute)
+	has, added = cache.HasOrAdd([]byte("key"), "new", 0)
+	assert.False(t, has)
+	assert.True(t, added)
+
+	value, _ := cache.Get([]byte("key"))
+	assert.Equal(t, "new", value)
+}
+
+func TestLRUCache_SweepExpiredShouldRemoveAndNotifyExpiredItems(t *testing.T) {
+	t.Parallel()
+
+	cache, fakeClock := createExpiringCacheWithFakeClock(t, 10000, time.Minute, 0)
+
+	mutReasons := sync.Mutex{}
+	reasons := make(map[string]types.EvictionReason)
+	cache.(types.EvictionNotifier).RegisterEvictionHandler(func(key []byte, value interface{}, reason types.EvictionReason) {
+		mutReasons.Lock()
+		reasons[string(key)] = reason
+		mutReasons.Unlock()
+
+		assert.Equal(t, "value", value)
+	}, "id")
+
+	_ = cache.Put([]byte("key1"), "value", 10)
+	_ = cache.Put([]byte("key2"), "value", 10)
+	fakeClock.Advance(40 * time.Second)
+	_ = cache.Put([]byte("key3"), "value", 10)
+	_ = cache.Put([]byte("key4"), "value", 10)
+	fakeClock.Advance(20 * time.Second)
+
+	numRemoved := cache.(interface{ SweepExpired() int }).SweepExpired()
+	assert.Equal(t, 2, numRemoved)
+	assert.Equal(t, [][]byte{[]byte("key3"), []byte("key4")}, cache.Keys())
+
+	cache.Remove([]byte("key3"))
+	cache.Clear()
+
+	mutReasons.Lock()
+	expectedReasons := map[string]types.EvictionReason{
+		"key1": types.EvictedByExpiry,
+		"key2": types.EvictedByExpiry,
+		"key3": types.EvictedByRemoval,
+		"key4": types.EvictedByClear,
+	}
+	assert.Equal(t, expectedReasons, reasons)
+	mutReasons.Unlock()
+
+	stats := cache.(types.CacheStatsProvider).CacheStats()
+	assert.Equal(t, uint64(2), stats.NumEvictions)
+}
+
+func TestLRUCache_BackgroundCleanupShouldRemoveExpiredItems(t *testing.T) {
+	t.Parallel()
+
+	fakeClock := testscommon.NewFakeClock(time.Now())
+	expiry := lrucache.ExpiryConfig{
+		TTL:             time.Minute,
+		CleanupInterval: 10 * time.Second,
+		Clock:           fakeClock,
+	}
+	cache, err := lrucache.NewExpiringCache(10, 0, expiry)
+	require.Nil(t, err)
+	defer func() {
+		_ = cache.Close()
+	}()
+
+	_ = cache.Put([]byte("key1"), "value", 0)
+	_ = cache.Put([]byte("key2"), "value", 0)
+	assert.Equal(t, 2, cache.Len())
+
+	// the cleanup waits on the clock, so it only runs when the clock is advanced
+	require.Eventually(t, func() bool {
+		return fakeClock.NumWaiters() == 1
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, 2, cache.Len())
+
+	fakeClock.Advance(time.Minute)
+	assert.Eventually(t, func() bool {
+		return cache.Len() == 0
+	}, time.Second, time.Millisecond)
+}
+
+func TestLRUCache_ExpiredItemReplacedConcurrentlyShouldNotBeRemoved(t *testing.T) {
+	t.Parallel()
+
+	for _, sizeInBytes := range []int64{0, 10000} {
+		cache, fakeClock := createExpiringCacheWithFakeClock(t, sizeInBytes, time.Minute, 0)
+		internalCache := cache.(interface {
+			PeekWrapped(key string) (interface{}, bool)
+			RemoveExpired(key string, value interface{})
+		})
+
+		_ = cache.Put([]byte("key"), "old", 10)
+		expired, ok := internalCache.PeekWrapped("key")
+		require.True(t, ok)
+
+		// the item is replaced between the expiry check and the removal
+		fakeClock.Advance(time.Minute)
+		_ = cache.Put([]byte("key"), "new", 10)
+		internalCache.RemoveExpired("key", expired)
+
+		value, ok := cache.Get([]byte("key"))
+		assert.True(t, ok)
+		assert.Equal(t, "new", value)
+		assert.Zero(t, cache.(types.CacheStatsProvider).CacheStats().NumEvictions)
+
+		internalCache.RemoveExpired("key", expired)
+		assert.True(t, cache.Has([]byte("key")))
+	}
+}
+
+func TestLRUCache_PutWithTTL(t *testing.T) {
+	t.Parallel()
+
+	t.Run("cache without expiry should not add", func(t *testing.T) {
+		t.Parallel()
+
+		cache, _ := lrucache.NewCache(10)
+		evicted := cache.PutWithTTL([]byte("key"), "value", 10, time.Minute)
+		assert.False(t, evicted)
+		assert.Zero(t, cache.Len())
+	})
+	t.Run("invalid TTL should not add", func(t *testing.T) {
+		t.Parallel()
+
+		cache, _ := createExpiringCacheWithFakeClock(t, 0, time.Minute, 0)
+		evicted := cache.(types.CacherWithTTL).PutWithTTL([]byte("key"), "value", 10, 0)
+		assert.False(t, evicted)
+		assert.Zero(t, cache.Len())
+	})
+	t.Run("should override the configured TTL", func(t *testing.T) {
+		t.Parallel()
+
+		for _, sizeInBytes := range []int64{0, 10000} {
+			cache, fakeClock := createExpiringCacheWithFakeClock(t, sizeInBytes, time.Minute, 0)
+			cacheWithTTL := cache.(types.CacherWithTTL)
+
+			_ = cacheWithTTL.PutWithTTL([]byte("short"), "value", 10, 10*time.Second)
+			_ = cacheWithTTL.PutWithTTL([]byte("long"), "value", 10, time.Hour)
+			_ = cache.Put([]byte("configured"), "value", 10)
+
+			fakeClock.Advance(10 * time.Second)
+			assert.False(t, cache.Has([]byte("short")))
+			assert.True(t, cache.Has([]byte("configured")))
+
+			fakeClock.Advance(time.Minute)
+			assert.False(t, cache.Has([]byte("configured")))
+			value, ok := cache.Get([]byte("long"))
+			assert.True(t, ok)
+			assert.Equal(t, "value", value)
+		}
+	})
+	t.Run("max idle should still apply", func(t *testing.T) {
+		t.Parallel()
+
+		cache, fakeClock := createExpiringCacheWithFakeClock(t, 0, 0, 10*time.Second)
+		_ = cache.(types.CacherWithTTL).PutWithTTL([]byte("key"), "value", 10, time.Hour)
+
+		fakeClock.Advance(10 * time.Second)
+		assert.False(t, cache.Has([]byte("key")))
+	})
+}
@@ -3,3 +3,15 @@ package lrucache
 func (c *lruCache) AddedDataHandlers() map[string]func(key []byte, value interface{}) {
 	return c.mapDataHandlers
 }
+
+func (c *lruCache) SweepExpired() int {
+	return c.sweepExpired()
+}
+
+func (c *lruCache) RemoveExpired(key string, value interface{}) {
+	c.removeExpired(key, value.(*expiringValue))
+}
+
+func (c *lruCache) PeekWrapped(key string) (interface{}, bool) {
+	return c.cache.Peek(key)
+}
@@ -3,20 +3,39 @@ package lrucache
 import (
 	"sync"
 
-	lru "github.com/hashicorp/golang-lru"
 	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/eviction"
 	"github.com/multiversx/mx-chain-storage-go/lrucache/capacity"
+	"github.com/multiversx/mx-chain-storage-go/lrucache/twoqueue"
+	"github.com/multiversx/mx-chain-storage-go/monitoring"
 	"github.com/multiversx/mx-chain-storage-go/types"
 )
 
 var _ types.Cacher = (*lruCache)(nil)
+var _ types.CacheStatsProvider = (*lruCache)(nil)
+var _ types.ResizableCacher = (*lruCache)(nil)
+var _ types.EvictionNotifier = (*lruCache)(nil)
+var _ types.CacherWithTTL = (*lruCache)(nil)
 
 var log = logger.GetOrCreate("storage/lrucache")
 
+type resizableLRUCache interface {
+	Resize(size int, byteCapacity int64) error
+}
+
 // LRUCache implements a Least Recently Used eviction cache
 type lruCache struct {
-	cache   types.SizedLRUCacheHandler
-	maxsize int
+	cache      types.SizedLRUCacheHandler
+	mutMaxSize sync.RWMutex
+	maxsize    int
+	counters   monitoring.CacheCounters
+	// onRemoved is called for every item leaving the cache, as the golang-lru eviction callback used to be
+	onRemoved        func(key interface{}, value interface{})
+	evictionHandlers eviction.Handlers
+	expiry           ExpiryConfig
+	clock            types.Clock
+	cancelCleanup    func()
 
 	mutAddedDataHandlers sync.RWMutex
 	mapDataHandlers      map[string]func(key []byte, value interface{})
@@ -24,66 +43,173 @@ type lruCache struct {
 
 // NewCache creates a new LRU cache instance
 func NewCache(size int) (*lruCache, error) {
-	cache, err := lru.New(size)
-	if err != nil {
-		return nil, err
-	}
-
-	c := createLRUCache(size, cache)
-
-	return c, nil
+	return NewCacheWithEviction(size, nil)
 }
 
-// NewCacheWithEviction creates a new sized LRU cache instance with eviction function
+// NewCacheWithEviction creates a new LRU cache instance with eviction function. The optional onEvicted callback
+// is called for every item leaving the cache, either evicted, removed or cleared.
 func NewCacheWithEviction(size int, onEvicted func(key interface{}, value interface{})) (*lruCache, error) {
-	cache, err := lru.NewWithEvict(size, onEvicted)
-	if err != nil {
-		return nil, err
-	}
-
-	c := createLRUCache(size, cache)
-
-	return c, nil
+	return newLRUCache(size, onEvicted, func(onCapacityEviction func(key interface{}, value interface{})) (types.SizedLRUCacheHandler, error) {
+		return newSimpleLRUCacheAdapter(size, onCapacityEviction)
+	})
 }
 
-func createLRUCache(size int, cache *lru.Cache) *lruCache {
+// newLRUCache wraps the cache created by the provided function, which receives the callback that should be called
+// for the items evicted due to the capacity
+func newLRUCache(
+	size int,
+	onRemoved func(key interface{}, value interface{}),
+	createCache func(onCapacityEviction func(key interface{}, value interface{})) (types.SizedLRUCacheHandler, error),
+) (*lruCache, error) {
 	c := &lruCache{
-		cache: &simpleLRUCacheAdapter{
-			LRUCacheHandler: cache,
-		},
 		maxsize:              size,
+		onRemoved:            onRemoved,
 		mutAddedDataHandlers: sync.RWMutex{},
 		mapDataHandlers:      make(map[string]func(key []byte, value interface{})),
 	}
-	return c
+
+	cache, err := createCache(c.onCapacityEviction)
+	if err != nil {
+		return nil, err
+	}
+	c.cache = cache
+
+	return c, nil
 }
 
 // NewCacheWithSizeInBytes creates a new sized LRU cache instance
 func NewCacheWithSizeInBytes(size int, sizeInBytes int64) (*lruCache, error) {
-	cache, err := capacity.NewCapacityLRU(size, sizeInBytes)
+	return newLRUCache(size, nil, func(onCapacityEviction func(key interface{}, value interface{})) (types.SizedLRUCacheHandler, error) {
+		return capacity.NewCapacityLRUWithEviction(size, sizeInBytes, onCapacityEviction)
+	})
+}
+
+// NewExpiringCache creates a new LRU cache instance whose items expire after the configured TTL or idle time.
+// The cache is also bounded in bytes if the provided size in bytes is not zero. Close should be called in order to
+// stop the background cleanup of the expired items.
+func NewExpiringCache(size int, sizeInBytes int64, expiry ExpiryConfig) (*lruCache, error) {
+	err := expiry.check()
 	if err != nil {
 		return nil, err
 	}
 
-	c := &lruCache{
-		cache:                cache,
-		maxsize:              size,
-		mutAddedDataHandlers: sync.RWMutex{},
-		mapDataHandlers:      make(map[string]func(key []byte, value interface{})),
+	c, err := newLRUCache(size, nil, func(onCapacityEviction func(key interface{}, value interface{})) (types.SizedLRUCacheHandler, error) {
+		if sizeInBytes == 0 {
+			return newSimpleLRUCacheAdapter(size, onCapacityEviction)
+		}
+
+		return capacity.NewCapacityLRUWithEviction(size, sizeInBytes, onCapacityEviction)
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	c.expiry = expiry
+	c.clock = clockOrDefault(expiry.Clock)
+	c.startCleanup()
+
 	return c, nil
 }
 
-// Clear is used to completely clear the cache.
+// NewShardedCacheWithSizeInBytes creates a new sized LRU cache instance, partitioned in independently locked shards
+func NewShardedCacheWithSizeInBytes(numShards int, size int, sizeInBytes int64) (*lruCache, error) {
+	return newLRUCache(size, nil, func(onCapacityEviction func(key interface{}, value interface{})) (types.SizedLRUCacheHandler, error) {
+		return capacity.NewShardedCapacityLRUWithEviction(numShards, size, sizeInBytes, onCapacityEviction)
+	})
+}
+
+// NewTinyLFUCacheWithSizeInBytes creates a new sized cache instance using the W-TinyLFU admission policy
+func NewTinyLFUCacheWithSizeInBytes(size int, sizeInBytes int64) (*lruCache, error) {
+	return newLRUCache(size, nil, func(onCapacityEviction func(key interface{}, value interface{})) (types.SizedLRUCacheHandler, error) {
+		return capacity.NewTinyLFUCapacityLRUWithEviction(size, sizeInBytes, onCapacityEviction)
+	})
+}
+
+// NewTwoQueueCache creates a new scan resistant 2Q cache instance
+func NewTwoQueueCache(size int) (*lruCache, error) {
+	return NewTwoQueueCacheWithEviction(size, nil)
+}
+
+// NewTwoQueueCacheWithEviction creates a new scan resistant 2Q cache instance with eviction function.
+// The optional onEvicted callback is called for each item evicted due to the capacity.
+func NewTwoQueueCacheWithEviction(size int, onEvicted func(key interface{}, value interface{})) (*lruCache, error) {
+	return newLRUCache(size, nil, func(onCapacityEviction func(key interface{}, value interface{})) (types.SizedLRUCacheHandler, error) {
+		return twoqueue.NewTwoQueueLRU(size, chainEvictionCallbacks(onEvicted, onCapacityEviction))
+	})
+}
+
+// NewTwoQueueCacheWithSizeInBytes creates a new scan resistant sized 2Q cache instance
+func NewTwoQueueCacheWithSizeInBytes(size int, sizeInBytes int64) (*lruCache, error) {
+	return NewTwoQueueCacheWithSizeInBytesAndEviction(size, sizeInBytes, nil)
+}
+
+// NewTwoQueueCacheWithSizeInBytesAndEviction creates a new scan resistant sized 2Q cache instance with eviction function.
+// The optional onEvicted callback is called for each item evicted due to the capacity.
+func NewTwoQueueCacheWithSizeInBytesAndEviction(
+	size int,
+	sizeInBytes int64,
+	onEvicted func(key interface{}, value interface{}),
+) (*lruCache, error) {
+	return newLRUCache(size, nil, func(onCapacityEviction func(key interface{}, value interface{})) (types.SizedLRUCacheHandler, error) {
+		return twoqueue.NewSizedTwoQueueLRU(size, sizeInBytes, chainEvictionCallbacks(onEvicted, onCapacityEviction))
+	})
+}
+
+func chainEvictionCallbacks(first func(key interface{}, value interface{}), second func(key interface{}, value interface{})) func(key interface{}, value interface{}) {
+	if first == nil {
+		return second
+	}
+
+	return func(key interface{}, value interface{}) {
+		first(key, value)
+		second(key, value)
+	}
+}
+
+func (c *lruCache) onCapacityEviction(key interface{}, value interface{}) {
+	c.counters.RecordEvictions(1)
+	c.notifyEvicted(key.(string), unwrapExpiringValue(value), types.EvictedByCapacity)
+}
+
+func (c *lruCache) notifyEvicted(key string, value interface{}, reason types.EvictionReason) {
+	if c.onRemoved != nil {
+		c.onRemoved(key, value)
+	}
+
+	c.evictionHandlers.Notify([]byte(key), value, reason)
+}
+
+// shouldNotifyRemovals returns true if someone listens for the removed items, as collecting them has a cost
+func (c *lruCache) shouldNotifyRemovals() bool {
+	return c.onRemoved != nil || c.evictionHandlers.HasHandlers()
+}
+
+// Clear is used to completely clear the cache. The items added concurrently with the clear may be dropped
+// without being notified to the eviction handlers.
 func (c *lruCache) Clear() {
+	if !c.shouldNotifyRemovals() {
+		c.cache.Purge()
+		return
+	}
+
+	keys := c.cache.Keys()
+	removed := make([]evictedEntry, 0, len(keys))
+	for _, key := range keys {
+		value, ok := c.cache.Peek(key)
+		if ok {
+			removed = append(removed, evictedEntry{key: key, value: unwrapExpiringValue(value)})
+		}
+	}
 	c.cache.Purge()
+
+	for _, ent := range removed {
+		c.notifyEvicted(ent.key.(string), ent.value, types.EvictedByClear)
+	}
 }
 
 // Put adds a value to the cache.  Returns true if an eviction occurred.
 func (c *lruCache) Put(key []byte, value interface{}, sizeInBytes int) (evicted bool) {
-	evicted = c.cache.AddSized(string(key), value, int64(sizeInBytes))
-
+	evicted = c.cache.AddSized(string(key), c.wrapValue(value, sizeInBytes), int64(sizeInBytes))
 	c.callAddedDataHandlers(key, value)
 
 	return evicted
@@ -108,21 +234,58 @@ func (c *lruCache) UnRegisterHandler(id string) {
 	c.mutAddedDataHandlers.Unlock()
 }
 
-// Get looks up a key's value from the cache.
+// RegisterEvictionHandler registers a new handler to be called for each item leaving the cache
+func (c *lruCache) RegisterEvictionHandler(handler types.EvictionHandlerFunc, id string) {
+	c.evictionHandlers.Register(handler, id)
+}
+
+// UnRegisterEvictionHandler removes the eviction handler registered under the provided id
+func (c *lruCache) UnRegisterEvictionHandler(id string) {
+	c.evictionHandlers.UnRegister(id)
+}
+
+// Get looks up a key's value from the cache. An expired item is removed, while a read one has its idle expiry
+// postponed.
 func (c *lruCache) Get(key []byte) (value interface{}, ok bool) {
-	return c.cache.Get(string(key))
+	value, ok = c.cache.Get(string(key))
+	if ok {
+		value, ok = c.checkExpiry(string(key), value, true)
+	}
+	c.counters.RecordLookup(ok)
+
+	return value, ok
 }
 
 // Has checks if a key is in the cache, without updating the
-// recent-ness or deleting it for being stale.
+// recent-ness. An expired item is removed.
 func (c *lruCache) Has(key []byte) bool {
-	return c.cache.Contains(string(key))
+	ok := c.has(string(key))
+	c.counters.RecordLookup(ok)
+
+	return ok
+}
+
+func (c *lruCache) has(key string) bool {
+	if !c.expiry.isEnabled() {
+		return c.cache.Contains(key)
+	}
+
+	value, ok := c.cache.Peek(key)
+	if !ok {
+		return false
+	}
+	_, ok = c.checkExpiry(key, value, false)
+
+	return ok
 }
 
 // Peek returns the key value (or undefined if not found) without updating
-// the "recently used"-ness of the key.
+// the "recently used"-ness of the key or the hit and miss statistics. An expired item is removed.
 func (c *lruCache) Peek(key []byte) (value interface{}, ok bool) {
 	v, ok := c.cache.Peek(string(key))
+	if ok {
+		v, ok = c.checkExpiry(string(key), v, false)
+	}
 
 	if !ok {
 		return nil, ok
@@ -132,11 +295,17 @@ func (c *lruCache) Peek(key []byte) (value interface{}, ok bool) {
 }
 
 // HasOrAdd checks if a key is in the cache  without updating the
-// recent-ness or deleting it for being stale,  and if not, adds the value.
+// recent-ness,  and if not, adds the value. An expired item is replaced.
 // Returns whether found and whether an eviction occurred.
 func (c *lruCache) HasOrAdd(key []byte, value interface{}, sizeInBytes int) (has, added bool) {
-	has, _ = c.cache.AddSizedIfMissing(string(key), value, int64(sizeInBytes))
+	if c.expiry.isEnabled() {
+		existing, ok := c.cache.Peek(string(key))
+		if ok {
+			_, _ = c.checkExpiry(string(key), existing, false)
+		}
+	}
 
+	has, _ = c.cache.AddSizedIfMissing(string(key), c.wrapValue(value, sizeInBytes), int64(sizeInBytes))
 	if !has {
 		c.callAddedDataHandlers(key, value)
 	}
@@ -154,10 +323,20 @@ func (c *lruCache) callAddedDataHandlers(key []byte, value interface{}) {
 
 // Remove removes the provided key from the cache.
 func (c *lruCache) Remove(key []byte) {
-	c.cache.Remove(string(key))
+	if !c.shouldNotifyRemovals() {
+		c.cache.Remove(string(key))
+		return
+	}
+
+	value, found := c.cache.Peek(string(key))
+	removed := c.cache.Remove(string(key))
+	if found && removed {
+		c.notifyEvicted(string(key), unwrapExpiringValue(value), types.EvictedByRemoval)
+	}
 }
 
-// Keys returns a slice of the keys in the cache, from oldest to newest.
+// Keys returns a slice of the keys in the cache, from oldest to newest. The expired items not yet cleaned up are
+// included.
 func (c *lruCache) Keys() [][]byte {
 	res := c.cache.Keys()
 	r := make([][]byte, len(res))
@@ -169,23 +348,57 @@ func (c *lruCache) Keys() [][]byte {
 	return r
 }
 
-// Len returns the number of items in the cache.
+// Len returns the number of items in the cache, the expired items not yet cleaned up included.
 func (c *lruCache) Len() int {
 	return c.cache.Len()
 }
 
-// SizeInBytesContained returns the size in bytes of all contained elements
+// SizeInBytesContained returns the size in bytes of all contained elements, including the keys and the overhead of
+// the entries. The capacity is bounded by the sizes provided by the callers.
 func (c *lruCache) SizeInBytesContained() uint64 {
-	return c.cache.SizeInBytesContained()
+	return c.cache.EntriesSizeInBytes()
 }
 
 // MaxSize returns the maximum number of items which can be stored in cache.
 func (c *lruCache) MaxSize() int {
+	c.mutMaxSize.RLock()
+	defer c.mutMaxSize.RUnlock()
+
 	return c.maxsize
 }
 
-// Close does nothing for this cacher implementation
+// Resize changes the limits of the cache, evicting the least recently used items if the new limits are exceeded.
+// The size in bytes is ignored if the cache was not created with a size in bytes.
+func (c *lruCache) Resize(maxNumItems int, maxSizeInBytes int64) error {
+	resizable, ok := c.cache.(resizableLRUCache)
+	if !ok {
+		return common.ErrResizeNotSupported
+	}
+
+	c.mutMaxSize.Lock()
+	defer c.mutMaxSize.Unlock()
+
+	err := resizable.Resize(maxNumItems, maxSizeInBytes)
+	if err != nil {
+		return err
+	}
+
+	c.maxsize = maxNumItems
+
+	return nil
+}
+
+// CacheStats returns the statistics of the cache
+func (c *lruCache) CacheStats() types.CacheStats {
+	return c.counters.Stats(c.cache.Len(), c.cache.EntriesSizeInBytes())
+}
+
+// Close stops the background cleanup of the expired items, if any
 func (c *lruCache) Close() error {
+	if c.cancelCleanup != nil {
+		c.cancelCleanup()
+	}
+
 	return nil
 }
 
@@ -112,6 +112,21 @@ func TestLRUCache_PutPresentRewrite(t *testing.T) {
 	assert.Equal(t, val2, recoveredVal)
 }
 
+func TestLRUCache_NegativeSizeShouldBeIgnoredByTheCountBoundCache(t *testing.T) {
+	t.Parallel()
+
+	c, _ := lrucache.NewCache(10)
+	_ = c.Put([]byte("key"), "value", -1)
+	has, added := c.HasOrAdd([]byte("other"), "value", -1)
+
+	assert.False(t, has)
+	assert.True(t, added)
+	assert.True(t, c.Has([]byte("key")))
+	assert.True(t, c.Has([]byte("other")))
+	expectedSize := len("key") + len("other") + 2*types.EntryOverheadInBytes
+	assert.Equal(t, uint64(expectedSize), c.SizeInBytesContained())
+}
+
 func TestLRUCache_GetNotPresent(t *testing.T) {
 	t.Parallel()
 
@@ -459,3 +474,397 @@ func TestLruCache_LenDuringEviction(t *testing.T) {
 		assert.Fail(t, "test failed, deadlock occurred")
 	}
 }
+
+func TestLruCache_CacheStats(t *testing.T) {
+	t.Parallel()
+
+	c, _ := lrucache.NewCacheWithSizeInBytes(2, 1024)
+	_ = c.Put([]byte("key1"), "value1", 10)
+	_ = c.Put([]byte("key2"), "value2", 20)
+	_ = c.Put([]byte("key3"), "value3", 30)
+
+	_, _ = c.Get([]byte("key1"))
+	_, _ = c.Get([]byte("key2"))
+	_ = c.Has([]byte("key3"))
+	// peeking does not count as a lookup
+	_, _ = c.Peek([]byte("key3"))
+	_, _ = c.Peek([]byte("missing"))
+
+	// the reported size includes the keys and the overhead of the entries
+	expectedSizeInBytes := 20 + 30 + 2*(len("key2")+types.EntryOverheadInBytes)
+	expectedStats := types.CacheStats{
+		NumItems:     2,
+		SizeInBytes:  uint64(expectedSizeInBytes),
+		NumHits:      2,
+		NumMisses:    1,
+		NumEvictions: 1,
+	}
+	assert.Equal(t, expectedStats, c.CacheStats())
+}
+
+func TestLruCache_CacheStatsShouldReportTheEntriesSize(t *testing.T) {
+	t.Parallel()
+
+	caches := map[string]types.Cacher{}
+	caches["count"], _ = lrucache.NewCache(10)
+	caches["size in bytes"], _ = lrucache.NewCacheWithSizeInBytes(10, 2000)
+	caches["2Q"], _ = lrucache.NewTwoQueueCache(10)
+
+	for name, c := range caches {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			statsProvider := c.(types.CacheStatsProvider)
+			_ = c.Put([]byte("first"), "value", 1000)
+			_, _ = c.HasOrAdd([]byte("other"), "value", 50)
+			expectedSize := 1000 + 50 + 2*(5+types.EntryOverheadInBytes)
+			assert.Equal(t, uint64(expectedSize), c.SizeInBytesContained())
+			assert.Equal(t, uint64(expectedSize), statsProvider.CacheStats().SizeInBytes)
+
+			// an update replaces the reported size
+			_ = c.Put([]byte("other"), "value", 10)
+			expectedSize -= 40
+			assert.Equal(t, uint64(expectedSize), statsProvider.CacheStats().SizeInBytes)
+
+			c.Remove([]byte("first"))
+			expectedSize -= 1000 + 5 + types.EntryOverheadInBytes
+			assert.Equal(t, uint64(expectedSize), statsProvider.CacheStats().SizeInBytes)
+
+			c.Clear()
+			assert.Zero(t, statsProvider.CacheStats().SizeInBytes)
+		})
+	}
+}
+
+func TestLruCache_Resize(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sized cache should resize", func(t *testing.T) {
+		t.Parallel()
+
+		c, _ := lrucache.NewCacheWithSizeInBytes(10, 1000)
+		for i := 0; i < 10; i++ {
+			_ = c.Put([]byte(fmt.Sprintf("key%d", i)), i, 100)
+		}
+
+		err := c.Resize(3, 1000)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, c.MaxSize())
+		assert.Equal(t, 3, c.Len())
+		assert.True(t, c.Has([]byte("key9")))
+
+		err = c.Resize(0, 1000)
+		assert.Equal(t, common.ErrCacheSizeInvalid, err)
+		assert.Equal(t, 3, c.MaxSize())
+	})
+	t.Run("cache without size in bytes should resize by number of items", func(t *testing.T) {
+		t.Parallel()
+
+		c, _ := lrucache.NewCache(10)
+		for i := 0; i < 10; i++ {
+			_ = c.Put([]byte(fmt.Sprintf("key%d", i)), i, 100)
+		}
+
+		err := c.Resize(3, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, c.MaxSize())
+		assert.Equal(t, 3, c.Len())
+		assert.True(t, c.Has([]byte("key9")))
+		assert.False(t, c.Has([]byte("key0")))
+		assert.Equal(t, uint64(7), c.CacheStats().NumEvictions)
+
+		err = c.Resize(0, 1)
+		assert.Equal(t, common.ErrCacheSizeInvalid, err)
+		assert.Equal(t, 3, c.MaxSize())
+	})
+	t.Run("concurrent operations while resizing should work", func(t *testing.T) {
+		t.Parallel()
+
+		c, _ := lrucache.NewCacheWithSizeInBytes(100, 10000)
+		numOperations := 1000
+		wg := sync.WaitGroup{}
+		wg.Add(numOperations)
+		for i := 0; i < numOperations; i++ {
+			go func(idx int) {
+				defer wg.Done()
+
+				key := []byte(fmt.Sprintf("key%d", idx))
+				switch idx % 4 {
+				case 0:
+					_ = c.Resize(idx%50+1, int64(idx*10+100))
+				case 1:
+					_, _ = c.Get(key)
+				default:
+					_ = c.Put(key, idx, 10)
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		assert.True(t, c.Len() <= c.MaxSize())
+	})
+}
+
+func TestNewTwoQueueCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid size should error", func(t *testing.T) {
+		t.Parallel()
+
+		c, err := lrucache.NewTwoQueueCache(0)
+		assert.True(t, check.IfNil(c))
+		assert.Equal(t, common.ErrCacheSizeInvalid, err)
+
+		c, err = lrucache.NewTwoQueueCacheWithSizeInBytes(10, 0)
+		assert.True(t, check.IfNil(c))
+		assert.Equal(t, common.ErrCacheCapacityInvalid, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		c, err := lrucache.NewTwoQueueCache(10)
+		assert.False(t, check.IfNil(c))
+		assert.Nil(t, err)
+		assert.Equal(t, 10, c.MaxSize())
+
+		c, err = lrucache.NewTwoQueueCacheWithSizeInBytes(10, 1000)
+		assert.False(t, check.IfNil(c))
+		assert.Nil(t, err)
+		assert.Equal(t, 10, c.MaxSize())
+	})
+}
+
+func TestTwoQueueCache_ScanShouldNotFlushFrequentItems(t *testing.T) {
+	t.Parallel()
+
+	numEvicted := 0
+	c, _ := lrucache.NewTwoQueueCacheWithSizeInBytesAndEviction(100, 100000, func(_ interface{}, _ interface{}) {
+		numEvicted++
+	})
+	for i := 0; i < 50; i++ {
+		key := []byte(fmt.Sprintf("hot%d", i))
+		_ = c.Put(key, i, 10)
+		_, _ = c.Get(key)
+	}
+
+	for i := 0; i < 1000; i++ {
+		_ = c.Put([]byte(fmt.Sprintf("scan%d", i)), i, 10)
+	}
+
+	for i := 0; i < 50; i++ {
+		assert.True(t, c.Has([]byte(fmt.Sprintf("hot%d", i))))
+	}
+	assert.Equal(t, 100, c.Len())
+	expectedSize := 0
+	for _, key := range c.Keys() {
+		expectedSize += 10 + len(key) + types.EntryOverheadInBytes
+	}
+	assert.Equal(t, uint64(expectedSize), c.SizeInBytesContained())
+	assert.Equal(t, 950, numEvicted)
+}
+
+func TestTwoQueueCache_RegisterHandlerShouldBeCalledOnPut(t *testing.T) {
+	t.Parallel()
+
+	chCalled := make(chan []byte, 1)
+	c, _ := lrucache.NewTwoQueueCacheWithEviction(10, nil)
+	c.RegisterHandler(func(key []byte, _ interface{}) {
+		chCalled <- key
+	}, "id")
+
+	_ = c.Put([]byte("key"), "value", 0)
+
+	select {
+	case key := <-chCalled:
+		assert.Equal(t, []byte("key"), key)
+	case <-time.After(timeoutWaitForWaitGroups):
+		assert.Fail(t, "handler was not called")
+	}
+}
+
+func TestTwoQueueCache_Resize(t *testing.T) {
+	t.Parallel()
+
+	c, _ := lrucache.NewTwoQueueCache(10)
+	for i := 0; i < 10; i++ {
+		_ = c.Put([]byte(fmt.Sprintf("key%d", i)), i, 0)
+	}
+
+	err := c.Resize(3, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, c.MaxSize())
+	assert.Equal(t, 3, c.Len())
+	assert.Equal(t, uint64(7), c.CacheStats().NumEvictions)
+}
+
+func TestNewTinyLFUCacheWithSizeInBytes(t *testing.T) {
+	t.Parallel()
+
+	c, err := lrucache.NewTinyLFUCacheWithSizeInBytes(0, 1000)
+	assert.True(t, check.IfNil(c))
+	assert.Equal(t, common.ErrCacheSizeInvalid, err)
+
+	c, err = lrucache.NewTinyLFUCacheWithSizeInBytes(10, 1000)
+	assert.False(t, check.IfNil(c))
+	assert.Nil(t, err)
+	assert.Equal(t, 10, c.MaxSize())
+
+	_ = c.Put([]byte("key"), "value", 10)
+	value, ok := c.Get([]byte("key"))
+	assert.True(t, ok)
+	assert.Equal(t, "value", value)
+	assert.Equal(t, uint64(10+len("key")+types.EntryOverheadInBytes), c.SizeInBytesContained())
+
+	err = c.Resize(5, 500)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, c.MaxSize())
+}
+
+//------- eviction handlers
+
+type evictionRecord struct {
+	key    string
+	value  interface{}
+	reason types.EvictionReason
+}
+
+type evictionRecorder struct {
+	mut     sync.Mutex
+	records []evictionRecord
+}
+
+func (recorder *evictionRecorder) handler(key []byte, value interface{}, reason types.EvictionReason) {
+	recorder.mut.Lock()
+	recorder.records = append(recorder.records, evictionRecord{key: string(key), value: value, reason: reason})
+	recorder.mut.Unlock()
+}
+
+func (recorder *evictionRecorder) getRecords() []evictionRecord {
+	recorder.mut.Lock()
+	defer recorder.mut.Unlock()
+
+	return append([]evictionRecord(nil), recorder.records...)
+}
+
+func TestLRUCache_EvictionHandlersShouldReceiveTheReason(t *testing.T) {
+	t.Parallel()
+
+	createCaches := map[string]func() types.Cacher{
+		"count": func() types.Cacher {
+			c, _ := lrucache.NewCache(2)
+			return c
+		},
+		"size in bytes": func() types.Cacher {
+			c, _ := lrucache.NewCacheWithSizeInBytes(100, 20)
+			return c
+		},
+		"sharded size in bytes": func() types.Cacher {
+			c, _ := lrucache.NewShardedCacheWithSizeInBytes(1, 100, 20)
+			return c
+		},
+		"TinyLFU": func() types.Cacher {
+			c, _ := lrucache.NewTinyLFUCacheWithSizeInBytes(2, 1000)
+			return c
+		},
+		"2Q": func() types.Cacher {
+			c, _ := lrucache.NewTwoQueueCache(2)
+			return c
+		},
+	}
+
+	for name, createCache := range createCaches {
+		createCache := createCache
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			c := createCache()
+			recorder := &evictionRecorder{}
+			c.(types.EvictionNotifier).RegisterEvictionHandler(recorder.handler, "recorder")
+
+			_ = c.Put([]byte("key0"), 0, 10)
+			_ = c.Put([]byte("key1"), 1, 10)
+			_, _ = c.HasOrAdd([]byte("key2"), 2, 10)
+			records := recorder.getRecords()
+			assert.Equal(t, 1, len(records))
+			assert.Equal(t, types.EvictedByCapacity, records[0].reason)
+			assert.False(t, c.Has([]byte(records[0].key)))
+			assert.Equal(t, uint64(1), c.(types.CacheStatsProvider).CacheStats().NumEvictions)
+
+			c.Remove([]byte("key2"))
+			c.Remove([]byte("missing"))
+			records = recorder.getRecords()
+			assert.Equal(t, 2, len(records))
+			assert.Equal(t, evictionRecord{key: "key2", value: 2, reason: types.EvictedByRemoval}, records[1])
+
+			c.Clear()
+			records = recorder.getRecords()
+			assert.Equal(t, 3, len(records))
+			assert.Equal(t, types.EvictedByClear, records[2].reason)
+			assert.Zero(t, c.Len())
+
+			c.(types.EvictionNotifier).UnRegisterEvictionHandler("recorder")
+			_ = c.Put([]byte("key3"), 3, 10)
+			c.Remove([]byte("key3"))
+			assert.Equal(t, 3, len(recorder.getRecords()))
+		})
+	}
+}
+
+func TestLRUCache_EvictionHandlersShouldBeCalledOnResize(t *testing.T) {
+	t.Parallel()
+
+	c, _ := lrucache.NewCache(10)
+	recorder := &evictionRecorder{}
+	c.RegisterEvictionHandler(recorder.handler, "recorder")
+	for i := 0; i < 10; i++ {
+		_ = c.Put([]byte(fmt.Sprintf("key%d", i)), i, 0)
+	}
+
+	err := c.Resize(7, 0)
+	assert.Nil(t, err)
+	records := recorder.getRecords()
+	assert.Equal(t, 3, len(records))
+	for i, record := range records {
+		assert.Equal(t, evictionRecord{key: fmt.Sprintf("key%d", i), value: i, reason: types.EvictedByCapacity}, record)
+	}
+	assert.Equal(t, uint64(3), c.CacheStats().NumEvictions)
+}
+
+func TestLRUCache_EvictionCallbackShouldBeCalledForEveryRemovedItem(t *testing.T) {
+	t.Parallel()
+
+	evictedKeys := make([]interface{}, 0)
+	c, _ := lrucache.NewCacheWithEviction(2, func(key interface{}, _ interface{}) {
+		evictedKeys = append(evictedKeys, key)
+	})
+
+	_ = c.Put([]byte("key0"), 0, 0)
+	_ = c.Put([]byte("key1"), 1, 0)
+	_ = c.Put([]byte("key2"), 2, 0)
+	c.Remove([]byte("key1"))
+	c.Clear()
+
+	assert.Equal(t, []interface{}{"key0", "key1", "key2"}, evictedKeys)
+}
+
+func TestTwoQueueCache_EvictionCallbackAndHandlersShouldBothBeCalled(t *testing.T) {
+	t.Parallel()
+
+	evictedKeys := make([]interface{}, 0)
+	c, _ := lrucache.NewTwoQueueCacheWithEviction(1, func(key interface{}, _ interface{}) {
+		evictedKeys = append(evictedKeys, key)
+	})
+	recorder := &evictionRecorder{}
+	c.RegisterEvictionHandler(recorder.handler, "recorder")
+
+	_ = c.Put([]byte("key0"), 0, 0)
+	_ = c.Put([]byte("key1"), 1, 0)
+	c.Remove([]byte("key1"))
+
+	assert.Equal(t, []interface{}{"key0"}, evictedKeys)
+	assert.Equal(t, []evictionRecord{
+		{key: "key0", value: 0, reason: types.EvictedByCapacity},
+		{key: "key1", value: 1, reason: types.EvictedByRemoval},
+	}, recorder.getRecords())
+}
@@ -1,25 +1,251 @@
 package lrucache
 
 import (
+	"sync"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+	"github.com/multiversx/mx-chain-storage-go/common"
 	"github.com/multiversx/mx-chain-storage-go/types"
 )
 
-// simpleLRUCacheAdapter provides an adapter between LRUCacheHandler and SizeLRUCacheHandler
+// simpleLRUCacheAdapter provides a thread safe LRU cache, bounded only by the number of items, with the
+// SizedLRUCacheHandler interface. The sizes in bytes of the items are only accounted, not bounded.
 type simpleLRUCacheAdapter struct {
-	types.LRUCacheHandler
+	mut                sync.RWMutex
+	lru                *simplelru.LRU
+	sizeInBytes        int64
+	entriesSizeInBytes int64
+	// evicted buffers the entries evicted during an operation, so that onEvicted is called outside the lock
+	evicted   []evictedEntry
+	onEvicted func(key interface{}, value interface{})
+}
+
+type evictedEntry struct {
+	key   interface{}
+	value interface{}
+}
+
+// sizedValue is the value held by the underlying LRU, so that the size of a removed item is known
+type sizedValue struct {
+	value     interface{}
+	size      int64
+	entrySize int64
+}
+
+// newSimpleLRUCacheAdapter creates an adapter of the given size. The optional onEvicted callback is called,
+// outside the cache lock, for each item evicted due to the capacity.
+func newSimpleLRUCacheAdapter(size int, onEvicted func(key interface{}, value interface{})) (*simpleLRUCacheAdapter, error) {
+	slca := &simpleLRUCacheAdapter{
+		onEvicted: onEvicted,
+	}
+
+	var err error
+	slca.lru, err = simplelru.NewLRU(size, slca.bufferEvicted)
+	if err != nil {
+		return nil, err
+	}
+
+	return slca, nil
 }
 
-// AddSized calls the Add method without the size in bytes parameter
-func (slca *simpleLRUCacheAdapter) AddSized(key, value interface{}, _ int64) bool {
-	return slca.Add(key, value)
+// bufferEvicted is called by the underlying LRU under the lock, for the evicted and for the removed items alike
+func (slca *simpleLRUCacheAdapter) bufferEvicted(key interface{}, value interface{}) {
+	sv := value.(*sizedValue)
+	slca.sizeInBytes -= sv.size
+	slca.entriesSizeInBytes -= sv.entrySize
+	slca.evicted = append(slca.evicted, evictedEntry{key: key, value: sv.value})
 }
 
-// AddSizedIfMissing calls ContainsOrAdd without the size in bytes parameter
-func (slca *simpleLRUCacheAdapter) AddSizedIfMissing(key, value interface{}, _ int64) (ok, evicted bool) {
-	return slca.ContainsOrAdd(key, value)
+// add replaces the value of an existing key without reporting an eviction, then adds the sized value. A negative
+// size is accounted as zero, as the adapter is not bounded in bytes.
+func (slca *simpleLRUCacheAdapter) add(key interface{}, value interface{}, sizeInBytes int64) {
+	if sizeInBytes < 0 {
+		sizeInBytes = 0
+	}
+
+	existing, ok := slca.lru.Peek(key)
+	if ok {
+		slca.sizeInBytes -= existing.(*sizedValue).size
+		slca.entriesSizeInBytes -= existing.(*sizedValue).entrySize
+	}
+
+	sv := &sizedValue{
+		value:     value,
+		size:      sizeInBytes,
+		entrySize: int64(types.ComputeAnyKeyEntrySizeInBytes(key, value, int(sizeInBytes))),
+	}
+	_ = slca.lru.Add(key, sv)
+	slca.sizeInBytes += sv.size
+	slca.entriesSizeInBytes += sv.entrySize
+}
+
+func (slca *simpleLRUCacheAdapter) takeEvicted() []evictedEntry {
+	evicted := slca.evicted
+	slca.evicted = nil
+
+	return evicted
+}
+
+func (slca *simpleLRUCacheAdapter) notifyEvicted(evicted []evictedEntry) {
+	if slca.onEvicted == nil {
+		return
+	}
+
+	for _, ent := range evicted {
+		slca.onEvicted(ent.key, ent.value)
+	}
 }
 
-// SizeInBytesContained returns 0
+// AddSized adds a value to the cache, the size in bytes being only accounted. Returns true if an eviction occurred.
+func (slca *simpleLRUCacheAdapter) AddSized(key, value interface{}, sizeInBytes int64) bool {
+	slca.mut.Lock()
+	slca.add(key, value, sizeInBytes)
+	evicted := slca.takeEvicted()
+	slca.mut.Unlock()
+
+	slca.notifyEvicted(evicted)
+
+	return len(evicted) > 0
+}
+
+// AddSizedIfMissing checks if a key is in the cache without updating the
+// recent-ness or deleting it for being stale, and if not, adds the value.
+// Returns whether found and whether an eviction occurred.
+func (slca *simpleLRUCacheAdapter) AddSizedIfMissing(key, value interface{}, sizeInBytes int64) (ok, evicted bool) {
+	slca.mut.Lock()
+	if slca.lru.Contains(key) {
+		slca.mut.Unlock()
+		return true, false
+	}
+
+	slca.add(key, value, sizeInBytes)
+	evictedEntries := slca.takeEvicted()
+	slca.mut.Unlock()
+
+	slca.notifyEvicted(evictedEntries)
+
+	return false, len(evictedEntries) > 0
+}
+
+// Get looks up a key's value from the cache.
+func (slca *simpleLRUCacheAdapter) Get(key interface{}) (interface{}, bool) {
+	slca.mut.Lock()
+	defer slca.mut.Unlock()
+
+	return unwrapValue(slca.lru.Get(key))
+}
+
+func unwrapValue(value interface{}, ok bool) (interface{}, bool) {
+	if !ok {
+		return nil, false
+	}
+
+	return value.(*sizedValue).value, true
+}
+
+// Contains checks if a key is in the cache, without updating the recent-ness
+// or deleting it for being stale.
+func (slca *simpleLRUCacheAdapter) Contains(key interface{}) bool {
+	slca.mut.RLock()
+	defer slca.mut.RUnlock()
+
+	return slca.lru.Contains(key)
+}
+
+// Peek returns the key value (or undefined if not found) without updating
+// the "recently used"-ness of the key.
+func (slca *simpleLRUCacheAdapter) Peek(key interface{}) (interface{}, bool) {
+	slca.mut.RLock()
+	defer slca.mut.RUnlock()
+
+	return unwrapValue(slca.lru.Peek(key))
+}
+
+// Remove removes the provided key from the cache, returning if the key was contained.
+// The removal is not reported as an eviction.
+func (slca *simpleLRUCacheAdapter) Remove(key interface{}) bool {
+	slca.mut.Lock()
+	defer slca.mut.Unlock()
+
+	removed := slca.lru.Remove(key)
+	_ = slca.takeEvicted()
+
+	return removed
+}
+
+// RemoveIfSame removes the provided key from the cache only if it still holds the provided value, returning if the
+// key was removed. The values are compared by identity, hence they should be comparable.
+func (slca *simpleLRUCacheAdapter) RemoveIfSame(key interface{}, value interface{}) bool {
+	slca.mut.Lock()
+	defer slca.mut.Unlock()
+
+	existing, ok := slca.lru.Peek(key)
+	if !ok || existing.(*sizedValue).value != value {
+		return false
+	}
+
+	removed := slca.lru.Remove(key)
+	_ = slca.takeEvicted()
+
+	return removed
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest.
+func (slca *simpleLRUCacheAdapter) Keys() []interface{} {
+	slca.mut.RLock()
+	defer slca.mut.RUnlock()
+
+	return slca.lru.Keys()
+}
+
+// Len returns the number of items in the cache.
+func (slca *simpleLRUCacheAdapter) Len() int {
+	slca.mut.RLock()
+	defer slca.mut.RUnlock()
+
+	return slca.lru.Len()
+}
+
+// Purge is used to completely clear the cache. The removals are not reported as evictions.
+func (slca *simpleLRUCacheAdapter) Purge() {
+	slca.mut.Lock()
+	defer slca.mut.Unlock()
+
+	slca.lru.Purge()
+	_ = slca.takeEvicted()
+	slca.sizeInBytes = 0
+	slca.entriesSizeInBytes = 0
+}
+
+// SizeInBytesContained returns the size in bytes of all contained items
 func (slca *simpleLRUCacheAdapter) SizeInBytesContained() uint64 {
-	return 0
+	slca.mut.RLock()
+	defer slca.mut.RUnlock()
+
+	return uint64(slca.sizeInBytes)
+}
+
+// EntriesSizeInBytes returns the size in bytes of all contained entries, including the keys and the overhead of
+// the entries
+func (slca *simpleLRUCacheAdapter) EntriesSizeInBytes() uint64 {
+	slca.mut.RLock()
+	defer slca.mut.RUnlock()
+
+	return uint64(slca.entriesSizeInBytes)
+}
+
+// Resize changes the maximum number of items, the size in bytes parameter is ignored
+func (slca *simpleLRUCacheAdapter) Resize(size int, _ int64) error {
+	if size < 1 {
+		return common.ErrCacheSizeInvalid
+	}
+
+	slca.mut.Lock()
+	_ = slca.lru.Resize(size)
+	evicted := slca.takeEvicted()
+	slca.mut.Unlock()
+
+	slca.notifyEvicted(evicted)
+
+	return nil
 }
@@ -0,0 +1,414 @@
+package twoqueue
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+var log = logger.GetOrCreate("storage/lrucache/twoqueue")
+
+const (
+	// recentRatio is the share of the cache that the items seen only once can hold before being evicted
+	// in favour of the frequently used ones
+	recentRatio = 0.25
+	// ghostRatio is the number of evicted keys, relative to the cache size, remembered in order to detect
+	// the keys that are added again
+	ghostRatio = 0.5
+)
+
+// twoQueueLRU implements a thread safe 2Q cache. The items seen once are kept in a FIFO queue, separated from
+// the items used more than once, which are kept in an LRU queue. A sequential scan through many keys will only
+// cycle through the first queue, so that the frequently used items are not flushed. The keys recently evicted
+// from the first queue are remembered and, if added again, they go straight to the frequently used items.
+type twoQueueLRU struct {
+	lock                   sync.Mutex
+	sized                  bool
+	size                   int
+	maxCapacityInBytes     int64
+	currentCapacityInBytes int64
+	entriesSizeInBytes     int64
+	recent                 *list.List
+	frequent               *list.List
+	items                  map[interface{}]*list.Element
+	ghost                  *list.List
+	ghostItems             map[interface{}]*list.Element
+	onEvicted              func(key interface{}, value interface{})
+}
+
+// entry is used to hold a value in the recent or frequent lists
+type entry struct {
+	key        interface{}
+	value      interface{}
+	size       int64
+	entrySize  int64
+	isFrequent bool
+}
+
+// NewTwoQueueLRU constructs a 2Q cache bounded by the number of items. The sizes in bytes of the items are ignored.
+// The optional onEvicted callback is called, outside the cache lock, for each item evicted due to the capacity.
+func NewTwoQueueLRU(size int, onEvicted func(key interface{}, value interface{})) (*twoQueueLRU, error) {
+	if size < 1 {
+		return nil, common.ErrCacheSizeInvalid
+	}
+
+	return newTwoQueueLRU(size, 0, onEvicted), nil
+}
+
+// NewSizedTwoQueueLRU constructs a 2Q cache bounded by both the number of items and their size in bytes.
+// The optional onEvicted callback is called, outside the cache lock, for each item evicted due to the capacity.
+func NewSizedTwoQueueLRU(size int, byteCapacity int64, onEvicted func(key interface{}, value interface{})) (*twoQueueLRU, error) {
+	if size < 1 {
+		return nil, common.ErrCacheSizeInvalid
+	}
+	if byteCapacity < 1 {
+		return nil, common.ErrCacheCapacityInvalid
+	}
+
+	return newTwoQueueLRU(size, byteCapacity, onEvicted), nil
+}
+
+func newTwoQueueLRU(size int, byteCapacity int64, onEvicted func(key interface{}, value interface{})) *twoQueueLRU {
+	return &twoQueueLRU{
+		sized:              byteCapacity > 0,
+		size:               size,
+		maxCapacityInBytes: byteCapacity,
+		recent:             list.New(),
+		frequent:           list.New(),
+		items:              make(map[interface{}]*list.Element),
+		ghost:              list.New(),
+		ghostItems:         make(map[interface{}]*list.Element),
+		onEvicted:          onEvicted,
+	}
+}
+
+// Resize changes the limits of the cache, evicting items if the new limits are exceeded.
+// The size in bytes is ignored if the cache is bounded only by the number of items.
+func (c *twoQueueLRU) Resize(size int, byteCapacity int64) error {
+	if size < 1 {
+		return common.ErrCacheSizeInvalid
+	}
+	if c.isSized() && byteCapacity < 1 {
+		return common.ErrCacheCapacityInvalid
+	}
+
+	c.lock.Lock()
+	c.size = size
+	if c.isSized() {
+		c.maxCapacityInBytes = byteCapacity
+	}
+	c.trimGhost()
+	evicted := c.evictIfNeeded()
+	c.lock.Unlock()
+
+	c.notifyEvicted(evicted)
+
+	return nil
+}
+
+// isSized does not need the lock, as a cache can not switch between being sized or not
+func (c *twoQueueLRU) isSized() bool {
+	return c.sized
+}
+
+// Purge is used to completely clear the cache, including the remembered evicted keys
+func (c *twoQueueLRU) Purge() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.recent.Init()
+	c.frequent.Init()
+	c.items = make(map[interface{}]*list.Element)
+	c.ghost.Init()
+	c.ghostItems = make(map[interface{}]*list.Element)
+	c.currentCapacityInBytes = 0
+	c.entriesSizeInBytes = 0
+}
+
+// AddSized adds a value to the cache. Returns true if an eviction occurred.
+func (c *twoQueueLRU) AddSized(key, value interface{}, sizeInBytes int64) bool {
+	if sizeInBytes < 0 {
+		log.Error("2Q cache add error",
+			"key", fmt.Sprintf("%v", key),
+			"value", fmt.Sprintf("%v", value),
+			"error", common.ErrNegativeSizeInBytes,
+		)
+
+		return false
+	}
+
+	c.lock.Lock()
+	c.addSized(key, value, sizeInBytes)
+	evicted := c.evictIfNeeded()
+	c.lock.Unlock()
+
+	c.notifyEvicted(evicted)
+
+	return len(evicted) > 0
+}
+
+// AddSizedIfMissing checks if a key is in the cache without updating the
+// recent-ness or deleting it for being stale, and if not, adds the value.
+// Returns whether found and whether an eviction occurred.
+func (c *twoQueueLRU) AddSizedIfMissing(key, value interface{}, sizeInBytes int64) (bool, bool) {
+	if sizeInBytes < 0 {
+		log.Error("2Q cache contains or add error",
+			"key", fmt.Sprintf("%v", key),
+			"value", fmt.Sprintf("%v", value),
+			"error", common.ErrNegativeSizeInBytes,
+		)
+
+		return false, false
+	}
+
+	c.lock.Lock()
+	_, ok := c.items[key]
+	if ok {
+		c.lock.Unlock()
+		return true, false
+	}
+
+	c.addSized(key, value, sizeInBytes)
+	evicted := c.evictIfNeeded()
+	c.lock.Unlock()
+
+	c.notifyEvicted(evicted)
+
+	return false, len(evicted) > 0
+}
+
+func (c *twoQueueLRU) addSized(key interface{}, value interface{}, sizeInBytes int64) {
+	element, ok := c.items[key]
+	if ok {
+		// an update counts as a second use
+		ent := c.removeElement(element)
+		ent.value = value
+		ent.size = sizeInBytes
+		ent.entrySize = int64(types.ComputeAnyKeyEntrySizeInBytes(key, value, int(sizeInBytes)))
+		c.pushFrequent(ent)
+		return
+	}
+
+	ent := &entry{
+		key:       key,
+		value:     value,
+		size:      sizeInBytes,
+		entrySize: int64(types.ComputeAnyKeyEntrySizeInBytes(key, value, int(sizeInBytes))),
+	}
+
+	ghostElement, wasEvicted := c.ghostItems[key]
+	if wasEvicted {
+		c.ghost.Remove(ghostElement)
+		delete(c.ghostItems, key)
+		c.pushFrequent(ent)
+		return
+	}
+
+	c.pushRecent(ent)
+}
+
+func (c *twoQueueLRU) pushRecent(ent *entry) {
+	ent.isFrequent = false
+	c.items[ent.key] = c.recent.PushFront(ent)
+	c.currentCapacityInBytes += ent.size
+	c.entriesSizeInBytes += ent.entrySize
+}
+
+func (c *twoQueueLRU) pushFrequent(ent *entry) {
+	ent.isFrequent = true
+	c.items[ent.key] = c.frequent.PushFront(ent)
+	c.currentCapacityInBytes += ent.size
+	c.entriesSizeInBytes += ent.entrySize
+}
+
+// Get looks up a key's value from the cache. An item seen once is promoted to the frequently used items.
+func (c *twoQueueLRU) Get(key interface{}) (interface{}, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	ent := element.Value.(*entry)
+	if ent.isFrequent {
+		c.frequent.MoveToFront(element)
+		return ent.value, true
+	}
+
+	c.removeElement(element)
+	c.pushFrequent(ent)
+
+	return ent.value, true
+}
+
+// Contains checks if a key is in the cache, without updating the recent-ness
+// or deleting it for being stale.
+func (c *twoQueueLRU) Contains(key interface{}) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	_, ok := c.items[key]
+
+	return ok
+}
+
+// Peek returns the key value (or undefined if not found) without updating
+// the "recently used"-ness of the key.
+func (c *twoQueueLRU) Peek(key interface{}) (interface{}, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	return element.Value.(*entry).value, true
+}
+
+// Remove removes the provided key from the cache, returning if the key was contained.
+// The key is forgotten, so that adding it again is handled as a first use.
+func (c *twoQueueLRU) Remove(key interface{}) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	ghostElement, ok := c.ghostItems[key]
+	if ok {
+		c.ghost.Remove(ghostElement)
+		delete(c.ghostItems, key)
+	}
+
+	element, ok := c.items[key]
+	if !ok {
+		return false
+	}
+
+	c.removeElement(element)
+
+	return true
+}
+
+// Keys returns a slice of the keys in the cache, from the oldest to the newest frequently used ones,
+// followed by the keys seen once, from the oldest to the newest.
+func (c *twoQueueLRU) Keys() []interface{} {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	keys := make([]interface{}, 0, len(c.items))
+	for element := c.frequent.Back(); element != nil; element = element.Prev() {
+		keys = append(keys, element.Value.(*entry).key)
+	}
+	for element := c.recent.Back(); element != nil; element = element.Prev() {
+		keys = append(keys, element.Value.(*entry).key)
+	}
+
+	return keys
+}
+
+// Len returns the number of items in the cache.
+func (c *twoQueueLRU) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return len(c.items)
+}
+
+// SizeInBytesContained returns the size in bytes of all contained elements. The size is accounted
+// even if the cache is bounded only by the number of items.
+func (c *twoQueueLRU) SizeInBytesContained() uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return uint64(c.currentCapacityInBytes)
+}
+
+// EntriesSizeInBytes returns the size in bytes of all contained entries, including the keys and the overhead of
+// the entries
+func (c *twoQueueLRU) EntriesSizeInBytes() uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return uint64(c.entriesSizeInBytes)
+}
+
+func (c *twoQueueLRU) removeElement(element *list.Element) *entry {
+	ent := element.Value.(*entry)
+	if ent.isFrequent {
+		c.frequent.Remove(element)
+	} else {
+		c.recent.Remove(element)
+	}
+	delete(c.items, ent.key)
+	c.currentCapacityInBytes -= ent.size
+	c.entriesSizeInBytes -= ent.entrySize
+
+	return ent
+}
+
+func (c *twoQueueLRU) shouldEvict() bool {
+	if len(c.items) == 1 {
+		// keep at least one element, no matter how large it is
+		return false
+	}
+
+	return len(c.items) > c.size || (c.isSized() && c.currentCapacityInBytes > c.maxCapacityInBytes)
+}
+
+// evictIfNeeded evicts the items seen once while they exceed their share of the cache, then the least
+// recently used frequent items. It returns the evicted entries, so that the callback is called outside the lock.
+func (c *twoQueueLRU) evictIfNeeded() []*entry {
+	var evicted []*entry
+	for c.shouldEvict() {
+		evicted = append(evicted, c.evictOne())
+	}
+
+	return evicted
+}
+
+func (c *twoQueueLRU) evictOne() *entry {
+	recentTarget := int(float64(c.size) * recentRatio)
+	shouldEvictRecent := c.recent.Len() > recentTarget || c.frequent.Len() == 0
+	if c.recent.Len() > 0 && shouldEvictRecent {
+		ent := c.removeElement(c.recent.Back())
+		c.rememberEvicted(ent.key)
+
+		return ent
+	}
+
+	return c.removeElement(c.frequent.Back())
+}
+
+func (c *twoQueueLRU) rememberEvicted(key interface{}) {
+	c.ghostItems[key] = c.ghost.PushFront(key)
+	c.trimGhost()
+}
+
+func (c *twoQueueLRU) trimGhost() {
+	maxGhostLen := int(float64(c.size) * ghostRatio)
+	for c.ghost.Len() > maxGhostLen {
+		oldest := c.ghost.Back()
+		c.ghost.Remove(oldest)
+		delete(c.ghostItems, oldest.Value)
+	}
+}
+
+func (c *twoQueueLRU) notifyEvicted(evicted []*entry) {
+	if c.onEvicted == nil {
+		return
+	}
+
+	for _, ent := range evicted {
+		c.onEvicted(ent.key, ent.value)
+	}
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (c *twoQueueLRU) IsInterfaceNil() bool {
+	return c == nil
+}
@@ -0,0 +1,317 @@
+package twoqueue
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createDefaultCache() *twoQueueLRU {
+	cache, _ := NewTwoQueueLRU(100, nil)
+	return cache
+}
+
+func keyAt(idx int) string {
+	return fmt.Sprintf("key%d", idx)
+}
+
+//------- constructors
+
+func TestNewTwoQueueLRU(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid size should error", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := NewTwoQueueLRU(0, nil)
+		assert.True(t, check.IfNil(cache))
+		assert.Equal(t, common.ErrCacheSizeInvalid, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := NewTwoQueueLRU(10, nil)
+		assert.False(t, check.IfNil(cache))
+		assert.Nil(t, err)
+		assert.False(t, cache.isSized())
+	})
+}
+
+func TestNewSizedTwoQueueLRU(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid size should error", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := NewSizedTwoQueueLRU(0, 10, nil)
+		assert.True(t, check.IfNil(cache))
+		assert.Equal(t, common.ErrCacheSizeInvalid, err)
+	})
+	t.Run("invalid capacity should error", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := NewSizedTwoQueueLRU(10, 0, nil)
+		assert.True(t, check.IfNil(cache))
+		assert.Equal(t, common.ErrCacheCapacityInvalid, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := NewSizedTwoQueueLRU(10, 100, nil)
+		assert.False(t, check.IfNil(cache))
+		assert.Nil(t, err)
+		assert.True(t, cache.isSized())
+	})
+}
+
+//------- AddSized
+
+func TestTwoQueueLRU_AddSizedNegativeSizeInBytesShouldReturn(t *testing.T) {
+	t.Parallel()
+
+	c, _ := NewSizedTwoQueueLRU(10, 100, nil)
+	evicted := c.AddSized("key", "value", -1)
+	assert.False(t, evicted)
+	assert.Zero(t, c.Len())
+
+	found, evicted := c.AddSizedIfMissing("key", "value", -1)
+	assert.False(t, found)
+	assert.False(t, evicted)
+	assert.Zero(t, c.Len())
+}
+
+func TestTwoQueueLRU_AddSizedShouldTrackBytes(t *testing.T) {
+	t.Parallel()
+
+	// the size is accounted, but not bounded, for a cache bounded only by the number of items
+	c := createDefaultCache()
+	c.AddSized("key", "value", 10)
+	assert.Equal(t, uint64(10), c.SizeInBytesContained())
+	c.AddSized("key", "value", 1000000)
+	assert.Equal(t, uint64(1000000), c.SizeInBytesContained())
+	assert.Equal(t, 1, c.Len())
+
+	sized, _ := NewSizedTwoQueueLRU(10, 100, nil)
+	sized.AddSized("key1", "value", 10)
+	sized.AddSized("key2", "value", 20)
+	sized.AddSized("key1", "value", 15)
+	assert.Equal(t, uint64(35), sized.SizeInBytesContained())
+
+	sized.Remove("key2")
+	assert.Equal(t, uint64(15), sized.SizeInBytesContained())
+}
+
+func TestTwoQueueLRU_AddSizedShouldEvictWhenCapacityExceeded(t *testing.T) {
+	t.Parallel()
+
+	evictedKeys := make([]interface{}, 0)
+	onEvicted := func(key interface{}, _ interface{}) {
+		evictedKeys = append(evictedKeys, key)
+	}
+	c, _ := NewSizedTwoQueueLRU(10, 100, onEvicted)
+
+	assert.False(t, c.AddSized("key1", "value", 40))
+	assert.False(t, c.AddSized("key2", "value", 40))
+	assert.True(t, c.AddSized("key3", "value", 40))
+	assert.Equal(t, []interface{}{"key1"}, evictedKeys)
+	assert.Equal(t, uint64(80), c.SizeInBytesContained())
+
+	// an item larger than the capacity is kept alone
+	assert.True(t, c.AddSized("key4", "value", 500))
+	assert.Equal(t, []interface{}{"key4"}, c.Keys())
+}
+
+func TestTwoQueueLRU_AddSizedIfMissing(t *testing.T) {
+	t.Parallel()
+
+	c, _ := NewTwoQueueLRU(2, nil)
+	found, evicted := c.AddSizedIfMissing("key1", "value1", 0)
+	assert.False(t, found)
+	assert.False(t, evicted)
+
+	found, evicted = c.AddSizedIfMissing("key1", "value2", 0)
+	assert.True(t, found)
+	assert.False(t, evicted)
+	value, _ := c.Peek("key1")
+	assert.Equal(t, "value1", value)
+
+	_, _ = c.AddSizedIfMissing("key2", "value2", 0)
+	found, evicted = c.AddSizedIfMissing("key3", "value3", 0)
+	assert.False(t, found)
+	assert.True(t, evicted)
+	assert.Equal(t, 2, c.Len())
+}
+
+//------- scan resistance
+
+func TestTwoQueueLRU_ScanShouldNotFlushFrequentItems(t *testing.T) {
+	t.Parallel()
+
+	c := createDefaultCache()
+	for i := 0; i < 50; i++ {
+		c.AddSized(keyAt(i), i, 0)
+		_, _ = c.Get(keyAt(i))
+	}
+
+	// a scan through many more keys than the cache can hold
+	for i := 1000; i < 2000; i++ {
+		c.AddSized(keyAt(i), i, 0)
+	}
+
+	for i := 0; i < 50; i++ {
+		assert.True(t, c.Contains(keyAt(i)), "frequent key %s was flushed", keyAt(i))
+	}
+	assert.Equal(t, 100, c.Len())
+	assert.True(t, c.Contains(keyAt(1999)))
+}
+
+func TestTwoQueueLRU_ReAddedEvictedKeyShouldBecomeFrequent(t *testing.T) {
+	t.Parallel()
+
+	c, _ := NewTwoQueueLRU(4, nil)
+	for i := 0; i < 5; i++ {
+		c.AddSized(keyAt(i), i, 0)
+	}
+	require.False(t, c.Contains(keyAt(0)))
+
+	c.AddSized(keyAt(0), 0, 0)
+	for i := 10; i < 20; i++ {
+		c.AddSized(keyAt(i), i, 0)
+	}
+	assert.True(t, c.Contains(keyAt(0)))
+}
+
+func TestTwoQueueLRU_RemovedKeyShouldBeForgotten(t *testing.T) {
+	t.Parallel()
+
+	c, _ := NewTwoQueueLRU(4, nil)
+	for i := 0; i < 5; i++ {
+		c.AddSized(keyAt(i), i, 0)
+	}
+
+	assert.False(t, c.Remove(keyAt(0)))
+	c.AddSized(keyAt(0), 0, 0)
+	for i := 10; i < 20; i++ {
+		c.AddSized(keyAt(i), i, 0)
+	}
+	assert.False(t, c.Contains(keyAt(0)))
+}
+
+//------- Get, Peek, Keys
+
+func TestTwoQueueLRU_GetShouldPromoteAndPeekShouldNot(t *testing.T) {
+	t.Parallel()
+
+	c := createDefaultCache()
+	c.AddSized("key1", "value1", 0)
+	c.AddSized("key2", "value2", 0)
+	c.AddSized("key3", "value3", 0)
+
+	value, ok := c.Peek("key1")
+	assert.True(t, ok)
+	assert.Equal(t, "value1", value)
+	assert.Equal(t, []interface{}{"key1", "key2", "key3"}, c.Keys())
+
+	value, ok = c.Get("key2")
+	assert.True(t, ok)
+	assert.Equal(t, "value2", value)
+	assert.Equal(t, []interface{}{"key2", "key1", "key3"}, c.Keys())
+
+	_, ok = c.Get("missing")
+	assert.False(t, ok)
+	_, ok = c.Peek("missing")
+	assert.False(t, ok)
+}
+
+func TestTwoQueueLRU_Purge(t *testing.T) {
+	t.Parallel()
+
+	c, _ := NewSizedTwoQueueLRU(2, 100, nil)
+	for i := 0; i < 3; i++ {
+		c.AddSized(keyAt(i), i, 10)
+	}
+	c.Purge()
+
+	assert.Zero(t, c.Len())
+	assert.Zero(t, c.SizeInBytesContained())
+	assert.Empty(t, c.Keys())
+	assert.Zero(t, c.ghost.Len())
+}
+
+//------- Resize
+
+func TestTwoQueueLRU_Resize(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid values should error", func(t *testing.T) {
+		t.Parallel()
+
+		c, _ := NewSizedTwoQueueLRU(10, 100, nil)
+		assert.Equal(t, common.ErrCacheSizeInvalid, c.Resize(0, 100))
+		assert.Equal(t, common.ErrCacheCapacityInvalid, c.Resize(10, 0))
+	})
+	t.Run("count cache should ignore the capacity", func(t *testing.T) {
+		t.Parallel()
+
+		c := createDefaultCache()
+		assert.Nil(t, c.Resize(10, 0))
+		assert.False(t, c.isSized())
+	})
+	t.Run("shrinking should evict and notify", func(t *testing.T) {
+		t.Parallel()
+
+		numEvicted := 0
+		c, _ := NewSizedTwoQueueLRU(10, 100, func(_ interface{}, _ interface{}) {
+			numEvicted++
+		})
+		for i := 0; i < 10; i++ {
+			c.AddSized(keyAt(i), i, 10)
+		}
+
+		err := c.Resize(8, 50)
+		assert.Nil(t, err)
+		assert.Equal(t, 5, c.Len())
+		assert.Equal(t, uint64(50), c.SizeInBytesContained())
+		assert.Equal(t, 5, numEvicted)
+		assert.True(t, c.ghost.Len() <= 4)
+	})
+}
+
+func TestTwoQueueLRU_ConcurrentOperations(t *testing.T) {
+	t.Parallel()
+
+	c, _ := NewSizedTwoQueueLRU(100, 1000, func(_ interface{}, _ interface{}) {})
+	numOperations := 1000
+	wg := sync.WaitGroup{}
+	wg.Add(numOperations)
+	for i := 0; i < numOperations; i++ {
+		go func(idx int) {
+			defer wg.Done()
+
+			key := keyAt(idx % 150)
+			switch idx % 6 {
+			case 0:
+				c.AddSized(key, idx, 10)
+			case 1:
+				_, _ = c.AddSizedIfMissing(key, idx, 10)
+			case 2:
+				_, _ = c.Get(key)
+			case 3:
+				c.Remove(key)
+			case 4:
+				_ = c.Keys()
+			case 5:
+				_ = c.Resize(idx%100+1, 1000)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.True(t, c.Len() <= 100)
+	assert.True(t, c.SizeInBytesContained() <= 1000)
+}
@@ -0,0 +1,283 @@
+package memorybudget
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+var _ types.MemoryBudgetHandler = (*coordinator)(nil)
+
+var log = logger.GetOrCreate("storage/memorybudget")
+
+const maxPercent = 100
+
+// shrinkOrder holds the priorities in the order the caches are shrunk
+var shrinkOrder = []common.CachePriority{
+	common.LowCachePriority,
+	common.NormalCachePriority,
+	common.HighCachePriority,
+}
+
+type budgetedCache struct {
+	name                 string
+	cache                types.ResizableCacher
+	priority             common.CachePriority
+	requestedSizeInBytes uint64
+	grantedSizeInBytes   uint64
+}
+
+// coordinator splits a process-wide memory budget between the registered caches. When the sum of the sizes
+// requested by the caches exceeds the budget, the caches are shrunk in the order of their priority,
+// but never below the configured minimum percent of their requested size.
+type coordinator struct {
+	mut            sync.Mutex
+	maxSizeInBytes uint64
+	minSizePercent uint32
+	caches         map[string]*budgetedCache
+}
+
+// NewCoordinator creates a new memory budget coordinator. A zero MaxSizeInBytes means unlimited budget.
+func NewCoordinator(config common.MemoryBudgetConfig) (*coordinator, error) {
+	if config.MinSizePercent == 0 || config.MinSizePercent > maxPercent {
+		return nil, common.ErrInvalidMinSizePercent
+	}
+
+	return &coordinator{
+		maxSizeInBytes: config.MaxSizeInBytes,
+		minSizePercent: config.MinSizePercent,
+		caches:         make(map[string]*budgetedCache),
+	}, nil
+}
+
+// RegisterCache adds the cache in the budget. The cache under an existing name has to be unregistered first.
+func (c *coordinator) RegisterCache(name string, cache types.ResizableCacher, sizeInBytes uint64, priority common.CachePriority) error {
+	if len(name) == 0 {
+		return common.ErrEmptyUnitName
+	}
+	if check.IfNil(cache) {
+		return common.ErrNilCacher
+	}
+	priority, err := checkPriority(priority)
+	if err != nil {
+		return err
+	}
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	_, exists := c.caches[name]
+	if exists {
+		return common.ErrCacheAlreadyRegistered
+	}
+
+	c.caches[name] = &budgetedCache{
+		name:                 name,
+		cache:                cache,
+		priority:             priority,
+		requestedSizeInBytes: sizeInBytes,
+		grantedSizeInBytes:   sizeInBytes,
+	}
+	c.rebalanceNoLock()
+
+	return nil
+}
+
+func checkPriority(priority common.CachePriority) (common.CachePriority, error) {
+	switch priority {
+	case "":
+		return common.NormalCachePriority, nil
+	case common.LowCachePriority, common.NormalCachePriority, common.HighCachePriority:
+		return priority, nil
+	default:
+		return "", common.ErrNotSupportedCachePriority
+	}
+}
+
+// UnregisterCache removes the cache from the budget, allowing the shrunk caches to grow back
+func (c *coordinator) UnregisterCache(name string) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	_, exists := c.caches[name]
+	if !exists {
+		return
+	}
+
+	delete(c.caches, name)
+	c.rebalanceNoLock()
+}
+
+// SetMaxSizeInBytes changes the budget, shrinking or growing back the caches as needed.
+// A zero value means unlimited budget.
+func (c *coordinator) SetMaxSizeInBytes(maxSizeInBytes uint64) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	c.maxSizeInBytes = maxSizeInBytes
+	c.rebalanceNoLock()
+}
+
+// Report returns the current state of the budget
+func (c *coordinator) Report() types.MemoryBudgetReport {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	report := types.MemoryBudgetReport{
+		MaxSizeInBytes: c.maxSizeInBytes,
+		Caches:         make([]types.CacheBudget, 0, len(c.caches)),
+	}
+	for _, bc := range c.sortedCachesNoLock() {
+		report.RequestedSizeInBytes += bc.requestedSizeInBytes
+		report.GrantedSizeInBytes += bc.grantedSizeInBytes
+		report.Caches = append(report.Caches, types.CacheBudget{
+			Name:                 bc.name,
+			Priority:             bc.priority,
+			RequestedSizeInBytes: bc.requestedSizeInBytes,
+			GrantedSizeInBytes:   bc.grantedSizeInBytes,
+			UsedSizeInBytes:      bc.cache.SizeInBytesContained(),
+		})
+	}
+	report.IsOverCommitted = c.maxSizeInBytes > 0 && report.GrantedSizeInBytes > c.maxSizeInBytes
+
+	return report
+}
+
+// CheckOverCommitment returns an error if the caches do not fit in the budget even after shrinking them.
+// It should be called after all the caches have been created.
+func (c *coordinator) CheckOverCommitment() error {
+	report := c.Report()
+	if !report.IsOverCommitted {
+		log.Debug("memory budget", "max", core.ConvertBytes(report.MaxSizeInBytes),
+			"requested", core.ConvertBytes(report.RequestedSizeInBytes),
+			"granted", core.ConvertBytes(report.GrantedSizeInBytes))
+		return nil
+	}
+
+	log.Warn("memory budget is over-committed", "max", core.ConvertBytes(report.MaxSizeInBytes),
+		"requested", core.ConvertBytes(report.RequestedSizeInBytes),
+		"granted", core.ConvertBytes(report.GrantedSizeInBytes))
+
+	return fmt.Errorf("%w: granted %d bytes, max %d bytes",
+		common.ErrMemoryBudgetOverCommitted, report.GrantedSizeInBytes, report.MaxSizeInBytes)
+}
+
+func (c *coordinator) sortedCachesNoLock() []*budgetedCache {
+	caches := make([]*budgetedCache, 0, len(c.caches))
+	for _, bc := range c.caches {
+		caches = append(caches, bc)
+	}
+
+	sort.Slice(caches, func(i, j int) bool {
+		return caches[i].name < caches[j].name
+	})
+
+	return caches
+}
+
+// rebalanceNoLock recomputes the granted sizes starting from the requested ones and resizes the caches whose
+// granted size changed
+func (c *coordinator) rebalanceNoLock() {
+	caches := c.sortedCachesNoLock()
+	granted := make(map[string]uint64, len(caches))
+	totalRequested := uint64(0)
+	for _, bc := range caches {
+		granted[bc.name] = bc.requestedSizeInBytes
+		totalRequested += bc.requestedSizeInBytes
+	}
+
+	if c.maxSizeInBytes > 0 && totalRequested > c.maxSizeInBytes {
+		deficit := totalRequested - c.maxSizeInBytes
+		for _, priority := range shrinkOrder {
+			if deficit == 0 {
+				break
+			}
+
+			deficit = c.shrinkPriorityGroup(filterByPriority(caches, priority), granted, deficit)
+		}
+	}
+
+	for _, bc := range caches {
+		c.applyGrantedSize(bc, granted[bc.name])
+	}
+}
+
+func filterByPriority(caches []*budgetedCache, priority common.CachePriority) []*budgetedCache {
+	filtered := make([]*budgetedCache, 0, len(caches))
+	for _, bc := range caches {
+		if bc.priority == priority {
+			filtered = append(filtered, bc)
+		}
+	}
+
+	return filtered
+}
+
+// shrinkPriorityGroup shrinks the caches proportionally with their reducible size and returns the remaining deficit
+func (c *coordinator) shrinkPriorityGroup(caches []*budgetedCache, granted map[string]uint64, deficit uint64) uint64 {
+	totalReducible := uint64(0)
+	for _, bc := range caches {
+		totalReducible += c.reducibleSize(bc)
+	}
+	if totalReducible == 0 {
+		return deficit
+	}
+
+	toShrink := deficit
+	if toShrink > totalReducible {
+		toShrink = totalReducible
+	}
+
+	shrunk := uint64(0)
+	for i, bc := range caches {
+		reducible := c.reducibleSize(bc)
+		share := uint64(float64(toShrink) * float64(reducible) / float64(totalReducible))
+		isLast := i == len(caches)-1
+		if isLast {
+			// the rounding leftovers go to the last cache
+			share = toShrink - shrunk
+		}
+		if share > reducible {
+			share = reducible
+		}
+
+		granted[bc.name] -= share
+		shrunk += share
+	}
+
+	return deficit - shrunk
+}
+
+func (c *coordinator) reducibleSize(bc *budgetedCache) uint64 {
+	minSize := bc.requestedSizeInBytes * uint64(c.minSizePercent) / maxPercent
+
+	return bc.requestedSizeInBytes - minSize
+}
+
+func (c *coordinator) applyGrantedSize(bc *budgetedCache, grantedSizeInBytes uint64) {
+	if bc.grantedSizeInBytes == grantedSizeInBytes {
+		return
+	}
+
+	err := bc.cache.Resize(bc.cache.MaxSize(), int64(grantedSizeInBytes))
+	if err != nil {
+		log.Warn("coordinator: could not resize cache", "name", bc.name,
+			"size", core.ConvertBytes(grantedSizeInBytes), "error", err)
+		return
+	}
+
+	log.Debug("coordinator: resized cache", "name", bc.name, "priority", bc.priority,
+		"requested", core.ConvertBytes(bc.requestedSizeInBytes), "granted", core.ConvertBytes(grantedSizeInBytes))
+	bc.grantedSizeInBytes = grantedSizeInBytes
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (c *coordinator) IsInterfaceNil() bool {
+	return c == nil
+}
@@ -0,0 +1,162 @@
+package memorybudget_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/lrucache"
+	"github.com/multiversx/mx-chain-storage-go/memorybudget"
+	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createFilledCache(tb testing.TB, numItems int, itemSize int) types.ResizableCacher {
+	cache, err := lrucache.NewCacheWithSizeInBytes(1000, int64(numItems*itemSize))
+	require.Nil(tb, err)
+
+	for i := 0; i < numItems; i++ {
+		_ = cache.Put([]byte(fmt.Sprintf("key%d", i)), i, itemSize)
+	}
+
+	return cache
+}
+
+func getGrantedSize(report types.MemoryBudgetReport, name string) uint64 {
+	for _, cache := range report.Caches {
+		if cache.Name == name {
+			return cache.GrantedSizeInBytes
+		}
+	}
+
+	return 0
+}
+
+func TestNewCoordinator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid min size percent should error", func(t *testing.T) {
+		t.Parallel()
+
+		c, err := memorybudget.NewCoordinator(common.MemoryBudgetConfig{MinSizePercent: 0})
+		assert.True(t, check.IfNil(c))
+		assert.Equal(t, common.ErrInvalidMinSizePercent, err)
+
+		c, err = memorybudget.NewCoordinator(common.MemoryBudgetConfig{MinSizePercent: 101})
+		assert.True(t, check.IfNil(c))
+		assert.Equal(t, common.ErrInvalidMinSizePercent, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		c, err := memorybudget.NewCoordinator(common.MemoryBudgetConfig{MaxSizeInBytes: 1000, MinSizePercent: 10})
+		assert.False(t, check.IfNil(c))
+		assert.Nil(t, err)
+	})
+}
+
+func TestCoordinator_RegisterCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid arguments should error", func(t *testing.T) {
+		t.Parallel()
+
+		c, _ := memorybudget.NewCoordinator(common.MemoryBudgetConfig{MinSizePercent: 10})
+		cache := createFilledCache(t, 1, 10)
+
+		err := c.RegisterCache("", cache, 10, common.LowCachePriority)
+		assert.Equal(t, common.ErrEmptyUnitName, err)
+		err = c.RegisterCache("cache", nil, 10, common.LowCachePriority)
+		assert.Equal(t, common.ErrNilCacher, err)
+		err = c.RegisterCache("cache", cache, 10, "unknown")
+		assert.Equal(t, common.ErrNotSupportedCachePriority, err)
+	})
+	t.Run("empty priority should be handled as normal", func(t *testing.T) {
+		t.Parallel()
+
+		c, _ := memorybudget.NewCoordinator(common.MemoryBudgetConfig{MinSizePercent: 10})
+		err := c.RegisterCache("cache", createFilledCache(t, 1, 10), 10, "")
+		require.Nil(t, err)
+		assert.Equal(t, common.NormalCachePriority, c.Report().Caches[0].Priority)
+	})
+	t.Run("same name should error", func(t *testing.T) {
+		t.Parallel()
+
+		c, _ := memorybudget.NewCoordinator(common.MemoryBudgetConfig{MinSizePercent: 10})
+		_ = c.RegisterCache("cache", createFilledCache(t, 1, 10), 10, common.LowCachePriority)
+		err := c.RegisterCache("cache", createFilledCache(t, 1, 10), 20, common.HighCachePriority)
+		assert.Equal(t, common.ErrCacheAlreadyRegistered, err)
+		assert.Equal(t, uint64(10), c.Report().Caches[0].RequestedSizeInBytes)
+
+		c.UnregisterCache("cache")
+		err = c.RegisterCache("cache", createFilledCache(t, 1, 10), 20, common.HighCachePriority)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(20), c.Report().Caches[0].RequestedSizeInBytes)
+	})
+}
+
+func TestCoordinator_ShouldShrinkLowPriorityCachesFirst(t *testing.T) {
+	t.Parallel()
+
+	c, _ := memorybudget.NewCoordinator(common.MemoryBudgetConfig{MaxSizeInBytes: 1000, MinSizePercent: 10})
+	lowCache := createFilledCache(t, 6, 100)
+	normalCache := createFilledCache(t, 6, 100)
+
+	_ = c.RegisterCache("low", lowCache, 600, common.LowCachePriority)
+	_ = c.RegisterCache("normal", normalCache, 600, common.NormalCachePriority)
+
+	report := c.Report()
+	assert.Equal(t, uint64(400), getGrantedSize(report, "low"))
+	assert.Equal(t, uint64(600), getGrantedSize(report, "normal"))
+	assert.Equal(t, uint64(1200), report.RequestedSizeInBytes)
+	assert.Equal(t, uint64(1000), report.GrantedSizeInBytes)
+	assert.False(t, report.IsOverCommitted)
+	assert.Nil(t, c.CheckOverCommitment())
+
+	// the shrunk cache evicted its oldest items
+	assert.Equal(t, 4, lowCache.Len())
+	assert.Equal(t, uint64(4*(100+len("key0")+types.EntryOverheadInBytes)), lowCache.SizeInBytesContained())
+	assert.Equal(t, 6, normalCache.Len())
+
+	// removing a cache frees budget, so the shrunk cache grows back
+	c.UnregisterCache("normal")
+	assert.Equal(t, uint64(600), getGrantedSize(c.Report(), "low"))
+}
+
+func TestCoordinator_ShouldShrinkSamePriorityCachesProportionally(t *testing.T) {
+	t.Parallel()
+
+	c, _ := memorybudget.NewCoordinator(common.MemoryBudgetConfig{MaxSizeInBytes: 900, MinSizePercent: 10})
+	_ = c.RegisterCache("a", createFilledCache(t, 2, 100), 200, common.NormalCachePriority)
+	_ = c.RegisterCache("b", createFilledCache(t, 10, 100), 1000, common.NormalCachePriority)
+
+	report := c.Report()
+	assert.Equal(t, uint64(150), getGrantedSize(report, "a"))
+	assert.Equal(t, uint64(750), getGrantedSize(report, "b"))
+}
+
+func TestCoordinator_OverCommitmentShouldBeReported(t *testing.T) {
+	t.Parallel()
+
+	c, _ := memorybudget.NewCoordinator(common.MemoryBudgetConfig{MaxSizeInBytes: 500, MinSizePercent: 50})
+	_ = c.RegisterCache("low", createFilledCache(t, 6, 100), 600, common.LowCachePriority)
+	_ = c.RegisterCache("high", createFilledCache(t, 6, 100), 600, common.HighCachePriority)
+
+	report := c.Report()
+	assert.Equal(t, uint64(300), getGrantedSize(report, "low"))
+	assert.Equal(t, uint64(300), getGrantedSize(report, "high"))
+	assert.True(t, report.IsOverCommitted)
+
+	err := c.CheckOverCommitment()
+	assert.True(t, errors.Is(err, common.ErrMemoryBudgetOverCommitted))
+
+	// lifting the limit grows back all the caches
+	c.SetMaxSizeInBytes(0)
+	report = c.Report()
+	assert.Equal(t, uint64(600), getGrantedSize(report, "low"))
+	assert.Equal(t, uint64(600), getGrantedSize(report, "high"))
+	assert.False(t, report.IsOverCommitted)
+}
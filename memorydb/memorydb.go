@@ -2,14 +2,14 @@ package memorydb
 
 import (
 	"encoding/base64"
-	"errors"
 	"fmt"
 	"sync"
 
+	"github.com/multiversx/mx-chain-storage-go/common"
 	"github.com/multiversx/mx-chain-storage-go/types"
 )
 
-var _ types.Persister = (*DB)(nil)
+var _ types.PersisterWithMultiGet = (*DB)(nil)
 
 // DB represents the memory database storage. It holds a map of key value pairs
 // and a mutex to handle concurrent accesses to the map
@@ -44,12 +44,34 @@ func (s *DB) Get(key []byte) ([]byte, error) {
 	val, ok := s.db[string(key)]
 
 	if !ok {
-		return nil, fmt.Errorf("key: %s not found", base64.StdEncoding.EncodeToString(key))
+		return nil, fmt.Errorf("%w, key: %s", common.ErrKeyNotFound, base64.StdEncoding.EncodeToString(key))
 	}
 
 	return val, nil
 }
 
+// MultiGet returns the results for all the provided keys, read under the same lock acquisition.
+// The results are in the same order as the provided keys.
+func (s *DB) MultiGet(keys [][]byte) []types.KeyValueResult {
+	s.mutx.RLock()
+	defer s.mutx.RUnlock()
+
+	results := make([]types.KeyValueResult, len(keys))
+	for i, key := range keys {
+		results[i].Key = key
+
+		val, ok := s.db[string(key)]
+		if !ok {
+			results[i].Err = common.ErrKeyNotFound
+			continue
+		}
+
+		results[i].Value = val
+	}
+
+	return results
+}
+
 // Has returns true if the given key is present in the persistence medium, false otherwise
 func (s *DB) Has(key []byte) error {
 	s.mutx.RLock()
@@ -58,7 +80,7 @@ func (s *DB) Has(key []byte) error {
 	_, ok := s.db[string(key)]
 
 	if !ok {
-		return errors.New("key not found")
+		return common.ErrKeyNotFound
 	}
 	return nil
 }
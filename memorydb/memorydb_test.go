@@ -3,6 +3,7 @@ package memorydb_test
 import (
 	"testing"
 
+	"github.com/multiversx/mx-chain-storage-go/common"
 	"github.com/multiversx/mx-chain-storage-go/memorydb"
 	"github.com/stretchr/testify/assert"
 )
@@ -120,3 +121,18 @@ func Test_RangeKeys(t *testing.T) {
 
 	assert.Equal(t, keysVals, recovered)
 }
+
+func TestMultiGet(t *testing.T) {
+	mdb := memorydb.New()
+	_ = mdb.Put([]byte("key1"), []byte("value1"))
+	_ = mdb.Put([]byte("key2"), []byte("value2"))
+
+	results := mdb.MultiGet([][]byte{[]byte("key2"), []byte("missing"), []byte("key1")})
+	assert.Equal(t, 3, len(results))
+	assert.Equal(t, []byte("value2"), results[0].Value)
+	assert.Nil(t, results[0].Err)
+	assert.Equal(t, []byte("missing"), results[1].Key)
+	assert.Equal(t, common.ErrKeyNotFound, results[1].Err)
+	assert.Equal(t, []byte("value1"), results[2].Value)
+	assert.Nil(t, results[2].Err)
+}
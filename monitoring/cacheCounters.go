@@ -0,0 +1,43 @@
+package monitoring
+
+import (
+	"github.com/multiversx/mx-chain-core-go/core/atomic"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+// CacheCounters counts the hits, misses and evictions of a cache. The zero value is ready to use.
+type CacheCounters struct {
+	numHits      atomic.Counter
+	numMisses    atomic.Counter
+	numEvictions atomic.Counter
+}
+
+// RecordLookup accounts a hit or a miss
+func (counters *CacheCounters) RecordLookup(found bool) {
+	if found {
+		counters.numHits.Increment()
+		return
+	}
+
+	counters.numMisses.Increment()
+}
+
+// RecordEvictions accounts the provided number of evicted items
+func (counters *CacheCounters) RecordEvictions(numEvicted int) {
+	if numEvicted <= 0 {
+		return
+	}
+
+	counters.numEvictions.Add(int64(numEvicted))
+}
+
+// Stats returns the cache statistics, completed with the provided number of items and size
+func (counters *CacheCounters) Stats(numItems int, sizeInBytes uint64) types.CacheStats {
+	return types.CacheStats{
+		NumItems:     numItems,
+		SizeInBytes:  sizeInBytes,
+		NumHits:      counters.numHits.GetUint64(),
+		NumMisses:    counters.numMisses.GetUint64(),
+		NumEvictions: counters.numEvictions.GetUint64(),
+	}
+}
@@ -0,0 +1,51 @@
+package monitoring
+
+import (
+	"errors"
+
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+const (
+	putOperation                    = "Put"
+	putInEpochOperation             = "PutInEpoch"
+	getOperation                    = "Get"
+	getFromEpochOperation           = "GetFromEpoch"
+	getBulkFromEpochOperation       = "GetBulkFromEpoch"
+	getBulkFromEpochRangeOperation  = "GetBulkFromEpochRange"
+	hasOperation                    = "Has"
+	multiGetOperation               = "MultiGet"
+	peekOperation                   = "Peek"
+	hasOrAddOperation               = "HasOrAdd"
+	searchFirstOperation            = "SearchFirst"
+	removeOperation                 = "Remove"
+	removeFromCurrentEpochOperation = "RemoveFromCurrentEpoch"
+)
+
+func lookupOutcome(err error) types.OperationOutcome {
+	if err == nil {
+		return types.HitOutcome
+	}
+	if errors.Is(err, common.ErrKeyNotFound) {
+		return types.MissOutcome
+	}
+
+	return types.ErrorOutcome
+}
+
+func writeOutcome(err error) types.OperationOutcome {
+	if err == nil {
+		return types.SuccessOutcome
+	}
+
+	return types.ErrorOutcome
+}
+
+func foundOutcome(found bool) types.OperationOutcome {
+	if found {
+		return types.HitOutcome
+	}
+
+	return types.MissOutcome
+}
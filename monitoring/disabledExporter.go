@@ -0,0 +1,16 @@
+package monitoring
+
+import "github.com/multiversx/mx-chain-storage-go/types"
+
+type disabledExporter struct {
+}
+
+// Export does nothing
+func (exporter *disabledExporter) Export(_ types.MetricsSnapshot) error {
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (exporter *disabledExporter) IsInterfaceNil() bool {
+	return exporter == nil
+}
@@ -0,0 +1,52 @@
+package monitoring
+
+import (
+	"sync"
+
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+var _ types.MetricsExporter = (*inMemoryExporter)(nil)
+
+// inMemoryExporter keeps the last exported metrics in memory
+type inMemoryExporter struct {
+	mut        sync.RWMutex
+	snapshot   types.MetricsSnapshot
+	numExports int
+}
+
+// NewInMemoryExporter creates a new exporter that keeps the last exported metrics in memory
+func NewInMemoryExporter() *inMemoryExporter {
+	return &inMemoryExporter{}
+}
+
+// Export stores the provided snapshot, replacing the previous one
+func (exporter *inMemoryExporter) Export(snapshot types.MetricsSnapshot) error {
+	exporter.mut.Lock()
+	exporter.snapshot = snapshot
+	exporter.numExports++
+	exporter.mut.Unlock()
+
+	return nil
+}
+
+// LastExport returns the last exported snapshot
+func (exporter *inMemoryExporter) LastExport() types.MetricsSnapshot {
+	exporter.mut.RLock()
+	defer exporter.mut.RUnlock()
+
+	return exporter.snapshot
+}
+
+// NumExports returns the number of exports done so far
+func (exporter *inMemoryExporter) NumExports() int {
+	exporter.mut.RLock()
+	defer exporter.mut.RUnlock()
+
+	return exporter.numExports
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (exporter *inMemoryExporter) IsInterfaceNil() bool {
+	return exporter == nil
+}
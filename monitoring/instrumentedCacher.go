@@ -0,0 +1,147 @@
+package monitoring
+
+import (
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+var _ types.Cacher = (*instrumentedCacher)(nil)
+
+// ArgsInstrumentedCacher holds the arguments needed to create an instrumented cacher
+type ArgsInstrumentedCacher struct {
+	Cacher   types.Cacher
+	Registry types.MetricsRegistry
+	Name     string
+}
+
+// instrumentedCacher records the metrics of the operations executed on the wrapped cacher
+type instrumentedCacher struct {
+	cacher   types.Cacher
+	registry types.MetricsRegistry
+	name     string
+	recorder types.OperationRecorder
+}
+
+// NewInstrumentedCacher creates a new cacher decorator that records its metrics under the provided name
+func NewInstrumentedCacher(args ArgsInstrumentedCacher) (*instrumentedCacher, error) {
+	if check.IfNil(args.Cacher) {
+		return nil, common.ErrNilCacher
+	}
+	if check.IfNil(args.Registry) {
+		return nil, common.ErrNilMetricsRegistry
+	}
+
+	recorder, err := args.Registry.RegisterUnit(args.Name, types.CacherUnit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &instrumentedCacher{
+		cacher:   args.Cacher,
+		registry: args.Registry,
+		name:     args.Name,
+		recorder: recorder,
+	}, nil
+}
+
+// Clear clears the wrapped cacher
+func (ic *instrumentedCacher) Clear() {
+	ic.cacher.Clear()
+}
+
+// Put adds the value in the wrapped cacher
+func (ic *instrumentedCacher) Put(key []byte, value interface{}, sizeInBytes int) (evicted bool) {
+	start := time.Now()
+	evicted = ic.cacher.Put(key, value, sizeInBytes)
+	ic.recorder.Record(putOperation, types.SuccessOutcome, time.Since(start))
+
+	return evicted
+}
+
+// Get returns the value of the key from the wrapped cacher
+func (ic *instrumentedCacher) Get(key []byte) (value interface{}, ok bool) {
+	start := time.Now()
+	value, ok = ic.cacher.Get(key)
+	ic.recorder.Record(getOperation, foundOutcome(ok), time.Since(start))
+
+	return value, ok
+}
+
+// Has checks if the key exists in the wrapped cacher
+func (ic *instrumentedCacher) Has(key []byte) bool {
+	start := time.Now()
+	has := ic.cacher.Has(key)
+	ic.recorder.Record(hasOperation, foundOutcome(has), time.Since(start))
+
+	return has
+}
+
+// Peek returns the value of the key from the wrapped cacher, without updating its recent-ness
+func (ic *instrumentedCacher) Peek(key []byte) (value interface{}, ok bool) {
+	start := time.Now()
+	value, ok = ic.cacher.Peek(key)
+	ic.recorder.Record(peekOperation, foundOutcome(ok), time.Since(start))
+
+	return value, ok
+}
+
+// HasOrAdd adds the value in the wrapped cacher if the key does not exist
+func (ic *instrumentedCacher) HasOrAdd(key []byte, value interface{}, sizeInBytes int) (has, added bool) {
+	start := time.Now()
+	has, added = ic.cacher.HasOrAdd(key, value, sizeInBytes)
+	ic.recorder.Record(hasOrAddOperation, foundOutcome(has), time.Since(start))
+
+	return has, added
+}
+
+// Remove removes the key from the wrapped cacher
+func (ic *instrumentedCacher) Remove(key []byte) {
+	start := time.Now()
+	ic.cacher.Remove(key)
+	ic.recorder.Record(removeOperation, types.SuccessOutcome, time.Since(start))
+}
+
+// Keys returns the keys of the wrapped cacher
+func (ic *instrumentedCacher) Keys() [][]byte {
+	return ic.cacher.Keys()
+}
+
+// Len returns the number of items in the wrapped cacher
+func (ic *instrumentedCacher) Len() int {
+	return ic.cacher.Len()
+}
+
+// SizeInBytesContained returns the size in bytes of the items in the wrapped cacher
+func (ic *instrumentedCacher) SizeInBytesContained() uint64 {
+	return ic.cacher.SizeInBytesContained()
+}
+
+// MaxSize returns the maximum number of items of the wrapped cacher
+func (ic *instrumentedCacher) MaxSize() int {
+	return ic.cacher.MaxSize()
+}
+
+// RegisterHandler registers a new handler on the wrapped cacher
+func (ic *instrumentedCacher) RegisterHandler(handler func(key []byte, value interface{}), id string) {
+	ic.cacher.RegisterHandler(handler, id)
+}
+
+// UnRegisterHandler removes the handler from the wrapped cacher
+func (ic *instrumentedCacher) UnRegisterHandler(id string) {
+	ic.cacher.UnRegisterHandler(id)
+}
+
+// Close closes the wrapped cacher and removes its metrics from the registry
+func (ic *instrumentedCacher) Close() error {
+	ic.registry.UnregisterUnit(ic.name)
+
+	return ic.cacher.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ic *instrumentedCacher) IsInterfaceNil() bool {
+	return ic == nil
+}
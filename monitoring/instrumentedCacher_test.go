@@ -0,0 +1,83 @@
+package monitoring_test
+
+import (
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/lrucache"
+	"github.com/multiversx/mx-chain-storage-go/monitoring"
+	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createArgsInstrumentedCacher() monitoring.ArgsInstrumentedCacher {
+	cacher, _ := lrucache.NewCache(10)
+	registry, _ := monitoring.NewRegistry(monitoring.NewInMemoryExporter())
+
+	return monitoring.ArgsInstrumentedCacher{
+		Cacher:   cacher,
+		Registry: registry,
+		Name:     "cacher",
+	}
+}
+
+func TestNewInstrumentedCacher(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil cacher should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgsInstrumentedCacher()
+		args.Cacher = nil
+		ic, err := monitoring.NewInstrumentedCacher(args)
+		assert.True(t, check.IfNil(ic))
+		assert.Equal(t, common.ErrNilCacher, err)
+	})
+	t.Run("nil registry should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgsInstrumentedCacher()
+		args.Registry = nil
+		ic, err := monitoring.NewInstrumentedCacher(args)
+		assert.True(t, check.IfNil(ic))
+		assert.Equal(t, common.ErrNilMetricsRegistry, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		ic, err := monitoring.NewInstrumentedCacher(createArgsInstrumentedCacher())
+		assert.False(t, check.IfNil(ic))
+		assert.Nil(t, err)
+	})
+}
+
+func TestInstrumentedCacher_ShouldRecordHitsAndMisses(t *testing.T) {
+	t.Parallel()
+
+	args := createArgsInstrumentedCacher()
+	ic, _ := monitoring.NewInstrumentedCacher(args)
+
+	_ = ic.Put([]byte("key"), "value", 5)
+	value, ok := ic.Get([]byte("key"))
+	assert.True(t, ok)
+	assert.Equal(t, "value", value)
+	_, _ = ic.Get([]byte("missing"))
+	_, _ = ic.Get([]byte("missing"))
+	_ = ic.Has([]byte("key"))
+	_, _ = ic.HasOrAdd([]byte("key2"), "value2", 6)
+
+	metrics := args.Registry.Snapshot().Units
+	require.Equal(t, 1, len(metrics))
+	assert.Equal(t, types.CacherUnit, metrics[0].Kind)
+
+	operations := metrics[0].Operations
+	assert.Equal(t, uint64(1), operations["Put"].NumCalls)
+	assert.Equal(t, uint64(3), operations["Get"].NumCalls)
+	assert.Equal(t, uint64(1), operations["Get"].NumHits)
+	assert.Equal(t, uint64(2), operations["Get"].NumMisses)
+	assert.Equal(t, uint64(1), operations["Has"].NumHits)
+	assert.Equal(t, uint64(1), operations["HasOrAdd"].NumMisses)
+	assert.Equal(t, 2, ic.Len())
+}
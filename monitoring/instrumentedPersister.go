@@ -0,0 +1,156 @@
+package monitoring
+
+import (
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+var _ types.Persister = (*instrumentedPersister)(nil)
+var _ types.PersisterWithMultiGet = (*instrumentedMultiGetPersister)(nil)
+
+// ArgsInstrumentedPersister holds the arguments needed to create an instrumented persister
+type ArgsInstrumentedPersister struct {
+	Persister types.Persister
+	Registry  types.MetricsRegistry
+	Name      string
+}
+
+// instrumentedPersister records the metrics of the operations executed on the wrapped persister
+type instrumentedPersister struct {
+	persister types.Persister
+	registry  types.MetricsRegistry
+	name      string
+	recorder  types.OperationRecorder
+}
+
+// instrumentedMultiGetPersister is an instrumented persister that also exposes the multi get of the wrapped persister
+type instrumentedMultiGetPersister struct {
+	*instrumentedPersister
+	multiGetter types.PersisterWithMultiGet
+}
+
+// NewInstrumentedPersister creates a new persister decorator that records its metrics under the provided name
+func NewInstrumentedPersister(args ArgsInstrumentedPersister) (*instrumentedPersister, error) {
+	if check.IfNil(args.Persister) {
+		return nil, common.ErrNilPersister
+	}
+	if check.IfNil(args.Registry) {
+		return nil, common.ErrNilMetricsRegistry
+	}
+
+	recorder, err := args.Registry.RegisterUnit(args.Name, types.PersisterUnit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &instrumentedPersister{
+		persister: args.Persister,
+		registry:  args.Registry,
+		name:      args.Name,
+		recorder:  recorder,
+	}, nil
+}
+
+// NewInstrumentedMultiGetPersister creates a new persister decorator that records its metrics under the provided name,
+// keeping the multi get ability of the wrapped persister
+func NewInstrumentedMultiGetPersister(args ArgsInstrumentedPersister) (*instrumentedMultiGetPersister, error) {
+	if check.IfNil(args.Persister) {
+		return nil, common.ErrNilPersister
+	}
+	multiGetter, ok := args.Persister.(types.PersisterWithMultiGet)
+	if !ok {
+		return nil, common.ErrWrongTypeAssertion
+	}
+
+	persister, err := NewInstrumentedPersister(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &instrumentedMultiGetPersister{
+		instrumentedPersister: persister,
+		multiGetter:           multiGetter,
+	}, nil
+}
+
+// Put adds the value in the wrapped persister
+func (ip *instrumentedPersister) Put(key, val []byte) error {
+	start := time.Now()
+	err := ip.persister.Put(key, val)
+	ip.recorder.Record(putOperation, writeOutcome(err), time.Since(start))
+
+	return err
+}
+
+// Get returns the value of the key from the wrapped persister
+func (ip *instrumentedPersister) Get(key []byte) ([]byte, error) {
+	start := time.Now()
+	value, err := ip.persister.Get(key)
+	ip.recorder.Record(getOperation, lookupOutcome(err), time.Since(start))
+
+	return value, err
+}
+
+// Has checks if the key exists in the wrapped persister
+func (ip *instrumentedPersister) Has(key []byte) error {
+	start := time.Now()
+	err := ip.persister.Has(key)
+	ip.recorder.Record(hasOperation, lookupOutcome(err), time.Since(start))
+
+	return err
+}
+
+// Remove removes the key from the wrapped persister
+func (ip *instrumentedPersister) Remove(key []byte) error {
+	start := time.Now()
+	err := ip.persister.Remove(key)
+	ip.recorder.Record(removeOperation, writeOutcome(err), time.Since(start))
+
+	return err
+}
+
+// Close closes the wrapped persister and removes its metrics from the registry
+func (ip *instrumentedPersister) Close() error {
+	ip.registry.UnregisterUnit(ip.name)
+
+	return ip.persister.Close()
+}
+
+// Destroy removes the data of the wrapped persister
+func (ip *instrumentedPersister) Destroy() error {
+	ip.registry.UnregisterUnit(ip.name)
+
+	return ip.persister.Destroy()
+}
+
+// DestroyClosed removes the data of the already closed wrapped persister
+func (ip *instrumentedPersister) DestroyClosed() error {
+	return ip.persister.DestroyClosed()
+}
+
+// RangeKeys iterates over all the keys of the wrapped persister
+func (ip *instrumentedPersister) RangeKeys(handler func(key []byte, val []byte) bool) {
+	ip.persister.RangeKeys(handler)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ip *instrumentedPersister) IsInterfaceNil() bool {
+	return ip == nil
+}
+
+// MultiGet returns the results for all the provided keys from the wrapped persister
+func (imp *instrumentedMultiGetPersister) MultiGet(keys [][]byte) []types.KeyValueResult {
+	start := time.Now()
+	results := imp.multiGetter.MultiGet(keys)
+	imp.recorder.Record(multiGetOperation, types.SuccessOutcome, time.Since(start))
+
+	return results
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (imp *instrumentedMultiGetPersister) IsInterfaceNil() bool {
+	return imp == nil
+}
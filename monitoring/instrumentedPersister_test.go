@@ -0,0 +1,138 @@
+package monitoring_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/memorydb"
+	"github.com/multiversx/mx-chain-storage-go/monitoring"
+	"github.com/multiversx/mx-chain-storage-go/testscommon"
+	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createArgsInstrumentedPersister() monitoring.ArgsInstrumentedPersister {
+	registry, _ := monitoring.NewRegistry(monitoring.NewInMemoryExporter())
+
+	return monitoring.ArgsInstrumentedPersister{
+		Persister: &testscommon.PersisterStub{},
+		Registry:  registry,
+		Name:      "persister",
+	}
+}
+
+func TestNewInstrumentedPersister(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil persister should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgsInstrumentedPersister()
+		args.Persister = nil
+		ip, err := monitoring.NewInstrumentedPersister(args)
+		assert.True(t, check.IfNil(ip))
+		assert.Equal(t, common.ErrNilPersister, err)
+	})
+	t.Run("nil registry should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgsInstrumentedPersister()
+		args.Registry = nil
+		ip, err := monitoring.NewInstrumentedPersister(args)
+		assert.True(t, check.IfNil(ip))
+		assert.Equal(t, common.ErrNilMetricsRegistry, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		ip, err := monitoring.NewInstrumentedPersister(createArgsInstrumentedPersister())
+		assert.False(t, check.IfNil(ip))
+		assert.Nil(t, err)
+	})
+}
+
+func TestInstrumentedPersister_ShouldRecordErrors(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("expected error")
+	args := createArgsInstrumentedPersister()
+	args.Persister = &testscommon.PersisterStub{
+		PutCalled: func(key, val []byte) error {
+			return expectedErr
+		},
+		GetCalled: func(key []byte) ([]byte, error) {
+			return nil, common.ErrKeyNotFound
+		},
+		HasCalled: func(key []byte) error {
+			return expectedErr
+		},
+	}
+	ip, _ := monitoring.NewInstrumentedPersister(args)
+
+	assert.Equal(t, expectedErr, ip.Put([]byte("key"), []byte("value")))
+	_, err := ip.Get([]byte("key"))
+	assert.Equal(t, common.ErrKeyNotFound, err)
+	assert.Equal(t, expectedErr, ip.Has([]byte("key")))
+
+	metrics := args.Registry.Snapshot().Units
+	require.Equal(t, 1, len(metrics))
+	assert.Equal(t, types.PersisterUnit, metrics[0].Kind)
+
+	operations := metrics[0].Operations
+	assert.Equal(t, uint64(1), operations["Put"].NumErrors)
+	assert.Equal(t, uint64(1), operations["Get"].NumMisses)
+	assert.Zero(t, operations["Get"].NumErrors)
+	assert.Equal(t, uint64(1), operations["Has"].NumErrors)
+}
+
+func TestNewInstrumentedMultiGetPersister(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil persister should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgsInstrumentedPersister()
+		args.Persister = nil
+		ip, err := monitoring.NewInstrumentedMultiGetPersister(args)
+		assert.True(t, check.IfNil(ip))
+		assert.Equal(t, common.ErrNilPersister, err)
+	})
+	t.Run("persister without multi get should error", func(t *testing.T) {
+		t.Parallel()
+
+		ip, err := monitoring.NewInstrumentedMultiGetPersister(createArgsInstrumentedPersister())
+		assert.True(t, check.IfNil(ip))
+		assert.Equal(t, common.ErrWrongTypeAssertion, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgsInstrumentedPersister()
+		args.Persister = memorydb.New()
+		ip, err := monitoring.NewInstrumentedMultiGetPersister(args)
+		assert.False(t, check.IfNil(ip))
+		assert.Nil(t, err)
+	})
+}
+
+func TestInstrumentedMultiGetPersister_MultiGetShouldForwardAndRecord(t *testing.T) {
+	t.Parallel()
+
+	args := createArgsInstrumentedPersister()
+	args.Persister = memorydb.New()
+	ip, _ := monitoring.NewInstrumentedMultiGetPersister(args)
+	_ = ip.Put([]byte("key"), []byte("value"))
+
+	results := ip.MultiGet([][]byte{[]byte("key"), []byte("missing")})
+	require.Equal(t, 2, len(results))
+	assert.Equal(t, []byte("value"), results[0].Value)
+	assert.Nil(t, results[0].Err)
+	assert.Equal(t, common.ErrKeyNotFound, results[1].Err)
+
+	metrics := args.Registry.Snapshot().Units
+	require.Equal(t, 1, len(metrics))
+	assert.Equal(t, uint64(1), metrics[0].Operations["MultiGet"].NumCalls)
+}
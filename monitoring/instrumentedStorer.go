@@ -0,0 +1,188 @@
+package monitoring
+
+import (
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+var _ types.StorerWithEpochRange = (*instrumentedStorer)(nil)
+
+// ArgsInstrumentedStorer holds the arguments needed to create an instrumented storer
+type ArgsInstrumentedStorer struct {
+	Storer   types.Storer
+	Registry types.MetricsRegistry
+	Name     string
+}
+
+// instrumentedStorer records the metrics of the operations executed on the wrapped storer
+type instrumentedStorer struct {
+	storer   types.Storer
+	registry types.MetricsRegistry
+	name     string
+	recorder types.OperationRecorder
+}
+
+// NewInstrumentedStorer creates a new storer decorator that records its metrics under the provided name
+func NewInstrumentedStorer(args ArgsInstrumentedStorer) (*instrumentedStorer, error) {
+	if check.IfNil(args.Storer) {
+		return nil, common.ErrNilStorer
+	}
+	if check.IfNil(args.Registry) {
+		return nil, common.ErrNilMetricsRegistry
+	}
+
+	recorder, err := args.Registry.RegisterUnit(args.Name, types.StorerUnit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &instrumentedStorer{
+		storer:   args.Storer,
+		registry: args.Registry,
+		name:     args.Name,
+		recorder: recorder,
+	}, nil
+}
+
+// Put adds the data in the wrapped storer
+func (is *instrumentedStorer) Put(key, data []byte) error {
+	start := time.Now()
+	err := is.storer.Put(key, data)
+	is.recorder.Record(putOperation, writeOutcome(err), time.Since(start))
+
+	return err
+}
+
+// PutInEpoch adds the data in the wrapped storer, in the provided epoch
+func (is *instrumentedStorer) PutInEpoch(key, data []byte, epoch uint32) error {
+	start := time.Now()
+	err := is.storer.PutInEpoch(key, data, epoch)
+	is.recorder.Record(putInEpochOperation, writeOutcome(err), time.Since(start))
+
+	return err
+}
+
+// Get returns the value of the key from the wrapped storer
+func (is *instrumentedStorer) Get(key []byte) ([]byte, error) {
+	start := time.Now()
+	value, err := is.storer.Get(key)
+	is.recorder.Record(getOperation, lookupOutcome(err), time.Since(start))
+
+	return value, err
+}
+
+// Has checks if the key exists in the wrapped storer
+func (is *instrumentedStorer) Has(key []byte) error {
+	start := time.Now()
+	err := is.storer.Has(key)
+	is.recorder.Record(hasOperation, lookupOutcome(err), time.Since(start))
+
+	return err
+}
+
+// SearchFirst returns the value of the key from the first epoch it is found in
+func (is *instrumentedStorer) SearchFirst(key []byte) ([]byte, error) {
+	start := time.Now()
+	value, err := is.storer.SearchFirst(key)
+	is.recorder.Record(searchFirstOperation, lookupOutcome(err), time.Since(start))
+
+	return value, err
+}
+
+// RemoveFromCurrentEpoch removes the key from the current epoch of the wrapped storer
+func (is *instrumentedStorer) RemoveFromCurrentEpoch(key []byte) error {
+	start := time.Now()
+	err := is.storer.RemoveFromCurrentEpoch(key)
+	is.recorder.Record(removeFromCurrentEpochOperation, writeOutcome(err), time.Since(start))
+
+	return err
+}
+
+// Remove removes the key from the wrapped storer
+func (is *instrumentedStorer) Remove(key []byte) error {
+	start := time.Now()
+	err := is.storer.Remove(key)
+	is.recorder.Record(removeOperation, writeOutcome(err), time.Since(start))
+
+	return err
+}
+
+// ClearCache clears the cache of the wrapped storer
+func (is *instrumentedStorer) ClearCache() {
+	is.storer.ClearCache()
+}
+
+// DestroyUnit destroys the wrapped storer
+func (is *instrumentedStorer) DestroyUnit() error {
+	return is.storer.DestroyUnit()
+}
+
+// GetFromEpoch returns the value of the key from the provided epoch
+func (is *instrumentedStorer) GetFromEpoch(key []byte, epoch uint32) ([]byte, error) {
+	start := time.Now()
+	value, err := is.storer.GetFromEpoch(key, epoch)
+	is.recorder.Record(getFromEpochOperation, lookupOutcome(err), time.Since(start))
+
+	return value, err
+}
+
+// GetBulkFromEpoch returns the values of the keys from the provided epoch
+func (is *instrumentedStorer) GetBulkFromEpoch(keys [][]byte, epoch uint32) ([]data.KeyValuePair, error) {
+	start := time.Now()
+	pairs, err := is.storer.GetBulkFromEpoch(keys, epoch)
+	is.recorder.Record(getBulkFromEpochOperation, writeOutcome(err), time.Since(start))
+
+	return pairs, err
+}
+
+// GetBulkFromEpochRange returns the values of the keys from the provided epoch range. It errors if the wrapped
+// storer does not support epoch range operations
+func (is *instrumentedStorer) GetBulkFromEpochRange(keys [][]byte, epochRange types.EpochRange) ([]types.EpochKeyValuePair, error) {
+	storer, ok := is.storer.(types.StorerWithEpochRange)
+	if !ok {
+		return nil, common.ErrEpochRangeNotSupported
+	}
+
+	start := time.Now()
+	pairs, err := storer.GetBulkFromEpochRange(keys, epochRange)
+	is.recorder.Record(getBulkFromEpochRangeOperation, writeOutcome(err), time.Since(start))
+
+	return pairs, err
+}
+
+// GetOldestEpoch returns the oldest epoch of the wrapped storer
+func (is *instrumentedStorer) GetOldestEpoch() (uint32, error) {
+	return is.storer.GetOldestEpoch()
+}
+
+// RangeKeys iterates over all the keys of the wrapped storer
+func (is *instrumentedStorer) RangeKeys(handler func(key []byte, val []byte) bool) {
+	is.storer.RangeKeys(handler)
+}
+
+// RangeKeysFromEpoch iterates over all the keys of the provided epoch. It does nothing if the wrapped
+// storer does not support epoch range operations
+func (is *instrumentedStorer) RangeKeysFromEpoch(epoch uint32, handler func(key []byte, val []byte) bool) {
+	storer, ok := is.storer.(types.StorerWithEpochRange)
+	if !ok {
+		return
+	}
+
+	storer.RangeKeysFromEpoch(epoch, handler)
+}
+
+// Close closes the wrapped storer and removes its metrics from the registry
+func (is *instrumentedStorer) Close() error {
+	is.registry.UnregisterUnit(is.name)
+
+	return is.storer.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (is *instrumentedStorer) IsInterfaceNil() bool {
+	return is == nil
+}
@@ -0,0 +1,138 @@
+package monitoring_test
+
+import (
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/lrucache"
+	"github.com/multiversx/mx-chain-storage-go/memorydb"
+	"github.com/multiversx/mx-chain-storage-go/monitoring"
+	"github.com/multiversx/mx-chain-storage-go/storageUnit"
+	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createArgsInstrumentedStorer(tb testing.TB) monitoring.ArgsInstrumentedStorer {
+	cacher, _ := lrucache.NewCache(10)
+	storer, err := storageUnit.NewStorageUnit(cacher, memorydb.New())
+	require.Nil(tb, err)
+	registry, _ := monitoring.NewRegistry(monitoring.NewInMemoryExporter())
+
+	return monitoring.ArgsInstrumentedStorer{
+		Storer:   storer,
+		Registry: registry,
+		Name:     "storer",
+	}
+}
+
+func TestNewInstrumentedStorer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil storer should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgsInstrumentedStorer(t)
+		args.Storer = nil
+		is, err := monitoring.NewInstrumentedStorer(args)
+		assert.True(t, check.IfNil(is))
+		assert.Equal(t, common.ErrNilStorer, err)
+	})
+	t.Run("nil registry should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgsInstrumentedStorer(t)
+		args.Registry = nil
+		is, err := monitoring.NewInstrumentedStorer(args)
+		assert.True(t, check.IfNil(is))
+		assert.Equal(t, common.ErrNilMetricsRegistry, err)
+	})
+	t.Run("empty name should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgsInstrumentedStorer(t)
+		args.Name = ""
+		is, err := monitoring.NewInstrumentedStorer(args)
+		assert.True(t, check.IfNil(is))
+		assert.Equal(t, common.ErrEmptyUnitName, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		is, err := monitoring.NewInstrumentedStorer(createArgsInstrumentedStorer(t))
+		assert.False(t, check.IfNil(is))
+		assert.Nil(t, err)
+	})
+}
+
+func TestInstrumentedStorer_ShouldRecordOperations(t *testing.T) {
+	t.Parallel()
+
+	args := createArgsInstrumentedStorer(t)
+	is, _ := monitoring.NewInstrumentedStorer(args)
+
+	_ = is.Put([]byte("key"), []byte("value"))
+	value, err := is.Get([]byte("key"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("value"), value)
+	_, _ = is.Get([]byte("missing"))
+	_ = is.Has([]byte("missing"))
+	_ = is.Remove([]byte("key"))
+
+	metrics := args.Registry.Snapshot().Units
+	require.Equal(t, 1, len(metrics))
+	assert.Equal(t, "storer", metrics[0].Name)
+	assert.Equal(t, types.StorerUnit, metrics[0].Kind)
+
+	operations := metrics[0].Operations
+	assert.Equal(t, uint64(1), operations["Put"].NumCalls)
+	assert.Equal(t, uint64(2), operations["Get"].NumCalls)
+	assert.Equal(t, uint64(1), operations["Get"].NumHits)
+	assert.Equal(t, uint64(1), operations["Get"].NumMisses)
+	assert.Equal(t, uint64(2), operations["Get"].Latency.Count)
+	assert.Equal(t, uint64(1), operations["Has"].NumMisses)
+	assert.Equal(t, uint64(1), operations["Remove"].NumCalls)
+
+	_ = is.Close()
+	assert.Empty(t, args.Registry.Snapshot().Units)
+}
+
+func TestInstrumentedStorer_EpochRangeOperations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("supporting storer should forward the calls", func(t *testing.T) {
+		t.Parallel()
+
+		is, _ := monitoring.NewInstrumentedStorer(createArgsInstrumentedStorer(t))
+		_ = is.Put([]byte("key"), []byte("value"))
+
+		results, err := is.GetBulkFromEpochRange([][]byte{[]byte("key")}, types.EpochRange{FirstEpoch: 1, LastEpoch: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(results))
+
+		numKeys := 0
+		is.RangeKeysFromEpoch(1, func(key []byte, val []byte) bool {
+			numKeys++
+			return true
+		})
+		assert.Equal(t, 1, numKeys)
+	})
+	t.Run("storer without epoch range support should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgsInstrumentedStorer(t)
+		args.Storer = struct{ types.Storer }{args.Storer}
+		is, _ := monitoring.NewInstrumentedStorer(args)
+		_ = is.Put([]byte("key"), []byte("value"))
+
+		results, err := is.GetBulkFromEpochRange([][]byte{[]byte("key")}, types.EpochRange{FirstEpoch: 1, LastEpoch: 2})
+		assert.Nil(t, results)
+		assert.Equal(t, common.ErrEpochRangeNotSupported, err)
+
+		is.RangeKeysFromEpoch(1, func(key []byte, val []byte) bool {
+			assert.Fail(t, "should not have been called")
+			return true
+		})
+	})
+}
@@ -0,0 +1,69 @@
+package monitoring
+
+import (
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/atomic"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+var latencyBucketsUpperBounds = []time.Duration{
+	time.Microsecond * 10,
+	time.Microsecond * 50,
+	time.Microsecond * 100,
+	time.Microsecond * 500,
+	time.Millisecond,
+	time.Millisecond * 5,
+	time.Millisecond * 10,
+	time.Millisecond * 50,
+	time.Millisecond * 100,
+	time.Millisecond * 500,
+	time.Second,
+}
+
+// latencyHistogram counts the observed latencies in fixed buckets, without locking
+type latencyHistogram struct {
+	buckets    []atomic.Counter
+	count      atomic.Counter
+	sumInNanos atomic.Counter
+}
+
+func newLatencyHistogram() *latencyHistogram {
+	return &latencyHistogram{
+		// the last bucket holds the observations above the highest upper bound
+		buckets: make([]atomic.Counter, len(latencyBucketsUpperBounds)+1),
+	}
+}
+
+func (lh *latencyHistogram) observe(duration time.Duration) {
+	index := len(latencyBucketsUpperBounds)
+	for i, upperBound := range latencyBucketsUpperBounds {
+		if duration <= upperBound {
+			index = i
+			break
+		}
+	}
+
+	lh.buckets[index].Increment()
+	lh.count.Increment()
+	lh.sumInNanos.Add(int64(duration))
+}
+
+func (lh *latencyHistogram) snapshot() types.LatencyHistogram {
+	histogram := types.LatencyHistogram{
+		Buckets: make([]types.LatencyBucket, 0, len(latencyBucketsUpperBounds)),
+	}
+
+	cumulated := uint64(0)
+	for i, upperBound := range latencyBucketsUpperBounds {
+		cumulated += lh.buckets[i].GetUint64()
+		histogram.Buckets = append(histogram.Buckets, types.LatencyBucket{
+			UpperBound: upperBound,
+			Count:      cumulated,
+		})
+	}
+	histogram.Count = cumulated + lh.buckets[len(latencyBucketsUpperBounds)].GetUint64()
+	histogram.Sum = time.Duration(lh.sumInNanos.Get())
+
+	return histogram
+}
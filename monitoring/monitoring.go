@@ -4,14 +4,47 @@ import (
 	"github.com/multiversx/mx-chain-core-go/core"
 	"github.com/multiversx/mx-chain-core-go/core/atomic"
 	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-storage-go/types"
 )
 
 var log = logger.GetOrCreate("storage")
 
 var cumulatedSizeInBytes atomic.Counter
 
+var defaultRegistry = &registry{
+	exporter: &disabledExporter{},
+	units:    make(map[string]*unitMetrics),
+	caches:   make(map[string]types.CacheStatsProvider),
+}
+
 // MonitorNewCache adds the size in the global cumulated size variable
 func MonitorNewCache(tag string, sizeInBytes uint64) {
 	cumulatedSizeInBytes.Add(int64(sizeInBytes))
 	log.Debug("MonitorNewCache", "name", tag, "capacity", core.ConvertBytes(sizeInBytes), "cumulated", core.ConvertBytes(cumulatedSizeInBytes.GetUint64()))
 }
+
+// DefaultRegistry returns the registry the caches and the persisters created by this module register with
+func DefaultRegistry() types.MetricsRegistry {
+	return defaultRegistry
+}
+
+// RegisterCache adds the cache in the default registry and returns true if the cache was registered.
+// Caches without a name are not monitored.
+func RegisterCache(name string, cache types.CacheStatsProvider) bool {
+	if len(name) == 0 {
+		return false
+	}
+
+	err := defaultRegistry.RegisterCache(name, cache)
+	if err != nil {
+		log.Warn("monitoring.RegisterCache", "name", name, "error", err)
+		return false
+	}
+
+	return true
+}
+
+// UnregisterCache removes the cache from the default registry
+func UnregisterCache(name string) {
+	defaultRegistry.UnregisterCache(name)
+}
@@ -0,0 +1,179 @@
+package monitoring
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// prometheusHandler renders the registry in the Prometheus text exposition format
+type prometheusHandler struct {
+	registry types.MetricsRegistry
+}
+
+// NewPrometheusHandler creates a new HTTP handler that renders the metrics of the provided registry
+func NewPrometheusHandler(registry types.MetricsRegistry) (*prometheusHandler, error) {
+	if check.IfNil(registry) {
+		return nil, common.ErrNilMetricsRegistry
+	}
+
+	return &prometheusHandler{
+		registry: registry,
+	}, nil
+}
+
+// ServeHTTP writes the current metrics snapshot
+func (handler *prometheusHandler) ServeHTTP(writer http.ResponseWriter, _ *http.Request) {
+	writer.Header().Set("Content-Type", prometheusContentType)
+
+	err := writePrometheusMetrics(writer, handler.registry.Snapshot())
+	if err != nil {
+		log.Debug("prometheusHandler.ServeHTTP", "error", err)
+	}
+}
+
+func writePrometheusMetrics(w io.Writer, snapshot types.MetricsSnapshot) error {
+	buffered := bufio.NewWriter(w)
+
+	writeCachesMetrics(buffered, snapshot.Caches)
+	writeUnitsMetrics(buffered, snapshot.Units)
+
+	return buffered.Flush()
+}
+
+func writeCachesMetrics(w io.Writer, caches []types.CacheMetrics) {
+	if len(caches) == 0 {
+		return
+	}
+
+	writeHeader(w, "storage_cache_items", "gauge", "Number of items held by the cache")
+	for _, cache := range caches {
+		writeSample(w, "storage_cache_items", cacheLabels(cache.Name), strconv.Itoa(cache.Stats.NumItems))
+	}
+	writeHeader(w, "storage_cache_size_bytes", "gauge", "Size in bytes of the items held by the cache")
+	for _, cache := range caches {
+		writeSample(w, "storage_cache_size_bytes", cacheLabels(cache.Name), formatUint(cache.Stats.SizeInBytes))
+	}
+	writeHeader(w, "storage_cache_hits_total", "counter", "Number of lookups that found the key")
+	for _, cache := range caches {
+		writeSample(w, "storage_cache_hits_total", cacheLabels(cache.Name), formatUint(cache.Stats.NumHits))
+	}
+	writeHeader(w, "storage_cache_misses_total", "counter", "Number of lookups that did not find the key")
+	for _, cache := range caches {
+		writeSample(w, "storage_cache_misses_total", cacheLabels(cache.Name), formatUint(cache.Stats.NumMisses))
+	}
+	writeHeader(w, "storage_cache_evictions_total", "counter", "Number of items evicted from the cache")
+	for _, cache := range caches {
+		writeSample(w, "storage_cache_evictions_total", cacheLabels(cache.Name), formatUint(cache.Stats.NumEvictions))
+	}
+	writeHeader(w, "storage_cache_hit_ratio", "gauge", "Ratio of the lookups that found the key")
+	for _, cache := range caches {
+		writeSample(w, "storage_cache_hit_ratio", cacheLabels(cache.Name), formatFloat(hitRatio(cache.Stats.NumHits, cache.Stats.NumMisses)))
+	}
+}
+
+func writeUnitsMetrics(w io.Writer, units []types.UnitMetrics) {
+	if len(units) == 0 {
+		return
+	}
+
+	writeHeader(w, "storage_operations_total", "counter", "Number of operations executed on the unit")
+	forEachOperation(units, func(labels string, metrics types.OperationMetrics) {
+		writeSample(w, "storage_operations_total", labels, formatUint(metrics.NumCalls))
+	})
+	writeHeader(w, "storage_operation_hits_total", "counter", "Number of lookups that found the key")
+	forEachOperation(units, func(labels string, metrics types.OperationMetrics) {
+		writeSample(w, "storage_operation_hits_total", labels, formatUint(metrics.NumHits))
+	})
+	writeHeader(w, "storage_operation_misses_total", "counter", "Number of lookups that did not find the key")
+	forEachOperation(units, func(labels string, metrics types.OperationMetrics) {
+		writeSample(w, "storage_operation_misses_total", labels, formatUint(metrics.NumMisses))
+	})
+	writeHeader(w, "storage_operation_errors_total", "counter", "Number of failed operations")
+	forEachOperation(units, func(labels string, metrics types.OperationMetrics) {
+		writeSample(w, "storage_operation_errors_total", labels, formatUint(metrics.NumErrors))
+	})
+	writeHeader(w, "storage_operation_latency_seconds", "histogram", "Latency of the operations executed on the unit")
+	forEachOperation(units, func(labels string, metrics types.OperationMetrics) {
+		for _, bucket := range metrics.Latency.Buckets {
+			bucketLabels := labels + `,le="` + formatFloat(bucket.UpperBound.Seconds()) + `"`
+			writeSample(w, "storage_operation_latency_seconds_bucket", bucketLabels, formatUint(bucket.Count))
+		}
+		writeSample(w, "storage_operation_latency_seconds_bucket", labels+`,le="+Inf"`, formatUint(metrics.Latency.Count))
+		writeSample(w, "storage_operation_latency_seconds_sum", labels, formatFloat(metrics.Latency.Sum.Seconds()))
+		writeSample(w, "storage_operation_latency_seconds_count", labels, formatUint(metrics.Latency.Count))
+	})
+}
+
+func forEachOperation(units []types.UnitMetrics, handler func(labels string, metrics types.OperationMetrics)) {
+	for _, unit := range units {
+		for _, operation := range sortedOperations(unit.Operations) {
+			labels := fmt.Sprintf(`unit="%s",kind="%s",operation="%s"`,
+				escapeLabelValue(unit.Name),
+				escapeLabelValue(string(unit.Kind)),
+				escapeLabelValue(operation),
+			)
+			handler(labels, unit.Operations[operation])
+		}
+	}
+}
+
+func sortedOperations(operations map[string]types.OperationMetrics) []string {
+	names := make([]string, 0, len(operations))
+	for name := range operations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func writeHeader(w io.Writer, name string, metricType string, help string) {
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+func writeSample(w io.Writer, name string, labels string, value string) {
+	_, _ = fmt.Fprintf(w, "%s{%s} %s\n", name, labels, value)
+}
+
+func cacheLabels(name string) string {
+	return `cache="` + escapeLabelValue(name) + `"`
+}
+
+func escapeLabelValue(value string) string {
+	return labelValueReplacer.Replace(value)
+}
+
+func hitRatio(numHits uint64, numMisses uint64) float64 {
+	numLookups := numHits + numMisses
+	if numLookups == 0 {
+		return 0
+	}
+
+	return float64(numHits) / float64(numLookups)
+}
+
+func formatUint(value uint64) string {
+	return strconv.FormatUint(value, 10)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (handler *prometheusHandler) IsInterfaceNil() bool {
+	return handler == nil
+}
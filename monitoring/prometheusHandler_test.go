@@ -0,0 +1,93 @@
+package monitoring_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/monitoring"
+	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPrometheusHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil registry should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler, err := monitoring.NewPrometheusHandler(nil)
+		assert.True(t, check.IfNil(handler))
+		assert.Equal(t, common.ErrNilMetricsRegistry, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		handler, err := monitoring.NewPrometheusHandler(monitoring.DefaultRegistry())
+		assert.False(t, check.IfNil(handler))
+		assert.Nil(t, err)
+	})
+}
+
+func TestPrometheusHandler_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	r, _ := monitoring.NewRegistry(monitoring.NewInMemoryExporter())
+	_ = r.RegisterCache(`txs "shard"`, &cacheStatsProviderStub{
+		stats: types.CacheStats{
+			NumItems:     10,
+			SizeInBytes:  2048,
+			NumHits:      3,
+			NumMisses:    1,
+			NumEvictions: 7,
+		},
+	})
+	recorder, _ := r.RegisterUnit("blocks", types.PersisterUnit)
+	recorder.Record("Get", types.HitOutcome, time.Microsecond*20)
+	recorder.Record("Get", types.ErrorOutcome, time.Second*3)
+
+	handler, _ := monitoring.NewPrometheusHandler(r)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	response, err := http.Get(server.URL)
+	require.Nil(t, err)
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", response.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(response.Body)
+	require.Nil(t, err)
+	output := string(body)
+
+	expectedLines := []string{
+		"# TYPE storage_cache_items gauge",
+		`storage_cache_items{cache="txs \"shard\""} 10`,
+		`storage_cache_size_bytes{cache="txs \"shard\""} 2048`,
+		`storage_cache_hits_total{cache="txs \"shard\""} 3`,
+		`storage_cache_misses_total{cache="txs \"shard\""} 1`,
+		`storage_cache_evictions_total{cache="txs \"shard\""} 7`,
+		`storage_cache_hit_ratio{cache="txs \"shard\""} 0.75`,
+		"# TYPE storage_operation_latency_seconds histogram",
+		`storage_operations_total{unit="blocks",kind="persister",operation="Get"} 2`,
+		`storage_operation_hits_total{unit="blocks",kind="persister",operation="Get"} 1`,
+		`storage_operation_errors_total{unit="blocks",kind="persister",operation="Get"} 1`,
+		`storage_operation_latency_seconds_bucket{unit="blocks",kind="persister",operation="Get",le="1e-05"} 0`,
+		`storage_operation_latency_seconds_bucket{unit="blocks",kind="persister",operation="Get",le="5e-05"} 1`,
+		`storage_operation_latency_seconds_bucket{unit="blocks",kind="persister",operation="Get",le="1"} 1`,
+		`storage_operation_latency_seconds_bucket{unit="blocks",kind="persister",operation="Get",le="+Inf"} 2`,
+		`storage_operation_latency_seconds_sum{unit="blocks",kind="persister",operation="Get"} 3.00002`,
+		`storage_operation_latency_seconds_count{unit="blocks",kind="persister",operation="Get"} 2`,
+	}
+	for _, line := range expectedLines {
+		assert.Contains(t, output, line+"\n")
+	}
+}
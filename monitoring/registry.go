@@ -0,0 +1,151 @@
+package monitoring
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+var _ types.MetricsRegistry = (*registry)(nil)
+
+// registry holds the metrics of all the monitored units and caches and pushes them to the configured exporter
+type registry struct {
+	exporter  types.MetricsExporter
+	mutUnits  sync.RWMutex
+	units     map[string]*unitMetrics
+	mutCaches sync.RWMutex
+	caches    map[string]types.CacheStatsProvider
+}
+
+// NewRegistry creates a new metrics registry that will publish the metrics through the provided exporter
+func NewRegistry(exporter types.MetricsExporter) (*registry, error) {
+	if check.IfNil(exporter) {
+		return nil, common.ErrNilMetricsExporter
+	}
+
+	return &registry{
+		exporter: exporter,
+		units:    make(map[string]*unitMetrics),
+		caches:   make(map[string]types.CacheStatsProvider),
+	}, nil
+}
+
+// RegisterUnit creates the metrics holder of a new monitored unit
+func (r *registry) RegisterUnit(name string, kind types.UnitKind) (types.OperationRecorder, error) {
+	if len(name) == 0 {
+		return nil, common.ErrEmptyUnitName
+	}
+
+	r.mutUnits.Lock()
+	defer r.mutUnits.Unlock()
+
+	_, exists := r.units[name]
+	if exists {
+		return nil, common.ErrUnitAlreadyRegistered
+	}
+
+	unit := newUnitMetrics(name, kind)
+	r.units[name] = unit
+
+	return unit, nil
+}
+
+// UnregisterUnit removes the metrics of the provided unit
+func (r *registry) UnregisterUnit(name string) {
+	r.mutUnits.Lock()
+	delete(r.units, name)
+	r.mutUnits.Unlock()
+}
+
+// RegisterCache adds the cache in the registry. The cache under an existing name has to be unregistered first.
+func (r *registry) RegisterCache(name string, cache types.CacheStatsProvider) error {
+	if len(name) == 0 {
+		return common.ErrEmptyUnitName
+	}
+	if check.IfNil(cache) {
+		return common.ErrNilCacheStatsProvider
+	}
+
+	r.mutCaches.Lock()
+	defer r.mutCaches.Unlock()
+
+	_, exists := r.caches[name]
+	if exists {
+		return common.ErrCacheAlreadyRegistered
+	}
+
+	r.caches[name] = cache
+
+	return nil
+}
+
+// UnregisterCache removes the cache from the registry
+func (r *registry) UnregisterCache(name string) {
+	r.mutCaches.Lock()
+	delete(r.caches, name)
+	r.mutCaches.Unlock()
+}
+
+// Snapshot returns the current metrics of all the registered units and caches, sorted by name
+func (r *registry) Snapshot() types.MetricsSnapshot {
+	return types.MetricsSnapshot{
+		Units:  r.unitsSnapshot(),
+		Caches: r.cachesSnapshot(),
+	}
+}
+
+func (r *registry) unitsSnapshot() []types.UnitMetrics {
+	r.mutUnits.RLock()
+	units := make([]*unitMetrics, 0, len(r.units))
+	for _, unit := range r.units {
+		units = append(units, unit)
+	}
+	r.mutUnits.RUnlock()
+
+	sort.Slice(units, func(i, j int) bool {
+		return units[i].name < units[j].name
+	})
+
+	snapshots := make([]types.UnitMetrics, 0, len(units))
+	for _, unit := range units {
+		snapshots = append(snapshots, unit.snapshot())
+	}
+
+	return snapshots
+}
+
+func (r *registry) cachesSnapshot() []types.CacheMetrics {
+	r.mutCaches.RLock()
+	caches := make(map[string]types.CacheStatsProvider, len(r.caches))
+	for name, cache := range r.caches {
+		caches[name] = cache
+	}
+	r.mutCaches.RUnlock()
+
+	snapshots := make([]types.CacheMetrics, 0, len(caches))
+	for name, cache := range caches {
+		snapshots = append(snapshots, types.CacheMetrics{
+			Name:  name,
+			Stats: cache.CacheStats(),
+		})
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Name < snapshots[j].Name
+	})
+
+	return snapshots
+}
+
+// Export publishes the current metrics through the exporter
+func (r *registry) Export() error {
+	return r.exporter.Export(r.Snapshot())
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (r *registry) IsInterfaceNil() bool {
+	return r == nil
+}
@@ -0,0 +1,176 @@
+package monitoring_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/monitoring"
+	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRegistry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil exporter should error", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := monitoring.NewRegistry(nil)
+		assert.True(t, check.IfNil(r))
+		assert.Equal(t, common.ErrNilMetricsExporter, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := monitoring.NewRegistry(monitoring.NewInMemoryExporter())
+		assert.False(t, check.IfNil(r))
+		assert.Nil(t, err)
+	})
+}
+
+func TestRegistry_RegisterUnit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty name should error", func(t *testing.T) {
+		t.Parallel()
+
+		r, _ := monitoring.NewRegistry(monitoring.NewInMemoryExporter())
+		recorder, err := r.RegisterUnit("", types.StorerUnit)
+		assert.Nil(t, recorder)
+		assert.Equal(t, common.ErrEmptyUnitName, err)
+	})
+	t.Run("duplicated name should error", func(t *testing.T) {
+		t.Parallel()
+
+		r, _ := monitoring.NewRegistry(monitoring.NewInMemoryExporter())
+		_, _ = r.RegisterUnit("unit", types.StorerUnit)
+		recorder, err := r.RegisterUnit("unit", types.CacherUnit)
+		assert.Nil(t, recorder)
+		assert.Equal(t, common.ErrUnitAlreadyRegistered, err)
+	})
+	t.Run("name should be reusable after unregister", func(t *testing.T) {
+		t.Parallel()
+
+		r, _ := monitoring.NewRegistry(monitoring.NewInMemoryExporter())
+		_, _ = r.RegisterUnit("unit", types.StorerUnit)
+		r.UnregisterUnit("unit")
+		assert.Empty(t, r.Snapshot().Units)
+
+		recorder, err := r.RegisterUnit("unit", types.StorerUnit)
+		assert.False(t, check.IfNil(recorder))
+		assert.Nil(t, err)
+	})
+}
+
+func TestRegistry_RecordAndExport(t *testing.T) {
+	t.Parallel()
+
+	exporter := monitoring.NewInMemoryExporter()
+	r, _ := monitoring.NewRegistry(exporter)
+	recorderB, _ := r.RegisterUnit("b", types.CacherUnit)
+	recorderA, _ := r.RegisterUnit("a", types.StorerUnit)
+
+	recorderA.Record("Get", types.HitOutcome, time.Microsecond*5)
+	recorderA.Record("Get", types.MissOutcome, time.Millisecond*3)
+	recorderA.Record("Get", types.ErrorOutcome, time.Second*2)
+	recorderA.Record("Put", types.SuccessOutcome, time.Microsecond*20)
+	recorderB.Record("Get", types.HitOutcome, time.Microsecond)
+
+	err := r.Export()
+	require.Nil(t, err)
+	assert.Equal(t, 1, exporter.NumExports())
+
+	metrics := exporter.LastExport().Units
+	require.Equal(t, 2, len(metrics))
+	assert.Equal(t, "a", metrics[0].Name)
+	assert.Equal(t, types.StorerUnit, metrics[0].Kind)
+	assert.Equal(t, "b", metrics[1].Name)
+	assert.Equal(t, types.CacherUnit, metrics[1].Kind)
+
+	getMetrics := metrics[0].Operations["Get"]
+	assert.Equal(t, uint64(3), getMetrics.NumCalls)
+	assert.Equal(t, uint64(1), getMetrics.NumHits)
+	assert.Equal(t, uint64(1), getMetrics.NumMisses)
+	assert.Equal(t, uint64(1), getMetrics.NumErrors)
+	assert.Equal(t, uint64(3), getMetrics.Latency.Count)
+	assert.Equal(t, time.Microsecond*5+time.Millisecond*3+time.Second*2, getMetrics.Latency.Sum)
+
+	buckets := getMetrics.Latency.Buckets
+	assert.Equal(t, time.Microsecond*10, buckets[0].UpperBound)
+	assert.Equal(t, uint64(1), buckets[0].Count)
+	assert.Equal(t, time.Millisecond*5, buckets[5].UpperBound)
+	assert.Equal(t, uint64(2), buckets[5].Count)
+	assert.Equal(t, uint64(2), buckets[len(buckets)-1].Count)
+
+	putMetrics := metrics[0].Operations["Put"]
+	assert.Equal(t, uint64(1), putMetrics.NumCalls)
+	assert.Zero(t, putMetrics.NumHits+putMetrics.NumMisses+putMetrics.NumErrors)
+}
+
+type cacheStatsProviderStub struct {
+	stats types.CacheStats
+}
+
+func (stub *cacheStatsProviderStub) CacheStats() types.CacheStats {
+	return stub.stats
+}
+
+func (stub *cacheStatsProviderStub) IsInterfaceNil() bool {
+	return stub == nil
+}
+
+func TestRegistry_RegisterCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty name should error", func(t *testing.T) {
+		t.Parallel()
+
+		r, _ := monitoring.NewRegistry(monitoring.NewInMemoryExporter())
+		err := r.RegisterCache("", &cacheStatsProviderStub{})
+		assert.Equal(t, common.ErrEmptyUnitName, err)
+	})
+	t.Run("nil cache should error", func(t *testing.T) {
+		t.Parallel()
+
+		r, _ := monitoring.NewRegistry(monitoring.NewInMemoryExporter())
+		err := r.RegisterCache("cache", nil)
+		assert.Equal(t, common.ErrNilCacheStatsProvider, err)
+	})
+	t.Run("same name should error", func(t *testing.T) {
+		t.Parallel()
+
+		r, _ := monitoring.NewRegistry(monitoring.NewInMemoryExporter())
+		_ = r.RegisterCache("cache", &cacheStatsProviderStub{stats: types.CacheStats{NumItems: 1}})
+		_ = r.RegisterCache("another cache", &cacheStatsProviderStub{stats: types.CacheStats{NumItems: 3}})
+		err := r.RegisterCache("cache", &cacheStatsProviderStub{stats: types.CacheStats{NumItems: 2}})
+		assert.Equal(t, common.ErrCacheAlreadyRegistered, err)
+
+		expectedCaches := []types.CacheMetrics{
+			{Name: "another cache", Stats: types.CacheStats{NumItems: 3}},
+			{Name: "cache", Stats: types.CacheStats{NumItems: 1}},
+		}
+		assert.Equal(t, expectedCaches, r.Snapshot().Caches)
+	})
+	t.Run("unregistered name should be registered again", func(t *testing.T) {
+		t.Parallel()
+
+		r, _ := monitoring.NewRegistry(monitoring.NewInMemoryExporter())
+		_ = r.RegisterCache("cache", &cacheStatsProviderStub{stats: types.CacheStats{NumItems: 1}})
+		_ = r.RegisterCache("another cache", &cacheStatsProviderStub{stats: types.CacheStats{NumItems: 3}})
+
+		r.UnregisterCache("cache")
+		assert.Equal(t, []types.CacheMetrics{{Name: "another cache", Stats: types.CacheStats{NumItems: 3}}}, r.Snapshot().Caches)
+
+		err := r.RegisterCache("cache", &cacheStatsProviderStub{stats: types.CacheStats{NumItems: 2}})
+		assert.Nil(t, err)
+
+		expectedCaches := []types.CacheMetrics{
+			{Name: "another cache", Stats: types.CacheStats{NumItems: 3}},
+			{Name: "cache", Stats: types.CacheStats{NumItems: 2}},
+		}
+		assert.Equal(t, expectedCaches, r.Snapshot().Caches)
+	})
+}
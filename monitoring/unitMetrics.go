@@ -0,0 +1,100 @@
+package monitoring
+
+import (
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/atomic"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+var _ types.OperationRecorder = (*unitMetrics)(nil)
+
+type operationMetrics struct {
+	numCalls  atomic.Counter
+	numHits   atomic.Counter
+	numMisses atomic.Counter
+	numErrors atomic.Counter
+	latency   *latencyHistogram
+}
+
+// unitMetrics holds the per-operation metrics of a monitored unit
+type unitMetrics struct {
+	name          string
+	kind          types.UnitKind
+	mutOperations sync.RWMutex
+	operations    map[string]*operationMetrics
+}
+
+func newUnitMetrics(name string, kind types.UnitKind) *unitMetrics {
+	return &unitMetrics{
+		name:       name,
+		kind:       kind,
+		operations: make(map[string]*operationMetrics),
+	}
+}
+
+// Record accounts the outcome and the latency of an operation
+func (um *unitMetrics) Record(operation string, outcome types.OperationOutcome, duration time.Duration) {
+	metrics := um.getOrCreateOperation(operation)
+
+	metrics.numCalls.Increment()
+	switch outcome {
+	case types.HitOutcome:
+		metrics.numHits.Increment()
+	case types.MissOutcome:
+		metrics.numMisses.Increment()
+	case types.ErrorOutcome:
+		metrics.numErrors.Increment()
+	}
+	metrics.latency.observe(duration)
+}
+
+func (um *unitMetrics) getOrCreateOperation(operation string) *operationMetrics {
+	um.mutOperations.RLock()
+	metrics, exists := um.operations[operation]
+	um.mutOperations.RUnlock()
+	if exists {
+		return metrics
+	}
+
+	um.mutOperations.Lock()
+	defer um.mutOperations.Unlock()
+
+	metrics, exists = um.operations[operation]
+	if !exists {
+		metrics = &operationMetrics{
+			latency: newLatencyHistogram(),
+		}
+		um.operations[operation] = metrics
+	}
+
+	return metrics
+}
+
+func (um *unitMetrics) snapshot() types.UnitMetrics {
+	um.mutOperations.RLock()
+	defer um.mutOperations.RUnlock()
+
+	snapshot := types.UnitMetrics{
+		Name:       um.name,
+		Kind:       um.kind,
+		Operations: make(map[string]types.OperationMetrics, len(um.operations)),
+	}
+	for operation, metrics := range um.operations {
+		snapshot.Operations[operation] = types.OperationMetrics{
+			NumCalls:  metrics.numCalls.GetUint64(),
+			NumHits:   metrics.numHits.GetUint64(),
+			NumMisses: metrics.numMisses.GetUint64(),
+			NumErrors: metrics.numErrors.GetUint64(),
+			Latency:   metrics.latency.snapshot(),
+		}
+	}
+
+	return snapshot
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (um *unitMetrics) IsInterfaceNil() bool {
+	return um == nil
+}
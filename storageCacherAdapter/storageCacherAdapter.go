@@ -248,12 +248,13 @@ func (c *storageCacherAdapter) Len() int {
 	return cacheLen + c.numValuesInStorage
 }
 
-// SizeInBytesContained returns the number of bytes stored in the cache
+// SizeInBytesContained returns the number of bytes stored in the cache, including the keys and the overhead of the
+// entries
 func (c *storageCacherAdapter) SizeInBytesContained() uint64 {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
 
-	return c.cacher.SizeInBytesContained()
+	return c.cacher.EntriesSizeInBytes()
 }
 
 // MaxSize returns MaxInt64
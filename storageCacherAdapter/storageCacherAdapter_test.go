@@ -7,6 +7,8 @@ import (
 
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/lrucache/capacity"
+	"github.com/multiversx/mx-chain-storage-go/memorydb"
 	storageMock "github.com/multiversx/mx-chain-storage-go/testscommon"
 	"github.com/multiversx/mx-chain-storage-go/testscommon/trieFactory"
 	"github.com/stretchr/testify/assert"
@@ -620,7 +622,7 @@ func TestStorageCacherAdapter_SizeInBytesContained(t *testing.T) {
 	_ = db.Put([]byte("key"), []byte("val"))
 	sca, err := NewStorageCacherAdapter(
 		&storageMock.AdaptedSizedLruCacheStub{
-			SizeInBytesContainedCalled: func() uint64 {
+			EntriesSizeInBytesCalled: func() uint64 {
 				return 1000
 			},
 		},
@@ -695,3 +697,27 @@ func TestStorageCacherAdapter_Close(t *testing.T) {
 	_ = sca.Close()
 	assert.True(t, closeCalled)
 }
+
+func TestStorageCacherAdapter_WithShardedCapacityLRU(t *testing.T) {
+	t.Parallel()
+
+	cacher, _ := capacity.NewShardedCapacityLRU(2, 10, 1000)
+	db := memorydb.New()
+	sca, err := NewStorageCacherAdapter(
+		cacher,
+		db,
+		trieFactory.NewTrieNodeFactory(),
+		&storageMock.MarshalizerMock{},
+	)
+	require.Nil(t, err)
+
+	numValues := 30
+	for i := 0; i < numValues; i++ {
+		_ = sca.Put([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i)), 10)
+	}
+
+	assert.Equal(t, numValues, cacher.Len()+sca.numValuesInStorage)
+	for i := 0; i < numValues; i++ {
+		assert.True(t, sca.Has([]byte(fmt.Sprintf("key%d", i))))
+	}
+}
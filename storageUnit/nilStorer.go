@@ -6,7 +6,8 @@ import (
 	"github.com/multiversx/mx-chain-storage-go/types"
 )
 
-var _ types.Storer = (*NilStorer)(nil)
+var _ types.StorerWithBulkGet = (*NilStorer)(nil)
+var _ types.StorerWithEpochRange = (*NilStorer)(nil)
 
 // NilStorer resembles a disabled implementation of the Storer interface
 type NilStorer struct {
@@ -27,6 +28,25 @@ func (ns *NilStorer) GetBulkFromEpoch(_ [][]byte, _ uint32) ([]data.KeyValuePair
 	return nil, nil
 }
 
+// GetBulkFromEpochRange will only validate the epoch range
+func (ns *NilStorer) GetBulkFromEpochRange(_ [][]byte, epochRange types.EpochRange) ([]types.EpochKeyValuePair, error) {
+	if epochRange.FirstEpoch > epochRange.LastEpoch {
+		return nil, common.ErrInvalidEpochRange
+	}
+
+	return nil, nil
+}
+
+// GetBulk will return a not found result for each key
+func (ns *NilStorer) GetBulk(keys [][]byte, _ types.GetBulkOptions) []types.KeyValueResult {
+	results := make([]types.KeyValueResult, 0, len(keys))
+	for _, key := range keys {
+		results = append(results, types.KeyValueResult{Key: key, Err: common.ErrKeyNotFound})
+	}
+
+	return results
+}
+
 // SearchFirst will do nothing
 func (ns *NilStorer) SearchFirst(_ []byte) ([]byte, error) {
 	return nil, nil
@@ -85,6 +105,10 @@ func (ns *NilStorer) DestroyUnit() error {
 func (ns *NilStorer) RangeKeys(_ func(key []byte, val []byte) bool) {
 }
 
+// RangeKeysFromEpoch does nothing
+func (ns *NilStorer) RangeKeysFromEpoch(_ uint32, _ func(key []byte, val []byte) bool) {
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (ns *NilStorer) IsInterfaceNil() bool {
 	return ns == nil
@@ -0,0 +1,43 @@
+package storageUnit_test
+
+import (
+	"testing"
+
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/storageUnit"
+	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNilStorer_GetBulkShouldReturnNotFoundResults(t *testing.T) {
+	t.Parallel()
+
+	ns := storageUnit.NewNilStorer()
+	results := ns.GetBulk([][]byte{[]byte("key1"), []byte("key2")}, types.GetBulkOptions{})
+
+	assert.Equal(t, []types.KeyValueResult{
+		{Key: []byte("key1"), Err: common.ErrKeyNotFound},
+		{Key: []byte("key2"), Err: common.ErrKeyNotFound},
+	}, results)
+}
+
+func TestNilStorer_GetBulkFromEpochRange(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid range should error", func(t *testing.T) {
+		t.Parallel()
+
+		ns := storageUnit.NewNilStorer()
+		results, err := ns.GetBulkFromEpochRange([][]byte{[]byte("key")}, types.EpochRange{FirstEpoch: 5, LastEpoch: 4})
+		assert.Nil(t, results)
+		assert.Equal(t, common.ErrInvalidEpochRange, err)
+	})
+	t.Run("valid range should return no results", func(t *testing.T) {
+		t.Parallel()
+
+		ns := storageUnit.NewNilStorer()
+		results, err := ns.GetBulkFromEpochRange([][]byte{[]byte("key")}, types.EpochRange{FirstEpoch: 4, LastEpoch: 5})
+		assert.Nil(t, results)
+		assert.Nil(t, err)
+	})
+}
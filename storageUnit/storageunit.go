@@ -1,27 +1,46 @@
 package storageUnit
 
 import (
+	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"sync"
 
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/multiversx/mx-chain-core-go/data"
 	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-storage-go/changefeed"
 	"github.com/multiversx/mx-chain-storage-go/common"
 	"github.com/multiversx/mx-chain-storage-go/types"
 )
 
-var _ types.Storer = (*Unit)(nil)
+var _ types.StorerWithBulkGet = (*Unit)(nil)
+var _ types.StorerWithEpochRange = (*Unit)(nil)
 
 var log = logger.GetOrCreate("storage/storageUnit")
 
+const defaultGetBulkConcurrency = 8
+
 // Unit represents a storer's data bank
 // holding the cache and persistence unit
 type Unit struct {
-	lock      sync.RWMutex
-	persister types.Persister
-	cacher    types.Cacher
+	lock          sync.RWMutex
+	persister     types.Persister
+	cacher        types.Cacher
+	changeFeed    types.ChangeFeed
+	cacheDumper   types.CacheDumper
+	cancelWarmUp  func()
+	warmUpRunning sync.WaitGroup
+}
+
+// ArgsStorageUnitWithCacheDump holds the arguments needed to create a storage unit whose cache contents
+// are dumped on Close and restored on start
+type ArgsStorageUnitWithCacheDump struct {
+	Cacher      types.Cacher
+	Persister   types.Persister
+	CacheDumper types.CacheDumper
+	WarmUpMode  common.CacheWarmUpMode
 }
 
 // NewStorageUnit is the constructor for the storage unit, creating a new storage unit
@@ -35,15 +54,150 @@ func NewStorageUnit(c types.Cacher, p types.Persister) (*Unit, error) {
 	}
 
 	sUnit := &Unit{
-		persister: p,
-		cacher:    c,
+		persister:  p,
+		cacher:     c,
+		changeFeed: changefeed.NewChangeFeed(),
+	}
+
+	return sUnit, nil
+}
+
+// NewStorageUnitWithCacheDump creates a new storage unit that dumps its cache on Close and warms it up, using the
+// provided mode, from the previous dump. The eager warm-up uses the dumped values, so the persistence medium
+// should not be changed while the unit is closed.
+func NewStorageUnitWithCacheDump(args ArgsStorageUnitWithCacheDump) (*Unit, error) {
+	if check.IfNil(args.CacheDumper) {
+		return nil, common.ErrNilCacheDumper
+	}
+	if !isCacheWarmUpModeSupported(args.WarmUpMode) {
+		return nil, fmt.Errorf("%w: %s", common.ErrNotSupportedCacheWarmUpMode, args.WarmUpMode)
 	}
 
+	sUnit, err := NewStorageUnit(args.Cacher, args.Persister)
+	if err != nil {
+		return nil, err
+	}
+
+	sUnit.cacheDumper = args.CacheDumper
+	sUnit.warmUpCache(args.WarmUpMode)
+
 	return sUnit, nil
 }
 
+func isCacheWarmUpModeSupported(mode common.CacheWarmUpMode) bool {
+	switch mode {
+	case common.NoCacheWarmUp, common.EagerCacheWarmUp, common.LazyCacheWarmUp:
+		return true
+	default:
+		return false
+	}
+}
+
+func (u *Unit) warmUpCache(mode common.CacheWarmUpMode) {
+	if mode == common.NoCacheWarmUp {
+		return
+	}
+
+	entries, err := u.cacheDumper.Load()
+	if err != nil {
+		log.Warn("cannot load the cache dump, the cache will start empty", "error", err)
+		return
+	}
+
+	if mode == common.EagerCacheWarmUp {
+		u.warmUpCacheEagerly(entries)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	u.cancelWarmUp = cancel
+	u.warmUpRunning.Add(1)
+	go u.warmUpCacheLazily(ctx, entries)
+}
+
+func (u *Unit) warmUpCacheEagerly(entries []types.CacheDumpEntry) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	for _, entry := range entries {
+		if entry.Value != nil {
+			u.cacher.Put(entry.Key, entry.Value, len(entry.Value))
+			continue
+		}
+
+		u.loadInCacheNoLock(entry.Key)
+	}
+
+	log.Debug("storage unit cache warmed up", "num keys", len(entries), "cache len", u.cacher.Len())
+}
+
+// the keys are loaded from the oldest to the newest, one at a time, so that the unit stays available
+func (u *Unit) warmUpCacheLazily(ctx context.Context, entries []types.CacheDumpEntry) {
+	defer u.warmUpRunning.Done()
+
+	for _, entry := range entries {
+		select {
+		case <-ctx.Done():
+			log.Debug("storage unit cache warm-up stopped")
+			return
+		default:
+		}
+
+		u.lock.Lock()
+		if !u.cacher.Has(entry.Key) {
+			u.loadInCacheNoLock(entry.Key)
+		}
+		u.lock.Unlock()
+	}
+
+	log.Debug("storage unit cache warmed up", "num keys", len(entries))
+}
+
+func (u *Unit) loadInCacheNoLock(key []byte) {
+	value, err := u.persister.Get(key)
+	if err != nil {
+		// removed from the persistence medium in the meantime
+		return
+	}
+
+	u.cacher.Put(key, value, len(value))
+}
+
+func (u *Unit) stopCacheWarmUp() {
+	if u.cancelWarmUp != nil {
+		u.cancelWarmUp()
+	}
+	u.warmUpRunning.Wait()
+}
+
+func (u *Unit) dumpCache() {
+	if check.IfNil(u.cacheDumper) {
+		return
+	}
+
+	err := u.cacheDumper.Dump(u.cacher)
+	if err != nil {
+		log.Warn("cannot dump the storage unit cache", "error", err)
+	}
+
+	// a second Close would overwrite the dump with the cleared cache
+	u.cacheDumper = nil
+}
+
 // Put adds data to both cache and persistence medium
 func (u *Unit) Put(key, data []byte) error {
+	err := u.putWithLock(key, data)
+	if err != nil {
+		return err
+	}
+
+	// the change is published after releasing the lock, so that a blocking consumer does not stall the unit
+	u.changeFeed.Publish(types.PutOperation, key, data)
+
+	return nil
+}
+
+func (u *Unit) putWithLock(key, data []byte) error {
 	u.lock.Lock()
 	defer u.lock.Unlock()
 
@@ -55,7 +209,7 @@ func (u *Unit) Put(key, data []byte) error {
 		return err
 	}
 
-	return err
+	return nil
 }
 
 // PutInEpoch will call the Put method as this storer doesn't handle epochs
@@ -68,9 +222,12 @@ func (u *Unit) GetOldestEpoch() (uint32, error) {
 	return 0, common.ErrOldestEpochNotAvailable
 }
 
-// Close will close unit
+// Close will close unit. The cache is dumped before being cleared, if the unit was created with a cache dumper.
 func (u *Unit) Close() error {
+	u.stopCacheWarmUp()
+	u.dumpCache()
 	u.cacher.Clear()
+	u.changeFeed.Close()
 
 	err := u.persister.Close()
 	if err != nil {
@@ -86,6 +243,11 @@ func (u *Unit) RangeKeys(handler func(key []byte, value []byte) bool) {
 	u.persister.RangeKeys(handler)
 }
 
+// RangeKeysFromEpoch will call the RangeKeys method as this storer doesn't handle epochs
+func (u *Unit) RangeKeysFromEpoch(_ uint32, handler func(key []byte, value []byte) bool) {
+	u.RangeKeys(handler)
+}
+
 // Get searches the key in the cache. In case it is not found,
 // it further searches it in the associated database.
 // In case it is found in the database, the cache is updated with the value as well.
@@ -122,24 +284,153 @@ func (u *Unit) GetFromEpoch(key []byte, _ uint32) ([]byte, error) {
 	return u.Get(key)
 }
 
-// GetBulkFromEpoch will call the Get method for all keys as this storer doesn't handle epochs
+// GetBulkFromEpoch will call the GetBulk method as this storer doesn't handle epochs. The keys that could not
+// be fetched are logged and skipped.
 func (u *Unit) GetBulkFromEpoch(keys [][]byte, _ uint32) ([]data.KeyValuePair, error) {
+	bulkResults := u.GetBulk(keys, types.GetBulkOptions{KeepOrder: true})
+
 	results := make([]data.KeyValuePair, 0, len(keys))
-	for _, key := range keys {
-		value, err := u.Get(key)
-		if err != nil {
+	for _, result := range bulkResults {
+		if result.Err != nil {
 			log.Warn("cannot get key from unit",
-				"key", key,
-				"error", err.Error(),
+				"key", result.Key,
+				"error", result.Err.Error(),
 			)
 			continue
 		}
-		keyValue := data.KeyValuePair{Key: key, Value: value}
+		keyValue := data.KeyValuePair{Key: result.Key, Value: result.Value}
 		results = append(results, keyValue)
 	}
 	return results, nil
 }
 
+// GetBulkFromEpochRange searches the keys in the provided epoch range. As this storer doesn't handle epochs,
+// each found key is reported as coming from the first epoch of the search order. The keys that are not found are skipped.
+func (u *Unit) GetBulkFromEpochRange(keys [][]byte, epochRange types.EpochRange) ([]types.EpochKeyValuePair, error) {
+	if epochRange.FirstEpoch > epochRange.LastEpoch {
+		return nil, common.ErrInvalidEpochRange
+	}
+
+	epoch := epochRange.LastEpoch
+	if epochRange.Order == types.OldestEpochFirst {
+		epoch = epochRange.FirstEpoch
+	}
+
+	bulkResults := u.GetBulk(keys, types.GetBulkOptions{KeepOrder: true})
+
+	results := make([]types.EpochKeyValuePair, 0, len(keys))
+	for _, result := range bulkResults {
+		if errors.Is(result.Err, common.ErrKeyNotFound) {
+			continue
+		}
+		if result.Err != nil {
+			log.Warn("cannot get key from unit",
+				"key", result.Key,
+				"error", result.Err.Error(),
+			)
+			continue
+		}
+
+		results = append(results, types.EpochKeyValuePair{Key: result.Key, Value: result.Value, Epoch: epoch})
+	}
+
+	return results, nil
+}
+
+// GetBulk returns the outcome of the read operation for each of the provided keys. The keys are first searched
+// in the cache and the misses are fetched from the persistence medium, either with a native multi-get, if the
+// persister supports it, or concurrently. The found values are added in the cache in one pass.
+// The whole operation is done under the unit's lock so all the writes done before the call are visible.
+func (u *Unit) GetBulk(keys [][]byte, options types.GetBulkOptions) []types.KeyValueResult {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	results := make([]types.KeyValueResult, 0, len(keys))
+	missingKeys := make([][]byte, 0, len(keys))
+	missingIndexes := make([]int, 0, len(keys))
+	for i, key := range keys {
+		v, ok := u.cacher.Get(key)
+		buff, isBuff := v.([]byte)
+		if ok && isBuff {
+			results = append(results, types.KeyValueResult{Key: key, Value: buff})
+			continue
+		}
+
+		// the result will be filled after reading the persistence medium
+		results = append(results, types.KeyValueResult{Key: key})
+		missingKeys = append(missingKeys, key)
+		missingIndexes = append(missingIndexes, i)
+	}
+
+	fetchedResults := u.fetchFromPersister(missingKeys, options.MaxConcurrency)
+
+	for _, result := range fetchedResults {
+		if result.Err == nil {
+			u.cacher.Put(result.Key, result.Value, len(result.Value))
+		}
+	}
+
+	if !options.KeepOrder {
+		return append(removeIndexes(results, missingIndexes), fetchedResults...)
+	}
+
+	for i, index := range missingIndexes {
+		results[index] = fetchedResults[i]
+	}
+
+	return results
+}
+
+func (u *Unit) fetchFromPersister(keys [][]byte, maxConcurrency int) []types.KeyValueResult {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	multiGetter, ok := u.persister.(types.PersisterWithMultiGet)
+	if ok {
+		return multiGetter.MultiGet(keys)
+	}
+
+	if maxConcurrency <= 0 {
+		maxConcurrency = defaultGetBulkConcurrency
+	}
+
+	results := make([]types.KeyValueResult, len(keys))
+	throttler := make(chan struct{}, maxConcurrency)
+	wg := &sync.WaitGroup{}
+	wg.Add(len(keys))
+	for i := range keys {
+		throttler <- struct{}{}
+
+		go func(index int) {
+			value, err := u.persister.Get(keys[index])
+			results[index] = types.KeyValueResult{Key: keys[index], Value: value, Err: err}
+
+			<-throttler
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// removeIndexes removes in place the elements found at the provided (sorted) indexes
+func removeIndexes(results []types.KeyValueResult, indexes []int) []types.KeyValueResult {
+	filtered := results[:0]
+	nextIndexToRemove := 0
+	for i, result := range results {
+		if nextIndexToRemove < len(indexes) && indexes[nextIndexToRemove] == i {
+			nextIndexToRemove++
+			continue
+		}
+
+		filtered = append(filtered, result)
+	}
+
+	return filtered
+}
+
 // Has checks if the key is in the Unit.
 // It first checks the cache. If it is not found, it checks the db
 func (u *Unit) Has(key []byte) error {
@@ -166,13 +457,31 @@ func (u *Unit) RemoveFromCurrentEpoch(key []byte) error {
 
 // Remove removes the data associated to the given key from both cache and persistence medium
 func (u *Unit) Remove(key []byte) error {
+	sequenceNumber, err := u.removeWithLock(key)
+	if err != nil {
+		return err
+	}
+
+	// the change is published after releasing the lock, so that a blocking consumer does not stall the unit, in the
+	// order reserved under the lock
+	u.changeFeed.PublishReserved(sequenceNumber, types.RemoveOperation, key, nil)
+
+	return nil
+}
+
+// removeWithLock returns the sequence number reserved for publishing the change, if it was applied
+func (u *Unit) removeWithLock(key []byte) (uint64, error) {
 	u.lock.Lock()
 	defer u.lock.Unlock()
 
 	u.cacher.Remove(key)
+
 	err := u.persister.Remove(key)
+	if err != nil {
+		return 0, err
+	}
 
-	return err
+	return u.changeFeed.ReserveSequenceNumber(), nil
 }
 
 // ClearCache cleans up the entire cache
@@ -189,6 +498,17 @@ func (u *Unit) DestroyUnit() error {
 	return u.persister.Destroy()
 }
 
+// Subscribe registers a new consumer of the changes persisted through this unit. The Put and Remove events are delivered
+// in the order they were applied. A consumer using the common.BlockPolicy will stall the unit's writes while its buffer is full.
+func (u *Unit) Subscribe(config common.SubscriptionConfig) (types.ChangeSubscription, error) {
+	return u.changeFeed.Subscribe(config)
+}
+
+// Unsubscribe removes the consumer with the provided ID, closing its events channel
+func (u *Unit) Unsubscribe(id string) {
+	u.changeFeed.Unsubscribe(id)
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (u *Unit) IsInterfaceNil() bool {
 	return u == nil
@@ -1,15 +1,27 @@
 package storageUnit_test
 
 import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/multiversx/mx-chain-storage-go/cachedump"
 	"github.com/multiversx/mx-chain-storage-go/common"
 	"github.com/multiversx/mx-chain-storage-go/lrucache"
 	"github.com/multiversx/mx-chain-storage-go/memorydb"
 	"github.com/multiversx/mx-chain-storage-go/storageUnit"
+	"github.com/multiversx/mx-chain-storage-go/testscommon"
+	"github.com/multiversx/mx-chain-storage-go/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+var expectedErr = errors.New("expected error")
+
 func initStorageUnit(tb testing.TB, cSize int) *storageUnit.Unit {
 	mdb := memorydb.New()
 	cache, err2 := lrucache.NewCache(cSize)
@@ -261,3 +273,467 @@ func TestDestroyUnitNoError(t *testing.T) {
 	err := s.DestroyUnit()
 	assert.Nil(t, err, "no error expected, but got %s", err)
 }
+
+func TestStorageUnit_SubscribeShouldReceivePersistedChanges(t *testing.T) {
+	t.Parallel()
+
+	s := initStorageUnit(t, 10)
+	sub, err := s.Subscribe(common.SubscriptionConfig{
+		ID:                 "indexer",
+		BufferSize:         10,
+		SlowConsumerPolicy: common.DropEventsPolicy,
+	})
+	assert.Nil(t, err)
+
+	_ = s.Put([]byte("key1"), []byte("value1"))
+	_ = s.Put([]byte("key2"), []byte("value2"))
+	_ = s.Remove([]byte("key1"))
+
+	event := <-sub.Events()
+	assert.Equal(t, uint64(1), event.SequenceNumber)
+	assert.Equal(t, types.PutOperation, event.Operation)
+	assert.Equal(t, []byte("key1"), event.Key)
+	assert.Equal(t, []byte("value1"), event.Value)
+
+	event = <-sub.Events()
+	assert.Equal(t, uint64(2), event.SequenceNumber)
+	assert.Equal(t, []byte("key2"), event.Key)
+
+	event = <-sub.Events()
+	assert.Equal(t, uint64(3), event.SequenceNumber)
+	assert.Equal(t, types.RemoveOperation, event.Operation)
+	assert.Equal(t, []byte("key1"), event.Key)
+
+	s.Unsubscribe("indexer")
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+}
+
+func TestStorageUnit_ConcurrentChangesOnTheSameKeyShouldBePublishedInTheAppliedOrder(t *testing.T) {
+	t.Parallel()
+
+	numWriters := 10
+	numChangesPerWriter := 100
+	s := initStorageUnit(t, 10)
+	sub, _ := s.Subscribe(common.SubscriptionConfig{
+		ID:                 "replica",
+		BufferSize:         uint32(numWriters * numChangesPerWriter),
+		SlowConsumerPolicy: common.BlockPolicy,
+	})
+
+	key := []byte("key")
+	wg := sync.WaitGroup{}
+	wg.Add(numWriters)
+	for i := 0; i < numWriters; i++ {
+		go func(writer int) {
+			defer wg.Done()
+
+			for j := 0; j < numChangesPerWriter; j++ {
+				if j%3 == 2 {
+					_ = s.Remove(key)
+					continue
+				}
+				_ = s.Put(key, []byte(fmt.Sprintf("value-%d-%d", writer, j)))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var replayed []byte
+	for i := 0; i < numWriters*numChangesPerWriter; i++ {
+		event := <-sub.Events()
+		require.Equal(t, uint64(i+1), event.SequenceNumber)
+		replayed = event.Value
+	}
+
+	stored, err := s.Get(key)
+	if replayed == nil {
+		assert.NotNil(t, err)
+		return
+	}
+	assert.Nil(t, err)
+	assert.Equal(t, replayed, stored)
+}
+
+func TestStorageUnit_BlockingConsumerShouldBeAbleToReadFromTheUnit(t *testing.T) {
+	t.Parallel()
+
+	s := initStorageUnit(t, 10)
+	sub, _ := s.Subscribe(common.SubscriptionConfig{
+		ID:                 "reader",
+		BufferSize:         1,
+		SlowConsumerPolicy: common.BlockPolicy,
+	})
+
+	numPuts := 10
+	readValues := make(chan []byte, numPuts)
+	go func() {
+		for event := range sub.Events() {
+			value, err := s.Get(event.Key)
+			assert.Nil(t, err)
+			readValues <- value
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < numPuts; i++ {
+			_ = s.Put([]byte{byte(i)}, []byte{byte(i), byte(i)})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		require.Fail(t, "the unit has been stalled by the blocking consumer")
+	}
+
+	for i := 0; i < numPuts; i++ {
+		assert.Equal(t, []byte{byte(i), byte(i)}, <-readValues)
+	}
+	_ = s.Close()
+}
+
+func TestStorageUnit_FailedPutShouldNotPublish(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := lrucache.NewCache(10)
+	persister := &testscommon.PersisterStub{
+		PutCalled: func(key, val []byte) error {
+			return expectedErr
+		},
+	}
+	s, _ := storageUnit.NewStorageUnit(cache, persister)
+	sub, _ := s.Subscribe(common.SubscriptionConfig{
+		ID:                 "indexer",
+		BufferSize:         10,
+		SlowConsumerPolicy: common.DropEventsPolicy,
+	})
+
+	err := s.Put([]byte("key"), []byte("value"))
+	assert.Equal(t, expectedErr, err)
+
+	_ = s.Close()
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+}
+
+func TestStorageUnit_GetBulk(t *testing.T) {
+	t.Parallel()
+
+	t.Run("native multi get, ordered", func(t *testing.T) {
+		t.Parallel()
+
+		cache, _ := lrucache.NewCache(10)
+		s, _ := storageUnit.NewStorageUnit(cache, memorydb.New())
+		_ = s.Put([]byte("key1"), []byte("value1"))
+		_ = s.Put([]byte("key2"), []byte("value2"))
+		cache.Remove([]byte("key2"))
+
+		keys := [][]byte{[]byte("key2"), []byte("missing"), []byte("key1")}
+		results := s.GetBulk(keys, types.GetBulkOptions{KeepOrder: true})
+		assert.Equal(t, 3, len(results))
+
+		assert.Equal(t, []byte("key2"), results[0].Key)
+		assert.Equal(t, []byte("value2"), results[0].Value)
+		assert.Nil(t, results[0].Err)
+
+		assert.Equal(t, []byte("missing"), results[1].Key)
+		assert.Nil(t, results[1].Value)
+		assert.True(t, errors.Is(results[1].Err, common.ErrKeyNotFound))
+
+		assert.Equal(t, []byte("key1"), results[2].Key)
+		assert.Equal(t, []byte("value1"), results[2].Value)
+		assert.Nil(t, results[2].Err)
+
+		assert.True(t, cache.Has([]byte("key2")))
+	})
+	t.Run("concurrent fetches, unordered", func(t *testing.T) {
+		t.Parallel()
+
+		cache, _ := lrucache.NewCache(10)
+		persister := &testscommon.PersisterStub{
+			GetCalled: func(key []byte) ([]byte, error) {
+				if string(key) == "failing" {
+					return nil, expectedErr
+				}
+
+				return append([]byte("value-"), key...), nil
+			},
+		}
+		s, _ := storageUnit.NewStorageUnit(cache, persister)
+		cache.Put([]byte("cached"), []byte("cached value"), 0)
+
+		keys := [][]byte{[]byte("key1"), []byte("failing"), []byte("cached"), []byte("key2")}
+		results := s.GetBulk(keys, types.GetBulkOptions{MaxConcurrency: 2})
+		assert.Equal(t, 4, len(results))
+
+		assert.Equal(t, []byte("cached"), results[0].Key)
+		assert.Equal(t, []byte("cached value"), results[0].Value)
+		assert.Equal(t, []byte("value-key1"), results[1].Value)
+		assert.Equal(t, expectedErr, results[2].Err)
+		assert.Equal(t, []byte("value-key2"), results[3].Value)
+
+		assert.True(t, cache.Has([]byte("key1")))
+		assert.True(t, cache.Has([]byte("key2")))
+		assert.False(t, cache.Has([]byte("failing")))
+	})
+}
+
+func TestStorageUnit_GetBulkFromEpochShouldSkipFailedKeys(t *testing.T) {
+	t.Parallel()
+
+	s := initStorageUnit(t, 10)
+	_ = s.Put([]byte("key1"), []byte("value1"))
+
+	results, err := s.GetBulkFromEpoch([][]byte{[]byte("missing"), []byte("key1")}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, []byte("key1"), results[0].Key)
+	assert.Equal(t, []byte("value1"), results[0].Value)
+}
+
+func TestStorageUnit_GetBulkFromEpochRange(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid range should error", func(t *testing.T) {
+		t.Parallel()
+
+		s := initStorageUnit(t, 10)
+		results, err := s.GetBulkFromEpochRange([][]byte{[]byte("key")}, types.EpochRange{FirstEpoch: 5, LastEpoch: 4})
+		assert.Nil(t, results)
+		assert.Equal(t, common.ErrInvalidEpochRange, err)
+	})
+	t.Run("newest epoch first should report the last epoch", func(t *testing.T) {
+		t.Parallel()
+
+		s := initStorageUnit(t, 10)
+		_ = s.Put([]byte("key1"), []byte("value1"))
+		_ = s.Put([]byte("key2"), []byte("value2"))
+
+		keys := [][]byte{[]byte("key1"), []byte("missing"), []byte("key2")}
+		epochRange := types.EpochRange{FirstEpoch: 2, LastEpoch: 7, Order: types.NewestEpochFirst}
+		results, err := s.GetBulkFromEpochRange(keys, epochRange)
+		assert.Nil(t, err)
+		expectedResults := []types.EpochKeyValuePair{
+			{Key: []byte("key1"), Value: []byte("value1"), Epoch: 7},
+			{Key: []byte("key2"), Value: []byte("value2"), Epoch: 7},
+		}
+		assert.Equal(t, expectedResults, results)
+	})
+	t.Run("oldest epoch first should report the first epoch", func(t *testing.T) {
+		t.Parallel()
+
+		s := initStorageUnit(t, 10)
+		_ = s.Put([]byte("key1"), []byte("value1"))
+
+		epochRange := types.EpochRange{FirstEpoch: 2, LastEpoch: 7, Order: types.OldestEpochFirst}
+		results, err := s.GetBulkFromEpochRange([][]byte{[]byte("key1")}, epochRange)
+		assert.Nil(t, err)
+		expectedResults := []types.EpochKeyValuePair{
+			{Key: []byte("key1"), Value: []byte("value1"), Epoch: 2},
+		}
+		assert.Equal(t, expectedResults, results)
+	})
+}
+
+func TestStorageUnit_RangeKeysFromEpoch(t *testing.T) {
+	t.Parallel()
+
+	s := initStorageUnit(t, 10)
+	_ = s.Put([]byte("key1"), []byte("value1"))
+	_ = s.Put([]byte("key2"), []byte("value2"))
+
+	found := make(map[string]string)
+	s.RangeKeysFromEpoch(3, func(key []byte, val []byte) bool {
+		found[string(key)] = string(val)
+		return true
+	})
+	assert.Equal(t, map[string]string{"key1": "value1", "key2": "value2"}, found)
+}
+
+func createArgsStorageUnitWithCacheDump(t *testing.T, persister types.Persister, dumpValues bool) storageUnit.ArgsStorageUnitWithCacheDump {
+	cache, _ := lrucache.NewCache(10)
+	cacheDumper, err := cachedump.NewCacheDumper(cachedump.ArgsCacheDumper{
+		FilePath:    filepath.Join(t.TempDir(), "cache.dump"),
+		Marshalizer: &testscommon.MarshalizerMock{},
+		DumpValues:  dumpValues,
+	})
+	require.Nil(t, err)
+
+	return storageUnit.ArgsStorageUnitWithCacheDump{
+		Cacher:      cache,
+		Persister:   persister,
+		CacheDumper: cacheDumper,
+		WarmUpMode:  common.EagerCacheWarmUp,
+	}
+}
+
+func TestNewStorageUnitWithCacheDump(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil cache dumper should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgsStorageUnitWithCacheDump(t, memorydb.New(), true)
+		args.CacheDumper = nil
+		sUnit, err := storageUnit.NewStorageUnitWithCacheDump(args)
+		assert.Nil(t, sUnit)
+		assert.Equal(t, common.ErrNilCacheDumper, err)
+	})
+	t.Run("unsupported warm-up mode should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgsStorageUnitWithCacheDump(t, memorydb.New(), true)
+		args.WarmUpMode = "Unknown"
+		sUnit, err := storageUnit.NewStorageUnitWithCacheDump(args)
+		assert.Nil(t, sUnit)
+		assert.True(t, errors.Is(err, common.ErrNotSupportedCacheWarmUpMode))
+	})
+	t.Run("nil persister should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgsStorageUnitWithCacheDump(t, nil, true)
+		sUnit, err := storageUnit.NewStorageUnitWithCacheDump(args)
+		assert.Nil(t, sUnit)
+		assert.Equal(t, common.ErrNilPersister, err)
+	})
+}
+
+func TestStorageUnit_CacheWarmUp(t *testing.T) {
+	t.Parallel()
+
+	// fills a unit, reads key1 so that it becomes the newest one, then closes the unit, dumping its cache
+	dumpCache := func(t *testing.T, args storageUnit.ArgsStorageUnitWithCacheDump) {
+		sUnit, err := storageUnit.NewStorageUnitWithCacheDump(args)
+		require.Nil(t, err)
+
+		_ = sUnit.Put([]byte("key1"), []byte("value1"))
+		_ = sUnit.Put([]byte("key2"), []byte("value2"))
+		_ = sUnit.Put([]byte("key3"), []byte("value3"))
+		_, _ = sUnit.Get([]byte("key1"))
+
+		err = sUnit.Close()
+		require.Nil(t, err)
+	}
+	expectedKeys := [][]byte{[]byte("key2"), []byte("key3"), []byte("key1")}
+
+	t.Run("eager warm-up should restore the dumped values in LRU order", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgsStorageUnitWithCacheDump(t, memorydb.New(), true)
+		dumpCache(t, args)
+
+		cache, _ := lrucache.NewCache(10)
+		args.Cacher = cache
+		args.Persister = &testscommon.PersisterStub{
+			GetCalled: func(key []byte) ([]byte, error) {
+				assert.Fail(t, "should have not read from the persister")
+				return nil, expectedErr
+			},
+		}
+		_, err := storageUnit.NewStorageUnitWithCacheDump(args)
+		require.Nil(t, err)
+
+		assert.Equal(t, expectedKeys, cache.Keys())
+		value, _ := cache.Peek([]byte("key1"))
+		assert.Equal(t, []byte("value1"), value)
+	})
+	t.Run("eager warm-up without dumped values should read from the persister", func(t *testing.T) {
+		t.Parallel()
+
+		persister := memorydb.New()
+		args := createArgsStorageUnitWithCacheDump(t, persister, false)
+		dumpCache(t, args)
+		_ = persister.Remove([]byte("key3"))
+
+		cache, _ := lrucache.NewCache(10)
+		args.Cacher = cache
+		_, err := storageUnit.NewStorageUnitWithCacheDump(args)
+		require.Nil(t, err)
+
+		assert.Equal(t, [][]byte{[]byte("key2"), []byte("key1")}, cache.Keys())
+		value, _ := cache.Peek([]byte("key2"))
+		assert.Equal(t, []byte("value2"), value)
+	})
+	t.Run("lazy warm-up should read the dumped keys from the persister", func(t *testing.T) {
+		t.Parallel()
+
+		persister := memorydb.New()
+		args := createArgsStorageUnitWithCacheDump(t, persister, true)
+		dumpCache(t, args)
+		_ = persister.Put([]byte("key1"), []byte("changed value1"))
+
+		cache, _ := lrucache.NewCache(10)
+		args.Cacher = cache
+		args.WarmUpMode = common.LazyCacheWarmUp
+		_, err := storageUnit.NewStorageUnitWithCacheDump(args)
+		require.Nil(t, err)
+
+		assert.Eventually(t, func() bool {
+			return cache.Len() == len(expectedKeys)
+		}, time.Second, time.Millisecond)
+		assert.Equal(t, expectedKeys, cache.Keys())
+		value, _ := cache.Peek([]byte("key1"))
+		assert.Equal(t, []byte("changed value1"), value)
+	})
+	t.Run("close should stop the lazy warm-up", func(t *testing.T) {
+		t.Parallel()
+
+		persister := memorydb.New()
+		args := createArgsStorageUnitWithCacheDump(t, persister, false)
+		dumpCache(t, args)
+
+		chBlockRead := make(chan struct{})
+		numReads := uint32(0)
+		args.Cacher, _ = lrucache.NewCache(10)
+		args.WarmUpMode = common.LazyCacheWarmUp
+		args.Persister = &testscommon.PersisterStub{
+			GetCalled: func(key []byte) ([]byte, error) {
+				atomic.AddUint32(&numReads, 1)
+				<-chBlockRead
+				return persister.Get(key)
+			},
+		}
+		sUnit, err := storageUnit.NewStorageUnitWithCacheDump(args)
+		require.Nil(t, err)
+		require.Eventually(t, func() bool {
+			return atomic.LoadUint32(&numReads) == 1
+		}, time.Second, time.Millisecond)
+
+		go func() {
+			time.Sleep(time.Millisecond * 50)
+			close(chBlockRead)
+		}()
+		err = sUnit.Close()
+		assert.Nil(t, err)
+		assert.Equal(t, uint32(1), atomic.LoadUint32(&numReads))
+	})
+	t.Run("no warm-up should leave the cache empty", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgsStorageUnitWithCacheDump(t, memorydb.New(), true)
+		dumpCache(t, args)
+
+		cache, _ := lrucache.NewCache(10)
+		args.Cacher = cache
+		args.WarmUpMode = common.NoCacheWarmUp
+		_, err := storageUnit.NewStorageUnitWithCacheDump(args)
+		require.Nil(t, err)
+		assert.Zero(t, cache.Len())
+	})
+	t.Run("closing twice should keep the dump", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgsStorageUnitWithCacheDump(t, memorydb.New(), true)
+		sUnit, _ := storageUnit.NewStorageUnitWithCacheDump(args)
+		_ = sUnit.Put([]byte("key1"), []byte("value1"))
+		_ = sUnit.Close()
+		_ = sUnit.Close()
+
+		entries, err := args.CacheDumper.Load()
+		require.Nil(t, err)
+		assert.Equal(t, []types.CacheDumpEntry{{Key: []byte("key1"), Value: []byte("value1")}}, entries)
+	})
+}
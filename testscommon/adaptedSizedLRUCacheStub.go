@@ -11,6 +11,7 @@ type AdaptedSizedLruCacheStub struct {
 	KeysCalled                     func() []interface{}
 	LenCalled                      func() int
 	SizeInBytesContainedCalled     func() uint64
+	EntriesSizeInBytesCalled       func() uint64
 	PurgeCalled                    func()
 	AddSizedAndReturnEvictedCalled func(key, value interface{}, sizeInBytes int64) map[interface{}]interface{}
 }
@@ -96,6 +97,15 @@ func (a *AdaptedSizedLruCacheStub) SizeInBytesContained() uint64 {
 	return 0
 }
 
+// EntriesSizeInBytes -
+func (a *AdaptedSizedLruCacheStub) EntriesSizeInBytes() uint64 {
+	if a.EntriesSizeInBytesCalled != nil {
+		return a.EntriesSizeInBytesCalled()
+	}
+
+	return 0
+}
+
 // Purge -
 func (a *AdaptedSizedLruCacheStub) Purge() {
 	if a.PurgeCalled != nil {
@@ -0,0 +1,89 @@
+package testscommon
+
+import (
+	"sync"
+	"time"
+)
+
+type fakeClockWaiter struct {
+	deadline time.Time
+	channel  chan time.Time
+}
+
+// FakeClock is a clock which only moves when advanced by the test
+type FakeClock struct {
+	mut     sync.Mutex
+	now     time.Time
+	waiters []*fakeClockWaiter
+}
+
+// NewFakeClock -
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{
+		now: now,
+	}
+}
+
+// Now -
+func (fc *FakeClock) Now() time.Time {
+	fc.mut.Lock()
+	defer fc.mut.Unlock()
+
+	return fc.now
+}
+
+// Since -
+func (fc *FakeClock) Since(t time.Time) time.Duration {
+	return fc.Now().Sub(t)
+}
+
+// After returns a channel receiving the time once the clock is advanced past the provided duration
+func (fc *FakeClock) After(d time.Duration) <-chan time.Time {
+	fc.mut.Lock()
+	defer fc.mut.Unlock()
+
+	waiter := &fakeClockWaiter{
+		deadline: fc.now.Add(d),
+		channel:  make(chan time.Time, 1),
+	}
+	if d <= 0 {
+		waiter.channel <- fc.now
+		return waiter.channel
+	}
+
+	fc.waiters = append(fc.waiters, waiter)
+
+	return waiter.channel
+}
+
+// Advance moves the clock forward, firing the channels whose deadline has been reached
+func (fc *FakeClock) Advance(d time.Duration) {
+	fc.mut.Lock()
+	defer fc.mut.Unlock()
+
+	fc.now = fc.now.Add(d)
+
+	pending := make([]*fakeClockWaiter, 0, len(fc.waiters))
+	for _, waiter := range fc.waiters {
+		if waiter.deadline.After(fc.now) {
+			pending = append(pending, waiter)
+			continue
+		}
+
+		waiter.channel <- fc.now
+	}
+	fc.waiters = pending
+}
+
+// NumWaiters returns the number of channels waiting for the clock to be advanced
+func (fc *FakeClock) NumWaiters() int {
+	fc.mut.Lock()
+	defer fc.mut.Unlock()
+
+	return len(fc.waiters)
+}
+
+// IsInterfaceNil -
+func (fc *FakeClock) IsInterfaceNil() bool {
+	return fc == nil
+}
@@ -0,0 +1,61 @@
+package testscommon
+
+import (
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+// MemoryBudgetHandlerStub -
+type MemoryBudgetHandlerStub struct {
+	RegisterCacheCalled       func(name string, cache types.ResizableCacher, sizeInBytes uint64, priority common.CachePriority) error
+	UnregisterCacheCalled     func(name string)
+	SetMaxSizeInBytesCalled   func(maxSizeInBytes uint64)
+	ReportCalled              func() types.MemoryBudgetReport
+	CheckOverCommitmentCalled func() error
+}
+
+// RegisterCache -
+func (stub *MemoryBudgetHandlerStub) RegisterCache(name string, cache types.ResizableCacher, sizeInBytes uint64, priority common.CachePriority) error {
+	if stub.RegisterCacheCalled != nil {
+		return stub.RegisterCacheCalled(name, cache, sizeInBytes, priority)
+	}
+
+	return nil
+}
+
+// UnregisterCache -
+func (stub *MemoryBudgetHandlerStub) UnregisterCache(name string) {
+	if stub.UnregisterCacheCalled != nil {
+		stub.UnregisterCacheCalled(name)
+	}
+}
+
+// SetMaxSizeInBytes -
+func (stub *MemoryBudgetHandlerStub) SetMaxSizeInBytes(maxSizeInBytes uint64) {
+	if stub.SetMaxSizeInBytesCalled != nil {
+		stub.SetMaxSizeInBytesCalled(maxSizeInBytes)
+	}
+}
+
+// Report -
+func (stub *MemoryBudgetHandlerStub) Report() types.MemoryBudgetReport {
+	if stub.ReportCalled != nil {
+		return stub.ReportCalled()
+	}
+
+	return types.MemoryBudgetReport{}
+}
+
+// CheckOverCommitment -
+func (stub *MemoryBudgetHandlerStub) CheckOverCommitment() error {
+	if stub.CheckOverCommitmentCalled != nil {
+		return stub.CheckOverCommitmentCalled()
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (stub *MemoryBudgetHandlerStub) IsInterfaceNil() bool {
+	return stub == nil
+}
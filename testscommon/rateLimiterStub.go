@@ -0,0 +1,48 @@
+package testscommon
+
+// RateLimiterStub -
+type RateLimiterStub struct {
+	RecordCalled   func(key string) error
+	CountsCalled   func(key string) []uint32
+	IsBannedCalled func(key string) bool
+	SweepCalled    func()
+}
+
+// Record -
+func (rls *RateLimiterStub) Record(key string) error {
+	if rls.RecordCalled != nil {
+		return rls.RecordCalled(key)
+	}
+
+	return nil
+}
+
+// Counts -
+func (rls *RateLimiterStub) Counts(key string) []uint32 {
+	if rls.CountsCalled != nil {
+		return rls.CountsCalled(key)
+	}
+
+	return nil
+}
+
+// IsBanned -
+func (rls *RateLimiterStub) IsBanned(key string) bool {
+	if rls.IsBannedCalled != nil {
+		return rls.IsBannedCalled(key)
+	}
+
+	return false
+}
+
+// Sweep -
+func (rls *RateLimiterStub) Sweep() {
+	if rls.SweepCalled != nil {
+		rls.SweepCalled()
+	}
+}
+
+// IsInterfaceNil -
+func (rls *RateLimiterStub) IsInterfaceNil() bool {
+	return rls == nil
+}
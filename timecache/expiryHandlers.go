@@ -0,0 +1,29 @@
+package timecache
+
+import (
+	"github.com/multiversx/mx-chain-storage-go/eviction"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+// registerExpiryHandler adds an expiry hook to the provided handlers, the hook being called with the key of each
+// swept entry
+func registerExpiryHandler(handlers *eviction.Handlers, handler types.EvictionHandler, id string) {
+	if handler == nil {
+		log.Error("attempt to register a nil expiry handler", "id", id)
+		return
+	}
+
+	handlers.Register(func(key []byte, _ interface{}, _ types.EvictionReason) {
+		handler.Evicted(key)
+	}, id)
+}
+
+func notifyEntries(handlers *eviction.Handlers, entries map[string]*entry, reason types.EvictionReason) {
+	if !handlers.HasHandlers() {
+		return
+	}
+
+	for key, element := range entries {
+		handlers.Notify([]byte(key), element.value, reason)
+	}
+}
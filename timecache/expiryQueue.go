@@ -0,0 +1,69 @@
+package timecache
+
+import "time"
+
+// expiryQueue is a min-heap of entries, ordered by their expiry time, to be used through the container/heap package
+type expiryQueue []*entry
+
+// Len returns the number of entries in the queue
+func (eq expiryQueue) Len() int {
+	return len(eq)
+}
+
+// Less returns true if the entry at index i expires before the one at index j
+func (eq expiryQueue) Less(i, j int) bool {
+	return eq[i].expiresAt().Before(eq[j].expiresAt())
+}
+
+// Swap swaps the entries at the provided indices
+func (eq expiryQueue) Swap(i, j int) {
+	eq[i], eq[j] = eq[j], eq[i]
+	eq[i].queueIndex = i
+	eq[j].queueIndex = j
+}
+
+// Push appends an entry, as required by heap.Interface
+func (eq *expiryQueue) Push(x interface{}) {
+	element := x.(*entry)
+	element.queueIndex = len(*eq)
+	*eq = append(*eq, element)
+}
+
+// Pop removes the last entry, as required by heap.Interface
+func (eq *expiryQueue) Pop() interface{} {
+	old := *eq
+	n := len(old)
+	element := old[n-1]
+	old[n-1] = nil
+	element.queueIndex = -1
+	*eq = old[:n-1]
+
+	return element
+}
+
+// peek returns the entry expiring first, without removing it
+func (eq expiryQueue) peek() (*entry, bool) {
+	if len(eq) == 0 {
+		return nil, false
+	}
+
+	return eq[0], true
+}
+
+// countExpired returns the number of entries expired at the provided time in the subtree rooted at the provided
+// index. As no entry expires before its parent, only the expired entries and their direct children are visited.
+func (eq expiryQueue) countExpired(index int, now time.Time) int {
+	if index >= len(eq) || !eq[index].isExpired(now) {
+		return 0
+	}
+
+	return 1 + eq.countExpired(2*index+1, now) + eq.countExpired(2*index+2, now)
+}
+
+func (e *entry) expiresAt() time.Time {
+	return e.timestamp.Add(e.span)
+}
+
+func (e *entry) isExpired(now time.Time) bool {
+	return now.Sub(e.timestamp) > e.span
+}
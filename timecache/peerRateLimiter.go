@@ -0,0 +1,48 @@
+package timecache
+
+import (
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+type peerRateLimiter struct {
+	rateLimiter types.RateLimiter
+}
+
+// NewPeerRateLimiter creates a new peer rate limiter instance
+func NewPeerRateLimiter(rateLimiter types.RateLimiter) (*peerRateLimiter, error) {
+	if check.IfNil(rateLimiter) {
+		return nil, common.ErrNilRateLimiter
+	}
+
+	return &peerRateLimiter{
+		rateLimiter: rateLimiter,
+	}, nil
+}
+
+// Record will call the inner rate limiter method with the provided pid as string
+func (prl *peerRateLimiter) Record(pid core.PeerID) error {
+	return prl.rateLimiter.Record(string(pid))
+}
+
+// Counts will call the inner rate limiter method with the provided pid as string
+func (prl *peerRateLimiter) Counts(pid core.PeerID) []uint32 {
+	return prl.rateLimiter.Counts(string(pid))
+}
+
+// IsBanned will call the inner rate limiter method with the provided pid as string
+func (prl *peerRateLimiter) IsBanned(pid core.PeerID) bool {
+	return prl.rateLimiter.IsBanned(string(pid))
+}
+
+// Sweep will call the inner rate limiter method
+func (prl *peerRateLimiter) Sweep() {
+	prl.rateLimiter.Sweep()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (prl *peerRateLimiter) IsInterfaceNil() bool {
+	return prl == nil
+}
@@ -0,0 +1,62 @@
+package timecache
+
+import (
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/testscommon"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPeerRateLimiter_NilRateLimiterShouldErr(t *testing.T) {
+	t.Parallel()
+
+	prl, err := NewPeerRateLimiter(nil)
+
+	assert.Equal(t, common.ErrNilRateLimiter, err)
+	assert.True(t, check.IfNil(prl))
+}
+
+func TestNewPeerRateLimiter_ShouldWork(t *testing.T) {
+	t.Parallel()
+
+	prl, err := NewPeerRateLimiter(&testscommon.RateLimiterStub{})
+
+	assert.Nil(t, err)
+	assert.False(t, check.IfNil(prl))
+}
+
+func TestPeerRateLimiter_Methods(t *testing.T) {
+	t.Parallel()
+
+	pid := core.PeerID("test peer id")
+	calledKeys := make([]string, 0)
+	sweepWasCalled := false
+	prl, _ := NewPeerRateLimiter(&testscommon.RateLimiterStub{
+		RecordCalled: func(key string) error {
+			calledKeys = append(calledKeys, key)
+			return common.ErrRateLimitExceeded
+		},
+		CountsCalled: func(key string) []uint32 {
+			calledKeys = append(calledKeys, key)
+			return []uint32{7}
+		},
+		IsBannedCalled: func(key string) bool {
+			calledKeys = append(calledKeys, key)
+			return true
+		},
+		SweepCalled: func() {
+			sweepWasCalled = true
+		},
+	})
+
+	assert.Equal(t, common.ErrRateLimitExceeded, prl.Record(pid))
+	assert.Equal(t, []uint32{7}, prl.Counts(pid))
+	assert.True(t, prl.IsBanned(pid))
+	prl.Sweep()
+
+	assert.Equal(t, []string{string(pid), string(pid), string(pid)}, calledKeys)
+	assert.True(t, sweepWasCalled)
+}
@@ -0,0 +1,215 @@
+package timecache
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+var _ types.TimeCacher = (*persistentTimeCache)(nil)
+
+// expirySizeInBytes is the size of a persisted value: the absolute expiry time, in unix nanoseconds
+const expirySizeInBytes = 8
+
+// ArgPersistentTimeCache is the argument used to create a new persistent time cache
+type ArgPersistentTimeCache struct {
+	DefaultSpan time.Duration
+	// MaxNumItems is the capacity of the time cache, the keys expiring first being evicted when it is reached.
+	// Zero means unbounded.
+	MaxNumItems int
+	Persister   types.Persister
+	// Clock is the source of time for the expiry of the keys. Nil means the system clock.
+	Clock types.Clock
+}
+
+// persistentTimeCache is a time cache which writes its keys, together with their absolute expiry, to a persister,
+// so that they survive a restart. The persister should be used exclusively by this component. Failing to persist
+// a key does not prevent it from being held in memory. The persister is written after releasing the lock of the time
+// cache. The writes are serialized, each one reflecting the state held in memory when it is made, so that concurrent
+// changes of the same key can not leave a stale state in the persister, such as a removed key which was added again.
+type persistentTimeCache struct {
+	timeCache  *timeCacheCore
+	persister  types.Persister
+	mutPersist sync.Mutex
+}
+
+// NewPersistentTimeCache creates a new persistent time cache, reloading the keys which have not expired yet from
+// the persister and removing the expired ones
+func NewPersistentTimeCache(arg ArgPersistentTimeCache) (*persistentTimeCache, error) {
+	if arg.DefaultSpan < minDuration {
+		return nil, common.ErrInvalidDefaultSpan
+	}
+	if arg.MaxNumItems < 0 {
+		return nil, common.ErrCacheSizeInvalid
+	}
+	if check.IfNil(arg.Persister) {
+		return nil, common.ErrNilPersister
+	}
+
+	ptc := &persistentTimeCache{
+		timeCache: newTimeCacheCore(arg.DefaultSpan, arg.MaxNumItems, arg.Clock),
+		persister: arg.Persister,
+	}
+	ptc.reload()
+
+	return ptc, nil
+}
+
+func (ptc *persistentTimeCache) reload() {
+	now := ptc.timeCache.clock.Now()
+	obsoleteKeys := make([]string, 0)
+	remainingSpans := make(map[string]time.Duration)
+
+	ptc.persister.RangeKeys(func(key []byte, val []byte) bool {
+		if len(val) != expirySizeInBytes {
+			log.Warn("persistentTimeCache.reload: malformed entry", "key", key)
+			obsoleteKeys = append(obsoleteKeys, string(key))
+			return true
+		}
+
+		expiresAt := time.Unix(0, int64(binary.BigEndian.Uint64(val)))
+		remainingSpan := expiresAt.Sub(now)
+		if remainingSpan <= 0 {
+			obsoleteKeys = append(obsoleteKeys, string(key))
+			return true
+		}
+
+		remainingSpans[string(key)] = remainingSpan
+
+		return true
+	})
+
+	ptc.timeCache.Lock()
+	for key, remainingSpan := range remainingSpans {
+		evicted := ptc.timeCache.setNoLock(key, nil, keyEntrySizeInBytes(key), remainingSpan)
+		for evictedKey := range evicted {
+			obsoleteKeys = append(obsoleteKeys, evictedKey)
+		}
+	}
+	ptc.timeCache.Unlock()
+
+	for _, key := range obsoleteKeys {
+		ptc.removePersisted(key)
+	}
+
+	log.Debug("persistentTimeCache.reload", "num reloaded", len(remainingSpans), "num removed", len(obsoleteKeys))
+}
+
+// Add will store the key in the time cache and in the persister
+// Double adding the key is permitted. It will replace the data, if existing. It does not trigger sweep.
+func (ptc *persistentTimeCache) Add(key string) error {
+	return ptc.AddWithSpan(key, ptc.timeCache.defaultSpan)
+}
+
+// AddWithSpan will store the key in the time cache and in the persister with the provided span duration
+// Double adding the key is permitted. It will replace the data, if existing. It does not trigger sweep.
+func (ptc *persistentTimeCache) AddWithSpan(key string, duration time.Duration) error {
+	if len(key) == 0 {
+		return common.ErrEmptyKey
+	}
+
+	ptc.timeCache.Lock()
+	evicted := ptc.timeCache.setNoLock(key, nil, keyEntrySizeInBytes(key), duration)
+	ptc.timeCache.Unlock()
+
+	ptc.syncPersistedEntries(evicted)
+	ptc.syncPersisted(key)
+
+	return nil
+}
+
+// Upsert will add the key and provided duration if not exists
+// If the record exists, will update the duration if the provided duration is larger than existing
+// Also, it will reset the contained timestamp to time.Now. The resulting expiry is persisted.
+func (ptc *persistentTimeCache) Upsert(key string, duration time.Duration) error {
+	if len(key) == 0 {
+		return common.ErrEmptyKey
+	}
+
+	ptc.timeCache.Lock()
+	_, evicted := ptc.timeCache.upsertNoLock(key, nil, keyEntrySizeInBytes(key), duration)
+	ptc.timeCache.Unlock()
+
+	ptc.syncPersistedEntries(evicted)
+	ptc.syncPersisted(key)
+
+	return nil
+}
+
+// Sweep removes the expired keys, both from the time cache and from the persister
+func (ptc *persistentTimeCache) Sweep() {
+	ptc.timeCache.Lock()
+	expired := ptc.timeCache.sweepNoLock()
+	ptc.timeCache.Unlock()
+
+	ptc.syncPersistedEntries(expired)
+}
+
+// Has returns if the key is still found in the time cache
+func (ptc *persistentTimeCache) Has(key string) bool {
+	return ptc.timeCache.has(key)
+}
+
+// Len returns the number of elements which are still stored in the time cache
+func (ptc *persistentTimeCache) Len() int {
+	return ptc.timeCache.len()
+}
+
+func (ptc *persistentTimeCache) syncPersistedEntries(entries map[string]*entry) {
+	for key := range entries {
+		ptc.syncPersisted(key)
+	}
+}
+
+// syncPersisted writes to the persister the state of the key held in memory: its expiry if it is still held, or its
+// removal otherwise. The state is read under the persist mutex, so that a write can not be overtaken by a stale one.
+func (ptc *persistentTimeCache) syncPersisted(key string) {
+	ptc.mutPersist.Lock()
+	defer ptc.mutPersist.Unlock()
+
+	ptc.timeCache.RLock()
+	e, isHeld := ptc.timeCache.data[key]
+	var expiresAt time.Time
+	if isHeld {
+		expiresAt = e.expiresAt()
+	}
+	ptc.timeCache.RUnlock()
+
+	if !isHeld {
+		ptc.removePersisted(key)
+		return
+	}
+
+	ptc.persist(key, expiresAt)
+}
+
+func (ptc *persistentTimeCache) persist(key string, expiresAt time.Time) {
+	val := make([]byte, expirySizeInBytes)
+	binary.BigEndian.PutUint64(val, uint64(expiresAt.UnixNano()))
+
+	err := ptc.persister.Put([]byte(key), val)
+	if err != nil {
+		log.Warn("persistentTimeCache.persist", "key", key, "error", err)
+	}
+}
+
+func (ptc *persistentTimeCache) removePersisted(key string) {
+	err := ptc.persister.Remove([]byte(key))
+	if err != nil {
+		log.Warn("persistentTimeCache.removePersisted", "key", key, "error", err)
+	}
+}
+
+// Close closes the underlying persister
+func (ptc *persistentTimeCache) Close() error {
+	return ptc.persister.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ptc *persistentTimeCache) IsInterfaceNil() bool {
+	return ptc == nil
+}
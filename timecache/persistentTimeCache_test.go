@@ -0,0 +1,262 @@
+package timecache
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/testscommon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createArgPersistentTimeCache() ArgPersistentTimeCache {
+	return ArgPersistentTimeCache{
+		DefaultSpan: time.Minute,
+		Persister:   testscommon.NewMemDbMock(),
+	}
+}
+
+func encodeExpiry(expiresAt time.Time) []byte {
+	val := make([]byte, expirySizeInBytes)
+	binary.BigEndian.PutUint64(val, uint64(expiresAt.UnixNano()))
+
+	return val
+}
+
+func TestNewPersistentTimeCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid DefaultSpan should error", func(t *testing.T) {
+		t.Parallel()
+
+		arg := createArgPersistentTimeCache()
+		arg.DefaultSpan = time.Second - time.Nanosecond
+		ptc, err := NewPersistentTimeCache(arg)
+		assert.True(t, check.IfNil(ptc))
+		assert.Equal(t, common.ErrInvalidDefaultSpan, err)
+	})
+	t.Run("negative MaxNumItems should error", func(t *testing.T) {
+		t.Parallel()
+
+		arg := createArgPersistentTimeCache()
+		arg.MaxNumItems = -1
+		ptc, err := NewPersistentTimeCache(arg)
+		assert.True(t, check.IfNil(ptc))
+		assert.Equal(t, common.ErrCacheSizeInvalid, err)
+	})
+	t.Run("nil Persister should error", func(t *testing.T) {
+		t.Parallel()
+
+		arg := createArgPersistentTimeCache()
+		arg.Persister = nil
+		ptc, err := NewPersistentTimeCache(arg)
+		assert.True(t, check.IfNil(ptc))
+		assert.Equal(t, common.ErrNilPersister, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		ptc, err := NewPersistentTimeCache(createArgPersistentTimeCache())
+		assert.Nil(t, err)
+		assert.False(t, check.IfNil(ptc))
+		assert.Nil(t, ptc.Close())
+	})
+}
+
+func TestPersistentTimeCache_ShouldReloadTheKeysNotExpired(t *testing.T) {
+	t.Parallel()
+
+	fakeClock := testscommon.NewFakeClock(time.Now())
+	now := fakeClock.Now()
+	persister := testscommon.NewMemDbMock()
+	_ = persister.Put([]byte("valid"), encodeExpiry(now.Add(time.Hour)))
+	_ = persister.Put([]byte("expired"), encodeExpiry(now.Add(-time.Second)))
+	_ = persister.Put([]byte("malformed"), []byte("bad"))
+
+	arg := createArgPersistentTimeCache()
+	arg.Persister = persister
+	arg.Clock = fakeClock
+	ptc, err := NewPersistentTimeCache(arg)
+	require.Nil(t, err)
+
+	assert.Equal(t, 1, ptc.Len())
+	assert.True(t, ptc.Has("valid"))
+	assert.Nil(t, persister.Has([]byte("valid")))
+	assert.NotNil(t, persister.Has([]byte("expired")))
+	assert.NotNil(t, persister.Has([]byte("malformed")))
+
+	// the reloaded key keeps its absolute expiry
+	element := ptc.timeCache.data["valid"]
+	assert.Equal(t, now.Add(time.Hour).UnixNano(), element.expiresAt().UnixNano())
+}
+
+func TestPersistentTimeCache_KeysShouldSurviveARestart(t *testing.T) {
+	t.Parallel()
+
+	arg := createArgPersistentTimeCache()
+	ptc, _ := NewPersistentTimeCache(arg)
+
+	assert.Equal(t, common.ErrEmptyKey, ptc.Add(""))
+	assert.Equal(t, common.ErrEmptyKey, ptc.Upsert("", time.Hour))
+	assert.Nil(t, ptc.Add("added"))
+	assert.Nil(t, ptc.AddWithSpan("added with span", time.Hour))
+	assert.Nil(t, ptc.Upsert("upserted", time.Minute))
+	assert.Nil(t, ptc.Upsert("upserted", time.Hour*2))
+
+	restarted, _ := NewPersistentTimeCache(arg)
+	assert.Equal(t, 3, restarted.Len())
+	for _, key := range []string{"added", "added with span", "upserted"} {
+		assert.True(t, restarted.Has(key))
+		assert.InDelta(t,
+			ptc.timeCache.data[key].expiresAt().UnixNano(),
+			restarted.timeCache.data[key].expiresAt().UnixNano(),
+			float64(time.Second),
+		)
+	}
+	assert.Equal(t, time.Hour*2, restarted.timeCache.data["upserted"].span.Round(time.Minute))
+}
+
+func TestPersistentTimeCache_SweepShouldRemoveFromPersister(t *testing.T) {
+	t.Parallel()
+
+	fakeClock := testscommon.NewFakeClock(time.Now())
+	arg := createArgPersistentTimeCache()
+	arg.Clock = fakeClock
+	ptc, _ := NewPersistentTimeCache(arg)
+
+	_ = ptc.AddWithSpan("short", time.Millisecond)
+	_ = ptc.Add("long")
+	fakeClock.Advance(time.Millisecond * 10)
+
+	ptc.Sweep()
+	assert.False(t, ptc.Has("short"))
+	assert.True(t, ptc.Has("long"))
+	assert.NotNil(t, arg.Persister.Has([]byte("short")))
+	assert.Nil(t, arg.Persister.Has([]byte("long")))
+}
+
+func TestPersistentTimeCache_CapacityEvictionsShouldRemoveFromPersister(t *testing.T) {
+	t.Parallel()
+
+	arg := createArgPersistentTimeCache()
+	arg.MaxNumItems = 1
+	ptc, _ := NewPersistentTimeCache(arg)
+
+	_ = ptc.AddWithSpan("first", time.Minute)
+	_ = ptc.AddWithSpan("second", time.Hour)
+	assert.False(t, ptc.Has("first"))
+	assert.NotNil(t, arg.Persister.Has([]byte("first")))
+	assert.Nil(t, arg.Persister.Has([]byte("second")))
+}
+
+func TestPersistentTimeCache_PersisterShouldBeWrittenOutsideTheLock(t *testing.T) {
+	t.Parallel()
+
+	var ptc *persistentTimeCache
+	numReads := 0
+	readWhilePersisting := func() {
+		// would deadlock if the persister was written under the lock of the time cache
+		_ = ptc.Has("key")
+		numReads++
+	}
+
+	fakeClock := testscommon.NewFakeClock(time.Now())
+	arg := createArgPersistentTimeCache()
+	arg.Clock = fakeClock
+	arg.MaxNumItems = 1
+	arg.Persister = &testscommon.PersisterStub{
+		PutCalled: func(key, val []byte) error {
+			readWhilePersisting()
+			return nil
+		},
+		RemoveCalled: func(key []byte) error {
+			readWhilePersisting()
+			return nil
+		},
+	}
+	ptc, _ = NewPersistentTimeCache(arg)
+
+	done := make(chan struct{})
+	go func() {
+		_ = ptc.AddWithSpan("key", time.Minute)
+		_ = ptc.Upsert("key", time.Hour)
+		_ = ptc.AddWithSpan("other", time.Millisecond)
+		fakeClock.Advance(time.Hour * 2)
+		ptc.Sweep()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		require.Fail(t, "the persister was written under the lock of the time cache")
+	}
+	// two puts and one eviction for the key, one put and one sweep removal for the other key
+	assert.Equal(t, 5, numReads)
+}
+
+func TestPersistentTimeCache_StaleRemovalShouldNotDeleteAKeyAddedAgain(t *testing.T) {
+	t.Parallel()
+
+	memDb := testscommon.NewMemDbMock()
+	chRemoving := make(chan struct{}, 1)
+	chReleaseRemoval := make(chan struct{})
+	fakeClock := testscommon.NewFakeClock(time.Now())
+	arg := createArgPersistentTimeCache()
+	arg.Clock = fakeClock
+	arg.Persister = &testscommon.PersisterStub{
+		PutCalled: memDb.Put,
+		HasCalled: memDb.Has,
+		RemoveCalled: func(key []byte) error {
+			chRemoving <- struct{}{}
+			<-chReleaseRemoval
+			return memDb.Remove(key)
+		},
+	}
+	ptc, _ := NewPersistentTimeCache(arg)
+
+	_ = ptc.AddWithSpan("key", time.Minute)
+	fakeClock.Advance(time.Minute * 2)
+
+	sweepDone := make(chan struct{})
+	go func() {
+		ptc.Sweep()
+		close(sweepDone)
+	}()
+	// the sweep removed the key from memory and is about to remove it from the persister
+	<-chRemoving
+
+	addDone := make(chan struct{})
+	go func() {
+		_ = ptc.AddWithSpan("key", time.Hour)
+		close(addDone)
+	}()
+	require.Eventually(t, func() bool {
+		return ptc.Has("key")
+	}, time.Second, time.Millisecond)
+
+	close(chReleaseRemoval)
+	<-sweepDone
+	<-addDone
+
+	assert.True(t, ptc.Has("key"))
+	assert.Nil(t, memDb.Has([]byte("key")))
+}
+
+func TestPersistentTimeCache_PeerTimeCacheShouldKeepBansAcrossRestarts(t *testing.T) {
+	t.Parallel()
+
+	pid := core.PeerID("banned peer")
+	arg := createArgPersistentTimeCache()
+	ptc, _ := NewPersistentTimeCache(arg)
+	peerCache, _ := NewPeerTimeCache(ptc)
+	_ = peerCache.Upsert(pid, time.Hour)
+
+	restarted, _ := NewPersistentTimeCache(arg)
+	restartedPeerCache, _ := NewPeerTimeCache(restarted)
+	assert.True(t, restartedPeerCache.Has(pid))
+}
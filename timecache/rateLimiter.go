@@ -0,0 +1,242 @@
+package timecache
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+var _ types.RateLimiter = (*rateLimiter)(nil)
+
+// RateLimitWindow defines a sliding window over which the occurrences of each key are counted
+type RateLimitWindow struct {
+	Span     time.Duration
+	MaxCount uint32
+	// BanSpan is the time for which a key exceeding MaxCount is refused. Zero means the key is refused only while
+	// its count is above MaxCount.
+	BanSpan time.Duration
+}
+
+// ArgRateLimiter is the argument used to create a new rate limiter
+type ArgRateLimiter struct {
+	Windows []RateLimitWindow
+	// MaxNumKeys bounds the number of tracked keys, the keys expiring first being evicted when it is reached
+	MaxNumKeys int
+	// Clock is the source of time for the windows and the bans. Nil means the system clock.
+	Clock types.Clock
+}
+
+// windowCounter approximates the count over a sliding window from the counts of the current and of the previous
+// fixed windows, the previous count being weighted by its overlap with the sliding window
+type windowCounter struct {
+	currentStart time.Time
+	current      uint32
+	previous     uint32
+}
+
+// rateLimitState is the value held by the time cache for each key
+type rateLimitState struct {
+	counters    []windowCounter
+	bannedUntil time.Time
+}
+
+// rateLimiter counts the occurrences of the keys over sliding windows. The keys live in a capacity bounded time
+// cache, being forgotten once all their counts have decayed and their ban has passed.
+type rateLimiter struct {
+	timeCache *timeCacheCore
+	windows   []RateLimitWindow
+	keepSpan  time.Duration
+}
+
+// NewRateLimiter creates a new rate limiter
+func NewRateLimiter(arg ArgRateLimiter) (*rateLimiter, error) {
+	err := checkArgRateLimiter(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	maxSpan := time.Duration(0)
+	for _, window := range arg.Windows {
+		maxSpan = max(maxSpan, window.Span)
+	}
+
+	// a counter decays completely two spans after its current fixed window has started
+	keepSpan := 2 * maxSpan
+
+	return &rateLimiter{
+		timeCache: newTimeCacheCore(keepSpan, arg.MaxNumKeys, arg.Clock),
+		windows:   append([]RateLimitWindow(nil), arg.Windows...),
+		keepSpan:  keepSpan,
+	}, nil
+}
+
+func checkArgRateLimiter(arg ArgRateLimiter) error {
+	if len(arg.Windows) == 0 {
+		return fmt.Errorf("%w: no rate limit window provided", common.ErrInvalidConfig)
+	}
+	for i, window := range arg.Windows {
+		if window.Span <= 0 {
+			return fmt.Errorf("%w: non positive span for window %d", common.ErrInvalidConfig, i)
+		}
+		if window.MaxCount == 0 {
+			return fmt.Errorf("%w: zero max count for window %d", common.ErrInvalidConfig, i)
+		}
+		if window.BanSpan < 0 {
+			return fmt.Errorf("%w: negative ban span for window %d", common.ErrInvalidConfig, i)
+		}
+	}
+	if arg.MaxNumKeys < 1 {
+		return fmt.Errorf("%w: max num keys should be positive", common.ErrInvalidConfig)
+	}
+
+	return nil
+}
+
+// Record accounts an occurrence of the key in all the windows. It returns common.ErrRateLimitExceeded if the key
+// is banned, in which case the occurrence is not accounted, or if the occurrence exceeds the count of a window, in
+// which case the key is banned for the span of that window.
+func (rl *rateLimiter) Record(key string) error {
+	if len(key) == 0 {
+		return common.ErrEmptyKey
+	}
+
+	now := rl.timeCache.clock.Now()
+
+	rl.timeCache.Lock()
+	defer rl.timeCache.Unlock()
+
+	element := rl.getOrAddNoLock(key, now)
+	state := element.value.(*rateLimitState)
+	if now.Before(state.bannedUntil) {
+		return common.ErrRateLimitExceeded
+	}
+
+	isExceeded := false
+	for i, window := range rl.windows {
+		counter := &state.counters[i]
+		counter.advance(now, window.Span)
+		counter.current++
+
+		if counter.estimate(now, window.Span) <= window.MaxCount {
+			continue
+		}
+
+		isExceeded = true
+		bannedUntil := now.Add(window.BanSpan)
+		if bannedUntil.After(state.bannedUntil) {
+			state.bannedUntil = bannedUntil
+		}
+	}
+
+	rl.timeCache.refreshNoLock(element, max(rl.keepSpan, state.bannedUntil.Sub(now)))
+	if isExceeded {
+		return common.ErrRateLimitExceeded
+	}
+
+	return nil
+}
+
+func (rl *rateLimiter) getOrAddNoLock(key string, now time.Time) *entry {
+	element, found := rl.timeCache.data[key]
+	if found {
+		return element
+	}
+
+	state := &rateLimitState{
+		counters: make([]windowCounter, len(rl.windows)),
+	}
+	for i := range state.counters {
+		state.counters[i].currentStart = now
+	}
+
+	evicted := rl.timeCache.setNoLock(key, state, keyEntrySizeInBytes(key), rl.keepSpan)
+	if len(evicted) > 0 {
+		log.Trace("rateLimiter: capacity reached", "num evicted keys", len(evicted))
+	}
+
+	return rl.timeCache.data[key]
+}
+
+// Counts returns the current counts of the key, in the order of the windows
+func (rl *rateLimiter) Counts(key string) []uint32 {
+	now := rl.timeCache.clock.Now()
+	counts := make([]uint32, len(rl.windows))
+
+	rl.timeCache.RLock()
+	defer rl.timeCache.RUnlock()
+
+	element, found := rl.timeCache.data[key]
+	if !found {
+		return counts
+	}
+
+	state := element.value.(*rateLimitState)
+	for i, window := range rl.windows {
+		counter := state.counters[i]
+		counter.advance(now, window.Span)
+		counts[i] = counter.estimate(now, window.Span)
+	}
+
+	return counts
+}
+
+// IsBanned returns true if the key is currently banned
+func (rl *rateLimiter) IsBanned(key string) bool {
+	now := rl.timeCache.clock.Now()
+
+	rl.timeCache.RLock()
+	defer rl.timeCache.RUnlock()
+
+	element, found := rl.timeCache.data[key]
+	if !found {
+		return false
+	}
+
+	return now.Before(element.value.(*rateLimitState).bannedUntil)
+}
+
+// Sweep forgets the keys whose counts have decayed and whose ban has passed
+func (rl *rateLimiter) Sweep() {
+	rl.timeCache.sweep()
+}
+
+// Len returns the number of tracked keys
+func (rl *rateLimiter) Len() int {
+	return rl.timeCache.len()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (rl *rateLimiter) IsInterfaceNil() bool {
+	return rl == nil
+}
+
+// advance moves the fixed windows so that the current one contains the provided time
+func (wc *windowCounter) advance(now time.Time, span time.Duration) {
+	elapsed := now.Sub(wc.currentStart)
+	if elapsed < span {
+		return
+	}
+
+	if elapsed < 2*span {
+		wc.previous = wc.current
+		wc.currentStart = wc.currentStart.Add(span)
+	} else {
+		wc.previous = 0
+		wc.currentStart = now
+	}
+	wc.current = 0
+}
+
+// estimate returns the count over the sliding window ending at the provided time. The counter should be advanced.
+func (wc *windowCounter) estimate(now time.Time, span time.Duration) uint32 {
+	remaining := span - now.Sub(wc.currentStart)
+	if remaining <= 0 {
+		return wc.current
+	}
+
+	weightedPrevious := uint64(wc.previous) * uint64(remaining) / uint64(span)
+
+	return uint32(weightedPrevious) + wc.current
+}
@@ -0,0 +1,237 @@
+package timecache
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/testscommon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createArgRateLimiter() ArgRateLimiter {
+	return ArgRateLimiter{
+		Windows: []RateLimitWindow{
+			{Span: time.Minute, MaxCount: 3, BanSpan: time.Hour},
+			{Span: time.Hour, MaxCount: 5},
+		},
+		MaxNumKeys: 100,
+	}
+}
+
+func TestNewRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid arguments should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := make([]ArgRateLimiter, 0)
+		arg := createArgRateLimiter()
+		arg.Windows = nil
+		args = append(args, arg)
+		arg = createArgRateLimiter()
+		arg.Windows[0].Span = 0
+		args = append(args, arg)
+		arg = createArgRateLimiter()
+		arg.Windows[1].MaxCount = 0
+		args = append(args, arg)
+		arg = createArgRateLimiter()
+		arg.Windows[0].BanSpan = -time.Second
+		args = append(args, arg)
+		arg = createArgRateLimiter()
+		arg.MaxNumKeys = 0
+		args = append(args, arg)
+
+		for _, arg = range args {
+			rl, err := NewRateLimiter(arg)
+			assert.True(t, errors.Is(err, common.ErrInvalidConfig))
+			assert.True(t, check.IfNil(rl))
+		}
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		rl, err := NewRateLimiter(createArgRateLimiter())
+		assert.Nil(t, err)
+		assert.False(t, check.IfNil(rl))
+		assert.Equal(t, time.Hour*2, rl.keepSpan)
+	})
+}
+
+func TestRateLimiter_RecordEmptyKeyShouldErr(t *testing.T) {
+	t.Parallel()
+
+	rl, _ := NewRateLimiter(createArgRateLimiter())
+	assert.Equal(t, common.ErrEmptyKey, rl.Record(""))
+	assert.Equal(t, 0, rl.Len())
+}
+
+func TestRateLimiter_RecordShouldCountPerKeyAndBan(t *testing.T) {
+	t.Parallel()
+
+	rl, _ := NewRateLimiter(createArgRateLimiter())
+
+	for i := 0; i < 3; i++ {
+		assert.Nil(t, rl.Record("flooder"))
+	}
+	assert.Nil(t, rl.Record("other"))
+	assert.Equal(t, []uint32{3, 3}, rl.Counts("flooder"))
+	assert.Equal(t, []uint32{1, 1}, rl.Counts("other"))
+	assert.Equal(t, []uint32{0, 0}, rl.Counts("missing"))
+	assert.False(t, rl.IsBanned("flooder"))
+
+	assert.Equal(t, common.ErrRateLimitExceeded, rl.Record("flooder"))
+	assert.True(t, rl.IsBanned("flooder"))
+	assert.False(t, rl.IsBanned("other"))
+
+	// the occurrences of a banned key are not accounted
+	assert.Equal(t, common.ErrRateLimitExceeded, rl.Record("flooder"))
+	assert.Equal(t, []uint32{4, 4}, rl.Counts("flooder"))
+	assert.Equal(t, 2, rl.Len())
+}
+
+func TestRateLimiter_WindowWithoutBanSpanShouldOnlyRefuseAboveThreshold(t *testing.T) {
+	t.Parallel()
+
+	arg := createArgRateLimiter()
+	arg.Windows = []RateLimitWindow{{Span: time.Minute, MaxCount: 1}}
+	rl, _ := NewRateLimiter(arg)
+
+	assert.Nil(t, rl.Record("key"))
+	assert.Equal(t, common.ErrRateLimitExceeded, rl.Record("key"))
+	assert.False(t, rl.IsBanned("key"))
+	assert.Equal(t, common.ErrRateLimitExceeded, rl.Record("key"))
+	assert.Equal(t, []uint32{3}, rl.Counts("key"))
+}
+
+func TestRateLimiter_BanShouldPass(t *testing.T) {
+	t.Parallel()
+
+	fakeClock := testscommon.NewFakeClock(time.Now())
+	arg := createArgRateLimiter()
+	arg.Windows = []RateLimitWindow{{Span: time.Minute, MaxCount: 1, BanSpan: time.Hour}}
+	arg.Clock = fakeClock
+	rl, _ := NewRateLimiter(arg)
+
+	assert.Nil(t, rl.Record("key"))
+	assert.Equal(t, common.ErrRateLimitExceeded, rl.Record("key"))
+	assert.True(t, rl.IsBanned("key"))
+
+	fakeClock.Advance(time.Hour - time.Second)
+	assert.True(t, rl.IsBanned("key"))
+
+	fakeClock.Advance(time.Second)
+	assert.False(t, rl.IsBanned("key"))
+	assert.Equal(t, []uint32{0}, rl.Counts("key"))
+	assert.Nil(t, rl.Record("key"))
+}
+
+func TestRateLimiter_SweepShouldForgetDecayedKeys(t *testing.T) {
+	t.Parallel()
+
+	fakeClock := testscommon.NewFakeClock(time.Now())
+	arg := createArgRateLimiter()
+	arg.Windows = []RateLimitWindow{{Span: time.Minute, MaxCount: 1, BanSpan: time.Hour}}
+	arg.Clock = fakeClock
+	rl, _ := NewRateLimiter(arg)
+
+	assert.Nil(t, rl.Record("decayed"))
+	assert.Nil(t, rl.Record("banned"))
+	assert.Equal(t, common.ErrRateLimitExceeded, rl.Record("banned"))
+
+	fakeClock.Advance(time.Minute * 2)
+	rl.Sweep()
+	assert.Equal(t, 2, rl.Len())
+
+	fakeClock.Advance(time.Nanosecond)
+	rl.Sweep()
+	assert.Equal(t, 1, rl.Len())
+	assert.True(t, rl.IsBanned("banned"))
+}
+
+func TestRateLimiter_CapacityShouldEvictTheKeysExpiringFirst(t *testing.T) {
+	t.Parallel()
+
+	arg := createArgRateLimiter()
+	arg.MaxNumKeys = 2
+	// the banned key outlives the keys expiring after the decay of their counts
+	arg.Windows[0].BanSpan = time.Hour * 3
+	rl, _ := NewRateLimiter(arg)
+
+	for i := 0; i < 4; i++ {
+		_ = rl.Record("banned")
+	}
+	_ = rl.Record("key1")
+	_ = rl.Record("key2")
+
+	assert.Equal(t, 2, rl.Len())
+	assert.True(t, rl.IsBanned("banned"))
+	assert.Equal(t, []uint32{1, 1}, rl.Counts("key2"))
+	assert.Equal(t, []uint32{0, 0}, rl.Counts("key1"))
+}
+
+func TestWindowCounter_ShouldSlide(t *testing.T) {
+	t.Parallel()
+
+	span := time.Second * 10
+	start := time.Now()
+	wc := windowCounter{
+		currentStart: start,
+		current:      10,
+	}
+
+	wc.advance(start.Add(time.Second*5), span)
+	assert.Equal(t, uint32(10), wc.estimate(start.Add(time.Second*5), span))
+
+	// a quarter of the sliding window overlaps the previous fixed window
+	now := start.Add(time.Second * 17)
+	wc.advance(now, span)
+	wc.current += 2
+	assert.Equal(t, start.Add(span), wc.currentStart)
+	assert.Equal(t, uint32(10), wc.previous)
+	assert.Equal(t, uint32(5), wc.estimate(now, span))
+
+	now = start.Add(time.Second * 45)
+	wc.advance(now, span)
+	assert.Equal(t, uint32(0), wc.estimate(now, span))
+	assert.Equal(t, now, wc.currentStart)
+}
+
+func TestRateLimiter_ConcurrentOperations(t *testing.T) {
+	t.Parallel()
+
+	rl, _ := NewRateLimiter(createArgRateLimiter())
+	numOperations := 1000
+	wg := &sync.WaitGroup{}
+	wg.Add(numOperations)
+	for i := 0; i < numOperations; i++ {
+		go func(idx int) {
+			key := fmt.Sprintf("key%d", idx%10)
+
+			switch idx % 5 {
+			case 0:
+				_ = rl.Record(key)
+			case 1:
+				_ = rl.Counts(key)
+			case 2:
+				_ = rl.IsBanned(key)
+			case 3:
+				rl.Sweep()
+			case 4:
+				_ = rl.Len()
+			default:
+				assert.Fail(t, "test setup error, change the line 'switch idx % xxx {' from this test")
+			}
+
+			wg.Done()
+		}(i)
+	}
+
+	wg.Wait()
+	require.LessOrEqual(t, rl.Len(), 10)
+}
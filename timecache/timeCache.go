@@ -4,20 +4,62 @@ import (
 	"time"
 
 	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/eviction"
+	"github.com/multiversx/mx-chain-storage-go/types"
 )
 
 // TimeCache can retain an amount of string keys for a defined period of time
 // sweeping (clean-up) is triggered each time a new item is added or a key is present in the time cache
 // This data structure is concurrent safe.
 type TimeCache struct {
-	timeCache *timeCacheCore
+	timeCache      *timeCacheCore
+	expiryHandlers eviction.Handlers
+}
+
+// ArgTimeCache is the argument used to create a new TimeCache instance
+type ArgTimeCache struct {
+	DefaultSpan time.Duration
+	// MaxNumItems is the capacity of the time cache, the keys expiring first being evicted when it is reached.
+	// Zero means unbounded.
+	MaxNumItems int
+	// StrictExpiry hides the expired keys from Has and Len right away, instead of at the next sweep
+	StrictExpiry bool
+	// Clock is the source of time for the expiry of the keys. Nil means the system clock.
+	Clock types.Clock
 }
 
 // NewTimeCache creates a new time cache data structure instance
 func NewTimeCache(defaultSpan time.Duration) *TimeCache {
 	return &TimeCache{
-		timeCache: newTimeCacheCore(defaultSpan),
+		timeCache: newTimeCacheCore(defaultSpan, 0, nil),
+	}
+}
+
+// NewTimeCacheWithCapacity creates a new time cache data structure instance holding at most maxNumItems keys.
+// Adding a new key to a full time cache evicts the keys expiring first.
+func NewTimeCacheWithCapacity(defaultSpan time.Duration, maxNumItems int) (*TimeCache, error) {
+	if maxNumItems < 1 {
+		return nil, common.ErrCacheSizeInvalid
 	}
+
+	return NewTimeCacheWithArgs(ArgTimeCache{
+		DefaultSpan: defaultSpan,
+		MaxNumItems: maxNumItems,
+	})
+}
+
+// NewTimeCacheWithArgs creates a new time cache data structure instance from the provided arguments
+func NewTimeCacheWithArgs(arg ArgTimeCache) (*TimeCache, error) {
+	if arg.MaxNumItems < 0 {
+		return nil, common.ErrCacheSizeInvalid
+	}
+
+	timeCache := newTimeCacheCore(arg.DefaultSpan, arg.MaxNumItems, arg.Clock)
+	timeCache.strictExpiry = arg.StrictExpiry
+
+	return &TimeCache{
+		timeCache: timeCache,
+	}, nil
 }
 
 // Add will store the key in the time cache
@@ -34,10 +76,8 @@ func (tc *TimeCache) add(key string, duration time.Duration) error {
 	tc.timeCache.Lock()
 	defer tc.timeCache.Unlock()
 
-	tc.timeCache.data[key] = &entry{
-		timestamp: time.Now(),
-		span:      duration,
-	}
+	_ = tc.timeCache.setNoLock(key, nil, keyEntrySizeInBytes(key), duration)
+
 	return nil
 }
 
@@ -51,15 +91,25 @@ func (tc *TimeCache) AddWithSpan(key string, duration time.Duration) error {
 // If the record exists, will update the duration if the provided duration is larger than existing
 // Also, it will reset the contained timestamp to time.Now
 func (tc *TimeCache) Upsert(key string, duration time.Duration) error {
-	_, err := tc.timeCache.upsert(key, nil, duration)
+	_, _, err := tc.timeCache.upsert(key, nil, keyEntrySizeInBytes(key), duration)
 
 	return err
 }
 
-// Sweep starts from the oldest element and will search each element if it is still valid to be kept. Sweep ends when
-// it finds an element that is still valid
+// Sweep starts from the element expiring first and will search each element if it is still valid to be kept. Sweep
+// ends when it finds an element that is still valid
 func (tc *TimeCache) Sweep() {
-	tc.timeCache.sweep()
+	notifyEntries(&tc.expiryHandlers, tc.timeCache.sweep(), types.EvictedByExpiry)
+}
+
+// RegisterExpiryHandler registers a handler to be called, on sweep, with each expired key
+func (tc *TimeCache) RegisterExpiryHandler(handler types.EvictionHandler, id string) {
+	registerExpiryHandler(&tc.expiryHandlers, handler, id)
+}
+
+// UnRegisterExpiryHandler removes the expiry handler registered under the provided id
+func (tc *TimeCache) UnRegisterExpiryHandler(id string) {
+	tc.expiryHandlers.UnRegister(id)
 }
 
 // Has returns if the key is still found in the time cache
@@ -76,3 +126,8 @@ func (tc *TimeCache) Len() int {
 func (tc *TimeCache) IsInterfaceNil() bool {
 	return tc == nil
 }
+
+// keyEntrySizeInBytes returns the size accounted for an entry holding only a key
+func keyEntrySizeInBytes(key string) int {
+	return len(key) + types.EntryOverheadInBytes
+}
@@ -1,115 +1,234 @@
 package timecache
 
 import (
+	"container/heap"
 	"sync"
 	"time"
 
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/clock"
 	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
 )
 
 type entry struct {
+	key       string
 	timestamp time.Time
 	span      time.Duration
 	value     interface{}
+	size      int64
+	// queueIndex is the position of the entry in the expiry queue
+	queueIndex int
 }
 
+// timeCacheCore holds the entries both in a map, for lookups, and in a min-heap ordered by the expiry time, so that
+// sweeping and capacity eviction only touch the entries expiring first
 type timeCacheCore struct {
 	*sync.RWMutex
 	data        map[string]*entry
+	expiryQueue expiryQueue
+	numBytes    int64
 	defaultSpan time.Duration
+	// maxNumItems is the capacity of the cache, zero meaning unbounded
+	maxNumItems int
+	// strictExpiry hides the expired entries from the reads before they are swept
+	strictExpiry bool
+	clock        types.Clock
 }
 
-func newTimeCacheCore(defaultSpan time.Duration) *timeCacheCore {
+func newTimeCacheCore(defaultSpan time.Duration, maxNumItems int, providedClock types.Clock) *timeCacheCore {
 	return &timeCacheCore{
 		RWMutex:     &sync.RWMutex{},
 		data:        make(map[string]*entry),
 		defaultSpan: defaultSpan,
+		maxNumItems: maxNumItems,
+		clock:       clockOrDefault(providedClock),
 	}
 }
 
+// clockOrDefault returns the provided clock or, if none was provided, the system clock
+func clockOrDefault(providedClock types.Clock) types.Clock {
+	if check.IfNil(providedClock) {
+		return clock.NewSystemClock()
+	}
+
+	return providedClock
+}
+
 // upsert will add the key, value and provided duration if not exists
 // If the record exists, will update the duration if the provided duration is larger than existing
 // Also, it will reset the contained timestamp to time.Now
-// It returns if the value existed before this call. It also operates on the locker so the call is concurrent safe
-func (tcc *timeCacheCore) upsert(key string, value interface{}, duration time.Duration) (bool, error) {
+// It returns if the value existed before this call and the entries evicted to make room for the key.
+// It also operates on the locker so the call is concurrent safe
+func (tcc *timeCacheCore) upsert(key string, value interface{}, sizeInBytes int, duration time.Duration) (bool, map[string]*entry, error) {
 	if len(key) == 0 {
-		return false, common.ErrEmptyKey
+		return false, nil, common.ErrEmptyKey
 	}
 
 	tcc.Lock()
 	defer tcc.Unlock()
 
+	found, evicted := tcc.upsertNoLock(key, value, sizeInBytes, duration)
+
+	return found, evicted, nil
+}
+
+func (tcc *timeCacheCore) upsertNoLock(key string, value interface{}, sizeInBytes int, duration time.Duration) (bool, map[string]*entry) {
 	existing, found := tcc.data[key]
 	if found {
-		if existing.span < duration {
-			existing.span = duration
-		}
-		existing.timestamp = time.Now()
+		tcc.refreshNoLock(existing, max(existing.span, duration))
 
-		return found, nil
+		return true, nil
 	}
 
-	tcc.data[key] = &entry{
-		timestamp: time.Now(),
-		span:      duration,
-		value:     value,
-	}
-	return found, nil
+	return false, tcc.setNoLock(key, value, sizeInBytes, duration)
 }
 
 // put will add the key, value and provided duration, overriding values if the data already existed
-// It also operates on the locker so the call is concurrent safe
-func (tcc *timeCacheCore) put(key string, value interface{}, duration time.Duration) error {
+// It returns the entries evicted to make room for the key. It also operates on the locker so the call is concurrent safe
+func (tcc *timeCacheCore) put(key string, value interface{}, sizeInBytes int, duration time.Duration) (map[string]*entry, error) {
 	if len(key) == 0 {
-		return common.ErrEmptyKey
+		return nil, common.ErrEmptyKey
 	}
 
 	tcc.Lock()
 	defer tcc.Unlock()
 
-	tcc.data[key] = &entry{
-		timestamp: time.Now(),
-		span:      duration,
-		value:     value,
-	}
-	return nil
+	return tcc.setNoLock(key, value, sizeInBytes, duration), nil
 }
 
 // hasOrAdd will add the key, value and provided duration, if the key is not found
-// It returns true if the value existed before this call and if it has been added or not. It also operates on the locker so the call is concurrent safe
-func (tcc *timeCacheCore) hasOrAdd(key string, value interface{}, duration time.Duration) (bool, bool, error) {
+// It returns true if the value existed before this call, if it has been added or not and the entries evicted to
+// make room for the key. It also operates on the locker so the call is concurrent safe
+func (tcc *timeCacheCore) hasOrAdd(key string, value interface{}, sizeInBytes int, duration time.Duration) (bool, bool, map[string]*entry, error) {
 	if len(key) == 0 {
-		return false, false, common.ErrEmptyKey
+		return false, false, nil, common.ErrEmptyKey
 	}
 
 	tcc.Lock()
 	defer tcc.Unlock()
 
-	_, found := tcc.data[key]
+	_, found := tcc.getNoLock(key)
 	if found {
-		return true, false, nil
+		return true, false, nil, nil
 	}
 
-	tcc.data[key] = &entry{
-		timestamp: time.Now(),
+	evicted := tcc.setNoLock(key, value, sizeInBytes, duration)
+
+	return false, true, evicted, nil
+}
+
+// setNoLock adds or replaces the entry, accounting its size. A new key exceeding the capacity evicts the entries
+// expiring first, which are returned.
+func (tcc *timeCacheCore) setNoLock(key string, value interface{}, sizeInBytes int, duration time.Duration) map[string]*entry {
+	existing, found := tcc.data[key]
+	if found {
+		tcc.numBytes += int64(sizeInBytes) - existing.size
+		existing.timestamp = tcc.clock.Now()
+		existing.span = duration
+		existing.value = value
+		existing.size = int64(sizeInBytes)
+		heap.Fix(&tcc.expiryQueue, existing.queueIndex)
+
+		return nil
+	}
+
+	evicted := tcc.makeRoomNoLock()
+
+	element := &entry{
+		key:       key,
+		timestamp: tcc.clock.Now(),
 		span:      duration,
 		value:     value,
+		size:      int64(sizeInBytes),
 	}
-	return false, true, nil
+	tcc.data[key] = element
+	heap.Push(&tcc.expiryQueue, element)
+	tcc.numBytes += element.size
+
+	return evicted
 }
 
-// sweep iterates over all contained elements checking if the element is still valid to be kept
-// It also operates on the locker so the call is concurrent safe
-func (tcc *timeCacheCore) sweep() {
+// refreshNoLock resets the timestamp of a contained entry to the current time and sets its span
+func (tcc *timeCacheCore) refreshNoLock(element *entry, duration time.Duration) {
+	element.timestamp = tcc.clock.Now()
+	element.span = duration
+	heap.Fix(&tcc.expiryQueue, element.queueIndex)
+}
+
+// makeRoomNoLock evicts the entries expiring first until a new entry can be added without exceeding the capacity
+func (tcc *timeCacheCore) makeRoomNoLock() map[string]*entry {
+	isBounded := tcc.maxNumItems > 0
+	if !isBounded || len(tcc.data) < tcc.maxNumItems {
+		return nil
+	}
+
+	evicted := make(map[string]*entry)
+	for len(tcc.data) >= tcc.maxNumItems {
+		element := heap.Pop(&tcc.expiryQueue).(*entry)
+		tcc.forgetNoLock(element)
+		evicted[element.key] = element
+	}
+
+	return evicted
+}
+
+// removeNoLock removes the entry, if contained, and returns it
+func (tcc *timeCacheCore) removeNoLock(key string) (*entry, bool) {
+	element, found := tcc.data[key]
+	if !found {
+		return nil, false
+	}
+
+	heap.Remove(&tcc.expiryQueue, element.queueIndex)
+	tcc.forgetNoLock(element)
+
+	return element, true
+}
+
+// forgetNoLock removes an entry already taken out of the expiry queue
+func (tcc *timeCacheCore) forgetNoLock(element *entry) {
+	delete(tcc.data, element.key)
+	tcc.numBytes -= element.size
+}
+
+// sweep removes the expired elements, starting with the one expiring first and stopping at the first element still
+// valid to be kept. It returns the removed elements. It also operates on the locker so the call is concurrent safe
+func (tcc *timeCacheCore) sweep() map[string]*entry {
 	tcc.Lock()
 	defer tcc.Unlock()
 
-	for key, element := range tcc.data {
-		isOldElement := time.Since(element.timestamp) > element.span
-		if isOldElement {
-			delete(tcc.data, key)
+	return tcc.sweepNoLock()
+}
+
+func (tcc *timeCacheCore) sweepNoLock() map[string]*entry {
+	now := tcc.clock.Now()
+	swept := make(map[string]*entry)
+	for {
+		element, ok := tcc.expiryQueue.peek()
+		if !ok || !element.isExpired(now) {
+			return swept
 		}
+
+		_ = heap.Pop(&tcc.expiryQueue)
+		tcc.forgetNoLock(element)
+		swept[element.key] = element
+	}
+}
+
+// getNoLock returns the entry of the key, unless it has expired while in strict expiry mode
+func (tcc *timeCacheCore) getNoLock(key string) (*entry, bool) {
+	element, ok := tcc.data[key]
+	if !ok || !tcc.isVisibleNoLock(element) {
+		return nil, false
 	}
+
+	return element, true
+}
+
+// isVisibleNoLock returns false for the expired entries not yet swept, if in strict expiry mode
+func (tcc *timeCacheCore) isVisibleNoLock(element *entry) bool {
+	return !tcc.strictExpiry || !element.isExpired(tcc.clock.Now())
 }
 
 // has returns if the key is still found in the time cache
@@ -117,23 +236,58 @@ func (tcc *timeCacheCore) has(key string) bool {
 	tcc.RLock()
 	defer tcc.RUnlock()
 
-	_, ok := tcc.data[key]
+	_, ok := tcc.getNoLock(key)
 
 	return ok
 }
 
-// len returns the number of elements which are still stored in the time cache
+// len returns the number of elements which are still stored in the time cache, the expired ones not yet swept
+// being excluded in strict expiry mode
 func (tcc *timeCacheCore) len() int {
 	tcc.RLock()
 	defer tcc.RUnlock()
 
-	return len(tcc.data)
+	if !tcc.strictExpiry {
+		return len(tcc.data)
+	}
+
+	return len(tcc.data) - tcc.expiryQueue.countExpired(0, tcc.clock.Now())
 }
 
-// clear recreates the map, thus deleting any existing entries
+// keys returns the keys which are still stored in the time cache, the expired ones not yet swept being excluded in
+// strict expiry mode
+func (tcc *timeCacheCore) keys() []string {
+	tcc.RLock()
+	defer tcc.RUnlock()
+
+	now := tcc.clock.Now()
+	keys := make([]string, 0, len(tcc.data))
+	for key, element := range tcc.data {
+		if !tcc.strictExpiry || !element.isExpired(now) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// sizeInBytes returns the size in bytes of the elements which are still stored in the time cache
+func (tcc *timeCacheCore) sizeInBytes() uint64 {
+	tcc.RLock()
+	defer tcc.RUnlock()
+
+	return uint64(tcc.numBytes)
+}
+
+// clear recreates the map and the expiry queue, thus deleting any existing entries, and returns the deleted entries
 // It also operates on the locker so the call is concurrent safe
-func (tcc *timeCacheCore) clear() {
+func (tcc *timeCacheCore) clear() map[string]*entry {
 	tcc.Lock()
+	cleared := tcc.data
 	tcc.data = make(map[string]*entry)
+	tcc.expiryQueue = nil
+	tcc.numBytes = 0
 	tcc.Unlock()
+
+	return cleared
 }
@@ -6,23 +6,27 @@ import (
 	"testing"
 	"time"
 
+	"github.com/multiversx/mx-chain-storage-go/testscommon"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTimeCacheCore_ConcurrentOperations(t *testing.T) {
 	t.Parallel()
 
-	tcc := newTimeCacheCore(time.Second)
+	fakeClock := testscommon.NewFakeClock(time.Now())
+	tcc := newTimeCacheCore(time.Second, 0, fakeClock)
 	numOperations := 1000
 	wg := &sync.WaitGroup{}
 	wg.Add(numOperations)
 	for i := 0; i < numOperations; i++ {
 		go func(idx int) {
-			time.Sleep(time.Millisecond * 10)
+			// the time moves concurrently with the operations, so that some of the entries expire meanwhile
+			fakeClock.Advance(time.Millisecond * 10)
 
 			switch idx % 7 {
 			case 0:
-				_, err := tcc.upsert(fmt.Sprintf("key%d", idx), fmt.Sprintf("valuey%d", idx), time.Second)
+				_, _, err := tcc.upsert(fmt.Sprintf("key%d", idx), fmt.Sprintf("valuey%d", idx), 0, time.Second)
 				assert.Nil(t, err)
 			case 1:
 				tcc.sweep()
@@ -33,10 +37,10 @@ func TestTimeCacheCore_ConcurrentOperations(t *testing.T) {
 			case 4:
 				tcc.clear()
 			case 5:
-				err := tcc.put(fmt.Sprintf("key%d", idx), fmt.Sprintf("valuey%d", idx), time.Second)
+				_, err := tcc.put(fmt.Sprintf("key%d", idx), fmt.Sprintf("valuey%d", idx), 0, time.Second)
 				assert.Nil(t, err)
 			case 6:
-				_, _, err := tcc.hasOrAdd(fmt.Sprintf("key%d", idx), fmt.Sprintf("valuey%d", idx), time.Second)
+				_, _, _, err := tcc.hasOrAdd(fmt.Sprintf("key%d", idx), fmt.Sprintf("valuey%d", idx), 0, time.Second)
 				assert.Nil(t, err)
 			default:
 				assert.Fail(t, "test setup error, change the line 'switch idx % xxx {' from this test")
@@ -48,3 +52,59 @@ func TestTimeCacheCore_ConcurrentOperations(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestTimeCacheCore_SweepShouldOnlyTouchTheExpiredEntries(t *testing.T) {
+	t.Parallel()
+
+	fakeClock := testscommon.NewFakeClock(time.Now())
+	tcc := newTimeCacheCore(time.Minute, 0, fakeClock)
+	for i := 0; i < 100; i++ {
+		_, err := tcc.put(fmt.Sprintf("key%d", i), i, 0, time.Hour)
+		require.Nil(t, err)
+	}
+	for i := 0; i < 5; i++ {
+		_, err := tcc.put(fmt.Sprintf("expired%d", i), i, 0, time.Millisecond)
+		require.Nil(t, err)
+	}
+	fakeClock.Advance(time.Millisecond * 10)
+
+	swept := tcc.sweep()
+	assert.Equal(t, 5, len(swept))
+	for i := 0; i < 5; i++ {
+		assert.Contains(t, swept, fmt.Sprintf("expired%d", i))
+	}
+	assert.Equal(t, 100, tcc.len())
+	assert.Equal(t, 100, tcc.expiryQueue.Len())
+	assert.Equal(t, 0, len(tcc.sweep()))
+}
+
+func TestTimeCacheCore_QueueShouldFollowTheDataChanges(t *testing.T) {
+	t.Parallel()
+
+	tcc := newTimeCacheCore(time.Minute, 3, nil)
+	_, _ = tcc.put("a", nil, 10, time.Minute)
+	_, _ = tcc.put("b", nil, 20, time.Minute*2)
+	_, _ = tcc.put("c", nil, 30, time.Minute*3)
+
+	// extending "a" makes "b" the first to expire
+	_, evicted, _ := tcc.upsert("a", nil, 10, time.Hour)
+	assert.Nil(t, evicted)
+
+	_, _, evicted, _ = tcc.hasOrAdd("d", nil, 40, time.Hour)
+	assert.Equal(t, 1, len(evicted))
+	assert.Contains(t, evicted, "b")
+
+	element, ok := tcc.removeNoLock("c")
+	assert.True(t, ok)
+	assert.Equal(t, "c", element.key)
+	assert.Equal(t, uint64(50), tcc.sizeInBytes())
+	assert.Equal(t, 2, tcc.expiryQueue.Len())
+	for i, element := range tcc.expiryQueue {
+		assert.Equal(t, i, element.queueIndex)
+	}
+
+	cleared := tcc.clear()
+	assert.Equal(t, 2, len(cleared))
+	assert.Equal(t, 0, tcc.expiryQueue.Len())
+	assert.Equal(t, uint64(0), tcc.sizeInBytes())
+}
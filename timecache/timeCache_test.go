@@ -7,6 +7,7 @@ import (
 
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/testscommon"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -62,11 +63,12 @@ func TestTimeCache_DoubleAddShouldWork(t *testing.T) {
 func TestTimeCache_DoubleAddAfterExpirationAndSweepShouldWork(t *testing.T) {
 	t.Parallel()
 
-	tc := NewTimeCache(time.Millisecond)
+	fakeClock := testscommon.NewFakeClock(time.Now())
+	tc, _ := NewTimeCacheWithArgs(ArgTimeCache{DefaultSpan: time.Millisecond, Clock: fakeClock})
 	key := "key1"
 
 	_ = tc.Add(key)
-	time.Sleep(time.Second)
+	fakeClock.Advance(time.Second)
 	tc.Sweep()
 	err := tc.Add(key)
 
@@ -124,12 +126,13 @@ func TestTimeCache_HasExistsShouldRetTrue(t *testing.T) {
 func TestTimeCache_HasCheckEvictionIsDoneProperly(t *testing.T) {
 	t.Parallel()
 
-	tc := NewTimeCache(time.Millisecond)
+	fakeClock := testscommon.NewFakeClock(time.Now())
+	tc, _ := NewTimeCacheWithArgs(ArgTimeCache{DefaultSpan: time.Millisecond, Clock: fakeClock})
 	key1 := "key1"
 	key2 := "key2"
 	_ = tc.Add(key1)
 	_ = tc.Add(key2)
-	time.Sleep(time.Second)
+	fakeClock.Advance(time.Second)
 	tc.Sweep()
 
 	exists1 := tc.Has(key1)
@@ -228,6 +231,104 @@ func TestTimeCache_Len(t *testing.T) {
 	}
 }
 
+// ------- Capacity
+
+func TestNewTimeCacheWithCapacity_InvalidCapacityShouldErr(t *testing.T) {
+	t.Parallel()
+
+	tc, err := NewTimeCacheWithCapacity(time.Second, 0)
+	assert.Nil(t, tc)
+	assert.Equal(t, common.ErrCacheSizeInvalid, err)
+}
+
+func TestTimeCache_CapacityShouldEvictTheKeysExpiringFirst(t *testing.T) {
+	t.Parallel()
+
+	tc, err := NewTimeCacheWithCapacity(time.Minute, 3)
+	require.Nil(t, err)
+
+	_ = tc.AddWithSpan("long", time.Hour)
+	_ = tc.AddWithSpan("short", time.Second)
+	_ = tc.Add("default")
+	_ = tc.AddWithSpan("medium", time.Minute*30)
+	assert.Equal(t, 3, tc.Len())
+	assert.False(t, tc.Has("short"))
+
+	// the upsert extends the span of an existing key, without evicting
+	_ = tc.Upsert("default", time.Hour*2)
+	assert.Equal(t, 3, tc.Len())
+
+	_ = tc.Add("new")
+	assert.Equal(t, 3, tc.Len())
+	assert.False(t, tc.Has("medium"))
+	assert.True(t, tc.Has("long"))
+	assert.True(t, tc.Has("default"))
+	assert.True(t, tc.Has("new"))
+}
+
+// ------- Strict expiry
+
+func TestNewTimeCacheWithArgs_InvalidCapacityShouldErr(t *testing.T) {
+	t.Parallel()
+
+	tc, err := NewTimeCacheWithArgs(ArgTimeCache{DefaultSpan: time.Second, MaxNumItems: -1})
+	assert.Nil(t, tc)
+	assert.Equal(t, common.ErrCacheSizeInvalid, err)
+}
+
+func TestTimeCache_StrictExpiryShouldHideExpiredKeys(t *testing.T) {
+	t.Parallel()
+
+	for _, strictExpiry := range []bool{false, true} {
+		fakeClock := testscommon.NewFakeClock(time.Now())
+		tc, err := NewTimeCacheWithArgs(ArgTimeCache{
+			DefaultSpan:  time.Minute,
+			StrictExpiry: strictExpiry,
+			Clock:        fakeClock,
+		})
+		require.Nil(t, err)
+
+		for i := 0; i < 10; i++ {
+			_ = tc.AddWithSpan(fmt.Sprintf("expired%d", i), time.Millisecond*time.Duration(i+1))
+		}
+		_ = tc.Add("valid")
+		fakeClock.Advance(time.Millisecond * 20)
+
+		assert.Equal(t, !strictExpiry, tc.Has("expired0"))
+		assert.True(t, tc.Has("valid"))
+		if strictExpiry {
+			assert.Equal(t, 1, tc.Len())
+		} else {
+			assert.Equal(t, 11, tc.Len())
+		}
+	}
+}
+
+func TestTimeCache_ExpiryHandlersShouldBeCalledOnSweep(t *testing.T) {
+	t.Parallel()
+
+	fakeClock := testscommon.NewFakeClock(time.Now())
+	tc, _ := NewTimeCacheWithArgs(ArgTimeCache{DefaultSpan: time.Minute, Clock: fakeClock})
+	expiredKeys := make([]string, 0)
+	tc.RegisterExpiryHandler(&testscommon.EvictionHandlerStub{
+		EvictedCalled: func(key []byte) {
+			expiredKeys = append(expiredKeys, string(key))
+		},
+	}, "id")
+
+	_ = tc.AddWithSpan("expired", time.Millisecond)
+	_ = tc.Add("valid")
+	fakeClock.Advance(time.Millisecond * 10)
+	tc.Sweep()
+	assert.Equal(t, []string{"expired"}, expiredKeys)
+
+	tc.UnRegisterExpiryHandler("id")
+	_ = tc.AddWithSpan("expired again", time.Millisecond)
+	fakeClock.Advance(time.Millisecond * 10)
+	tc.Sweep()
+	assert.Equal(t, []string{"expired"}, expiredKeys)
+}
+
 // ------- IsInterfaceNil
 
 func TestTimeCache_IsInterfaceNilNotNil(t *testing.T) {
@@ -2,14 +2,19 @@ package timecache
 
 import (
 	"context"
+	"fmt"
 	"math"
 	"sync"
 	"time"
 
 	logger "github.com/multiversx/mx-chain-logger-go"
 	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/eviction"
+	"github.com/multiversx/mx-chain-storage-go/types"
 )
 
+var _ types.EvictionNotifier = (*timeCacher)(nil)
+
 var log = logger.GetOrCreate("storage/timecache")
 
 const minDuration = time.Second
@@ -18,6 +23,13 @@ const minDuration = time.Second
 type ArgTimeCacher struct {
 	DefaultSpan time.Duration
 	CacheExpiry time.Duration
+	// MaxNumItems is the capacity of the cacher, the items expiring first being evicted when it is reached.
+	// Zero means unbounded.
+	MaxNumItems int
+	// StrictExpiry hides the expired items from the reads right away, instead of at the next sweep
+	StrictExpiry bool
+	// Clock is the source of time for the expiry of the items and for the sweeping. Nil means the system clock.
+	Clock types.Clock
 }
 
 // timeCacher implements a time cacher with automatic sweeping mechanism
@@ -26,6 +38,9 @@ type timeCacher struct {
 	cacheExpiry time.Duration
 	cancelFunc  func()
 
+	evictionHandlers eviction.Handlers
+	expiryHandlers   eviction.Handlers
+
 	mutAddedDataHandlers sync.RWMutex
 	mapDataHandlers      map[string]func(key []byte, value interface{})
 }
@@ -38,11 +53,13 @@ func NewTimeCacher(arg ArgTimeCacher) (*timeCacher, error) {
 	}
 
 	tc := &timeCacher{
-		timeCache:       newTimeCacheCore(arg.DefaultSpan),
+		timeCache:       newTimeCacheCore(arg.DefaultSpan, arg.MaxNumItems, arg.Clock),
 		cacheExpiry:     arg.CacheExpiry,
 		mapDataHandlers: make(map[string]func(key []byte, value interface{})),
 	}
 
+	tc.timeCache.strictExpiry = arg.StrictExpiry
+
 	var ctx context.Context
 	ctx, tc.cancelFunc = context.WithCancel(context.Background())
 	go tc.startSweeping(ctx)
@@ -57,21 +74,19 @@ func checkArg(arg ArgTimeCacher) error {
 	if arg.CacheExpiry < minDuration {
 		return common.ErrInvalidCacheExpiry
 	}
+	if arg.MaxNumItems < 0 {
+		return fmt.Errorf("%w: negative max num items", common.ErrInvalidConfig)
+	}
 
 	return nil
 }
 
 // startSweeping handles sweeping the time cache
 func (tc *timeCacher) startSweeping(ctx context.Context) {
-	timer := time.NewTimer(tc.cacheExpiry)
-	defer timer.Stop()
-
 	for {
-		timer.Reset(tc.cacheExpiry)
-
 		select {
-		case <-timer.C:
-			tc.timeCache.sweep()
+		case <-tc.timeCache.clock.After(tc.cacheExpiry):
+			tc.sweep()
 		case <-ctx.Done():
 			log.Info("closing mapTimeCacher's sweep go routine...")
 			return
@@ -79,22 +94,50 @@ func (tc *timeCacher) startSweeping(ctx context.Context) {
 	}
 }
 
+func (tc *timeCacher) sweep() {
+	swept := tc.timeCache.sweep()
+	tc.notifyEvicted(swept, types.EvictedByExpiry)
+	notifyEntries(&tc.expiryHandlers, swept, types.EvictedByExpiry)
+}
+
 // Clear deletes all stored data
 func (tc *timeCacher) Clear() {
-	tc.timeCache.clear()
+	tc.notifyEvicted(tc.timeCache.clear(), types.EvictedByClear)
+}
+
+func (tc *timeCacher) notifyEvicted(entries map[string]*entry, reason types.EvictionReason) {
+	notifyEntries(&tc.evictionHandlers, entries, reason)
+}
+
+// Put adds a value to the cache, for the default span. Returns true if items expiring first were evicted to make
+// room for a new key
+func (tc *timeCacher) Put(key []byte, value interface{}, sizeInBytes int) (evicted bool) {
+	return tc.PutWithSpan(key, value, sizeInBytes, tc.timeCache.defaultSpan)
 }
 
-// Put adds a value to the cache. It will always return false since the eviction did not occur
-func (tc *timeCacher) Put(key []byte, value interface{}, _ int) (evicted bool) {
-	err := tc.timeCache.put(string(key), value, tc.timeCache.defaultSpan)
+// PutWithSpan adds a value to the cache, for the provided span. Returns true if items expiring first were evicted
+// to make room for a new key. With strict expiry, the expired items are swept first, so that an expired item being
+// replaced is notified to the expiry handlers.
+func (tc *timeCacher) PutWithSpan(key []byte, value interface{}, sizeInBytes int, span time.Duration) (evicted bool) {
+	if span <= 0 {
+		log.Error("mapTimeCacher.PutWithSpan", "key", key, "error", common.ErrInvalidSpan)
+		return false
+	}
+	if tc.timeCache.strictExpiry {
+		// the expired items are swept first, so that they are notified before being replaced
+		tc.sweep()
+	}
+
+	evictedEntries, err := tc.timeCache.put(string(key), value, entrySizeInBytes(key, value, sizeInBytes), span)
 	if err != nil {
-		log.Error("mapTimeCacher.Put", "key", key, "error", err)
+		log.Error("mapTimeCacher.PutWithSpan", "key", key, "error", err)
 		return
 	}
 
+	tc.notifyEvicted(evictedEntries, types.EvictedByCapacity)
 	tc.callAddedDataHandlers(key, value)
 
-	return false
+	return len(evictedEntries) > 0
 }
 
 // Get returns a key's value from the cache
@@ -102,7 +145,7 @@ func (tc *timeCacher) Get(key []byte) (interface{}, bool) {
 	tc.timeCache.RLock()
 	defer tc.timeCache.RUnlock()
 
-	v, ok := tc.timeCache.data[string(key)]
+	v, ok := tc.timeCache.getNoLock(string(key))
 	if !ok {
 		return nil, ok
 	}
@@ -122,14 +165,22 @@ func (tc *timeCacher) Peek(key []byte) (value interface{}, ok bool) {
 
 // HasOrAdd checks if a key is in the cache.
 // If key exists, does not update the value. Otherwise, adds the key-value in the cache
-func (tc *timeCacher) HasOrAdd(key []byte, value interface{}, _ int) (has, added bool) {
+func (tc *timeCacher) HasOrAdd(key []byte, value interface{}, sizeInBytes int) (has, added bool) {
+	if tc.timeCache.strictExpiry {
+		// the expired items are swept first, so that they are notified before being replaced
+		tc.sweep()
+	}
+
 	var err error
-	has, added, err = tc.timeCache.hasOrAdd(string(key), value, tc.timeCache.defaultSpan)
+	var evictedEntries map[string]*entry
+	has, added, evictedEntries, err = tc.timeCache.hasOrAdd(string(key), value, entrySizeInBytes(key, value, sizeInBytes), tc.timeCache.defaultSpan)
 	if err != nil {
 		log.Error("mapTimeCacher.HasOrAdd", "key", key, "error", err)
 		return
 	}
 
+	tc.notifyEvicted(evictedEntries, types.EvictedByCapacity)
+
 	if !has {
 		tc.callAddedDataHandlers(key, value)
 	}
@@ -144,21 +195,20 @@ func (tc *timeCacher) Remove(key []byte) {
 	}
 
 	tc.timeCache.Lock()
-	defer tc.timeCache.Unlock()
+	element, ok := tc.timeCache.removeNoLock(string(key))
+	tc.timeCache.Unlock()
 
-	delete(tc.timeCache.data, string(key))
+	if ok {
+		tc.evictionHandlers.Notify(key, element.value, types.EvictedByRemoval)
+	}
 }
 
 // Keys returns all keys from cache
 func (tc *timeCacher) Keys() [][]byte {
-	tc.timeCache.RLock()
-	defer tc.timeCache.RUnlock()
-
-	keys := make([][]byte, len(tc.timeCache.data))
-	idx := 0
-	for k := range tc.timeCache.data {
-		keys[idx] = []byte(k)
-		idx++
+	stringKeys := tc.timeCache.keys()
+	keys := make([][]byte, len(stringKeys))
+	for i, key := range stringKeys {
+		keys[i] = []byte(key)
 	}
 
 	return keys
@@ -169,13 +219,28 @@ func (tc *timeCacher) Len() int {
 	return tc.timeCache.len()
 }
 
-// SizeInBytesContained will always return 0
+// SizeInBytesContained returns the size in bytes of all contained elements, including the keys and the
+// overhead of the entries
 func (tc *timeCacher) SizeInBytesContained() uint64 {
-	return 0
+	return tc.timeCache.sizeInBytes()
+}
+
+// entrySizeInBytes accounts the time cacher entries, the negative sizes being ignored as the time cacher is not
+// bounded in bytes
+func entrySizeInBytes(key []byte, value interface{}, sizeInBytes int) int {
+	if sizeInBytes < 0 {
+		sizeInBytes = 0
+	}
+
+	return types.ComputeEntrySizeInBytes(key, value, sizeInBytes)
 }
 
 // MaxSize returns the maximum number of items which can be stored in cache.
 func (tc *timeCacher) MaxSize() int {
+	if tc.timeCache.maxNumItems > 0 {
+		return tc.timeCache.maxNumItems
+	}
+
 	return math.MaxInt32
 }
 
@@ -198,6 +263,26 @@ func (tc *timeCacher) UnRegisterHandler(id string) {
 	tc.mutAddedDataHandlers.Unlock()
 }
 
+// RegisterEvictionHandler registers a new handler to be called for each item leaving the cache
+func (tc *timeCacher) RegisterEvictionHandler(handler types.EvictionHandlerFunc, id string) {
+	tc.evictionHandlers.Register(handler, id)
+}
+
+// UnRegisterEvictionHandler removes the eviction handler registered under the provided id
+func (tc *timeCacher) UnRegisterEvictionHandler(id string) {
+	tc.evictionHandlers.UnRegister(id)
+}
+
+// RegisterExpiryHandler registers a handler to be called, on sweep, with the key of each expired item
+func (tc *timeCacher) RegisterExpiryHandler(handler types.EvictionHandler, id string) {
+	registerExpiryHandler(&tc.expiryHandlers, handler, id)
+}
+
+// UnRegisterExpiryHandler removes the expiry handler registered under the provided id
+func (tc *timeCacher) UnRegisterExpiryHandler(id string) {
+	tc.expiryHandlers.UnRegister(id)
+}
+
 func (tc *timeCacher) callAddedDataHandlers(key []byte, value interface{}) {
 	tc.mutAddedDataHandlers.RLock()
 	for _, handler := range tc.mapDataHandlers {
@@ -2,6 +2,7 @@ package timecache_test
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"math"
 	"sort"
@@ -11,8 +12,11 @@ import (
 	"time"
 
 	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/testscommon"
 	"github.com/multiversx/mx-chain-storage-go/timecache"
+	"github.com/multiversx/mx-chain-storage-go/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func createArgTimeCacher() timecache.ArgTimeCacher {
@@ -54,6 +58,15 @@ func TestNewTimeCache(t *testing.T) {
 		assert.Nil(t, cacher)
 		assert.Equal(t, common.ErrInvalidCacheExpiry, err)
 	})
+	t.Run("negative MaxNumItems should error", func(t *testing.T) {
+		t.Parallel()
+
+		arg := createArgTimeCacher()
+		arg.MaxNumItems = -1
+		cacher, err := timecache.NewTimeCacher(arg)
+		assert.Nil(t, cacher)
+		assert.True(t, errors.Is(err, common.ErrInvalidConfig))
+	})
 	t.Run("should work", func(t *testing.T) {
 		t.Parallel()
 
@@ -128,49 +141,62 @@ func TestTimeCacher_HasOrAdd(t *testing.T) {
 		t.Parallel()
 
 		cacher, _ := timecache.NewTimeCacher(createArgTimeCacher())
+		addedKeys := make(chan []byte, 10)
 		cacher.RegisterHandler(func(key []byte, value interface{}) {
-			assert.Fail(t, "should have not added")
+			addedKeys <- key
 		}, "test")
 		t.Run("nil key", func(t *testing.T) {
 			has, added := cacher.HasOrAdd(nil, nil, 0)
 			assert.False(t, has)
 			assert.False(t, added)
 			assert.Equal(t, 0, cacher.Len())
-			time.Sleep(time.Second)
 		})
 		t.Run("empty key", func(t *testing.T) {
 			has, added := cacher.HasOrAdd(make([]byte, 0), nil, 0)
 			assert.False(t, has)
 			assert.False(t, added)
 			assert.Equal(t, 0, cacher.Len())
-			time.Sleep(time.Second)
 		})
+
+		// the handlers are called asynchronously, thus a valid key is added to check that it is the first one notified
+		_, _ = cacher.HasOrAdd([]byte("key"), nil, 0)
+		assert.Equal(t, []byte("key"), waitForAddedKey(t, addedKeys))
 	})
 	t.Run("should work", func(t *testing.T) {
 		t.Parallel()
 
 		cacher, _ := timecache.NewTimeCacher(createArgTimeCacher())
 		assert.False(t, cacher.IsInterfaceNil())
-		numAdded := int64(0)
+		addedKeys := make(chan []byte, 10)
 		cacher.RegisterHandler(func(key []byte, value interface{}) {
-			atomic.AddInt64(&numAdded, 1)
+			addedKeys <- key
 		}, "test")
 
 		providedKey, providedVal := []byte("key"), []byte("val")
 		has, added := cacher.HasOrAdd(providedKey, providedVal, len(providedVal))
 		assert.False(t, has)
 		assert.True(t, added)
-		time.Sleep(time.Second)
-		assert.Equal(t, int64(1), atomic.LoadInt64(&numAdded))
+		assert.Equal(t, providedKey, waitForAddedKey(t, addedKeys))
 
 		has, added = cacher.HasOrAdd(providedKey, providedVal, len(providedVal))
 		assert.True(t, has)
 		assert.False(t, added)
-		time.Sleep(time.Second)
-		assert.Equal(t, int64(1), atomic.LoadInt64(&numAdded))
+
+		_, _ = cacher.HasOrAdd([]byte("other"), providedVal, len(providedVal))
+		assert.Equal(t, []byte("other"), waitForAddedKey(t, addedKeys))
 	})
 }
 
+func waitForAddedKey(t *testing.T, addedKeys chan []byte) []byte {
+	select {
+	case key := <-addedKeys:
+		return key
+	case <-time.After(time.Second):
+		require.Fail(t, "the added data handler was not called")
+		return nil
+	}
+}
+
 func TestTimeCacher_Keys(t *testing.T) {
 	t.Parallel()
 
@@ -198,9 +224,11 @@ func TestTimeCacher_Keys(t *testing.T) {
 func TestTimeCacher_Evicted(t *testing.T) {
 	t.Parallel()
 
+	fakeClock := testscommon.NewFakeClock(time.Now())
 	arg := createArgTimeCacher()
 	arg.CacheExpiry = 2 * time.Second
 	arg.DefaultSpan = time.Second
+	arg.Clock = fakeClock
 	cacher, _ := timecache.NewTimeCacher(arg)
 	assert.False(t, cacher.IsInterfaceNil())
 
@@ -211,12 +239,26 @@ func TestTimeCacher_Evicted(t *testing.T) {
 	}
 	assert.Equal(t, numOfPairs, cacher.Len())
 
-	time.Sleep(2 * arg.CacheExpiry)
-	assert.Equal(t, 0, cacher.Len())
+	// the sweeping go routine waits for the clock to reach the cache expiry
+	waitForClockWaiters(t, fakeClock)
+	fakeClock.Advance(arg.CacheExpiry - time.Nanosecond)
+	assert.Equal(t, 1, fakeClock.NumWaiters())
+	assert.Equal(t, numOfPairs, cacher.Len())
+
+	fakeClock.Advance(time.Nanosecond)
+	assert.Eventually(t, func() bool {
+		return cacher.Len() == 0
+	}, time.Second, time.Millisecond)
 	err := cacher.Close()
 	assert.Nil(t, err)
 }
 
+func waitForClockWaiters(t *testing.T, fakeClock *testscommon.FakeClock) {
+	assert.Eventually(t, func() bool {
+		return fakeClock.NumWaiters() > 0
+	}, time.Second, time.Millisecond)
+}
+
 func TestTimeCacher_Peek(t *testing.T) {
 	t.Parallel()
 
@@ -242,30 +284,33 @@ func TestTimeCacher_Put(t *testing.T) {
 		t.Parallel()
 
 		cacher, _ := timecache.NewTimeCacher(createArgTimeCacher())
+		addedKeys := make(chan []byte, 10)
 		cacher.RegisterHandler(func(key []byte, value interface{}) {
-			assert.Fail(t, "should have not added")
+			addedKeys <- key
 		}, "test")
 		t.Run("nil key", func(t *testing.T) {
 			evicted := cacher.Put(nil, nil, 0)
 			assert.False(t, evicted)
 			assert.Equal(t, 0, cacher.Len())
-			time.Sleep(time.Second)
 		})
 		t.Run("empty key", func(t *testing.T) {
 			evicted := cacher.Put(make([]byte, 0), nil, 0)
 			assert.False(t, evicted)
 			assert.Equal(t, 0, cacher.Len())
-			time.Sleep(time.Second)
 		})
+
+		// the handlers are called asynchronously, thus a valid key is added to check that it is the first one notified
+		_ = cacher.Put([]byte("key"), nil, 0)
+		assert.Equal(t, []byte("key"), waitForAddedKey(t, addedKeys))
 	})
 	t.Run("should work", func(t *testing.T) {
 		t.Parallel()
 
 		cacher, _ := timecache.NewTimeCacher(createArgTimeCacher())
 		assert.False(t, cacher.IsInterfaceNil())
-		numAdded := int64(0)
+		addedKeys := make(chan []byte, 10)
 		cacher.RegisterHandler(func(key []byte, value interface{}) {
-			atomic.AddInt64(&numAdded, 1)
+			addedKeys <- key
 		}, "test")
 
 		numOfPairs := 2
@@ -273,14 +318,12 @@ func TestTimeCacher_Put(t *testing.T) {
 		evicted := cacher.Put(keys[0], vals[0], len(vals[0]))
 		assert.False(t, evicted)
 		assert.Equal(t, 1, cacher.Len())
-		time.Sleep(time.Second)
-		assert.Equal(t, int64(1), atomic.LoadInt64(&numAdded))
+		assert.Equal(t, keys[0], waitForAddedKey(t, addedKeys))
 
 		evicted = cacher.Put(keys[0], vals[1], len(vals[1]))
 		assert.False(t, evicted)
 		assert.Equal(t, 1, cacher.Len())
-		time.Sleep(time.Second)
-		assert.Equal(t, int64(2), atomic.LoadInt64(&numAdded))
+		assert.Equal(t, keys[0], waitForAddedKey(t, addedKeys))
 	})
 }
 
@@ -317,7 +360,23 @@ func TestTimeCacher_SizeInBytesContained(t *testing.T) {
 
 	providedKey, providedVal := []byte("key"), []byte("val")
 	cacher.Put(providedKey, providedVal, len(providedVal))
+	expectedSize := uint64(types.ComputeEntrySizeInBytes(providedKey, providedVal, len(providedVal)))
+	assert.Equal(t, expectedSize, cacher.SizeInBytesContained())
+
+	// replacing the value accounts only the new size
+	cacher.Put(providedKey, "value", 100)
+	expectedSize = uint64(types.ComputeEntrySizeInBytes(providedKey, "value", 100))
+	assert.Equal(t, expectedSize, cacher.SizeInBytesContained())
 
+	otherKey := []byte("other key")
+	_, _ = cacher.HasOrAdd(otherKey, "value", 10)
+	expectedSize += uint64(types.ComputeEntrySizeInBytes(otherKey, "value", 10))
+	assert.Equal(t, expectedSize, cacher.SizeInBytesContained())
+
+	cacher.Remove(providedKey)
+	assert.Equal(t, uint64(types.ComputeEntrySizeInBytes(otherKey, "value", 10)), cacher.SizeInBytesContained())
+
+	cacher.Clear()
 	assert.Zero(t, cacher.SizeInBytesContained())
 }
 
@@ -363,16 +422,60 @@ func TestTimeCacher_MaxSize(t *testing.T) {
 	assert.Equal(t, math.MaxInt32, cacher.MaxSize())
 }
 
+func TestTimeCacher_CapacityShouldEvictTheItemsExpiringFirst(t *testing.T) {
+	t.Parallel()
+
+	fakeClock := testscommon.NewFakeClock(time.Now())
+	arg := createArgTimeCacher()
+	arg.MaxNumItems = 2
+	arg.Clock = fakeClock
+	cacher, _ := timecache.NewTimeCacher(arg)
+	assert.Equal(t, 2, cacher.MaxSize())
+
+	mutEvicted := sync.Mutex{}
+	evicted := make(map[string]types.EvictionReason)
+	cacher.RegisterEvictionHandler(func(key []byte, value interface{}, reason types.EvictionReason) {
+		mutEvicted.Lock()
+		evicted[string(key)] = reason
+		mutEvicted.Unlock()
+	}, "id")
+
+	assert.False(t, cacher.Put([]byte("key1"), "value1", 0))
+	fakeClock.Advance(time.Millisecond)
+	assert.False(t, cacher.Put([]byte("key2"), "value2", 0))
+	fakeClock.Advance(time.Millisecond)
+	assert.True(t, cacher.Put([]byte("key3"), "value3", 0))
+	has, added := cacher.HasOrAdd([]byte("key4"), "value4", 0)
+	assert.False(t, has)
+	assert.True(t, added)
+
+	assert.Equal(t, 2, cacher.Len())
+	assert.True(t, cacher.Has([]byte("key3")))
+	assert.True(t, cacher.Has([]byte("key4")))
+
+	mutEvicted.Lock()
+	expectedEvicted := map[string]types.EvictionReason{
+		"key1": types.EvictedByCapacity,
+		"key2": types.EvictedByCapacity,
+	}
+	assert.Equal(t, expectedEvicted, evicted)
+	mutEvicted.Unlock()
+}
+
 func TestTimeCacher_ConcurrentOperations(t *testing.T) {
 	t.Parallel()
 
-	tc, _ := timecache.NewTimeCacher(createArgTimeCacher())
+	fakeClock := testscommon.NewFakeClock(time.Now())
+	arg := createArgTimeCacher()
+	arg.Clock = fakeClock
+	tc, _ := timecache.NewTimeCacher(arg)
 	numOperations := 1000
 	wg := &sync.WaitGroup{}
 	wg.Add(numOperations)
 	for i := 0; i < numOperations; i++ {
 		go func(idx int) {
-			time.Sleep(time.Millisecond * 10)
+			// the time moves concurrently with the operations, so that some of the items expire meanwhile
+			fakeClock.Advance(time.Millisecond * 10)
 
 			switch idx % 14 {
 			case 0:
@@ -421,3 +524,208 @@ func createKeyByteSlice(index int) []byte {
 func createValueByteSlice(index int) []byte {
 	return []byte(fmt.Sprintf("value%d", index))
 }
+
+func TestTimeCacher_EvictionHandlersShouldReceiveTheReason(t *testing.T) {
+	t.Parallel()
+
+	fakeClock := testscommon.NewFakeClock(time.Now())
+	arg := createArgTimeCacher()
+	arg.CacheExpiry = time.Second
+	arg.DefaultSpan = time.Second
+	arg.Clock = fakeClock
+	cacher, _ := timecache.NewTimeCacher(arg)
+	defer func() {
+		_ = cacher.Close()
+	}()
+
+	mut := sync.Mutex{}
+	reasons := make(map[string]types.EvictionReason)
+	cacher.RegisterEvictionHandler(func(key []byte, value interface{}, reason types.EvictionReason) {
+		mut.Lock()
+		reasons[string(key)] = reason
+		mut.Unlock()
+		assert.Equal(t, "value", value)
+	}, "id")
+	getReasons := func() map[string]types.EvictionReason {
+		mut.Lock()
+		defer mut.Unlock()
+
+		copied := make(map[string]types.EvictionReason)
+		for key, reason := range reasons {
+			copied[key] = reason
+		}
+
+		return copied
+	}
+
+	cacher.Put([]byte("expired"), "value", 0)
+	waitForClockWaiters(t, fakeClock)
+	fakeClock.Advance(arg.CacheExpiry * 2)
+	assert.Eventually(t, func() bool {
+		return getReasons()["expired"] == types.EvictedByExpiry && cacher.Len() == 0
+	}, time.Second, time.Millisecond)
+
+	cacher.Put([]byte("removed"), "value", 0)
+	cacher.Remove([]byte("removed"))
+	cacher.Remove([]byte("missing"))
+	cacher.Put([]byte("cleared"), "value", 0)
+	cacher.Clear()
+
+	assert.Equal(t, map[string]types.EvictionReason{
+		"expired": types.EvictedByExpiry,
+		"removed": types.EvictedByRemoval,
+		"cleared": types.EvictedByClear,
+	}, getReasons())
+}
+
+func TestTimeCacher_PutWithSpan(t *testing.T) {
+	t.Parallel()
+
+	fakeClock := testscommon.NewFakeClock(time.Now())
+	arg := createArgTimeCacher()
+	arg.Clock = fakeClock
+	cacher, _ := timecache.NewTimeCacher(arg)
+	defer func() {
+		_ = cacher.Close()
+	}()
+
+	assert.False(t, cacher.PutWithSpan([]byte("key"), "value", 0, 0))
+	assert.False(t, cacher.Has([]byte("key")))
+
+	assert.False(t, cacher.PutWithSpan([]byte("key"), "value", 0, time.Millisecond*10))
+	assert.True(t, cacher.Has([]byte("key")))
+
+	// without strict expiry the expired item is visible until swept
+	fakeClock.Advance(time.Millisecond * 20)
+	assert.True(t, cacher.Has([]byte("key")))
+}
+
+func TestTimeCacher_StrictExpiryShouldHideExpiredItems(t *testing.T) {
+	t.Parallel()
+
+	fakeClock := testscommon.NewFakeClock(time.Now())
+	arg := createArgTimeCacher()
+	arg.StrictExpiry = true
+	arg.Clock = fakeClock
+	cacher, _ := timecache.NewTimeCacher(arg)
+	defer func() {
+		_ = cacher.Close()
+	}()
+
+	expiredKeys := make(chan string, 10)
+	cacher.RegisterExpiryHandler(&testscommon.EvictionHandlerStub{
+		EvictedCalled: func(key []byte) {
+			expiredKeys <- string(key)
+		},
+	}, "id")
+
+	_ = cacher.PutWithSpan([]byte("short"), "value", 0, time.Millisecond*10)
+	_ = cacher.PutWithSpan([]byte("shorter"), "value", 0, time.Millisecond)
+	_ = cacher.Put([]byte("long"), "value", 0)
+	fakeClock.Advance(time.Millisecond * 20)
+
+	_, ok := cacher.Get([]byte("short"))
+	assert.False(t, ok)
+	_, ok = cacher.Peek([]byte("short"))
+	assert.False(t, ok)
+	assert.False(t, cacher.Has([]byte("short")))
+	assert.Equal(t, 1, cacher.Len())
+	assert.Equal(t, [][]byte{[]byte("long")}, cacher.Keys())
+
+	// the expired items are swept, thus notified, before being replaced
+	has, added := cacher.HasOrAdd([]byte("short"), "new value", 0)
+	assert.False(t, has)
+	assert.True(t, added)
+	value, _ := cacher.Get([]byte("short"))
+	assert.Equal(t, "new value", value)
+
+	close(expiredKeys)
+	notified := make([]string, 0)
+	for key := range expiredKeys {
+		notified = append(notified, key)
+	}
+	sort.Strings(notified)
+	assert.Equal(t, []string{"short", "shorter"}, notified)
+}
+
+func TestTimeCacher_StrictExpiryPutShouldNotifyTheReplacedExpiredItem(t *testing.T) {
+	t.Parallel()
+
+	fakeClock := testscommon.NewFakeClock(time.Now())
+	arg := createArgTimeCacher()
+	arg.StrictExpiry = true
+	arg.Clock = fakeClock
+	cacher, _ := timecache.NewTimeCacher(arg)
+	defer func() {
+		_ = cacher.Close()
+	}()
+
+	expiredKeys := make(chan string, 10)
+	cacher.RegisterExpiryHandler(&testscommon.EvictionHandlerStub{
+		EvictedCalled: func(key []byte) {
+			expiredKeys <- string(key)
+		},
+	}, "id")
+	reasons := make(chan types.EvictionReason, 10)
+	cacher.RegisterEvictionHandler(func(key []byte, value interface{}, reason types.EvictionReason) {
+		assert.Equal(t, "value", value)
+		reasons <- reason
+	}, "id")
+
+	_ = cacher.PutWithSpan([]byte("key"), "value", 0, time.Millisecond*10)
+	fakeClock.Advance(time.Millisecond * 20)
+
+	// the expired item is swept, thus notified, before being replaced
+	_ = cacher.Put([]byte("key"), "new value", 0)
+	value, _ := cacher.Get([]byte("key"))
+	assert.Equal(t, "new value", value)
+
+	close(expiredKeys)
+	close(reasons)
+	notified := make([]string, 0)
+	for key := range expiredKeys {
+		notified = append(notified, key)
+	}
+	assert.Equal(t, []string{"key"}, notified)
+	notifiedReasons := make([]types.EvictionReason, 0)
+	for reason := range reasons {
+		notifiedReasons = append(notifiedReasons, reason)
+	}
+	assert.Equal(t, []types.EvictionReason{types.EvictedByExpiry}, notifiedReasons)
+}
+
+func TestTimeCacher_ExpiryHandlersShouldBeCalledOnSweep(t *testing.T) {
+	t.Parallel()
+
+	fakeClock := testscommon.NewFakeClock(time.Now())
+	arg := createArgTimeCacher()
+	arg.CacheExpiry = time.Second
+	arg.DefaultSpan = time.Second
+	arg.Clock = fakeClock
+	cacher, _ := timecache.NewTimeCacher(arg)
+	defer func() {
+		_ = cacher.Close()
+	}()
+
+	numExpired := atomic.Int32{}
+	handler := &testscommon.EvictionHandlerStub{
+		EvictedCalled: func(key []byte) {
+			assert.Equal(t, []byte("key"), key)
+			numExpired.Add(1)
+		},
+	}
+	cacher.RegisterExpiryHandler(nil, "nil")
+	cacher.RegisterExpiryHandler(handler, "id")
+	cacher.RegisterExpiryHandler(handler, "removed")
+	cacher.UnRegisterExpiryHandler("removed")
+
+	cacher.Put([]byte("key"), "value", 0)
+	cacher.Put([]byte("removed"), "value", 0)
+	cacher.Remove([]byte("removed"))
+
+	waitForClockWaiters(t, fakeClock)
+	fakeClock.Advance(arg.CacheExpiry * 2)
+	assert.Eventually(t, func() bool {
+		return numExpired.Load() == 1 && cacher.Len() == 0
+	}, time.Second, time.Millisecond)
+}
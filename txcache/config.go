@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/types"
 )
 
 const numChunksLowerBound = 1
@@ -27,6 +28,8 @@ type ConfigSourceMe struct {
 	CountThreshold              uint32
 	CountPerSenderThreshold     uint32
 	NumItemsToPreemptivelyEvict uint32
+	// Clock is the source of time for the duration of the selection loop. Nil means the system clock.
+	Clock types.Clock `json:"-"`
 }
 
 type senderConstraints struct {
@@ -85,6 +88,17 @@ type ConfigDestinationMe struct {
 	MaxNumItems                 uint32
 	MaxNumBytes                 uint32
 	NumItemsToPreemptivelyEvict uint32
+	// MaxNumProtectedItems and MaxNumImmuneItems are the quotas of the immunity levels. Zero means MaxNumItems.
+	MaxNumProtectedItems uint32
+	MaxNumImmuneItems    uint32
+	// ImmunitySpanInSeconds is the time after which the immunity of a transaction is revoked. Zero means never.
+	ImmunitySpanInSeconds uint32
+	// MaxNumPendingImmunities bounds the number of immune transactions not received yet, the oldest ones being dropped
+	// when it is reached. Zero means unbounded.
+	MaxNumPendingImmunities uint32
+	// PendingImmunitySpanInSeconds is the time after which the immunity of a transaction not received yet is revoked.
+	// Zero means never.
+	PendingImmunitySpanInSeconds uint32
 }
 
 func (config *ConfigDestinationMe) verify() error {
@@ -23,11 +23,16 @@ func NewCrossTxCache(config ConfigDestinationMe) (*CrossTxCache, error) {
 	}
 
 	immunityCacheConfig := immunitycache.CacheConfig{
-		Name:                        config.Name,
-		NumChunks:                   config.NumChunks,
-		MaxNumBytes:                 config.MaxNumBytes,
-		MaxNumItems:                 config.MaxNumItems,
-		NumItemsToPreemptivelyEvict: config.NumItemsToPreemptivelyEvict,
+		Name:                         config.Name,
+		NumChunks:                    config.NumChunks,
+		MaxNumBytes:                  config.MaxNumBytes,
+		MaxNumItems:                  config.MaxNumItems,
+		NumItemsToPreemptivelyEvict:  config.NumItemsToPreemptivelyEvict,
+		MaxNumProtectedItems:         config.MaxNumProtectedItems,
+		MaxNumImmuneItems:            config.MaxNumImmuneItems,
+		ImmunitySpanInSeconds:        config.ImmunitySpanInSeconds,
+		MaxNumPendingImmunities:      config.MaxNumPendingImmunities,
+		PendingImmunitySpanInSeconds: config.PendingImmunitySpanInSeconds,
 	}
 
 	immunityCache, err := immunitycache.NewImmunityCache(immunityCacheConfig)
@@ -55,6 +60,30 @@ func (cache *CrossTxCache) ImmunizeTxsAgainstEviction(keys [][]byte) {
 	cache.Diagnose(false)
 }
 
+// ProtectTxsAgainstEviction marks items as evictable only if evicting the items without immunity does not suffice
+func (cache *CrossTxCache) ProtectTxsAgainstEviction(keys [][]byte) {
+	numNow, numFuture := cache.ImmunityCache.ImmunizeKeysWithLevel(keys, immunitycache.LevelProtected)
+	log.Trace("CrossTxCache.ProtectTxsAgainstEviction",
+		"name", cache.config.Name,
+		"len(keys)", len(keys),
+		"numNow", numNow,
+		"numFuture", numFuture,
+	)
+}
+
+// DemoteImmuneTxs downgrades the items immunized so far to protected ones. Called when a new block is processed,
+// the transactions of the current block are thus protected more strongly than the older ones.
+func (cache *CrossTxCache) DemoteImmuneTxs() {
+	numDemoted := cache.ImmunityCache.DemoteImmunity(immunitycache.LevelImmune, immunitycache.LevelProtected)
+	log.Trace("CrossTxCache.DemoteImmuneTxs", "name", cache.config.Name, "numDemoted", numDemoted)
+}
+
+// RevokeTxsImmunity makes the items evictable again, whatever their immunity level
+func (cache *CrossTxCache) RevokeTxsImmunity(keys [][]byte) {
+	numRevoked := cache.ImmunityCache.RevokeImmunity(keys)
+	log.Trace("CrossTxCache.RevokeTxsImmunity", "name", cache.config.Name, "len(keys)", len(keys), "numRevoked", numRevoked)
+}
+
 // AddTx adds a transaction in the cache
 func (cache *CrossTxCache) AddTx(tx *WrappedTransaction) (has, added bool) {
 	log.Trace("CrossTxCache.AddTx", "name", cache.config.Name, "txHash", tx.TxHash)
@@ -5,6 +5,7 @@ import (
 	"math"
 	"testing"
 
+	"github.com/multiversx/mx-chain-storage-go/immunitycache"
 	"github.com/stretchr/testify/require"
 )
 
@@ -24,6 +25,32 @@ func TestCrossTxCache_DoImmunizeTxsAgainstEviction(t *testing.T) {
 	require.ElementsMatch(t, []string{"a", "b", "e", "f", "i", "j", "k", "l"}, hashesAsStrings(cache.Keys()))
 }
 
+func TestCrossTxCache_TxsOfTheCurrentBlockShouldBeProtectedMoreStrongly(t *testing.T) {
+	cache := newCrossTxCacheToTest(1, 8, math.MaxUint16)
+
+	cache.addTestTxs("a", "b", "c", "d", "e", "f", "g", "h")
+	cache.ImmunizeTxsAgainstEviction(hashesAsBytes([]string{"a", "b"}))
+
+	// new block
+	cache.DemoteImmuneTxs()
+	cache.ImmunizeTxsAgainstEviction(hashesAsBytes([]string{"c", "d"}))
+	cache.ProtectTxsAgainstEviction(hashesAsBytes([]string{"e"}))
+	require.Equal(t, 5, cache.CountImmune())
+	require.Equal(t, 2, cache.CountImmuneByLevel(immunitycache.LevelImmune))
+
+	cache.addTestTxs("i", "j", "k")
+	require.ElementsMatch(t, []string{"a", "b", "c", "d", "e", "i", "j", "k"}, hashesAsStrings(cache.Keys()))
+
+	cache.ImmunizeTxsAgainstEviction(hashesAsBytes([]string{"i", "j", "k"}))
+	cache.addTestTxs("l", "m")
+	require.ElementsMatch(t, []string{"b", "c", "d", "e", "i", "j", "k", "m"}, hashesAsStrings(cache.Keys()))
+
+	cache.RevokeTxsImmunity(hashesAsBytes([]string{"c", "d", "e"}))
+	require.Equal(t, 4, cache.CountImmune())
+	cache.addTestTxs("n")
+	require.ElementsMatch(t, []string{"b", "d", "e", "i", "j", "k", "m", "n"}, hashesAsStrings(cache.Keys()))
+}
+
 func TestCrossTxCache_Get(t *testing.T) {
 	cache := newCrossTxCacheToTest(1, 8, math.MaxUint16)
 
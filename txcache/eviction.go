@@ -44,6 +44,7 @@ func (cache *TxCache) doEviction() *evictionJournal {
 	stopWatch.Start("eviction")
 
 	evictionJournal := cache.evictLeastLikelyToSelectTransactions()
+	cache.counters.RecordEvictions(evictionJournal.numEvicted)
 
 	stopWatch.Stop("eviction")
 
@@ -225,3 +225,37 @@ func TestBenchmarkTxCache_DoEviction(t *testing.T) {
 	// 0.546757s (TestBenchmarkTxCache_DoEviction/numSenders_=_10000,_numTransactions_=_100)
 	// 0.542678s (TestBenchmarkTxCache_DoEviction/numSenders_=_400000,_numTransactions_=_1)
 }
+
+func TestTxCache_DoEviction_ShouldCountEvictionsInStats(t *testing.T) {
+	config := ConfigSourceMe{
+		Name:                        "untitled",
+		NumChunks:                   16,
+		NumBytesThreshold:           maxNumBytesUpperBound,
+		NumBytesPerSenderThreshold:  maxNumBytesPerSenderUpperBound,
+		CountThreshold:              4,
+		CountPerSenderThreshold:     math.MaxUint32,
+		EvictionEnabled:             true,
+		NumItemsToPreemptivelyEvict: 1,
+	}
+
+	host := txcachemocks.NewMempoolHostMock()
+	cache, err := NewTxCache(config, host)
+	require.Nil(t, err)
+
+	cache.AddTx(createTx([]byte("hash-alice"), "alice", 1).withGasPrice(1 * oneBillion))
+	cache.AddTx(createTx([]byte("hash-bob"), "bob", 1).withGasPrice(2 * oneBillion))
+	cache.AddTx(createTx([]byte("hash-carol"), "carol", 1).withGasPrice(3 * oneBillion))
+	cache.AddTx(createTx([]byte("hash-eve"), "eve", 1).withGasPrice(4 * oneBillion))
+	cache.AddTx(createTx([]byte("hash-dan"), "dan", 1).withGasPrice(5 * oneBillion))
+	_ = cache.doEviction()
+
+	_, _ = cache.Get([]byte("hash-carol"))
+	_, _ = cache.Get([]byte("hash-alice"))
+
+	stats := cache.CacheStats()
+	require.Equal(t, 4, stats.NumItems)
+	require.Equal(t, cache.SizeInBytesContained(), stats.SizeInBytes)
+	require.Equal(t, uint64(1), stats.NumHits)
+	require.Equal(t, uint64(1), stats.NumMisses)
+	require.Equal(t, uint64(1), stats.NumEvictions)
+}
@@ -3,12 +3,14 @@ package txcache
 import (
 	"container/heap"
 	"time"
+
+	"github.com/multiversx/mx-chain-storage-go/types"
 )
 
 func (cache *TxCache) doSelectTransactions(session SelectionSession, gasRequested uint64, maxNum int, selectionLoopMaximumDuration time.Duration) (bunchOfTransactions, uint64) {
 	bunches := cache.acquireBunchesOfTransactions()
 
-	return selectTransactionsFromBunches(session, bunches, gasRequested, maxNum, selectionLoopMaximumDuration)
+	return selectTransactionsFromBunches(session, bunches, gasRequested, maxNum, selectionLoopMaximumDuration, cache.clock)
 }
 
 func (cache *TxCache) acquireBunchesOfTransactions() []bunchOfTransactions {
@@ -23,7 +25,8 @@ func (cache *TxCache) acquireBunchesOfTransactions() []bunchOfTransactions {
 }
 
 // Selection tolerates concurrent transaction additions / removals.
-func selectTransactionsFromBunches(session SelectionSession, bunches []bunchOfTransactions, gasRequested uint64, maxNum int, selectionLoopMaximumDuration time.Duration) (bunchOfTransactions, uint64) {
+// The duration of the selection loop is measured using the provided clock.
+func selectTransactionsFromBunches(session SelectionSession, bunches []bunchOfTransactions, gasRequested uint64, maxNum int, selectionLoopMaximumDuration time.Duration, clock types.Clock) (bunchOfTransactions, uint64) {
 	selectedTransactions := make(bunchOfTransactions, 0, initialCapacityOfSelectionSlice)
 	sessionWrapper := newSelectionSessionWrapper(session)
 
@@ -43,7 +46,7 @@ func selectTransactionsFromBunches(session SelectionSession, bunches []bunchOfTr
 	}
 
 	accumulatedGas := uint64(0)
-	selectionLoopStartTime := time.Now()
+	selectionLoopStartTime := clock.Now()
 
 	// Select transactions (sorted).
 	for transactionsHeap.Len() > 0 {
@@ -58,8 +61,9 @@ func selectTransactionsFromBunches(session SelectionSession, bunches []bunchOfTr
 			break
 		}
 		if len(selectedTransactions)%selectionLoopDurationCheckInterval == 0 {
-			if time.Since(selectionLoopStartTime) > selectionLoopMaximumDuration {
-				logSelect.Debug("TxCache.selectTransactionsFromBunches, selection loop timeout", "duration", time.Since(selectionLoopStartTime))
+			selectionLoopDuration := clock.Since(selectionLoopStartTime)
+			if selectionLoopDuration > selectionLoopMaximumDuration {
+				logSelect.Debug("TxCache.selectTransactionsFromBunches, selection loop timeout", "duration", selectionLoopDuration)
 				break
 			}
 		}
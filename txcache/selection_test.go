@@ -10,6 +10,8 @@ import (
 
 	"github.com/multiversx/mx-chain-core-go/core"
 	"github.com/multiversx/mx-chain-core-go/data"
+	"github.com/multiversx/mx-chain-storage-go/clock"
+	"github.com/multiversx/mx-chain-storage-go/testscommon"
 	"github.com/multiversx/mx-chain-storage-go/testscommon/txcachemocks"
 	"github.com/stretchr/testify/require"
 )
@@ -290,7 +292,7 @@ func TestTxCache_SelectTransactions_WhenTransactionsAddedInReversedNonceOrder(t
 func TestTxCache_selectTransactionsFromBunches(t *testing.T) {
 	t.Run("empty cache", func(t *testing.T) {
 		session := txcachemocks.NewSelectionSessionMock()
-		selected, accumulatedGas := selectTransactionsFromBunches(session, []bunchOfTransactions{}, 10_000_000_000, math.MaxInt, selectionLoopMaximumDuration)
+		selected, accumulatedGas := selectTransactionsFromBunches(session, []bunchOfTransactions{}, 10_000_000_000, math.MaxInt, selectionLoopMaximumDuration, clock.NewSystemClock())
 
 		require.Equal(t, 0, len(selected))
 		require.Equal(t, uint64(0), accumulatedGas)
@@ -407,7 +409,7 @@ func TestBenchmarkTxCache_selectTransactionsFromBunches(t *testing.T) {
 		bunches := createBunchesOfTransactionsWithUniformDistribution(1000, 1000)
 
 		sw.Start(t.Name())
-		selected, accumulatedGas := selectTransactionsFromBunches(session, bunches, 10_000_000_000, math.MaxInt, selectionLoopMaximumDuration)
+		selected, accumulatedGas := selectTransactionsFromBunches(session, bunches, 10_000_000_000, math.MaxInt, selectionLoopMaximumDuration, clock.NewSystemClock())
 		sw.Stop(t.Name())
 
 		require.Equal(t, 200000, len(selected))
@@ -419,7 +421,7 @@ func TestBenchmarkTxCache_selectTransactionsFromBunches(t *testing.T) {
 		bunches := createBunchesOfTransactionsWithUniformDistribution(1000, 1000)
 
 		sw.Start(t.Name())
-		selected, accumulatedGas := selectTransactionsFromBunches(session, bunches, 10_000_000_000, math.MaxInt, selectionLoopMaximumDuration)
+		selected, accumulatedGas := selectTransactionsFromBunches(session, bunches, 10_000_000_000, math.MaxInt, selectionLoopMaximumDuration, clock.NewSystemClock())
 		sw.Stop(t.Name())
 
 		require.Equal(t, 200000, len(selected))
@@ -431,7 +433,7 @@ func TestBenchmarkTxCache_selectTransactionsFromBunches(t *testing.T) {
 		bunches := createBunchesOfTransactionsWithUniformDistribution(100000, 3)
 
 		sw.Start(t.Name())
-		selected, accumulatedGas := selectTransactionsFromBunches(session, bunches, 10_000_000_000, math.MaxInt, selectionLoopMaximumDuration)
+		selected, accumulatedGas := selectTransactionsFromBunches(session, bunches, 10_000_000_000, math.MaxInt, selectionLoopMaximumDuration, clock.NewSystemClock())
 		sw.Stop(t.Name())
 
 		require.Equal(t, 200000, len(selected))
@@ -443,7 +445,7 @@ func TestBenchmarkTxCache_selectTransactionsFromBunches(t *testing.T) {
 		bunches := createBunchesOfTransactionsWithUniformDistribution(300000, 1)
 
 		sw.Start(t.Name())
-		selected, accumulatedGas := selectTransactionsFromBunches(session, bunches, 10_000_000_000, math.MaxInt, selectionLoopMaximumDuration)
+		selected, accumulatedGas := selectTransactionsFromBunches(session, bunches, 10_000_000_000, math.MaxInt, selectionLoopMaximumDuration, clock.NewSystemClock())
 		sw.Stop(t.Name())
 
 		require.Equal(t, 200000, len(selected))
@@ -472,11 +474,55 @@ func TestTxCache_selectTransactionsFromBunches_loopBreaks_whenTakesTooLong(t *te
 	t.Run("numSenders = 300000, numTransactions = 1", func(t *testing.T) {
 		session := txcachemocks.NewSelectionSessionMock()
 		bunches := createBunchesOfTransactionsWithUniformDistribution(300000, 1)
-		selected, accumulatedGas := selectTransactionsFromBunches(session, bunches, 10_000_000_000, 50_000, 1*time.Millisecond)
+		selected, accumulatedGas := selectTransactionsFromBunches(session, bunches, 10_000_000_000, 50_000, 1*time.Millisecond, clock.NewSystemClock())
 
 		require.Less(t, len(selected), 50_000)
 		require.Less(t, int(accumulatedGas), 10_000_000_000)
 	})
+
+	t.Run("with fake clock, each transaction taking 1 millisecond", func(t *testing.T) {
+		fakeClock := testscommon.NewFakeClock(time.Now())
+		session := txcachemocks.NewSelectionSessionMock()
+		session.IsIncorrectlyGuardedCalled = func(tx data.TransactionHandler) bool {
+			fakeClock.Advance(time.Millisecond)
+			return false
+		}
+
+		bunches := createBunchesOfTransactionsWithUniformDistribution(1000, 1)
+		selected, accumulatedGas := selectTransactionsFromBunches(session, bunches, 10_000_000_000, 50_000, 95*time.Millisecond, fakeClock)
+
+		// the duration is checked every "selectionLoopDurationCheckInterval" selected transactions
+		require.Equal(t, 100, len(selected))
+		require.Equal(t, uint64(100*50_000), accumulatedGas)
+	})
+}
+
+func TestTxCache_SelectTransactions_loopBreaks_whenClockExceedsMaximumDuration(t *testing.T) {
+	fakeClock := testscommon.NewFakeClock(time.Now())
+	session := txcachemocks.NewSelectionSessionMock()
+	session.IsIncorrectlyGuardedCalled = func(tx data.TransactionHandler) bool {
+		fakeClock.Advance(time.Millisecond)
+		return false
+	}
+
+	cache, err := NewTxCache(ConfigSourceMe{
+		Name:                        "test",
+		NumChunks:                   16,
+		NumBytesThreshold:           maxNumBytesUpperBound,
+		NumBytesPerSenderThreshold:  maxNumBytesPerSenderUpperBound,
+		CountThreshold:              math.MaxUint32,
+		CountPerSenderThreshold:     math.MaxUint32,
+		NumItemsToPreemptivelyEvict: 1,
+		Clock:                       fakeClock,
+	}, txcachemocks.NewMempoolHostMock())
+	require.Nil(t, err)
+	addManyTransactionsWithUniformDistribution(cache, 100, 10)
+
+	selected, _ := cache.SelectTransactions(session, 10_000_000_000, math.MaxInt, 20*time.Millisecond)
+	require.Equal(t, 30, len(selected))
+
+	selected, _ = cache.SelectTransactions(session, 10_000_000_000, math.MaxInt, selectionLoopMaximumDuration)
+	require.Equal(t, 1000, len(selected))
 }
 
 func TestBenchmarkTxCache_doSelectTransactions(t *testing.T) {
@@ -3,6 +3,7 @@ package txcache
 import (
 	"github.com/multiversx/mx-chain-core-go/core/atomic"
 	"github.com/multiversx/mx-chain-storage-go/txcache/maps"
+	"github.com/multiversx/mx-chain-storage-go/types"
 )
 
 // txByHashMap is a new map-like structure for holding and accessing transactions by txHash
@@ -10,6 +11,8 @@ type txByHashMap struct {
 	backingMap *maps.ConcurrentMap
 	counter    atomic.Counter
 	numBytes   atomic.Counter
+	// sizeInBytes accounts the hashes and the overhead of the entries besides the sizes of the transactions
+	sizeInBytes atomic.Counter
 }
 
 // newTxByHashMap creates a new TxByHashMap instance
@@ -27,6 +30,7 @@ func (txMap *txByHashMap) addTx(tx *WrappedTransaction) bool {
 	if added {
 		txMap.counter.Increment()
 		txMap.numBytes.Add(tx.Size)
+		txMap.sizeInBytes.Add(entrySizeInBytes(tx))
 	}
 
 	return added
@@ -47,6 +51,7 @@ func (txMap *txByHashMap) removeTx(txHash string) (*WrappedTransaction, bool) {
 	if removed {
 		txMap.counter.Decrement()
 		txMap.numBytes.Subtract(tx.Size)
+		txMap.sizeInBytes.Subtract(entrySizeInBytes(tx))
 	}
 
 	return tx, true
@@ -88,6 +93,12 @@ func (txMap *txByHashMap) forEach(function ForEachTransaction) {
 func (txMap *txByHashMap) clear() {
 	txMap.backingMap.Clear()
 	txMap.counter.Set(0)
+	txMap.numBytes.Set(0)
+	txMap.sizeInBytes.Set(0)
+}
+
+func entrySizeInBytes(tx *WrappedTransaction) int64 {
+	return int64(types.ComputeEntrySizeInBytes(tx.TxHash, nil, int(tx.Size)))
 }
 
 func (txMap *txByHashMap) keys() [][]byte {
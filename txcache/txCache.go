@@ -7,11 +7,13 @@ import (
 	"github.com/multiversx/mx-chain-core-go/core"
 	"github.com/multiversx/mx-chain-core-go/core/atomic"
 	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/clock"
 	"github.com/multiversx/mx-chain-storage-go/monitoring"
 	"github.com/multiversx/mx-chain-storage-go/types"
 )
 
 var _ types.Cacher = (*TxCache)(nil)
+var _ types.CacheStatsProvider = (*TxCache)(nil)
 
 // TxCache represents a cache-like structure (it has a fixed capacity and implements an eviction mechanism) for holding transactions
 type TxCache struct {
@@ -23,6 +25,9 @@ type TxCache struct {
 	evictionMutex        sync.Mutex
 	isEvictionInProgress atomic.Flag
 	mutTxOperation       sync.Mutex
+	counters             monitoring.CacheCounters
+	isMonitored          bool
+	clock                types.Clock
 }
 
 // NewTxCache creates a new transaction cache
@@ -48,7 +53,13 @@ func NewTxCache(config ConfigSourceMe, host MempoolHost) (*TxCache, error) {
 		txByHash:       newTxByHashMap(numChunks),
 		config:         config,
 		host:           host,
+		clock:          config.Clock,
 	}
+	if check.IfNil(txCache.clock) {
+		txCache.clock = clock.NewSystemClock()
+	}
+
+	txCache.isMonitored = monitoring.RegisterCache(config.Name, txCache)
 
 	return txCache, nil
 }
@@ -82,6 +93,7 @@ func (cache *TxCache) AddTx(tx *WrappedTransaction) (ok bool, added bool) {
 	}
 
 	if len(evicted) > 0 {
+		cache.counters.RecordEvictions(len(evicted))
 		logRemove.Trace("TxCache.AddTx with eviction", "sender", tx.Tx.GetSndAddr(), "num evicted txs", len(evicted))
 		cache.txByHash.RemoveTxsBulk(evicted)
 	}
@@ -171,9 +183,16 @@ func (cache *TxCache) Len() int {
 	return int(cache.CountTx())
 }
 
-// SizeInBytesContained returns 0
+// SizeInBytesContained returns the size in bytes of all contained transactions, including the hashes and the
+// overhead of the entries
 func (cache *TxCache) SizeInBytesContained() uint64 {
-	return 0
+	return cache.txByHash.sizeInBytes.GetUint64()
+}
+
+// CacheStats returns the statistics of the cache. The evictions include the transactions
+// removed due to the per-sender limits.
+func (cache *TxCache) CacheStats() types.CacheStats {
+	return cache.counters.Stats(cache.Len(), cache.SizeInBytesContained())
 }
 
 // CountSenders gets the number of senders in the cache
@@ -225,6 +244,7 @@ func (cache *TxCache) Put(_ []byte, _ interface{}, _ int) (evicted bool) {
 // Implemented for compatibility reasons (see txPoolsCleaner.go).
 func (cache *TxCache) Get(key []byte) (value interface{}, ok bool) {
 	tx, ok := cache.GetByTxHash(key)
+	cache.counters.RecordLookup(ok)
 	if ok {
 		return tx.Tx, true
 	}
@@ -241,6 +261,7 @@ func (cache *TxCache) Has(key []byte) bool {
 // Implemented for compatibility reasons (see transactions.go, common.go).
 func (cache *TxCache) Peek(key []byte) (value interface{}, ok bool) {
 	tx, ok := cache.GetByTxHash(key)
+	cache.counters.RecordLookup(ok)
 	if ok {
 		return tx.Tx, true
 	}
@@ -283,8 +304,12 @@ func (cache *TxCache) UnRegisterHandler(string) {
 func (cache *TxCache) ImmunizeTxsAgainstEviction(_ [][]byte) {
 }
 
-// Close does nothing for this cacher implementation
+// Close removes the cache from the monitoring registry
 func (cache *TxCache) Close() error {
+	if cache.isMonitored {
+		monitoring.UnregisterCache(cache.name)
+	}
+
 	return nil
 }
 
@@ -33,6 +33,8 @@ func Test_NewTxCache(t *testing.T) {
 	cache, err := NewTxCache(config, host)
 	require.Nil(t, err)
 	require.NotNil(t, cache)
+	// no clock means the system clock
+	require.NotNil(t, cache.clock)
 
 	badConfig := config
 	badConfig.Name = ""
@@ -229,6 +231,27 @@ func Test_RemoveByTxHash_RemovesFromByHash_WhenMapsInconsistency(t *testing.T) {
 	require.Equal(t, 0, cache.txByHash.backingMap.Count())
 }
 
+func Test_SizeInBytesContained(t *testing.T) {
+	cache := newUnconstrainedCacheToTest()
+
+	txAlice := createTx([]byte("hash-alice-1"), "alice", 1).withSize(128).withGasLimit(1500000)
+	txBob := createTx([]byte("hash-bob-7"), "bob", 7).withSize(256).withGasLimit(1500000)
+	cache.AddTx(txAlice)
+	cache.AddTx(txBob)
+
+	expectedSizeAlice := uint64(types.ComputeEntrySizeInBytes(txAlice.TxHash, nil, 128))
+	expectedSizeBob := uint64(types.ComputeEntrySizeInBytes(txBob.TxHash, nil, 256))
+	require.Equal(t, expectedSizeAlice+expectedSizeBob, cache.SizeInBytesContained())
+	require.Equal(t, cache.SizeInBytesContained(), cache.CacheStats().SizeInBytes)
+
+	_ = cache.RemoveTxByHash(txAlice.TxHash)
+	require.Equal(t, expectedSizeBob, cache.SizeInBytesContained())
+
+	cache.Clear()
+	require.Zero(t, cache.SizeInBytesContained())
+	require.Zero(t, cache.NumBytes())
+}
+
 func Test_Clear(t *testing.T) {
 	cache := newUnconstrainedCacheToTest()
 
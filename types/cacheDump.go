@@ -0,0 +1,7 @@
+package types
+
+// CacheDumpEntry holds one key of a dumped cache. Value is nil if the values were not dumped.
+type CacheDumpEntry struct {
+	Key   []byte
+	Value []byte
+}
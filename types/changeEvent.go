@@ -0,0 +1,31 @@
+package types
+
+// ChangeOperation represents the kind of change that occurred in a storage component
+type ChangeOperation uint8
+
+// Change operations that can be delivered by a change feed
+const (
+	PutOperation ChangeOperation = iota
+	RemoveOperation
+)
+
+// String returns a readable representation of the operation
+func (operation ChangeOperation) String() string {
+	switch operation {
+	case PutOperation:
+		return "put"
+	case RemoveOperation:
+		return "remove"
+	default:
+		return "unknown"
+	}
+}
+
+// ChangeEvent represents a change delivered by a change feed. Events are delivered in the order they were applied,
+// each one holding a strictly increasing sequence number, so that consumers can detect gaps.
+type ChangeEvent struct {
+	SequenceNumber uint64
+	Operation      ChangeOperation
+	Key            []byte
+	Value          []byte
+}
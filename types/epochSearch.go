@@ -0,0 +1,24 @@
+package types
+
+// EpochSearchOrder represents the order in which the epochs of a range are searched
+type EpochSearchOrder uint8
+
+// Epoch search orders
+const (
+	NewestEpochFirst EpochSearchOrder = iota
+	OldestEpochFirst
+)
+
+// EpochRange defines an inclusive range of epochs, together with the order in which they should be searched
+type EpochRange struct {
+	FirstEpoch uint32
+	LastEpoch  uint32
+	Order      EpochSearchOrder
+}
+
+// EpochKeyValuePair holds a key-value pair together with the epoch it was found in
+type EpochKeyValuePair struct {
+	Key   []byte
+	Value []byte
+	Epoch uint32
+}
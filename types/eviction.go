@@ -0,0 +1,34 @@
+package types
+
+// EvictionReason defines why an item left a cache
+type EvictionReason uint8
+
+const (
+	// EvictedByCapacity is an item evicted to make room for other items
+	EvictedByCapacity EvictionReason = iota
+	// EvictedByRemoval is an item explicitly removed from the cache
+	EvictedByRemoval
+	// EvictedByExpiry is an item whose time span has passed
+	EvictedByExpiry
+	// EvictedByClear is an item dropped when the whole cache was cleared
+	EvictedByClear
+)
+
+// String returns the readable name of the eviction reason
+func (reason EvictionReason) String() string {
+	switch reason {
+	case EvictedByCapacity:
+		return "capacity"
+	case EvictedByRemoval:
+		return "removal"
+	case EvictedByExpiry:
+		return "expiry"
+	case EvictedByClear:
+		return "clear"
+	default:
+		return "unknown"
+	}
+}
+
+// EvictionHandlerFunc is called for each item leaving a cache, with the reason of its departure
+type EvictionHandlerFunc func(key []byte, value interface{}, reason EvictionReason)
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/multiversx/mx-chain-core-go/data"
+	"github.com/multiversx/mx-chain-storage-go/common"
 )
 
 // Persister provides storage of data services in a database like construct
@@ -27,6 +28,12 @@ type Persister interface {
 	IsInterfaceNil() bool
 }
 
+// PersisterWithMultiGet is an extended persister able to fetch several keys in one call
+type PersisterWithMultiGet interface {
+	Persister
+	MultiGet(keys [][]byte) []KeyValueResult
+}
+
 // Batcher allows to batch the data first then write the batch to the persister in one go
 type Batcher interface {
 	// Put inserts one entry - key, value pair - into the batch
@@ -66,7 +73,9 @@ type Cacher interface {
 	Keys() [][]byte
 	// Len returns the number of items in the cache.
 	Len() int
-	// SizeInBytesContained returns the size in bytes of all contained elements
+	// SizeInBytesContained returns the size in bytes of all contained elements. Each entry accounts for the size of
+	// its value, the length of its key and the EntryOverheadInBytes, as computed by ComputeEntrySizeInBytes. The
+	// capacity of the caches bounded in bytes is still checked against the sizes provided by the callers.
 	SizeInBytesContained() uint64
 	// MaxSize returns the maximum number of items which can be stored in the cache.
 	MaxSize() int
@@ -81,6 +90,77 @@ type Cacher interface {
 	IsInterfaceNil() bool
 }
 
+// Cache is the type safe counterpart of the Cacher, holding values of type V under keys of type K
+type Cache[K comparable, V any] interface {
+	// Clear is used to completely clear the cache.
+	Clear()
+	// Put adds a value to the cache.  Returns true if an eviction occurred.
+	Put(key K, value V, sizeInBytes int) (evicted bool)
+	// Get looks up a key's value from the cache.
+	Get(key K) (value V, ok bool)
+	// Has checks if a key is in the cache, without updating the
+	// recent-ness or deleting it for being stale.
+	Has(key K) bool
+	// Peek returns the key value (or the zero value if not found) without updating
+	// the "recently used"-ness of the key.
+	Peek(key K) (value V, ok bool)
+	// HasOrAdd checks if a key is in the cache without updating the
+	// recent-ness or deleting it for being stale, and if not adds the value.
+	HasOrAdd(key K, value V, sizeInBytes int) (has, added bool)
+	// Remove removes the provided key from the cache.
+	Remove(key K)
+	// Keys returns a slice of the keys in the cache, from oldest to newest.
+	Keys() []K
+	// Len returns the number of items in the cache.
+	Len() int
+	// SizeInBytesContained returns the size in bytes of all contained elements
+	SizeInBytesContained() uint64
+	// MaxSize returns the maximum number of items which can be stored in the cache.
+	MaxSize() int
+	// IsInterfaceNil returns true if there is no value under the interface
+	IsInterfaceNil() bool
+}
+
+// LoaderFunc loads the value of a key which is missing from a cache, together with its size in bytes
+type LoaderFunc func(key []byte) (value interface{}, sizeInBytes int, err error)
+
+// LoadingCacher is a cache which loads by itself the values of the missing or stale keys
+type LoadingCacher interface {
+	// Get returns the value of the key, loading it if it is missing
+	Get(key []byte) (value interface{}, err error)
+	// Put adds or replaces a fresh value
+	Put(key []byte, value interface{}, sizeInBytes int)
+	// Remove removes the provided key from the cache
+	Remove(key []byte)
+	// Clear is used to completely clear the cache
+	Clear()
+	// Len returns the number of items in the cache, the cached load errors included
+	Len() int
+	// Close waits for the background refreshes to finish and closes the underlying cacher
+	Close() error
+	// IsInterfaceNil returns true if there is no value under the interface
+	IsInterfaceNil() bool
+}
+
+// ResizableCacher is a cacher whose limits can be changed at runtime
+type ResizableCacher interface {
+	Cacher
+	Resize(maxNumItems int, maxSizeInBytes int64) error
+}
+
+// CacherWithTTL is a cacher able to expire each item after its own TTL
+type CacherWithTTL interface {
+	Cacher
+	PutWithTTL(key []byte, value interface{}, sizeInBytes int, ttl time.Duration) (evicted bool)
+}
+
+// CacheDumper saves the contents of a cache so that they can be restored after a restart
+type CacheDumper interface {
+	Dump(cacher Cacher) error
+	Load() ([]CacheDumpEntry, error)
+	IsInterfaceNil() bool
+}
+
 // Storer provides storage services in a two layered storage construct, where the first layer is
 // represented by a cache and second layer by a persitent storage (DB-like)
 type Storer interface {
@@ -107,6 +187,19 @@ type StorerWithPutInEpoch interface {
 	SetEpochForPutOperation(epoch uint32)
 }
 
+// StorerWithBulkGet is an extended storer able to report the outcome of a bulk read for each key
+type StorerWithBulkGet interface {
+	Storer
+	GetBulk(keys [][]byte, options GetBulkOptions) []KeyValueResult
+}
+
+// StorerWithEpochRange is an extended storer able to search keys over a range of epochs and to iterate the keys of one epoch
+type StorerWithEpochRange interface {
+	Storer
+	GetBulkFromEpochRange(keys [][]byte, epochRange EpochRange) ([]EpochKeyValuePair, error)
+	RangeKeysFromEpoch(epoch uint32, handler func(key []byte, val []byte) bool)
+}
+
 // PersisterFactory defines which actions should be done for creating a persister
 type PersisterFactory interface {
 	Create(path string) (Persister, error)
@@ -174,7 +267,10 @@ type SizedLRUCacheHandler interface {
 	Remove(key interface{}) bool
 	Keys() []interface{}
 	Len() int
+	// SizeInBytesContained returns the sum of the sizes provided by the callers, which bounds the capacity
 	SizeInBytesContained() uint64
+	// EntriesSizeInBytes returns the size of all contained entries, as computed by ComputeAnyKeyEntrySizeInBytes
+	EntriesSizeInBytes() uint64
 	Purge()
 }
 
@@ -187,11 +283,36 @@ type TimeCacher interface {
 	IsInterfaceNil() bool
 }
 
+// Clock defines a source of time, so that the time dependent components can be tested deterministically
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	After(d time.Duration) <-chan time.Time
+	IsInterfaceNil() bool
+}
+
+// RateLimiter defines a component which counts the occurrences of the keys over sliding time windows, banning the
+// keys exceeding the configured thresholds
+type RateLimiter interface {
+	Record(key string) error
+	Counts(key string) []uint32
+	IsBanned(key string) bool
+	Sweep()
+	IsInterfaceNil() bool
+}
+
 // EvictionHandler defines a component which can be registered on TimeCacher
 type EvictionHandler interface {
 	Evicted(key []byte)
 }
 
+// EvictionNotifier defines a cacher which notifies the registered handlers about the items leaving it. The handlers
+// are called synchronously, outside the cache locks, so they should return fast.
+type EvictionNotifier interface {
+	RegisterEvictionHandler(handler EvictionHandlerFunc, id string)
+	UnRegisterEvictionHandler(id string)
+}
+
 // AdaptedSizedLRUCache defines a cache that returns the evicted value
 type AdaptedSizedLRUCache interface {
 	SizedLRUCacheHandler
@@ -230,3 +351,65 @@ type PersisterCreator interface {
 	CreateBasePersister(path string) (Persister, error)
 	IsInterfaceNil() bool
 }
+
+// ChangeSubscription defines a subscription to a change feed
+type ChangeSubscription interface {
+	ID() string
+	Events() <-chan ChangeEvent
+	NumDropped() uint64
+	IsInterfaceNil() bool
+}
+
+// ChangeFeedHandler defines a component able to deliver its changes to the registered subscribers
+type ChangeFeedHandler interface {
+	Subscribe(config common.SubscriptionConfig) (ChangeSubscription, error)
+	Unsubscribe(id string)
+	IsInterfaceNil() bool
+}
+
+// ChangeFeed defines a change feed on which the storage components publish their changes
+type ChangeFeed interface {
+	ChangeFeedHandler
+	Publish(operation ChangeOperation, key []byte, value []byte)
+	ReserveSequenceNumber() uint64
+	PublishReserved(sequenceNumber uint64, operation ChangeOperation, key []byte, value []byte)
+	Close()
+}
+
+// OperationRecorder records the outcome and the latency of the operations executed on a monitored unit
+type OperationRecorder interface {
+	Record(operation string, outcome OperationOutcome, duration time.Duration)
+	IsInterfaceNil() bool
+}
+
+// MetricsRegistry holds the metrics of the monitored units
+type MetricsRegistry interface {
+	RegisterUnit(name string, kind UnitKind) (OperationRecorder, error)
+	UnregisterUnit(name string)
+	RegisterCache(name string, cache CacheStatsProvider) error
+	UnregisterCache(name string)
+	Snapshot() MetricsSnapshot
+	IsInterfaceNil() bool
+}
+
+// CacheStatsProvider defines a cache able to report its statistics
+type CacheStatsProvider interface {
+	CacheStats() CacheStats
+	IsInterfaceNil() bool
+}
+
+// MetricsExporter defines the component able to publish the collected metrics
+type MetricsExporter interface {
+	Export(snapshot MetricsSnapshot) error
+	IsInterfaceNil() bool
+}
+
+// MemoryBudgetHandler splits a process-wide memory budget between the registered caches
+type MemoryBudgetHandler interface {
+	RegisterCache(name string, cache ResizableCacher, sizeInBytes uint64, priority common.CachePriority) error
+	UnregisterCache(name string)
+	SetMaxSizeInBytes(maxSizeInBytes uint64)
+	Report() MemoryBudgetReport
+	CheckOverCommitment() error
+	IsInterfaceNil() bool
+}
@@ -0,0 +1,19 @@
+package types
+
+// KeyValueResult holds the outcome of reading one key in a bulk operation. Err is nil if the key was found,
+// it wraps common.ErrKeyNotFound if the key is missing and holds the encountered error otherwise.
+type KeyValueResult struct {
+	Key   []byte
+	Value []byte
+	Err   error
+}
+
+// GetBulkOptions holds the options of a bulk read
+type GetBulkOptions struct {
+	// KeepOrder guarantees that the i-th result corresponds to the i-th provided key. Otherwise, the cache hits
+	// are returned first, followed by the results read from the persistence medium.
+	KeepOrder bool
+	// MaxConcurrency is the maximum number of concurrent reads done on the persistence medium.
+	// Zero means that a default value will be used.
+	MaxConcurrency int
+}
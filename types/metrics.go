@@ -0,0 +1,102 @@
+package types
+
+import (
+	"time"
+
+	"github.com/multiversx/mx-chain-storage-go/common"
+)
+
+// UnitKind defines the kind of a monitored unit
+type UnitKind string
+
+const (
+	// StorerUnit is a monitored storer
+	StorerUnit UnitKind = "storer"
+	// PersisterUnit is a monitored persister
+	PersisterUnit UnitKind = "persister"
+	// CacherUnit is a monitored cacher
+	CacherUnit UnitKind = "cacher"
+)
+
+// OperationOutcome defines the outcome of a monitored operation
+type OperationOutcome uint8
+
+const (
+	// SuccessOutcome is an operation that completed without error
+	SuccessOutcome OperationOutcome = iota
+	// HitOutcome is a lookup that found the key
+	HitOutcome
+	// MissOutcome is a lookup that did not find the key
+	MissOutcome
+	// ErrorOutcome is an operation that failed
+	ErrorOutcome
+)
+
+// LatencyBucket holds the number of observations with a latency lower or equal to the upper bound
+type LatencyBucket struct {
+	UpperBound time.Duration
+	Count      uint64
+}
+
+// LatencyHistogram is a snapshot of the latencies recorded for an operation. The buckets are cumulative,
+// the observations above the last upper bound are only accounted in Count
+type LatencyHistogram struct {
+	Buckets []LatencyBucket
+	Count   uint64
+	Sum     time.Duration
+}
+
+// OperationMetrics is a snapshot of the metrics recorded for an operation
+type OperationMetrics struct {
+	NumCalls  uint64
+	NumHits   uint64
+	NumMisses uint64
+	NumErrors uint64
+	Latency   LatencyHistogram
+}
+
+// UnitMetrics is a snapshot of the metrics recorded for a monitored unit, indexed by operation name
+type UnitMetrics struct {
+	Name       string
+	Kind       UnitKind
+	Operations map[string]OperationMetrics
+}
+
+// CacheStats is a snapshot of the statistics of a cache
+type CacheStats struct {
+	NumItems     int
+	SizeInBytes  uint64
+	NumHits      uint64
+	NumMisses    uint64
+	NumEvictions uint64
+}
+
+// CacheMetrics holds the statistics of a named cache
+type CacheMetrics struct {
+	Name  string
+	Stats CacheStats
+}
+
+// MetricsSnapshot holds the metrics of all the monitored units and caches
+type MetricsSnapshot struct {
+	Units  []UnitMetrics
+	Caches []CacheMetrics
+}
+
+// CacheBudget holds the memory budget state of a cache
+type CacheBudget struct {
+	Name                 string
+	Priority             common.CachePriority
+	RequestedSizeInBytes uint64
+	GrantedSizeInBytes   uint64
+	UsedSizeInBytes      uint64
+}
+
+// MemoryBudgetReport holds the state of the memory budget
+type MemoryBudgetReport struct {
+	MaxSizeInBytes       uint64
+	RequestedSizeInBytes uint64
+	GrantedSizeInBytes   uint64
+	IsOverCommitted      bool
+	Caches               []CacheBudget
+}
@@ -0,0 +1,48 @@
+package types
+
+// EntryOverheadInBytes estimates the memory used by a cache for holding an entry besides its key and value: the map
+// slot, the list element and the entry header
+const EntryOverheadInBytes = 64
+
+// Sizer is implemented by the cached values able to report their own size in bytes
+type Sizer interface {
+	Size() int
+}
+
+// ComputeEntrySizeInBytes returns the number of bytes reported by a cache for an entry: the value, the key and the
+// overhead per entry. The size of the value is computed by ComputeValueSizeInBytes. A negative provided size is
+// returned as it is. The caches keep bounding their capacity by the size provided by the caller, this size being
+// only reported.
+func ComputeEntrySizeInBytes[K string | []byte](key K, value interface{}, sizeInBytes int) int {
+	if sizeInBytes < 0 {
+		return sizeInBytes
+	}
+
+	return ComputeValueSizeInBytes(value, sizeInBytes) + len(key) + EntryOverheadInBytes
+}
+
+// ComputeAnyKeyEntrySizeInBytes is the counterpart of ComputeEntrySizeInBytes for the caches holding keys of any
+// type, only the length of the string and byte slice keys being accounted
+func ComputeAnyKeyEntrySizeInBytes(key interface{}, value interface{}, sizeInBytes int) int {
+	switch k := key.(type) {
+	case string:
+		return ComputeEntrySizeInBytes(k, value, sizeInBytes)
+	case []byte:
+		return ComputeEntrySizeInBytes(k, value, sizeInBytes)
+	default:
+		return ComputeEntrySizeInBytes("", value, sizeInBytes)
+	}
+}
+
+// ComputeValueSizeInBytes returns the size of a cached value: the one reported by a Sizer value, the length of a
+// byte slice value, or the size provided by the caller otherwise
+func ComputeValueSizeInBytes(value interface{}, sizeInBytes int) int {
+	switch v := value.(type) {
+	case Sizer:
+		return v.Size()
+	case []byte:
+		return len(v)
+	default:
+		return sizeInBytes
+	}
+}
@@ -0,0 +1,101 @@
+package types_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-storage-go/fifocache"
+	"github.com/multiversx/mx-chain-storage-go/genericcache"
+	"github.com/multiversx/mx-chain-storage-go/immunitycache"
+	"github.com/multiversx/mx-chain-storage-go/lrucache"
+	"github.com/multiversx/mx-chain-storage-go/lrucache/capacity"
+	"github.com/multiversx/mx-chain-storage-go/memorydb"
+	"github.com/multiversx/mx-chain-storage-go/storageCacherAdapter"
+	"github.com/multiversx/mx-chain-storage-go/testscommon"
+	"github.com/multiversx/mx-chain-storage-go/testscommon/trieFactory"
+	"github.com/multiversx/mx-chain-storage-go/timecache"
+	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sizerStub struct {
+	size int
+}
+
+// Size -
+func (s *sizerStub) Size() int {
+	return s.size
+}
+
+func createCachers(t *testing.T) map[string]types.Cacher {
+	cachers := make(map[string]types.Cacher)
+	cachers["LRU"], _ = lrucache.NewCache(10)
+	cachers["SizeLRU"], _ = lrucache.NewCacheWithSizeInBytes(10, 10000)
+	cachers["2Q"], _ = lrucache.NewTwoQueueCache(10)
+	cachers["Size2Q"], _ = lrucache.NewTwoQueueCacheWithSizeInBytes(10, 10000)
+	cachers["SizeTinyLFU"], _ = lrucache.NewTinyLFUCacheWithSizeInBytes(10, 10000)
+	cachers["ShardedSizeLRU"], _ = lrucache.NewShardedCacheWithSizeInBytes(2, 10, 10000)
+	cachers["ExpiringLRU"], _ = lrucache.NewExpiringCache(10, 10000, lrucache.ExpiryConfig{TTL: time.Hour})
+	cachers["FIFOSharded"], _ = fifocache.NewShardedCacheWithSizeInBytes(10, 2, 10000)
+	cachers["TimeCacher"], _ = timecache.NewTimeCacher(timecache.ArgTimeCacher{DefaultSpan: time.Hour, CacheExpiry: time.Hour})
+	cachers["ImmunityCache"], _ = immunitycache.NewImmunityCache(immunitycache.CacheConfig{
+		Name:                        "test",
+		NumChunks:                   2,
+		MaxNumItems:                 10,
+		MaxNumBytes:                 10000,
+		NumItemsToPreemptivelyEvict: 1,
+	})
+
+	genericLRU, _ := genericcache.NewSizedLRUCache[string, interface{}](10, 10000)
+	cachers["GenericLRU"], _ = genericcache.NewCacherAdapter[interface{}](genericLRU)
+	genericFIFO, _ := genericcache.NewSizedFIFOCache[string, interface{}](10, 2, 10000, genericcache.StringHasher)
+	cachers["GenericFIFO"], _ = genericcache.NewCacherAdapter[interface{}](genericFIFO)
+
+	capacityLRU, _ := capacity.NewCapacityLRU(10, 10000)
+	cachers["StorageCacherAdapter"], _ = storageCacherAdapter.NewStorageCacherAdapter(
+		capacityLRU,
+		memorydb.New(),
+		trieFactory.NewTrieNodeFactory(),
+		&testscommon.MarshalizerMock{},
+	)
+
+	for name, cacher := range cachers {
+		require.NotNil(t, cacher, name)
+	}
+
+	return cachers
+}
+
+func TestCachers_SizeInBytesContainedShouldBeTheSameForAllImplementations(t *testing.T) {
+	t.Parallel()
+
+	bytesEntrySize := uint64(types.ComputeEntrySizeInBytes("bytes", []byte("value"), 100))
+	stringEntrySize := uint64(types.ComputeEntrySizeInBytes("string", "value", 30))
+	sizerEntrySize := uint64(types.ComputeEntrySizeInBytes("sizer", &sizerStub{size: 300}, 1))
+	assert.Equal(t, uint64(len("value")+len("bytes")+types.EntryOverheadInBytes), bytesEntrySize)
+	assert.Equal(t, uint64(30+len("string")+types.EntryOverheadInBytes), stringEntrySize)
+	assert.Equal(t, uint64(300+len("sizer")+types.EntryOverheadInBytes), sizerEntrySize)
+
+	for name, cacher := range createCachers(t) {
+		cacher := cacher
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				_ = cacher.Close()
+			}()
+
+			_ = cacher.Put([]byte("bytes"), []byte("value"), 100)
+			_, _ = cacher.HasOrAdd([]byte("string"), "value", 30)
+			_ = cacher.Put([]byte("sizer"), &sizerStub{size: 300}, 1)
+			assert.Equal(t, bytesEntrySize+stringEntrySize+sizerEntrySize, cacher.SizeInBytesContained())
+
+			cacher.Remove([]byte("sizer"))
+			assert.Equal(t, bytesEntrySize+stringEntrySize, cacher.SizeInBytesContained())
+
+			cacher.Clear()
+			assert.Zero(t, cacher.SizeInBytesContained())
+		})
+	}
+}